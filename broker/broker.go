@@ -0,0 +1,175 @@
+package broker
+
+import (
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"context"
+	"strconv"
+	"sync"
+)
+
+const (
+	DefaultHistorySize = 1000
+	DefaultBufferSize  = 64
+)
+
+type Event struct {
+	ID   string
+	Type string
+	Data interface{}
+}
+
+// Subscription hands a subscriber the events it missed followed by live events.
+// Reset is set when the missed events are no longer held, the subscriber should refetch its state.
+// Events is closed when the subscriber falls too far behind, it can reconnect with the last ID it saw.
+type Subscription struct {
+	Replay []Event
+	Events <-chan Event
+	Reset  bool
+	cancel func()
+}
+
+func (s *Subscription) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+type Broker interface {
+	Publish(id int64, eventType string, data interface{})
+	Subscribe(lastEventID string) *Subscription
+	Close()
+}
+
+// BrokerImpl fans events out to in-process subscribers and keeps the latest historySize
+// of them so a reconnecting subscriber can resume from its Last-Event-ID.
+// Event IDs are the durable IDs they are published with, every instance publishes the same event
+// under the same ID, so a subscriber can resume on any instance and after a restart.
+type BrokerImpl struct {
+	mu 			sync.Mutex
+	// floor is the ID after which every event is held, -1 until the first publish
+	floor 		int64
+	last 		int64
+	history 	[]heldEvent
+	historySize int
+	bufferSize 	int
+	subscribers map[chan Event]int64
+	closed 		bool
+}
+
+type heldEvent struct {
+	id 		int64
+	event 	Event
+}
+
+func NewBrokerImpl(historySize, bufferSize int) Broker {
+	return &BrokerImpl{
+		floor: 			-1,
+		historySize: 	historySize,
+		bufferSize: 	bufferSize,
+		subscribers: 	map[chan Event]int64{},
+	}
+}
+
+// Publish hands the event to the subscribers, ids have to increase. An id not after the last one is
+// dropped, that event has been published already.
+func (b *BrokerImpl) Publish(id int64, eventType string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.floor >= 0 && id <= b.last {
+		return
+	}
+	if b.floor < 0 {
+		// nothing published before id is known, a subscriber that saw id-1 missed nothing held here
+		b.floor = id - 1
+	}
+	b.last = id
+	event := Event{
+		ID: 	strconv.FormatInt(id, 10),
+		Type: 	eventType,
+		Data: 	data,
+	}
+
+	b.history = append(b.history, heldEvent{id: id, event: event})
+	if len(b.history) > b.historySize {
+		b.floor = b.history[len(b.history) - b.historySize - 1].id
+		b.history = b.history[len(b.history) - b.historySize:]
+	}
+
+	for ch, after := range b.subscribers {
+		if id <= after {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			logger.Info(context.Background(), "Broker: Subscriber is too slow, dropping it")
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *BrokerImpl) Subscribe(lastEventID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, b.bufferSize)
+	if b.closed {
+		close(ch)
+		return &Subscription{Events: ch}
+	}
+
+	subscription := &Subscription{
+		Events: ch,
+		cancel: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if _, ok := b.subscribers[ch]; ok {
+				delete(b.subscribers, ch)
+				close(ch)
+			}
+		},
+	}
+	after := int64(-1)
+	if lastEventID != "" {
+		subscription.Replay, subscription.Reset, after = b.since(lastEventID)
+	}
+	b.subscribers[ch] = after
+	return subscription
+}
+
+// Close ends every subscription and the ones made after it, so the event streams let
+// the server drain on shutdown. Their clients reconnect to another instance with the last ID they saw.
+func (b *BrokerImpl) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// since returns the held events after lastEventID, or reset when they can not all be replayed, and the
+// ID after which live events are sent. A subscriber ahead of this instance, which has not published
+// what it saw yet, gets nothing until the events it has not seen.
+func (b *BrokerImpl) since(lastEventID string) ([]Event, bool, int64) {
+	id, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || id < 0 {
+		return nil, true, -1
+	}
+	if b.floor < 0 || id < b.floor {
+		return nil, true, id
+	}
+
+	replay := []Event{}
+	for _, held := range b.history {
+		if held.id > id {
+			replay = append(replay, held.event)
+		}
+	}
+	return replay, false, id
+}
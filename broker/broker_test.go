@@ -0,0 +1,214 @@
+package broker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, events <-chan broker.Event) broker.Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event was not received")
+		return broker.Event{}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("test case : publish fans out to every subscriber", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		first := b.Subscribe("")
+		defer first.Close()
+		second := b.Subscribe("")
+		defer second.Close()
+
+		b.Publish(1, "created", 1)
+
+		firstEvent, secondEvent := receive(t, first.Events), receive(t, second.Events)
+		assert.Equal(t, "created", firstEvent.Type)
+		assert.Equal(t, 1, firstEvent.Data)
+		assert.Equal(t, firstEvent, secondEvent)
+		assert.NotEmpty(t, firstEvent.ID)
+	})
+
+	t.Run("test case : slow subscriber is dropped", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 1)
+		slow := b.Subscribe("")
+		defer slow.Close()
+
+		b.Publish(1, "created", 1)
+		b.Publish(2, "updated", 1)
+
+		receive(t, slow.Events)
+		_, ok := <-slow.Events
+		assert.False(t, ok)
+	})
+
+	t.Run("test case : event published again is dropped", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		subscription := b.Subscribe("")
+		defer subscription.Close()
+
+		b.Publish(1, "created", 1)
+		b.Publish(1, "created", 1)
+		b.Publish(2, "updated", 1)
+
+		assert.Equal(t, "1", receive(t, subscription.Events).ID)
+		assert.Equal(t, "2", receive(t, subscription.Events).ID)
+	})
+
+	t.Run("test case : closed subscriber receives nothing", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 1)
+		subscription := b.Subscribe("")
+		subscription.Close()
+		subscription.Close()
+
+		b.Publish(1, "created", 1)
+
+		_, ok := <-subscription.Events
+		assert.False(t, ok)
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("test case : resume replays events after last event id", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		live := b.Subscribe("")
+		defer live.Close()
+
+		b.Publish(1, "created", 1)
+		b.Publish(2, "updated", 1)
+		b.Publish(3, "deleted", 1)
+		seen := receive(t, live.Events)
+
+		resumed := b.Subscribe(seen.ID)
+		defer resumed.Close()
+
+		require.False(t, resumed.Reset)
+		require.Len(t, resumed.Replay, 2)
+		assert.Equal(t, "updated", resumed.Replay[0].Type)
+		assert.Equal(t, "deleted", resumed.Replay[1].Type)
+	})
+
+	t.Run("test case : resume at latest event replays nothing", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		live := b.Subscribe("")
+		defer live.Close()
+
+		b.Publish(1, "created", 1)
+		seen := receive(t, live.Events)
+
+		resumed := b.Subscribe(seen.ID)
+		defer resumed.Close()
+
+		assert.False(t, resumed.Reset)
+		assert.Empty(t, resumed.Replay)
+	})
+
+	t.Run("test case : resume beyond history resets", func(t *testing.T) {
+		b := broker.NewBrokerImpl(2, 4)
+		live := b.Subscribe("")
+		defer live.Close()
+
+		b.Publish(1, "created", 1)
+		seen := receive(t, live.Events)
+		b.Publish(2, "created", 2)
+		b.Publish(3, "created", 3)
+		b.Publish(4, "created", 4)
+
+		resumed := b.Subscribe(seen.ID)
+		defer resumed.Close()
+
+		assert.True(t, resumed.Reset)
+		assert.Empty(t, resumed.Replay)
+	})
+
+	t.Run("test case : resume with an id that is not an event id resets", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		b.Publish(1, "created", 1)
+
+		resumed := b.Subscribe("previous-3")
+		defer resumed.Close()
+
+		assert.True(t, resumed.Reset)
+	})
+
+	t.Run("test case : resume on another broker replays by event id", func(t *testing.T) {
+		first, second := broker.NewBrokerImpl(10, 4), broker.NewBrokerImpl(10, 4)
+		live := first.Subscribe("")
+		defer live.Close()
+
+		for _, b := range []broker.Broker{first, second} {
+			b.Publish(5, "created", 1)
+			b.Publish(8, "updated", 1)
+		}
+		seen := receive(t, live.Events)
+
+		resumed := second.Subscribe(seen.ID)
+		defer resumed.Close()
+
+		require.False(t, resumed.Reset)
+		require.Len(t, resumed.Replay, 1)
+		assert.Equal(t, "8", resumed.Replay[0].ID)
+	})
+
+	t.Run("test case : resume ahead of the broker skips the events already seen", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		b.Publish(1, "created", 1)
+
+		resumed := b.Subscribe("3")
+		defer resumed.Close()
+		b.Publish(2, "updated", 1)
+		b.Publish(3, "updated", 1)
+		b.Publish(4, "deleted", 1)
+
+		require.False(t, resumed.Reset)
+		assert.Empty(t, resumed.Replay)
+		assert.Equal(t, "4", receive(t, resumed.Events).ID)
+	})
+
+	t.Run("test case : resume before the first event published resets", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		b.Publish(5, "created", 1)
+
+		resumed := b.Subscribe("3")
+		defer resumed.Close()
+
+		assert.True(t, resumed.Reset)
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Run("test case : close ends every subscription", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		first := b.Subscribe("")
+		second := b.Subscribe("")
+
+		b.Close()
+		first.Close()
+
+		_, ok := <-first.Events
+		assert.False(t, ok)
+		_, ok = <-second.Events
+		assert.False(t, ok)
+	})
+
+	t.Run("test case : subscribe after close is ended", func(t *testing.T) {
+		b := broker.NewBrokerImpl(10, 4)
+		b.Close()
+
+		subscription := b.Subscribe("")
+		defer subscription.Close()
+		b.Publish(1, "created", 1)
+
+		_, ok := <-subscription.Events
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newConfigCommand(flags *globalFlags) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use: 	"config",
+		Short: 	"Inspect the configuration",
+	}
+
+	var redacted bool
+	printCmd := &cobra.Command{
+		Use: 	"print",
+		Short: 	"Print the effective configuration, after the defaults, the config file, the environment and --set",
+		Args: 	cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configData, err := loadConfig(flags)
+			if err != nil {
+				return err
+			}
+			if err := configData.Print(cmd.OutOrStdout(), redacted); err != nil {
+				return err
+			}
+			// printed anyway, the values are what is needed to fix it
+			return configData.Validate()
+		},
+	}
+	printCmd.Flags().BoolVar(&redacted, "redacted", false, "hide the passwords, keys and tokens")
+
+	configCmd.AddCommand(printCmd)
+	return configCmd
+}
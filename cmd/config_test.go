@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigPrint(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.env")
+	require.NoError(t, os.WriteFile(file, []byte("POSTGRES_HOST=db\nPOSTGRES_USER=user\nPOSTGRES_DB=fiber\nJWT_SECRET_KEY=" + strings.Repeat("k", 32) + "\n"), 0600))
+
+	t.Run("test case : print redacted with overrides", func(t *testing.T) {
+		root := NewRootCommand()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"config", "print", "--redacted", "--config", file, "--set", "SERVER_PORT=9000"})
+
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, out.String(), "POSTGRES_HOST=db\n")
+		assert.Contains(t, out.String(), "SERVER_PORT=9000\n")
+		assert.Contains(t, out.String(), "JWT_SECRET_KEY=****\n")
+	})
+
+	t.Run("test case : fail invalid configuration", func(t *testing.T) {
+		root := NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"config", "print", "--config", file, "--set", "JWT_SECRET_KEY=short"})
+
+		err := root.Execute()
+
+		assert.EqualError(t, err, "invalid configuration:\n  - JWT_SECRET_KEY must be at least 32 characters, it has 5")
+	})
+
+	t.Run("test case : fail override without value", func(t *testing.T) {
+		root := NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"config", "print", "--config", file, "--set", "SERVER_PORT"})
+
+		assert.EqualError(t, root.Execute(), "--set SERVER_PORT is not KEY=VALUE")
+	})
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+
+	"github.com/spf13/cobra"
+
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+func newMigrateCommand(flags *globalFlags) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use: 	"migrate",
+		Short: 	"Manage the schema with the migrations built into the binary",
+	}
+
+	// withMigrator runs fn on a migrator of the database of --env
+	withMigrator := func(fn func(cmd *cobra.Command, migrator *migrate.Migrator, args []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			_, db, close, err := openDB(flags)
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			migrations, err := migrate.Load(config.Migrations, config.MigrationDirOf(db.Dialector.Name()))
+			if err != nil {
+				return err
+			}
+			return fn(cmd, migrate.NewMigrator(db, migrations), args)
+		}
+	}
+
+	migrateCmd.AddCommand(
+		&cobra.Command{
+			Use: 	"up",
+			Short: 	"Apply every pending migration",
+			Args: 	cobra.NoArgs,
+			RunE: withMigrator(func(cmd *cobra.Command, migrator *migrate.Migrator, args []string) error {
+				return migrator.Up(cmd.Context())
+			}),
+		},
+		&cobra.Command{
+			Use: 	"down",
+			Short: 	"Roll back the last applied migration",
+			Args: 	cobra.NoArgs,
+			RunE: withMigrator(func(cmd *cobra.Command, migrator *migrate.Migrator, args []string) error {
+				return migrator.Down(cmd.Context())
+			}),
+		},
+		&cobra.Command{
+			Use: 	"to <version>",
+			Short: 	"Apply or roll back migrations until the schema is at version, 0 rolls back everything",
+			Args: 	cobra.ExactArgs(1),
+			RunE: withMigrator(func(cmd *cobra.Command, migrator *migrate.Migrator, args []string) error {
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("version %s is not a number", args[0])
+				}
+				return migrator.To(cmd.Context(), uint(version))
+			}),
+		},
+		&cobra.Command{
+			Use: 	"baseline <version>",
+			Short: 	"Record the migrations up to version as applied without running them, for a schema created by hand",
+			Args: 	cobra.ExactArgs(1),
+			RunE: withMigrator(func(cmd *cobra.Command, migrator *migrate.Migrator, args []string) error {
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("version %s is not a number", args[0])
+				}
+				return migrator.Baseline(cmd.Context(), uint(version))
+			}),
+		},
+		&cobra.Command{
+			Use: 	"status",
+			Short: 	"List the migrations and whether they are applied",
+			Args: 	cobra.NoArgs,
+			RunE: withMigrator(func(cmd *cobra.Command, migrator *migrate.Migrator, args []string) error {
+				statuses, err := migrator.Status(cmd.Context())
+				if err != nil {
+					return err
+				}
+				writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(writer, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+				for _, status := range statuses {
+					appliedAt := "-"
+					if status.AppliedAt != nil {
+						appliedAt = status.AppliedAt.Format(time.RFC3339)
+					}
+					fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+				}
+				return writer.Flush()
+			}),
+		},
+	)
+	return migrateCmd
+}
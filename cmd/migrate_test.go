@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateBaseline(t *testing.T) {
+	t.Run("test case : adopt a schema created by hand", func(t *testing.T) {
+		dir := t.TempDir()
+		configData := &config.Config{DBDriver: "sqlite", SQLitePath: filepath.Join(dir, "fiber-test.db")}
+		file := filepath.Join(dir, "test.env")
+		require.NoError(t, os.WriteFile(file, []byte("DB_DRIVER=sqlite\nSQLITE_PATH=" + configData.SQLitePath + "\n"), 0600))
+
+		// 00001 as someone ran it before the migrations existed
+		db, err := gorm.Open(configData.Dialector(), &gorm.Config{})
+		require.NoError(t, err)
+		migrations, err := migrate.Load(config.Migrations, config.SQLiteMigrationDir)
+		require.NoError(t, err)
+		require.NoError(t, db.Exec(migrations[0].Up).Error)
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+
+		run := func(args ...string) error {
+			root := NewRootCommand()
+			root.SetOut(&bytes.Buffer{})
+			root.SetErr(&bytes.Buffer{})
+			root.SetArgs(append([]string{"migrate", "--config", file}, args...))
+			return root.Execute()
+		}
+
+		assert.ErrorContains(t, run("up"), "migration 1 failed")
+		assert.EqualError(t, run("up"), "migration 1 is dirty, repair the schema by hand and run migrate baseline 1")
+
+		assert.NoError(t, run("baseline", "1"))
+		assert.NoError(t, run("up"))
+	})
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/docs"
+
+	"github.com/spf13/cobra"
+
+	"io"
+	"os"
+)
+
+func newOpenAPICommand() *cobra.Command {
+	openAPICmd := &cobra.Command{
+		Use: 	"openapi",
+		Short: 	"Work with the OpenAPI spec of the API",
+	}
+
+	var output string
+	dumpCmd := &cobra.Command{
+		Use: 	"dump",
+		Short: 	"Write the OpenAPI spec as JSON to --output or stdout",
+		Args: 	cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			writer := cmd.OutOrStdout()
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				writer = file
+			}
+			_, err := io.WriteString(writer, docs.SwaggerInfo.ReadDoc())
+			return err
+		},
+	}
+	dumpCmd.Flags().StringVarP(&output, "output", "o", "", "file to write the spec to")
+
+	openAPICmd.AddCommand(dumpCmd)
+	return openAPICmd
+}
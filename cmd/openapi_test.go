@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenAPIDump(t *testing.T) {
+	t.Run("test case : dump to stdout", func(t *testing.T) {
+		root := NewRootCommand()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"openapi", "dump"})
+
+		assert.NoError(t, root.Execute())
+
+		spec := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &spec))
+		assert.Contains(t, spec["paths"], "/livez")
+	})
+
+	t.Run("test case : dump to file", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "openapi.json")
+		root := NewRootCommand()
+		root.SetArgs([]string{"openapi", "dump", "--output", output})
+
+		assert.NoError(t, root.Execute())
+
+		spec, err := os.ReadFile(output)
+		assert.NoError(t, err)
+		assert.Contains(t, string(spec), `"swagger": "2.0"`)
+	})
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultEnv = "dev"
+
+// globalFlags say where every subcommand loads the configuration from, see config.Options.
+type globalFlags struct {
+	env 	string
+	file 	string
+	set 	[]string
+}
+
+func (f *globalFlags) options() (config.Options, error) {
+	overrides := map[string]string{}
+	for _, setting := range f.set {
+		key, value, found := strings.Cut(setting, "=")
+		if !found || key == "" {
+			return config.Options{}, fmt.Errorf("--set %s is not KEY=VALUE", setting)
+		}
+		overrides[key] = value
+	}
+	return config.Options{Env: f.env, File: f.file, Overrides: overrides}, nil
+}
+
+// NewRootCommand builds the fiber-test command line. --env defaults to APP_ENV and then to dev.
+func NewRootCommand() *cobra.Command {
+	flags := &globalFlags{env: os.Getenv("APP_ENV")}
+	if flags.env == "" {
+		flags.env = defaultEnv
+	}
+
+	root := &cobra.Command{
+		Use: 			"fiber-test",
+		Short: 			"ProductType API server and the tasks to administer it",
+		SilenceUsage: 	true,
+	}
+	root.PersistentFlags().StringVar(&flags.env, "env", flags.env, "name of the config file of the working directory to load, without .env, .yaml or .toml")
+	root.PersistentFlags().StringVar(&flags.file, "config", "", "path of the config file to load instead of the one named by --env")
+	root.PersistentFlags().StringArrayVar(&flags.set, "set", nil, "KEY=VALUE overriding the config file and the environment, can be repeated")
+
+	root.AddCommand(
+		newServeCommand(flags),
+		newMigrateCommand(flags),
+		newSeedCommand(flags),
+		newUserCommand(flags),
+		newTokenCommand(flags),
+		newConfigCommand(flags),
+		newOpenAPICommand(),
+	)
+	return root
+}
+
+func Execute() error {
+	return NewRootCommand().Execute()
+}
+
+func loadConfig(flags *globalFlags) (*config.Config, error) {
+	options, err := flags.options()
+	if err != nil {
+		return nil, err
+	}
+	if err := config.LoadConfig(options); err != nil {
+		return nil, err
+	}
+	configData, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &configData, nil
+}
+
+// openDB connects to the database of the configuration for a one-off task, close releases the connections.
+func openDB(flags *globalFlags) (configData *config.Config, db *gorm.DB, close func(), err error) {
+	configData, err = loadConfig(flags)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := configData.ValidateDatabase(); err != nil {
+		return nil, nil, nil, err
+	}
+	if configData.DBDriver == "memory" {
+		return nil, nil, nil, errors.New("DB_DRIVER memory keeps the data in the server process, a command needs the postgres or sqlite driver")
+	}
+
+	db, err = config.ConnectionDB(context.Background(), configData)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return configData, db, func() { sqlDB.Close() }, nil
+}
+
+// services are what the admin tasks run through, the same ones the API uses.
+type services struct {
+	auth 		service.AuthService
+	user 		service.UserService
+	prodType 	service.ProductTypeService
+}
+
+func newServices(db *gorm.DB, configData *config.Config) *services {
+	store := repository.NewGormStore(db, configData.DBReadRetries)
+	auditService := service.NewAuditServiceImpl(repository.NewAuditRepositoryImpl(db, configData.DBReadRetries))
+
+	return &services{
+		auth: 		service.NewAuthServiceImpl(store.Users, store.Roles, store.Oauths, store.TxManager, auditService),
+		user: 		service.NewUserServiceImpl(store.Users, store.Roles, store.TxManager, auditService),
+		// nothing subscribes outside the server, the outbox relay publishes the changes
+		prodType: 	service.NewProductTypeServiceImpl(store.ProductTypes, store.TxManager, auditService, broker.NewBrokerImpl(broker.DefaultHistorySize, broker.DefaultBufferSize)),
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// fixtures is the content of a seed file, its fields are those of the API requests:
+//
+//	users:
+//	  - {user_id: 1, role_id: 2, user_name: Admin, user_email: admin@example.com, user_password: secret}
+//	producttypes:
+//	  - {prodtype_id: 1, prodtype_name: Drinks}
+//	  - {prodtype_id: 2, prodtype_name: Coffee, prodtype_parent_id: 1}
+type fixtures struct {
+	Users 			[]model.UserCreate 			`json:"users"`
+	ProductTypes 	[]model.ProductTypeCreate 	`json:"producttypes"`
+}
+
+func newSeedCommand(flags *globalFlags) *cobra.Command {
+	var file string
+	seedCmd := &cobra.Command{
+		Use: 	"seed",
+		Short: 	"Load users and product types from a YAML file, the ones that exist already are kept",
+		Args: 	cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reader, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+			seed, err := loadFixtures(reader)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+
+			configData, db, close, err := openDB(flags)
+			if err != nil {
+				return err
+			}
+			defer close()
+			return seedFixtures(cmd.Context(), cmd.OutOrStdout(), newServices(db, configData), seed)
+		},
+	}
+	seedCmd.Flags().StringVarP(&file, "file", "f", "", "YAML file of the fixtures")
+	seedCmd.MarkFlagRequired("file")
+	return seedCmd
+}
+
+// loadFixtures reads YAML into the JSON shape of the API requests, unknown fields are refused.
+func loadFixtures(reader io.Reader) (*fixtures, error) {
+	var document interface{}
+	if err := yaml.NewDecoder(reader).Decode(&document); err != nil && err != io.EOF {
+		return nil, err
+	}
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := &fixtures{}
+	if document == nil {
+		return seed, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(documentJSON))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// seedFixtures creates the fixtures in file order, so parents come before their children.
+func seedFixtures(ctx context.Context, out io.Writer, services *services, seed *fixtures) error {
+	created, existing := 0, 0
+	for i := range seed.Users {
+		err := services.auth.Register(ctx, &seed.Users[i])
+		if counted(err, &created, &existing) != nil {
+			return fmt.Errorf("user %d: %w", seed.Users[i].ID, err)
+		}
+	}
+	fmt.Fprintf(out, "users: %d created, %d already there\n", created, existing)
+
+	created, existing = 0, 0
+	for i := range seed.ProductTypes {
+		err := services.prodType.Create(ctx, &seed.ProductTypes[i])
+		if counted(err, &created, &existing) != nil {
+			return fmt.Errorf("producttype %d: %w", seed.ProductTypes[i].ID, err)
+		}
+	}
+	fmt.Fprintf(out, "producttypes: %d created, %d already there\n", created, existing)
+	return nil
+}
+
+// counted counts err as created or existing, any other error is returned.
+func counted(err error, created, existing *int) error {
+	var errResponse errs.ErrorResponse
+	switch {
+	case err == nil:
+		*created++
+	case errors.As(err, &errResponse) && errResponse.Code == http.StatusConflict:
+		*existing++
+	default:
+		return err
+	}
+	return nil
+}
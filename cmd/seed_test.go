@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	t.Run("test case : pass", func(t *testing.T) {
+		parentID := 1
+		seed, err := loadFixtures(strings.NewReader(`
+users:
+  - {user_id: 1, role_id: 2, user_name: Admin, user_email: admin@example.com, user_password: secret}
+producttypes:
+  - {prodtype_id: 1, prodtype_name: Drinks}
+  - {prodtype_id: 2, prodtype_name: Coffee, prodtype_parent_id: 1}
+`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.UserCreate{{ID:1,RoleID:2,Name:"Admin",Email:"admin@example.com",Password:"secret"}}, seed.Users)
+		assert.Equal(t, []model.ProductTypeCreate{{ID:1,Name:"Drinks"},{ID:2,Name:"Coffee",ParentID:&parentID}}, seed.ProductTypes)
+	})
+
+	t.Run("test case : pass empty file", func(t *testing.T) {
+		seed, err := loadFixtures(strings.NewReader(""))
+
+		assert.NoError(t, err)
+		assert.Empty(t, seed.Users)
+		assert.Empty(t, seed.ProductTypes)
+	})
+
+	t.Run("test case : fail unknown field", func(t *testing.T) {
+		_, err := loadFixtures(strings.NewReader("producttypes:\n  - {prodtype_id: 1, prodtype_title: Drinks}\n"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSeedFixtures(t *testing.T) {
+	t.Run("test case : existing ones are kept", func(t *testing.T) {
+		prodTypeService := testutils.NewProductTypeServiceMock()
+		prodTypeService.On("Create", mock.Anything, &model.ProductTypeCreate{ID:1,Name:"Drinks"}).Return(errs.NewConflictError("ProductType with this ID already exists"))
+		prodTypeService.On("Create", mock.Anything, &model.ProductTypeCreate{ID:2,Name:"Food"}).Return(nil)
+
+		var out bytes.Buffer
+		err := seedFixtures(context.Background(), &out, &services{prodType: prodTypeService}, &fixtures{
+			ProductTypes: []model.ProductTypeCreate{{ID:1,Name:"Drinks"},{ID:2,Name:"Food"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "users: 0 created, 0 already there\nproducttypes: 1 created, 1 already there\n", out.String())
+		prodTypeService.AssertExpectations(t)
+	})
+
+	t.Run("test case : fail stops at the first error", func(t *testing.T) {
+		prodTypeService := testutils.NewProductTypeServiceMock()
+		prodTypeService.On("Create", mock.Anything, &model.ProductTypeCreate{ID:1,Name:"Drinks"}).Return(errs.NewBadRequestError("Parent ProductType is not found"))
+
+		err := seedFixtures(context.Background(), &bytes.Buffer{}, &services{prodType: prodTypeService}, &fixtures{
+			ProductTypes: []model.ProductTypeCreate{{ID:1,Name:"Drinks"},{ID:2,Name:"Food"}},
+		})
+
+		assert.EqualError(t, err, "producttype 1: Parent ProductType is not found")
+		prodTypeService.AssertNumberOfCalls(t, "Create", 1)
+	})
+}
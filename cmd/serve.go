@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/router"
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/health"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/metrics"
+	"github.com/Yoshikrit/fiber-test/migrate"
+	"github.com/Yoshikrit/fiber-test/replica"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/sink"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/worker"
+	"github.com/Yoshikrit/fiber-test/model"
+	
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultShutdownTimeout 	= 30 * time.Second
+	// cleanupTimeout bounds closing the metrics server, NATS, the tracer and the database once the requests are drained.
+	cleanupTimeout 			= 10 * time.Second
+)
+
+func newServeCommand(flags *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use: 	"serve",
+		Short: 	"Run the API server until SIGINT or SIGTERM",
+		Args: 	cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(flags)
+		},
+	}
+}
+
+func serve(flags *globalFlags) error {
+	app := router.NewApp()
+	
+	//config
+	configData, err := loadConfig(flags)
+	if err != nil {
+		return err
+	}
+	if err := configData.Validate(); err != nil {
+		return err
+	}
+
+	//Runtime settings, the middleware and the tokens follow them on their own
+	if err := logger.SetLevel(configData.LogLevel); err != nil {
+		return err
+	}
+	config.Subscribe(func(next config.Config, changed []string) {
+		if slices.Contains(changed, "LOG_LEVEL") {
+			logger.SetLevel(next.LogLevel)
+		}
+		logger.Info(context.Background(), "Config: Applied settings", "keys", strings.Join(changed, ","))
+	})
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	err = config.Watch(watchCtx, func(changed []string, err error) {
+		if err != nil {
+			logger.Error(context.Background(), err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	//Tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), configData.TracingExporter, configData.AppName, configData.TracingEndpoint)
+	if err != nil {
+		return err
+	}
+
+	//Database
+	db, err := config.ConnectionDB(context.Background(), configData)
+	if err != nil {
+		return err
+	}
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return err
+	}
+	store := repository.NewStore(configData.DBDriver, db, configData.DBReadRetries)
+
+	//Read replicas
+	replicaDBs, err := config.ConnectReplicas(configData)
+	if err != nil {
+		return err
+	}
+	replicas := make([]replica.Replica, 0, len(replicaDBs))
+	for i, replicaDB := range replicaDBs {
+		replicas = append(replicas, replica.Replica{Name: fmt.Sprintf("replica-%d", i + 1), DB: replicaDB})
+	}
+	replicaResolver := replica.NewResolver(
+		strings.Split(configData.DBReplicaRoutes, ","),
+		time.Duration(configData.DBReplicaCheckInterval) * time.Second,
+		replicas...,
+	)
+	if err := db.Use(replicaResolver); err != nil {
+		return err
+	}
+	replicaResolver.Start()
+
+	//Migrations, replicas starting together wait for each other on the migration lock.
+	//The database of the memory driver starts empty every time
+	migrations, err := migrate.Load(config.Migrations, config.MigrationDirOf(db.Dialector.Name()))
+	if err != nil {
+		return err
+	}
+	if configData.MigrateOnStart || configData.DBDriver == "memory" {
+		if err := migrate.NewMigrator(db, migrations).Up(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	//Metrics
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := metrics.RegisterDBStats(sqlDB, configData.DBName); err != nil {
+		return err
+	}
+	if err := metrics.RegisterActiveSessions(store.Oauths); err != nil {
+		return err
+	}
+	var metricsServer *http.Server
+	if configData.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{Addr: configData.MetricsAddr, Handler: metricsMux}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error(context.Background(), err)
+			}
+		}()
+	}
+
+	//Broker
+	prodTypeBroker := broker.NewBrokerImpl(broker.DefaultHistorySize, broker.DefaultBufferSize)
+
+	//Webhooks
+	webhookService := service.NewWebhookServiceImpl(
+		repository.NewWebhookRepositoryImpl(db, configData.DBReadRetries),
+		time.Duration(configData.WebhookTimeout) * time.Second,
+		configData.WebhookMaxAttempts,
+		time.Duration(configData.WebhookBackoff) * time.Second,
+	)
+
+	//Probes
+	healthRegistry := health.NewRegistry(health.DefaultCheckTimeout)
+	healthRegistry.Register("database", health.DBPing(sqlDB), health.Readiness, health.Startup)
+	healthRegistry.Register("migrations", health.MigrationVersion(db, migrate.Latest(migrations)), health.Startup)
+	healthRegistry.Register("signing_keys", health.SigningKeys(func() string {
+		keyConfig, _ := config.GetConfig()
+		return keyConfig.JWTSecretKey
+	}), health.Readiness, health.Startup)
+
+	//Routes
+	router.NewRouter(app, db, store, prodTypeBroker, webhookService, healthRegistry, configData)
+
+	//Workers
+	trashRetentionWorker := worker.NewTrashRetentionWorker(
+		service.NewProductTypeServiceImpl(
+			store.ProductTypes,
+			store.TxManager,
+			service.NewAuditServiceImpl(repository.NewAuditRepositoryImpl(db, configData.DBReadRetries)),
+			prodTypeBroker,
+		),
+		time.Duration(configData.TrashRetentionDays) * 24 * time.Hour,
+		time.Duration(configData.TrashPurgeInterval) * time.Second,
+	)
+	trashRetentionWorker.Start()
+
+	webhookDeliveryWorker := worker.NewWebhookDeliveryWorker(
+		webhookService,
+		time.Duration(configData.WebhookDeliveryInterval) * time.Second,
+	)
+	webhookDeliveryWorker.Start()
+
+	//Outbox
+	var sinks []service.EventSink
+	var natsConn *nats.Conn
+	outboxTailInterval := time.Duration(-1)
+	sinkNames := configData.OutboxSinks
+	if sinkNames == "" {
+		sinkNames = "broker,webhook"
+	}
+	for _, name := range strings.Split(sinkNames, ",") {
+		switch strings.TrimSpace(name) {
+		case "broker":
+			// the event stream is fed by the outbox tail of every instance rather than by the relay
+			outboxTailInterval = time.Duration(configData.OutboxTailInterval) * time.Second
+		case "webhook":
+			sinks = append(sinks, sink.NewWebhookSink(webhookService))
+		case "log":
+			sinks = append(sinks, sink.NewLogSink())
+		case "nats":
+			natsConn, err = nats.Connect(configData.NATSURL)
+			if err != nil {
+				return err
+			}
+			sinks = append(sinks, sink.NewNATSSink(natsConn, configData.NATSSubjectPrefix))
+		default:
+			return fmt.Errorf("unknown outbox sink: %s", name)
+		}
+	}
+
+	outboxRelayWorker := worker.NewOutboxRelayWorker(
+		service.NewOutboxServiceImpl(
+			store.Outbox,
+			configData.OutboxMaxAttempts,
+			time.Duration(configData.OutboxBackoff) * time.Second,
+			sinks...,
+		),
+		time.Duration(configData.OutboxRelayInterval) * time.Second,
+	)
+	outboxRelayWorker.Start()
+
+	outboxTailWorker := worker.NewOutboxTailWorker(
+		service.NewOutboxTailServiceImpl(store.Outbox, model.AuditResourceProductType, prodTypeBroker),
+		outboxTailInterval,
+	)
+	outboxTailWorker.Start()
+
+	//Server
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- app.Listen(":" +  configData.ServerPort)
+	}()
+
+	// a listener that failed is still cleaned up after, then serve fails with its error
+	var listenErr error
+	select {
+	case sig := <-signals:
+		logger.Info(context.Background(), "Server: Shutting down", "signal", sig.String())
+	case err := <-serverErr:
+		logger.Error(context.Background(), err)
+		listenErr = fmt.Errorf("listen on port %s: %w", configData.ServerPort, err)
+	}
+
+	//Shutdown, readiness fails first so load balancers stop routing here before connections are refused
+	healthRegistry.Drain()
+	if listenErr == nil {
+		time.Sleep(time.Duration(configData.ShutdownDelay) * time.Second)
+	}
+
+	shutdownTimeout := time.Duration(configData.ShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// the event streams never finish on their own and would hold the drain until the timeout
+	prodTypeBroker.Close()
+	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+		logger.Error(shutdownCtx, err)
+	}
+
+	trashRetentionWorker.Stop()
+	webhookDeliveryWorker.Stop()
+	outboxRelayWorker.Stop()
+	outboxTailWorker.Stop()
+	replicaResolver.Stop()
+
+	cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cleanupCancel()
+
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(cleanupCtx); err != nil {
+			logger.Error(cleanupCtx, err)
+		}
+	}
+	if natsConn != nil {
+		if err := natsConn.Drain(); err != nil {
+			logger.Error(cleanupCtx, err)
+		}
+	}
+	if err := shutdownTracing(cleanupCtx); err != nil {
+		logger.Error(cleanupCtx, err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		logger.Error(cleanupCtx, err)
+	}
+	if listenErr != nil {
+		return listenErr
+	}
+	logger.Info(cleanupCtx, "Server: Shutdown Successfully")
+	return nil
+}
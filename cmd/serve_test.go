@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	t.Run("test case : fail port in use", func(t *testing.T) {
+		listener, err := net.Listen("tcp", ":0")
+		require.NoError(t, err)
+		defer listener.Close()
+		port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+		file := filepath.Join(t.TempDir(), "test.env")
+		require.NoError(t, os.WriteFile(file, []byte("DB_DRIVER=memory\nSERVER_PORT=" + port + "\nJWT_SECRET_KEY=" + strings.Repeat("k", 32) + "\n"), 0600))
+
+		root := NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"serve", "--config", file})
+
+		err = root.Execute()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "listen on port " + port + ": ")
+		assert.Contains(t, err.Error(), "address already in use")
+	})
+}
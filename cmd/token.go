@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"errors"
+	"fmt"
+)
+
+func newTokenCommand(flags *globalFlags) *cobra.Command {
+	tokenCmd := &cobra.Command{
+		Use: 	"token",
+		Short: 	"Manage the sessions of users",
+	}
+
+	var userID int
+	revokeCmd := &cobra.Command{
+		Use: 	"revoke",
+		Short: 	"Log a user out of every session",
+		Args: 	cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if userID <= 0 {
+				return errors.New("--user is required")
+			}
+			configData, db, close, err := openDB(flags)
+			if err != nil {
+				return err
+			}
+			defer close()
+
+			revoked, err := newServices(db, configData).user.RevokeTokens(cmd.Context(), userID)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "revoked %d sessions of user %d\n", revoked, userID)
+			return nil
+		},
+	}
+	revokeCmd.Flags().IntVar(&userID, "user", 0, "ID of the user")
+
+	tokenCmd.AddCommand(revokeCmd)
+	return tokenCmd
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"github.com/spf13/cobra"
+
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func newUserCommand(flags *globalFlags) *cobra.Command {
+	userCmd := &cobra.Command{
+		Use: 	"user",
+		Short: 	"Administer user accounts, such as bootstrapping the first admin",
+	}
+
+	// withServices runs fn on the services of the database of --env
+	withServices := func(fn func(cmd *cobra.Command, services *services, args []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			configData, db, close, err := openDB(flags)
+			if err != nil {
+				return err
+			}
+			defer close()
+			return fn(cmd, newServices(db, configData), args)
+		}
+	}
+
+	userCreate := &model.UserCreate{}
+	createCmd := &cobra.Command{
+		Use: 	"create",
+		Short: 	"Create a user, the password is read from stdin when --password is not given",
+		Args: 	cobra.NoArgs,
+		RunE: withServices(func(cmd *cobra.Command, services *services, args []string) error {
+			password, err := passwordOrStdin(cmd, userCreate.Password)
+			if err != nil {
+				return err
+			}
+			userCreate.Password = password
+			if err := services.auth.Register(cmd.Context(), userCreate); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "created user %d\n", userCreate.ID)
+			return nil
+		}),
+	}
+	createCmd.Flags().IntVar(&userCreate.ID, "id", 0, "ID of the user")
+	createCmd.Flags().IntVar(&userCreate.RoleID, "role-id", 0, "ID of the role of the user")
+	createCmd.Flags().StringVar(&userCreate.Name, "name", "", "name of the user")
+	createCmd.Flags().StringVar(&userCreate.Email, "email", "", "email the user logs in with")
+	createCmd.Flags().StringVar(&userCreate.Password, "password", "", "password of the user")
+
+	var roleTitle string
+	setRoleCmd := &cobra.Command{
+		Use: 	"set-role <user-id>",
+		Short: 	"Give a user another role, their sessions are revoked",
+		Args: 	cobra.ExactArgs(1),
+		RunE: withServices(func(cmd *cobra.Command, services *services, args []string) error {
+			id, err := userID(args[0])
+			if err != nil {
+				return err
+			}
+			if err := services.user.SetRole(cmd.Context(), id, roleTitle); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "user %d is now %s\n", id, roleTitle)
+			return nil
+		}),
+	}
+	setRoleCmd.Flags().StringVar(&roleTitle, "role", "", "title of the role, such as Admin or Manager")
+	setRoleCmd.MarkFlagRequired("role")
+
+	var password string
+	resetPasswordCmd := &cobra.Command{
+		Use: 	"reset-password <user-id>",
+		Short: 	"Set a new password, read from stdin when --password is not given, the sessions are revoked",
+		Args: 	cobra.ExactArgs(1),
+		RunE: withServices(func(cmd *cobra.Command, services *services, args []string) error {
+			id, err := userID(args[0])
+			if err != nil {
+				return err
+			}
+			newPassword, err := passwordOrStdin(cmd, password)
+			if err != nil {
+				return err
+			}
+			if err := services.user.ResetPassword(cmd.Context(), id, newPassword); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "reset the password of user %d\n", id)
+			return nil
+		}),
+	}
+	resetPasswordCmd.Flags().StringVar(&password, "password", "", "new password")
+
+	disableCmd := &cobra.Command{
+		Use: 	"disable <user-id>",
+		Short: 	"Stop a user from logging in and revoke their sessions",
+		Args: 	cobra.ExactArgs(1),
+		RunE: withServices(func(cmd *cobra.Command, services *services, args []string) error {
+			id, err := userID(args[0])
+			if err != nil {
+				return err
+			}
+			if err := services.user.Disable(cmd.Context(), id); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "disabled user %d\n", id)
+			return nil
+		}),
+	}
+
+	userCmd.AddCommand(createCmd, setRoleCmd, resetPasswordCmd, disableCmd)
+	return userCmd
+}
+
+func userID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("user ID %s is not a positive number", arg)
+	}
+	return id, nil
+}
+
+// passwordOrStdin returns password, or the first line of stdin when it is empty, so the
+// password does not have to show up in the shell history.
+func passwordOrStdin(cmd *cobra.Command, password string) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("no password given with --password or on stdin")
+	}
+	return line, nil
+}
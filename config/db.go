@@ -1,18 +1,214 @@
 package config
 
 import (
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
-	
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectionDB(config *Config) *gorm.DB {
-	sqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s timezone=%s sslmode=disable", config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName, config.TimeZone)
-	db, err := gorm.Open(postgres.Open(sqlInfo), &gorm.Config{})
+const (
+	connectBackoff 		= 500 * time.Millisecond
+	connectMaxBackoff 	= 5 * time.Second
+)
+
+type dsnSetting struct {
+	key 	string
+	value 	string
+}
+
+// PostgresDSN is the connection string of the settings, the values are quoted so a password may have spaces.
+func (c *Config) PostgresDSN() string {
+	sslMode := c.DBSSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	settings := []dsnSetting{
+		{"host", c.DBHost},
+		{"port", c.DBPort},
+		{"user", c.DBUser},
+		{"password", c.DBPassword},
+		{"dbname", c.DBName},
+		{"timezone", c.TimeZone},
+		{"sslmode", sslMode},
+		{"sslrootcert", c.DBSSLRootCert},
+	}
+	if c.DBStatementTimeout > 0 {
+		// a session setting, in milliseconds
+		settings = append(settings, dsnSetting{"statement_timeout", strconv.Itoa(c.DBStatementTimeout * 1000)})
+	}
+
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	var dsn []string
+	for _, setting := range settings {
+		if setting.value != "" {
+			dsn = append(dsn, setting.key + "='" + quote.Replace(setting.value) + "'")
+		}
+	}
+	return strings.Join(dsn, " ")
+}
+
+// SQLiteDSN opens SQLITE_PATH, or a database in memory for the memory driver, with the foreign keys
+// enforced as Postgres does. A writer waits for the one before it rather than failing at once.
+func (c *Config) SQLiteDSN() string {
+	if c.DBDriver == "memory" {
+		return "file::memory:?_pragma=foreign_keys(1)"
+	}
+	return "file:" + c.SQLitePath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+}
+
+// Dialector is the gorm driver of DB_DRIVER. The memory driver keeps its repositories in the process,
+// the audit log and the webhooks it does not cover go to a SQLite database in memory.
+func (c *Config) Dialector() gorm.Dialector {
+	switch c.DBDriver {
+	case "sqlite", "memory":
+		return sqlite.Open(c.SQLiteDSN())
+	default:
+		return postgres.Open(c.PostgresDSN())
+	}
+}
+
+// ConnectionDB connects to the database of config with its pool settings. While the database is
+// not up yet it tries again with backoff until DB_CONNECT_TIMEOUT, as under docker-compose the
+// server starts along with it, other errors such as a wrong password fail at once.
+func ConnectionDB(ctx context.Context, config *Config) (*gorm.DB, error) {
+	if config.DBConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.DBConnectTimeout) * time.Second)
+		defer cancel()
+	}
+
+	backoff := connectBackoff
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(config.Dialector(), &gorm.Config{})
+		if err == nil {
+			return db, configurePool(db, config)
+		}
+		if db != nil {
+			if sqlDB, _ := db.DB(); sqlDB != nil {
+				sqlDB.Close()
+			}
+		}
+		if !IsTransientError(err) {
+			return nil, err
+		}
+
+		logger.Error(ctx, err, "attempt", attempt, "retry_in", backoff.String())
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("database is not reachable after %d attempts: %w", attempt, err)
+		}
+		backoff = min(backoff * 2, connectMaxBackoff)
+	}
+}
+
+// ConnectReplicas opens a pool to each DSN of DB_READ_REPLICAS, a comma separated list, with the pool
+// settings of the primary. A replica that is down does not stop the server, the first check finds it.
+func ConnectReplicas(config *Config) ([]*sql.DB, error) {
+	var replicas []*sql.DB
+	for _, dsn := range strings.Split(config.DBReadReplicas, ",") {
+		if dsn = strings.TrimSpace(dsn); dsn == "" {
+			continue
+		}
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
+		if err == nil {
+			err = configurePool(db, config)
+		}
+		if err != nil {
+			for _, replica := range replicas {
+				replica.Close()
+			}
+			return nil, fmt.Errorf("read replica %d: %w", len(replicas) + 1, err)
+		}
+		sqlDB, _ := db.DB()
+		replicas = append(replicas, sqlDB)
+	}
+	return replicas, nil
+}
+
+func configurePool(db *gorm.DB, config *Config) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if config.DBDriver == "memory" {
+		// every connection opens a database of its own, the one connection must never be closed
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		return nil
+	}
+	sqlDB.SetMaxOpenConns(config.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(config.DBConnMaxLifetime) * time.Second)
+	return nil
+}
+
+// transientSQLStates are the errors a database that is starting, restarting or busy answers with,
+// the same statement is expected to succeed a moment later. Class 08 is every connection exception.
+var transientSQLStates = []string{
+	"40001", // serialization_failure
+	"40P01", // deadlock_detected
+	"53300", // too_many_connections
+	"57P01", // admin_shutdown
+	"57P02", // crash_shutdown
+	"57P03", // cannot_connect_now, the database is starting up
+}
+
+// IsTransientError tells whether running the same statement again may succeed: the connection
+// failed or broke, or the database is starting or shutting down. A done context never is.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
 	}
 
-	return db
-}
\ No newline at end of file
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		for _, transient := range transientSQLStates {
+			if state == transient {
+				return true
+			}
+		}
+		return strings.HasPrefix(state, "08")
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// sqliteConstraintPrimaryKey and sqliteConstraintUnique are the extended result codes of SQLite.
+const (
+	sqliteConstraintPrimaryKey 	= 1555
+	sqliteConstraintUnique 		= 2067
+)
+
+// IsDuplicateKeyError tells whether err is a row clashing with the primary key or a unique
+// column of another, unique_violation on Postgres.
+func IsDuplicateKeyError(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState() == "23505"
+	}
+	var codeErr interface{ Code() int }
+	if errors.As(err, &codeErr) {
+		return codeErr.Code() == sqliteConstraintPrimaryKey || codeErr.Code() == sqliteConstraintUnique
+	}
+	return false
+}
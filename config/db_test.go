@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	t.Run("test case : quotes values and adds the optional settings", func(t *testing.T) {
+		configData := validConfig()
+		configData.DBPassword = `it's a secret`
+		configData.DBSSLMode = "verify-full"
+		configData.DBSSLRootCert = "/etc/ssl/root.crt"
+		configData.DBStatementTimeout = 5
+
+		assert.Equal(t, `host='db' port='5432' user='user' password='it\'s a secret' dbname='fiber' timezone='Asia/Bangkok' sslmode='verify-full' sslrootcert='/etc/ssl/root.crt' statement_timeout='5000'`, configData.PostgresDSN())
+	})
+
+	t.Run("test case : ssl is disabled by default", func(t *testing.T) {
+		configData := validConfig()
+
+		assert.Equal(t, `host='db' port='5432' user='user' dbname='fiber' timezone='Asia/Bangkok' sslmode='disable'`, configData.PostgresDSN())
+	})
+}
+
+func TestIsTransientError(t *testing.T) {
+	for _, testCase := range []struct {
+		name 		string
+		err 		error
+		transient 	bool
+	}{
+		{"database starting up", testutils.SQLStateError("57P03"), true},
+		{"connection failure", fmt.Errorf("query: %w", testutils.SQLStateError("08006")), true},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"bad connection", driver.ErrBadConn, true},
+		{"wrong password", testutils.SQLStateError("28P01"), false},
+		{"statement timeout", testutils.SQLStateError("57014"), false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"no error", nil, false},
+	} {
+		t.Run("test case : " + testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.transient, config.IsTransientError(testCase.err))
+		})
+	}
+}
+
+func TestConnectionDB(t *testing.T) {
+	t.Run("test case : gives up at the connect timeout", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		configData := validConfig()
+		configData.DBHost = "127.0.0.1"
+		configData.DBPort = fmt.Sprint(port)
+		configData.DBConnectTimeout = 1
+
+		started := time.Now()
+		db, err := config.ConnectionDB(context.Background(), &configData)
+
+		assert.Nil(t, db)
+		assert.ErrorContains(t, err, "database is not reachable after")
+		assert.Less(t, time.Since(started), 3 * time.Second)
+	})
+}
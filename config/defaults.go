@@ -0,0 +1,47 @@
+package config
+
+// defaults are the settings a deployment does not have to give, the Postgres database and the JWT secret have none.
+var defaults = map[string]interface{}{
+	"DB_DRIVER": 			"postgres",
+	"SQLITE_PATH": 			"fiber-test.db",
+
+	"POSTGRES_PORT": 		"5432",
+	"POSTGRES_SSLMODE": 	"disable",
+
+	"DB_MAX_OPEN_CONNS": 	25,
+	"DB_MAX_IDLE_CONNS": 	5,
+	// seconds, connections are replaced every 30 minutes and the server waits a minute for the database
+	"DB_CONN_MAX_LIFETIME": 1800,
+	"DB_CONNECT_TIMEOUT": 	60,
+	"DB_READ_RETRIES": 		2,
+
+	// the reads these repository methods make go to DB_READ_REPLICAS when there are some
+	"DB_REPLICA_ROUTES": 	"ProductTypeRepository.FindAll,ProductTypeRepository.FindByID,ProductTypeRepository.Count,OauthRepository.FindByAccessToken",
+	"DB_REPLICA_CHECK_INTERVAL": 5,
+
+	"SERVER_PORT": 			"8081",
+	"APP_NAME": 			"fiber-test",
+	"TIMEZONE": 			"Asia/Bangkok",
+	"SHUTDOWN_TIMEOUT": 	30,
+	"LOG_LEVEL": 			"debug",
+
+	"CORS_ALLOW_ORIGINS": 	"http://localhost:8081, https://localhost:8081",
+	"RATE_LIMIT_MAX": 		20,
+	"RATE_LIMIT_WINDOW": 	60,
+
+	// seconds, 15 minutes and 7 days
+	"JWT_ACCESS_EXPIRES": 	900,
+	"JWT_REFRESH_EXPIRES": 	604800,
+
+	"WEBHOOK_MAX_ATTEMPTS": 8,
+	"WEBHOOK_BACKOFF": 		10,
+	"WEBHOOK_TIMEOUT": 		10,
+
+	"OUTBOX_RELAY_INTERVAL": 1,
+	"OUTBOX_MAX_ATTEMPTS": 	10,
+	"OUTBOX_BACKOFF": 		5,
+	"OUTBOX_TAIL_INTERVAL": 1,
+	"OUTBOX_SINKS": 		"broker,webhook",
+
+	"TRACING_EXPORTER": 	"none",
+}
@@ -1,45 +1,195 @@
 package config
 
 import (
-	"os"
-
 	"github.com/spf13/viper"
+
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
 )
 
 type Config struct {
+	DBDriver 		string `mapstructure:"DB_DRIVER"`
+	SQLitePath 		string `mapstructure:"SQLITE_PATH"`
+
 	DBHost 			string `mapstructure:"POSTGRES_HOST"`
 	DBUser 			string `mapstructure:"POSTGRES_USER"`
-	DBPassword 		string `mapstructure:"POSTGRES_PASSWORD"`
+	DBPassword 		string `mapstructure:"POSTGRES_PASSWORD" secret:"true"`
 	DBName 			string `mapstructure:"POSTGRES_DB"`
 	DBPort 			string `mapstructure:"POSTGRES_PORT"`
+	DBSSLMode 		string `mapstructure:"POSTGRES_SSLMODE"`
+	DBSSLRootCert 	string `mapstructure:"POSTGRES_SSLROOTCERT"`
+
+	DBMaxOpenConns 		int 	`mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns 		int 	`mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime 	int 	`mapstructure:"DB_CONN_MAX_LIFETIME"`
+	DBConnectTimeout 	int 	`mapstructure:"DB_CONNECT_TIMEOUT"`
+	DBStatementTimeout 	int 	`mapstructure:"DB_STATEMENT_TIMEOUT"`
+	DBReadRetries 		int 	`mapstructure:"DB_READ_RETRIES"`
+
+	DBReadReplicas 			string 	`mapstructure:"DB_READ_REPLICAS" secret:"true"`
+	DBReplicaRoutes 		string 	`mapstructure:"DB_REPLICA_ROUTES"`
+	DBReplicaCheckInterval 	int 	`mapstructure:"DB_REPLICA_CHECK_INTERVAL"`
 
 	ServerPort 		string `mapstructure:"SERVER_PORT"`
+	ShutdownTimeout int    `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ShutdownDelay 	int    `mapstructure:"SHUTDOWN_DELAY"`
 	AppName 		string `mapstructure:"APP_NAME"`
 	TimeZone 		string `mapstructure:"TIMEZONE"`        
+
+	RequestTimeout 		int 	`mapstructure:"REQUEST_TIMEOUT"`
+	LongRequestTimeout 	int 	`mapstructure:"LONG_REQUEST_TIMEOUT"`
+
+	LogLevel 			string 	`mapstructure:"LOG_LEVEL" reload:"true"`
+
+	CorsAllowOrigins 	string 	`mapstructure:"CORS_ALLOW_ORIGINS" reload:"true"`
+	RateLimitMax 		int 	`mapstructure:"RATE_LIMIT_MAX" reload:"true"`
+	RateLimitWindow 	int 	`mapstructure:"RATE_LIMIT_WINDOW" reload:"true"`
 	
-	JWTSecretKey 		string 	`mapstructure:"JWT_SECRET_KEY"`
-	JWTAccessExpires 	int 	`mapstructure:"JWT_ACCESS_EXPIRES"`
-	JWTRefleshExpires 	int 	`mapstructure:"JWT_REFRESH_EXPIRES"`
+	JWTSecretKey 		string 	`mapstructure:"JWT_SECRET_KEY" secret:"true"`
+	JWTAccessExpires 	int 	`mapstructure:"JWT_ACCESS_EXPIRES" reload:"true"`
+	JWTRefleshExpires 	int 	`mapstructure:"JWT_REFRESH_EXPIRES" reload:"true"`
+
+	TrashRetentionDays 	int 	`mapstructure:"TRASH_RETENTION_DAYS"`
+	TrashPurgeInterval 	int 	`mapstructure:"TRASH_PURGE_INTERVAL"`
+
+	WebhookMaxAttempts 		int 	`mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoff 			int 	`mapstructure:"WEBHOOK_BACKOFF"`
+	WebhookTimeout 			int 	`mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookDeliveryInterval int 	`mapstructure:"WEBHOOK_DELIVERY_INTERVAL"`
+
+	OutboxRelayInterval 	int 	`mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	OutboxMaxAttempts 		int 	`mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	OutboxBackoff 			int 	`mapstructure:"OUTBOX_BACKOFF"`
+	OutboxTailInterval 		int 	`mapstructure:"OUTBOX_TAIL_INTERVAL"`
+	OutboxSinks 			string 	`mapstructure:"OUTBOX_SINKS"`
+	NATSURL 				string 	`mapstructure:"NATS_URL"`
+	NATSSubjectPrefix 		string 	`mapstructure:"NATS_SUBJECT_PREFIX"`
+
+	MetricsToken 	string 	`mapstructure:"METRICS_TOKEN" secret:"true"`
+	MetricsAddr 	string 	`mapstructure:"METRICS_ADDR"`
+	OpsToken 		string 	`mapstructure:"OPS_TOKEN" secret:"true"`
+
+	TracingExporter 	string 	`mapstructure:"TRACING_EXPORTER"`
+	TracingEndpoint 	string 	`mapstructure:"TRACING_ENDPOINT"`
+
+	MigrateOnStart 	bool 	`mapstructure:"MIGRATE_ON_START"`
 }
 
-func LoadConfig() (err error) {
-	appEnv := os.Args[1]
-    if appEnv == "" {
-        appEnv = ""
-    }
+// Options is where LoadConfig looks for the configuration, each layer overrides the one before:
+// the defaults, File or else <Env>.env, .yaml, .yml or .toml of the working directory when there
+// is one, the environment variables, the files named by <KEY>_FILE and at last Overrides.
+type Options struct {
+	Env 		string
+	File 		string
+	Overrides 	map[string]string
+}
+
+const redactedValue = "****"
 
-	viper.SetConfigName(appEnv)
-	viper.AddConfigPath(".")
-	viper.SetConfigType("env")
+// LoadConfig reads the configuration of options, GetConfig gives the result and Reload reads
+// options again.
+func LoadConfig(options Options) error {
+	changeMu.Lock()
+	defer changeMu.Unlock()
 
-	err = viper.ReadInConfig()
+	configData, file, err := read(options)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	return
+	current.Store(&configData)
+	loaded, loadedFile = options, file
+	return nil
 }
 
-func GetConfig() (config Config, err error) {
-	err = viper.Unmarshal(&config)
-	return
-}
\ No newline at end of file
+// read layers the configuration of options and returns it with the path of the file it read, if any.
+func read(options Options) (configData Config, file string, err error) {
+	viper.Reset()
+	for key, value := range defaults {
+		viper.SetDefault(key, value)
+	}
+
+	if options.File != "" {
+		viper.SetConfigFile(options.File)
+		if err := viper.ReadInConfig(); err != nil {
+			return Config{}, "", err
+		}
+	} else if options.Env != "" {
+		viper.SetConfigName(options.Env)
+		viper.AddConfigPath(".")
+		if err := viper.ReadInConfig(); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+			return Config{}, "", err
+		}
+	}
+
+	for _, key := range Keys() {
+		if err := viper.BindEnv(key); err != nil {
+			return Config{}, "", err
+		}
+		if err := readSecretFile(key); err != nil {
+			return Config{}, "", err
+		}
+	}
+
+	for key, value := range options.Overrides {
+		viper.Set(strings.ToUpper(key), value)
+	}
+	err = viper.Unmarshal(&configData)
+	return configData, viper.ConfigFileUsed(), err
+}
+
+// readSecretFile sets key to the content of the file named by <key>_FILE, as Docker mounts secrets.
+func readSecretFile(key string) error {
+	path, ok := os.LookupEnv(key + "_FILE")
+	if !ok {
+		return nil
+	}
+	if _, set := os.LookupEnv(key); set {
+		return fmt.Errorf("set only one of %s and %s_FILE", key, key)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s_FILE: %w", key, err)
+	}
+	viper.Set(key, strings.TrimRight(string(content), "\r\n"))
+	return nil
+}
+
+// GetConfig returns the configuration as it is now, the reloadable settings may change between calls.
+// Before LoadConfig every setting is empty.
+func GetConfig() (Config, error) {
+	configData := current.Load()
+	if configData == nil {
+		return Config{}, nil
+	}
+	return *configData, nil
+}
+
+// Keys are the names of the settings, in the order of Config.
+func Keys() []string {
+	configType := reflect.TypeOf(Config{})
+	keys := make([]string, 0, configType.NumField())
+	for i := 0; i < configType.NumField(); i++ {
+		keys = append(keys, configType.Field(i).Tag.Get("mapstructure"))
+	}
+	return keys
+}
+
+// Print writes the settings as KEY=VALUE lines in the order of Config, redacted hides the secrets that are set.
+func (c Config) Print(writer io.Writer, redacted bool) error {
+	configType, configValue := reflect.TypeOf(c), reflect.ValueOf(c)
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		value := fmt.Sprint(configValue.Field(i).Interface())
+		if redacted && field.Tag.Get("secret") == "true" && value != "" {
+			value = redactedValue
+		}
+		if _, err := fmt.Fprintf(writer, "%s=%s\n", field.Tag.Get("mapstructure"), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
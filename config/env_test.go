@@ -0,0 +1,123 @@
+package config_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func loadConfig(t *testing.T, options config.Options) config.Config {
+	require.NoError(t, config.LoadConfig(options))
+	configData, err := config.GetConfig()
+	require.NoError(t, err)
+	return configData
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("test case : defaults without a config file", func(t *testing.T) {
+		configData := loadConfig(t, config.Options{Env: "does-not-exist"})
+
+		assert.Equal(t, "8081", configData.ServerPort)
+		assert.Equal(t, "5432", configData.DBPort)
+		assert.Equal(t, 900, configData.JWTAccessExpires)
+		assert.Empty(t, configData.DBHost)
+	})
+
+	t.Run("test case : env file over defaults", func(t *testing.T) {
+		file := writeFile(t, "prod.env", "POSTGRES_HOST=db\nSERVER_PORT=9000\n")
+
+		configData := loadConfig(t, config.Options{File: file})
+
+		assert.Equal(t, "db", configData.DBHost)
+		assert.Equal(t, "9000", configData.ServerPort)
+	})
+
+	t.Run("test case : yaml file", func(t *testing.T) {
+		file := writeFile(t, "prod.yaml", "postgres_host: db\nrate_limit_max: 50\nmigrate_on_start: true\n")
+
+		configData := loadConfig(t, config.Options{File: file})
+
+		assert.Equal(t, "db", configData.DBHost)
+		assert.Equal(t, 50, configData.RateLimitMax)
+		assert.True(t, configData.MigrateOnStart)
+	})
+
+	t.Run("test case : environment over file and overrides over environment", func(t *testing.T) {
+		file := writeFile(t, "prod.toml", "POSTGRES_HOST = \"db\"\nSERVER_PORT = \"9000\"\n")
+		t.Setenv("POSTGRES_HOST", "env-db")
+		t.Setenv("SERVER_PORT", "9001")
+
+		configData := loadConfig(t, config.Options{File: file, Overrides: map[string]string{"server_port": "9002"}})
+
+		assert.Equal(t, "env-db", configData.DBHost)
+		assert.Equal(t, "9002", configData.ServerPort)
+	})
+
+	t.Run("test case : secret from file", func(t *testing.T) {
+		t.Setenv("POSTGRES_PASSWORD_FILE", writeFile(t, "password", "from-docker-secret\n"))
+
+		configData := loadConfig(t, config.Options{})
+
+		assert.Equal(t, "from-docker-secret", configData.DBPassword)
+	})
+
+	t.Run("test case : fail secret given twice", func(t *testing.T) {
+		t.Setenv("POSTGRES_PASSWORD", "plain")
+		t.Setenv("POSTGRES_PASSWORD_FILE", writeFile(t, "password", "from-docker-secret"))
+
+		err := config.LoadConfig(config.Options{})
+
+		assert.EqualError(t, err, "set only one of POSTGRES_PASSWORD and POSTGRES_PASSWORD_FILE")
+	})
+
+	t.Run("test case : fail missing secret file", func(t *testing.T) {
+		t.Setenv("JWT_SECRET_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		err := config.LoadConfig(config.Options{})
+
+		assert.ErrorContains(t, err, "JWT_SECRET_KEY_FILE")
+	})
+
+	t.Run("test case : fail missing config file", func(t *testing.T) {
+		err := config.LoadConfig(config.Options{File: filepath.Join(t.TempDir(), "missing.env")})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPrint(t *testing.T) {
+	t.Run("test case : redacted hides secrets that are set", func(t *testing.T) {
+		var out bytes.Buffer
+		configData := config.Config{DBHost: "db", DBPassword: "pw", JWTSecretKey: "key"}
+
+		assert.NoError(t, configData.Print(&out, true))
+
+		lines := strings.Split(out.String(), "\n")
+		assert.Contains(t, lines, "POSTGRES_HOST=db")
+		assert.Contains(t, lines, "POSTGRES_PASSWORD=****")
+		assert.Contains(t, lines, "JWT_SECRET_KEY=****")
+		assert.Contains(t, lines, "METRICS_TOKEN=")
+		assert.Len(t, lines, len(config.Keys()) + 1)
+	})
+
+	t.Run("test case : not redacted", func(t *testing.T) {
+		var out bytes.Buffer
+
+		assert.NoError(t, config.Config{DBPassword: "pw"}.Print(&out, false))
+
+		assert.Contains(t, out.String(), "POSTGRES_PASSWORD=pw\n")
+	})
+}
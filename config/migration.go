@@ -0,0 +1,23 @@
+package config
+
+import (
+	"embed"
+)
+
+// MigrationDir is where Migrations keeps the scripts, named <version>_<name>.up.sql and <version>_<name>.down.sql.
+const MigrationDir = "migration"
+
+// SQLiteMigrationDir keeps the same migrations written for SQLite, a change to the schema needs both
+// scripts under the same version so the migrate commands and the startup probe agree on either database.
+const SQLiteMigrationDir = "migration/sqlite"
+
+//go:embed migration/*.sql migration/sqlite/*.sql
+var Migrations embed.FS
+
+// MigrationDirOf is the directory of Migrations with the scripts of the gorm dialect.
+func MigrationDirOf(dialect string) string {
+	if dialect == "sqlite" {
+		return SQLiteMigrationDir
+	}
+	return MigrationDir
+}
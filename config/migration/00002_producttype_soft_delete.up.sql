@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Add soft delete column to ProductType table
+ALTER TABLE "producttype" ADD COLUMN ProdType_Deleted_At TIMESTAMPTZ NULL;
+
+CREATE INDEX idx_producttype_deleted_at ON "producttype" (ProdType_Deleted_At);
+
+COMMIT;
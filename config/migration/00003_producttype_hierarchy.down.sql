@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_producttype_parent_code;
+
+ALTER TABLE "producttype" DROP CONSTRAINT IF EXISTS chk_producttype_parent_not_self;
+
+ALTER TABLE "producttype" DROP COLUMN IF EXISTS ProdType_Parent_Code;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Add optional parent to ProductType table, purging a parent detaches its children
+ALTER TABLE "producttype" ADD COLUMN ProdType_Parent_Code INT NULL
+    REFERENCES "producttype"(ProdType_Code) ON DELETE SET NULL;
+
+ALTER TABLE "producttype" ADD CONSTRAINT chk_producttype_parent_not_self
+    CHECK (ProdType_Parent_Code <> ProdType_Code);
+
+CREATE INDEX idx_producttype_parent_code ON "producttype" (ProdType_Parent_Code);
+
+COMMIT;
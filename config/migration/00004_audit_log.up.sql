@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Append only record of who changed what, Before and After hold the resource as JSON
+CREATE TABLE IF NOT EXISTS "audit_log" (
+    Audit_ID BIGSERIAL PRIMARY KEY,
+    Audit_Actor_User_ID INT NULL,
+    Audit_Action VARCHAR(40) NOT NULL,
+    Audit_Resource_Type VARCHAR(40) NOT NULL,
+    Audit_Resource_ID VARCHAR(64),
+    Audit_Before JSONB,
+    Audit_After JSONB,
+    Audit_Request_ID VARCHAR(64),
+    Audit_IP VARCHAR(45),
+    Audit_Created_At TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_actor_user_id ON "audit_log" (Audit_Actor_User_ID);
+
+CREATE INDEX idx_audit_log_resource ON "audit_log" (Audit_Resource_Type, Audit_Resource_ID);
+
+CREATE INDEX idx_audit_log_created_at ON "audit_log" (Audit_Created_At);
+
+COMMIT;
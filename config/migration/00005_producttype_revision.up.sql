@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Immutable snapshot of ProductType after every write, kept after the ProductType is purged
+CREATE TABLE IF NOT EXISTS "producttype_revision" (
+    Rev_ID BIGSERIAL PRIMARY KEY,
+    ProdType_Code INT NOT NULL,
+    Rev_Number INT NOT NULL,
+    ProdType_Name VARCHAR(40) NOT NULL,
+    ProdType_Parent_Code INT NULL,
+    ProdType_Deleted_At TIMESTAMPTZ NULL,
+    Rev_Operation VARCHAR(20) NOT NULL,
+    Rev_Created_At TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT uq_producttype_revision_number UNIQUE (ProdType_Code, Rev_Number)
+);
+
+CREATE INDEX idx_producttype_revision_created_at ON "producttype_revision" (ProdType_Code, Rev_Created_At);
+
+-- Existing ProductTypes start their history at revision 1
+INSERT INTO "producttype_revision" (ProdType_Code, Rev_Number, ProdType_Name, ProdType_Parent_Code, ProdType_Deleted_At, Rev_Operation)
+SELECT ProdType_Code, 1, ProdType_Name, ProdType_Parent_Code, ProdType_Deleted_At, 'create'
+FROM "producttype";
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Subscriptions of downstream systems to ProductType events, Webhook_Events is comma separated
+CREATE TABLE IF NOT EXISTS "webhook" (
+    Webhook_ID SERIAL PRIMARY KEY,
+    Webhook_URL VARCHAR(255) NOT NULL,
+    Webhook_Events VARCHAR(255) NOT NULL,
+    Webhook_Secret VARCHAR(128) NOT NULL,
+    Webhook_Active BOOLEAN NOT NULL DEFAULT TRUE,
+    Webhook_Created_At TIMESTAMPTZ NOT NULL DEFAULT now(),
+    Webhook_Updated_At TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One row per event per webhook, kept as the delivery log
+CREATE TABLE IF NOT EXISTS "webhook_delivery" (
+    Delivery_ID BIGSERIAL PRIMARY KEY,
+    Webhook_ID INT NOT NULL REFERENCES "webhook" (Webhook_ID) ON DELETE CASCADE,
+    Delivery_Event_ID VARCHAR(64) NOT NULL,
+    Delivery_Event_Type VARCHAR(64) NOT NULL,
+    Delivery_Payload JSONB NOT NULL,
+    Delivery_Status VARCHAR(16) NOT NULL,
+    Delivery_Attempts INT NOT NULL DEFAULT 0,
+    Delivery_Next_Attempt_At TIMESTAMPTZ NOT NULL DEFAULT now(),
+    Delivery_Last_Status_Code INT NULL,
+    Delivery_Last_Error VARCHAR(1024),
+    Delivery_Delivered_At TIMESTAMPTZ NULL,
+    Delivery_Created_At TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_delivery_webhook_id ON "webhook_delivery" (Webhook_ID, Delivery_ID);
+
+CREATE INDEX idx_webhook_delivery_due ON "webhook_delivery" (Delivery_Next_Attempt_At) WHERE Delivery_Status IN ('pending', 'failed');
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Domain events written in the same transaction as the change, the relay publishes them in Outbox_ID order
+CREATE TABLE IF NOT EXISTS "outbox" (
+    Outbox_ID BIGSERIAL PRIMARY KEY,
+    Outbox_Aggregate_Type VARCHAR(40) NOT NULL,
+    Outbox_Aggregate_ID VARCHAR(64) NOT NULL,
+    Outbox_Event_Type VARCHAR(40) NOT NULL,
+    Outbox_Payload JSONB,
+    Outbox_Attempts INT NOT NULL DEFAULT 0,
+    Outbox_Last_Error VARCHAR(1024),
+    Outbox_Created_At TIMESTAMPTZ NOT NULL DEFAULT now(),
+    Outbox_Published_At TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_outbox_unpublished ON "outbox" (Outbox_ID) WHERE Outbox_Published_At IS NULL;
+
+CREATE INDEX idx_outbox_published_at ON "outbox" (Outbox_Published_At);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS User_Disabled_At;
+
+COMMIT;
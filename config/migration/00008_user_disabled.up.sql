@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Add disable column to User table, a disabled user can not log in
+ALTER TABLE "user" ADD COLUMN User_Disabled_At TIMESTAMPTZ NULL;
+
+COMMIT;
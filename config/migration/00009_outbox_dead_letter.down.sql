@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_webhook_delivery_event;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON "outbox" (Outbox_ID) WHERE Outbox_Published_At IS NULL;
+
+ALTER TABLE "outbox" DROP COLUMN IF EXISTS Outbox_Dead_At;
+ALTER TABLE "outbox" DROP COLUMN IF EXISTS Outbox_Next_Attempt_At;
+ALTER TABLE "outbox" DROP COLUMN IF EXISTS Outbox_Published_Sinks;
+
+COMMIT;
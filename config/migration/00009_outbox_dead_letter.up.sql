@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Sinks that accepted an event are skipped when it is retried, an event failing too often is dead-lettered
+ALTER TABLE "outbox" ADD COLUMN Outbox_Published_Sinks VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "outbox" ADD COLUMN Outbox_Next_Attempt_At TIMESTAMPTZ NULL;
+ALTER TABLE "outbox" ADD COLUMN Outbox_Dead_At TIMESTAMPTZ NULL;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON "outbox" (Outbox_ID) WHERE Outbox_Published_At IS NULL AND Outbox_Dead_At IS NULL;
+
+-- An event is queued once per webhook however often the relay hands it over
+DELETE FROM "webhook_delivery" duplicate USING "webhook_delivery" kept
+    WHERE duplicate.Webhook_ID = kept.Webhook_ID
+    AND duplicate.Delivery_Event_ID = kept.Delivery_Event_ID
+    AND duplicate.Delivery_ID > kept.Delivery_ID;
+CREATE UNIQUE INDEX idx_webhook_delivery_event ON "webhook_delivery" (Webhook_ID, Delivery_Event_ID);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "audit_log" DROP COLUMN IF EXISTS Audit_Actor_System;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Names the background job behind an entry that has no user, such as the trash retention purge
+ALTER TABLE "audit_log" ADD COLUMN Audit_Actor_System VARCHAR(40) NULL;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Drop tables, the ones holding references first
+DROP TABLE IF EXISTS "oauth";
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS "role";
+DROP TABLE IF EXISTS "producttype";
+
+COMMIT;
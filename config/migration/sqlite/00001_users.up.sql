@@ -0,0 +1,56 @@
+BEGIN;
+
+-- Create the Role table
+CREATE TABLE "role" (
+    role_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    role_title VARCHAR(40) NOT NULL UNIQUE
+);
+
+-- Insert the role table
+INSERT INTO "role" (
+    role_title
+)
+VALUES
+    ('Manager'),
+    ('Admin'),
+    ('Customer');
+
+-- Create the User table
+CREATE TABLE "user" (
+    user_id INT PRIMARY KEY,
+    user_role_id INT REFERENCES "role"(role_id) NOT NULL,
+    user_name VARCHAR(40), 
+    user_email VARCHAR(50) UNIQUE NOT NULL,
+    user_password TEXT NOT NULL
+);
+
+-- Insert the User table
+INSERT INTO "user" (
+    user_id,
+    user_role_id,
+    user_name,
+    user_email,
+    user_password
+
+)
+VALUES
+    (1, 1, 'Gordon Freeman', 'gordon_freeman@gmail.com', '$2a$10$DAi7ije26J6vGiZ8EknTK.Go8VsH3/CerbE9QJTEbk3HnF6S0/h9O');
+
+-- Create the Oauth table
+CREATE TABLE "oauth" (
+    oauth_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    oauth_user_id INT REFERENCES "user"(user_id) NOT NULL,
+    access_token VARCHAR(300) NOT NULL,
+    reflesh_token VARCHAR(300) NOT NULL
+);
+
+CREATE TABLE "producttype" (
+    prodtype_code INT PRIMARY KEY,
+    prodtype_name VARCHAR(40) NOT NULL
+);
+
+INSERT INTO "producttype" (prodtype_code, prodtype_name) VALUES (1, 'Food');
+INSERT INTO "producttype" (prodtype_code, prodtype_name) VALUES (2, 'Drink');
+INSERT INTO "producttype" (prodtype_code, prodtype_name) VALUES (3, 'Snack');
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_producttype_deleted_at;
+
+ALTER TABLE "producttype" DROP COLUMN prodtype_deleted_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Add soft delete column to ProductType table
+ALTER TABLE "producttype" ADD COLUMN prodtype_deleted_at DATETIME NULL;
+
+CREATE INDEX idx_producttype_deleted_at ON "producttype" (prodtype_deleted_at);
+
+COMMIT;
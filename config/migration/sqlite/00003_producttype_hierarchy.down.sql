@@ -0,0 +1,21 @@
+BEGIN;
+
+-- SQLite can not drop a column holding a reference, the table is copied without it
+DROP INDEX IF EXISTS idx_producttype_parent_code;
+
+CREATE TABLE "producttype_without_parent" (
+    prodtype_code INT PRIMARY KEY,
+    prodtype_name VARCHAR(40) NOT NULL,
+    prodtype_deleted_at DATETIME NULL
+);
+
+INSERT INTO "producttype_without_parent" (prodtype_code, prodtype_name, prodtype_deleted_at)
+SELECT prodtype_code, prodtype_name, prodtype_deleted_at FROM "producttype";
+
+DROP TABLE "producttype";
+
+ALTER TABLE "producttype_without_parent" RENAME TO "producttype";
+
+CREATE INDEX idx_producttype_deleted_at ON "producttype" (prodtype_deleted_at);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Add optional parent to ProductType table, purging a parent detaches its children.
+-- SQLite can not add a constraint to a table, the check comes with the column
+ALTER TABLE "producttype" ADD COLUMN prodtype_parent_code INT NULL
+    REFERENCES "producttype"(prodtype_code) ON DELETE SET NULL
+    CONSTRAINT chk_producttype_parent_not_self CHECK (prodtype_parent_code <> prodtype_code);
+
+CREATE INDEX idx_producttype_parent_code ON "producttype" (prodtype_parent_code);
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Append only record of who changed what, Before and After hold the resource as JSON
+CREATE TABLE IF NOT EXISTS "audit_log" (
+    audit_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    audit_actor_user_id INT NULL,
+    audit_action VARCHAR(40) NOT NULL,
+    audit_resource_type VARCHAR(40) NOT NULL,
+    audit_resource_id VARCHAR(64),
+    audit_before TEXT,
+    audit_after TEXT,
+    audit_request_id VARCHAR(64),
+    audit_ip VARCHAR(45),
+    audit_created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_actor_user_id ON "audit_log" (audit_actor_user_id);
+
+CREATE INDEX idx_audit_log_resource ON "audit_log" (audit_resource_type, audit_resource_id);
+
+CREATE INDEX idx_audit_log_created_at ON "audit_log" (audit_created_at);
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Immutable snapshot of ProductType after every write, kept after the ProductType is purged
+CREATE TABLE IF NOT EXISTS "producttype_revision" (
+    rev_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    prodtype_code INT NOT NULL,
+    rev_number INT NOT NULL,
+    prodtype_name VARCHAR(40) NOT NULL,
+    prodtype_parent_code INT NULL,
+    prodtype_deleted_at DATETIME NULL,
+    rev_operation VARCHAR(20) NOT NULL,
+    rev_created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_producttype_revision_number UNIQUE (prodtype_code, rev_number)
+);
+
+CREATE INDEX idx_producttype_revision_created_at ON "producttype_revision" (prodtype_code, rev_created_at);
+
+-- Existing ProductTypes start their history at revision 1
+INSERT INTO "producttype_revision" (prodtype_code, rev_number, prodtype_name, prodtype_parent_code, prodtype_deleted_at, rev_operation)
+SELECT prodtype_code, 1, prodtype_name, prodtype_parent_code, prodtype_deleted_at, 'create'
+FROM "producttype";
+
+COMMIT;
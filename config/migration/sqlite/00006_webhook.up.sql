@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Subscriptions of downstream systems to ProductType events, webhook_events is comma separated
+CREATE TABLE IF NOT EXISTS "webhook" (
+    webhook_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_url VARCHAR(255) NOT NULL,
+    webhook_events VARCHAR(255) NOT NULL,
+    webhook_secret VARCHAR(128) NOT NULL,
+    webhook_active BOOLEAN NOT NULL DEFAULT TRUE,
+    webhook_created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    webhook_updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per event per webhook, kept as the delivery log
+CREATE TABLE IF NOT EXISTS "webhook_delivery" (
+    delivery_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INT NOT NULL REFERENCES "webhook" (webhook_id) ON DELETE CASCADE,
+    delivery_event_id VARCHAR(64) NOT NULL,
+    delivery_event_type VARCHAR(64) NOT NULL,
+    delivery_payload TEXT NOT NULL,
+    delivery_status VARCHAR(16) NOT NULL,
+    delivery_attempts INT NOT NULL DEFAULT 0,
+    delivery_next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivery_last_status_code INT NULL,
+    delivery_last_error VARCHAR(1024),
+    delivery_delivered_at DATETIME NULL,
+    delivery_created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_delivery_webhook_id ON "webhook_delivery" (webhook_id, delivery_id);
+
+CREATE INDEX idx_webhook_delivery_due ON "webhook_delivery" (delivery_next_attempt_at) WHERE delivery_status IN ('pending', 'failed');
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Domain events written in the same transaction as the change, the relay publishes them in outbox_id order
+CREATE TABLE IF NOT EXISTS "outbox" (
+    outbox_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    outbox_aggregate_type VARCHAR(40) NOT NULL,
+    outbox_aggregate_id VARCHAR(64) NOT NULL,
+    outbox_event_type VARCHAR(40) NOT NULL,
+    outbox_payload TEXT,
+    outbox_attempts INT NOT NULL DEFAULT 0,
+    outbox_last_error VARCHAR(1024),
+    outbox_created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    outbox_published_at DATETIME NULL
+);
+
+CREATE INDEX idx_outbox_unpublished ON "outbox" (outbox_id) WHERE outbox_published_at IS NULL;
+
+CREATE INDEX idx_outbox_published_at ON "outbox" (outbox_published_at);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "user" DROP COLUMN user_disabled_at;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_webhook_delivery_event;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON "outbox" (outbox_id) WHERE outbox_published_at IS NULL;
+
+ALTER TABLE "outbox" DROP COLUMN outbox_dead_at;
+ALTER TABLE "outbox" DROP COLUMN outbox_next_attempt_at;
+ALTER TABLE "outbox" DROP COLUMN outbox_published_sinks;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Sinks that accepted an event are skipped when it is retried, an event failing too often is dead-lettered
+ALTER TABLE "outbox" ADD COLUMN outbox_published_sinks VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "outbox" ADD COLUMN outbox_next_attempt_at DATETIME NULL;
+ALTER TABLE "outbox" ADD COLUMN outbox_dead_at DATETIME NULL;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON "outbox" (outbox_id) WHERE outbox_published_at IS NULL AND outbox_dead_at IS NULL;
+
+-- An event is queued once per webhook however often the relay hands it over
+DELETE FROM "webhook_delivery" WHERE EXISTS (
+    SELECT 1 FROM "webhook_delivery" kept
+    WHERE kept.webhook_id = "webhook_delivery".webhook_id
+    AND kept.delivery_event_id = "webhook_delivery".delivery_event_id
+    AND kept.delivery_id < "webhook_delivery".delivery_id
+);
+CREATE UNIQUE INDEX idx_webhook_delivery_event ON "webhook_delivery" (webhook_id, delivery_event_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE "audit_log" DROP COLUMN audit_actor_system;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Names the background job behind an entry that has no user, such as the trash retention purge
+ALTER TABLE "audit_log" ADD COLUMN audit_actor_system VARCHAR(40) NULL;
+
+COMMIT;
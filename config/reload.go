@@ -0,0 +1,260 @@
+package config
+
+import (
+	"github.com/fsnotify/fsnotify"
+
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// watchDebounce waits for an editor to finish writing the config file before reading it.
+const watchDebounce = 200 * time.Millisecond
+
+var (
+	// current is the configuration GetConfig returns, it is replaced as a whole so readers never see half a change.
+	current 	atomic.Pointer[Config]
+	// changeMu orders the changes and the notifications of the subscribers.
+	changeMu 	sync.Mutex
+	loaded 		Options
+	loadedFile 	string
+	subscribers []func(next Config, changed []string)
+)
+
+// Subscribe calls fn with the new configuration and the keys that changed after each change of the
+// reloadable settings, LoadConfig does not call it. fn runs with the changes blocked, it should not change them.
+func Subscribe(fn func(next Config, changed []string)) {
+	changeMu.Lock()
+	defer changeMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// ReloadableKeys are the settings Update and Reload apply without a restart, the fields tagged reload:"true".
+func ReloadableKeys() []string {
+	var keys []string
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		if configType.Field(i).Tag.Get("reload") == "true" {
+			keys = append(keys, configType.Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return keys
+}
+
+// Reloadable returns the reloadable settings of c by key.
+func (c Config) Reloadable() map[string]string {
+	settings := map[string]string{}
+	configValue := reflect.ValueOf(c)
+	for _, key := range ReloadableKeys() {
+		index, _ := fieldIndex(key)
+		settings[key] = fmt.Sprint(configValue.Field(index).Interface())
+	}
+	return settings
+}
+
+// Update sets the reloadable settings of changes by key, all of them or none when one is not a
+// reloadable setting, does not parse or leaves the configuration invalid.
+func Update(changes map[string]string) (Config, error) {
+	changeMu.Lock()
+	defer changeMu.Unlock()
+
+	previous := current.Load()
+	if previous == nil {
+		return Config{}, errors.New("configuration is not loaded")
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	next := *previous
+	nextValue := reflect.ValueOf(&next).Elem()
+	var problems ValidationError
+	for _, key := range keys {
+		index, ok := fieldIndex(strings.ToUpper(key))
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not a setting", key))
+			continue
+		}
+		if problem := reloadProblem(index); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		if problem := setField(nextValue.Field(index), strings.ToUpper(key), changes[key]); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) > 0 {
+		return *previous, problems
+	}
+	if err := next.Validate(); err != nil {
+		return *previous, err
+	}
+
+	apply(*previous, next)
+	return next, nil
+}
+
+// Reload reads the configuration again as LoadConfig did and applies the reloadable settings that
+// changed. The other settings that changed keep their value until a restart and are returned as an
+// error, which does not undo the reloadable ones. It returns the keys it applied.
+func Reload() ([]string, error) {
+	changeMu.Lock()
+	defer changeMu.Unlock()
+
+	previous := current.Load()
+	if previous == nil {
+		return nil, errors.New("configuration is not loaded")
+	}
+	reread, _, err := read(loaded)
+	if err != nil {
+		return nil, err
+	}
+
+	next := *previous
+	nextValue, rereadValue, previousValue := reflect.ValueOf(&next).Elem(), reflect.ValueOf(reread), reflect.ValueOf(*previous)
+	var problems ValidationError
+	for i := 0; i < rereadValue.NumField(); i++ {
+		if reflect.DeepEqual(rereadValue.Field(i).Interface(), previousValue.Field(i).Interface()) {
+			continue
+		}
+		if problem := reloadProblem(i); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		nextValue.Field(i).Set(rereadValue.Field(i))
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+
+	changed := apply(*previous, next)
+	if len(problems) > 0 {
+		return changed, problems
+	}
+	return changed, nil
+}
+
+// Watch calls Reload whenever the config file LoadConfig read is written until ctx is done, and
+// onReload with its result. Without a config file there is nothing to watch.
+func Watch(ctx context.Context, onReload func(changed []string, err error)) error {
+	changeMu.Lock()
+	file := loadedFile
+	changeMu.Unlock()
+	if file == "" {
+		return nil
+	}
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// editors replace the file rather than write it, the directory sees both
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == file && event.Has(fsnotify.Write | fsnotify.Create) {
+					debounce = time.After(watchDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				onReload(nil, err)
+			case <-debounce:
+				debounce = nil
+				onReload(Reload())
+			}
+		}
+	}()
+	return nil
+}
+
+// apply makes next the current configuration and notifies the subscribers of the keys that changed.
+func apply(previous, next Config) []string {
+	var changed []string
+	previousValue, nextValue := reflect.ValueOf(previous), reflect.ValueOf(next)
+	for i := 0; i < nextValue.NumField(); i++ {
+		if !reflect.DeepEqual(previousValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			changed = append(changed, nextValue.Type().Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	current.Store(&next)
+	for _, fn := range subscribers {
+		fn(next, changed)
+	}
+	return changed
+}
+
+func fieldIndex(key string) (int, bool) {
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		if configType.Field(i).Tag.Get("mapstructure") == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// reloadProblem explains why the setting of field index can not change at runtime, if it can not.
+func reloadProblem(index int) string {
+	field := reflect.TypeOf(Config{}).Field(index)
+	if field.Tag.Get("reload") == "true" {
+		return ""
+	}
+	return fmt.Sprintf("%s is only read at startup, restart the server to change it (reloadable: %s)",
+		field.Tag.Get("mapstructure"), strings.Join(ReloadableKeys(), ", "))
+}
+
+func setField(field reflect.Value, key, value string) string {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Sprintf("%s must be a whole number, it is %q", key, value)
+		}
+		field.SetInt(int64(number))
+	case reflect.Bool:
+		flag, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Sprintf("%s must be true or false, it is %q", key, value)
+		}
+		field.SetBool(flag)
+	default:
+		return fmt.Sprintf("%s can not be set", key)
+	}
+	return ""
+}
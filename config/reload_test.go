@@ -0,0 +1,123 @@
+package config_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadServerConfig loads file with the settings Validate requires given as overrides, which the file can not change.
+func loadServerConfig(t *testing.T, file string) {
+	require.NoError(t, config.LoadConfig(config.Options{File: file, Overrides: map[string]string{
+		"POSTGRES_HOST": 	"db",
+		"POSTGRES_USER": 	"user",
+		"POSTGRES_DB": 		"fiber",
+		"JWT_SECRET_KEY": 	strings.Repeat("k", config.JWTSecretMinLength),
+	}}))
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("test case : pass notifies the subscribers", func(t *testing.T) {
+		loadServerConfig(t, writeFile(t, "prod.env", "RATE_LIMIT_MAX=20\n"))
+		var notified []string
+		config.Subscribe(func(next config.Config, changed []string) {
+			notified = changed
+		})
+
+		configData, err := config.Update(map[string]string{"RATE_LIMIT_MAX": "50", "log_level": "warn", "RATE_LIMIT_WINDOW": "60"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 50, configData.RateLimitMax)
+		assert.Equal(t, "warn", configData.LogLevel)
+		assert.Equal(t, []string{"LOG_LEVEL", "RATE_LIMIT_MAX"}, notified)
+		current, _ := config.GetConfig()
+		assert.Equal(t, configData, current)
+	})
+
+	t.Run("test case : fail not reloadable changes nothing", func(t *testing.T) {
+		loadServerConfig(t, writeFile(t, "prod.env", "RATE_LIMIT_MAX=20\n"))
+
+		_, err := config.Update(map[string]string{"RATE_LIMIT_MAX": "50", "SERVER_PORT": "9000", "NOPE": "1"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "NOPE is not a setting")
+		assert.Contains(t, err.Error(), "SERVER_PORT is only read at startup, restart the server to change it")
+		current, _ := config.GetConfig()
+		assert.Equal(t, 20, current.RateLimitMax)
+		assert.Equal(t, "8081", current.ServerPort)
+	})
+
+	t.Run("test case : fail invalid value", func(t *testing.T) {
+		loadServerConfig(t, writeFile(t, "prod.env", "RATE_LIMIT_MAX=20\n"))
+
+		_, err := config.Update(map[string]string{"RATE_LIMIT_MAX": "many"})
+		assert.Contains(t, err.Error(), `RATE_LIMIT_MAX must be a whole number, it is "many"`)
+
+		_, err = config.Update(map[string]string{"JWT_REFRESH_EXPIRES": "60"})
+		assert.Contains(t, err.Error(), "JWT_REFRESH_EXPIRES (60) must be longer than JWT_ACCESS_EXPIRES (900)")
+
+		current, _ := config.GetConfig()
+		assert.Equal(t, 604800, current.JWTRefleshExpires)
+	})
+}
+
+func TestReload(t *testing.T) {
+	t.Run("test case : applies the reloadable settings and refuses the others", func(t *testing.T) {
+		file := writeFile(t, "prod.env", "RATE_LIMIT_MAX=20\nSERVER_PORT=8081\n")
+		loadServerConfig(t, file)
+		require.NoError(t, os.WriteFile(file, []byte("RATE_LIMIT_MAX=80\nSERVER_PORT=9000\n"), 0600))
+
+		changed, err := config.Reload()
+
+		assert.Equal(t, []string{"RATE_LIMIT_MAX"}, changed)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SERVER_PORT is only read at startup")
+		current, _ := config.GetConfig()
+		assert.Equal(t, 80, current.RateLimitMax)
+		assert.Equal(t, "8081", current.ServerPort)
+	})
+
+	t.Run("test case : fail invalid file keeps the configuration", func(t *testing.T) {
+		file := writeFile(t, "prod.env", "RATE_LIMIT_MAX=20\n")
+		loadServerConfig(t, file)
+		require.NoError(t, os.WriteFile(file, []byte("RATE_LIMIT_MAX=-1\n"), 0600))
+
+		changed, err := config.Reload()
+
+		assert.Empty(t, changed)
+		assert.Error(t, err)
+		current, _ := config.GetConfig()
+		assert.Equal(t, 20, current.RateLimitMax)
+	})
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("test case : reloads when the file is written", func(t *testing.T) {
+		file := writeFile(t, "prod.env", "LOG_LEVEL=info\n")
+		loadServerConfig(t, file)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		reloaded := make(chan []string, 1)
+		require.NoError(t, config.Watch(ctx, func(changed []string, err error) {
+			reloaded <- changed
+		}))
+
+		require.NoError(t, os.WriteFile(file, []byte("LOG_LEVEL=error\n"), 0600))
+
+		select {
+		case changed := <-reloaded:
+			assert.Equal(t, []string{"LOG_LEVEL"}, changed)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the config file was not reloaded")
+		}
+		current, _ := config.GetConfig()
+		assert.Equal(t, "error", current.LogLevel)
+	})
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JWTSecretMinLength is the shortest JWT_SECRET_KEY accepted, 32 bytes is the HS256 key size.
+const JWTSecretMinLength = 32
+
+// ValidationError lists every problem of the configuration, so all of them can be fixed at once.
+type ValidationError []string
+
+func (v ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(v, "\n  - ")
+}
+
+// ValidateDatabase checks the settings the commands that only need the database use.
+func (c *Config) ValidateDatabase() error {
+	problems := c.databaseProblems()
+	if len(problems) > 0 {
+		return ValidationError(problems)
+	}
+	return nil
+}
+
+// Validate checks the settings the server needs.
+func (c *Config) Validate() error {
+	problems := c.databaseProblems()
+
+	problems = append(problems, checkPort("SERVER_PORT", c.ServerPort)...)
+	if len(c.JWTSecretKey) < JWTSecretMinLength {
+		problems = append(problems, fmt.Sprintf("JWT_SECRET_KEY must be at least %d characters, it has %d", JWTSecretMinLength, len(c.JWTSecretKey)))
+	}
+	if c.JWTAccessExpires <= 0 {
+		problems = append(problems, fmt.Sprintf("JWT_ACCESS_EXPIRES must be a positive number of seconds, it is %d", c.JWTAccessExpires))
+	}
+	if c.JWTRefleshExpires <= c.JWTAccessExpires {
+		problems = append(problems, fmt.Sprintf("JWT_REFRESH_EXPIRES (%d) must be longer than JWT_ACCESS_EXPIRES (%d)", c.JWTRefleshExpires, c.JWTAccessExpires))
+	}
+
+	for _, setting := range []struct {
+		key 	string
+		value 	int
+	}{
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout},
+		{"SHUTDOWN_DELAY", c.ShutdownDelay},
+		{"REQUEST_TIMEOUT", c.RequestTimeout},
+		{"LONG_REQUEST_TIMEOUT", c.LongRequestTimeout},
+		{"RATE_LIMIT_MAX", c.RateLimitMax},
+		{"RATE_LIMIT_WINDOW", c.RateLimitWindow},
+		{"TRASH_RETENTION_DAYS", c.TrashRetentionDays},
+		{"TRASH_PURGE_INTERVAL", c.TrashPurgeInterval},
+		{"WEBHOOK_MAX_ATTEMPTS", c.WebhookMaxAttempts},
+		{"WEBHOOK_BACKOFF", c.WebhookBackoff},
+		{"WEBHOOK_TIMEOUT", c.WebhookTimeout},
+		{"OUTBOX_MAX_ATTEMPTS", c.OutboxMaxAttempts},
+		{"OUTBOX_BACKOFF", c.OutboxBackoff},
+	} {
+		if setting.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be negative, it is %d", setting.key, setting.value))
+		}
+	}
+
+	switch c.LogLevel {
+	case "", "trace", "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be trace, debug, info, warn or error, it is %q", c.LogLevel))
+	}
+	switch c.TracingExporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be none, stdout or otlp, it is %q", c.TracingExporter))
+	}
+	for _, sink := range strings.Split(c.OutboxSinks, ",") {
+		switch strings.TrimSpace(sink) {
+		case "", "broker", "webhook", "log":
+		case "nats":
+			if c.NATSURL == "" {
+				problems = append(problems, "NATS_URL is required by the nats outbox sink")
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("OUTBOX_SINKS has the unknown sink %q", strings.TrimSpace(sink)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return ValidationError(problems)
+	}
+	return nil
+}
+
+func (c *Config) databaseProblems() []string {
+	var problems []string
+	switch c.DBDriver {
+	case "", "postgres":
+		problems = append(problems, c.postgresProblems()...)
+	case "sqlite":
+		if c.SQLitePath == "" {
+			problems = append(problems, "SQLITE_PATH is required by the sqlite driver")
+		}
+	case "memory":
+	default:
+		problems = append(problems, fmt.Sprintf("DB_DRIVER must be postgres, sqlite or memory, it is %q", c.DBDriver))
+	}
+	if c.DBReadReplicas != "" && c.DBDriver != "" && c.DBDriver != "postgres" {
+		problems = append(problems, fmt.Sprintf("DB_READ_REPLICAS needs the postgres driver, DB_DRIVER is %q", c.DBDriver))
+	}
+	for _, setting := range []struct {
+		key 	string
+		value 	int
+	}{
+		{"DB_MAX_OPEN_CONNS", c.DBMaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", c.DBMaxIdleConns},
+		{"DB_CONN_MAX_LIFETIME", c.DBConnMaxLifetime},
+		{"DB_CONNECT_TIMEOUT", c.DBConnectTimeout},
+		{"DB_STATEMENT_TIMEOUT", c.DBStatementTimeout},
+		{"DB_READ_RETRIES", c.DBReadRetries},
+		{"DB_REPLICA_CHECK_INTERVAL", c.DBReplicaCheckInterval},
+	} {
+		if setting.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be negative, it is %d", setting.key, setting.value))
+		}
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		problems = append(problems, fmt.Sprintf("DB_MAX_IDLE_CONNS (%d) must not be more than DB_MAX_OPEN_CONNS (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns))
+	}
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		problems = append(problems, fmt.Sprintf("TIMEZONE %q is not a known time zone", c.TimeZone))
+	}
+	return problems
+}
+
+func (c *Config) postgresProblems() []string {
+	var problems []string
+	for _, setting := range []struct {
+		key 	string
+		value 	string
+	}{
+		{"POSTGRES_HOST", c.DBHost},
+		{"POSTGRES_USER", c.DBUser},
+		{"POSTGRES_DB", c.DBName},
+	} {
+		if setting.value == "" {
+			problems = append(problems, setting.key + " is required")
+		}
+	}
+	problems = append(problems, checkPort("POSTGRES_PORT", c.DBPort)...)
+	switch c.DBSSLMode {
+	case "", "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		problems = append(problems, fmt.Sprintf("POSTGRES_SSLMODE must be disable, allow, prefer, require, verify-ca or verify-full, it is %q", c.DBSSLMode))
+	}
+	if c.DBSSLRootCert != "" {
+		if _, err := os.Stat(c.DBSSLRootCert); err != nil {
+			problems = append(problems, fmt.Sprintf("POSTGRES_SSLROOTCERT %q can not be read: %s", c.DBSSLRootCert, err))
+		}
+	}
+	return problems
+}
+
+func checkPort(key, value string) []string {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return []string{fmt.Sprintf("%s must be a port between 1 and 65535, it is %q", key, value)}
+	}
+	return nil
+}
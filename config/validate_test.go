@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+
+	"github.com/stretchr/testify/assert"
+
+	"strings"
+	"testing"
+)
+
+func validConfig() config.Config {
+	return config.Config{
+		DBHost: 			"db",
+		DBUser: 			"user",
+		DBName: 			"fiber",
+		DBPort: 			"5432",
+		TimeZone: 			"Asia/Bangkok",
+		ServerPort: 		"8081",
+		JWTSecretKey: 		strings.Repeat("k", config.JWTSecretMinLength),
+		JWTAccessExpires: 	900,
+		JWTRefleshExpires: 	604800,
+		OutboxSinks: 		"broker,webhook",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("test case : pass", func(t *testing.T) {
+		configData := validConfig()
+
+		assert.NoError(t, configData.Validate())
+	})
+
+	t.Run("test case : fail lists every problem", func(t *testing.T) {
+		configData := validConfig()
+		configData.DBHost = ""
+		configData.JWTSecretKey = "secret"
+		configData.JWTRefleshExpires = 60
+
+		err := configData.Validate()
+
+		assert.Equal(t, config.ValidationError{
+			"POSTGRES_HOST is required",
+			"JWT_SECRET_KEY must be at least 32 characters, it has 6",
+			"JWT_REFRESH_EXPIRES (60) must be longer than JWT_ACCESS_EXPIRES (900)",
+		}, err)
+		assert.Equal(t, "invalid configuration:\n  - POSTGRES_HOST is required\n  - JWT_SECRET_KEY must be at least 32 characters, it has 6\n  - JWT_REFRESH_EXPIRES (60) must be longer than JWT_ACCESS_EXPIRES (900)", err.Error())
+	})
+
+	t.Run("test case : fail invalid values", func(t *testing.T) {
+		configData := validConfig()
+		configData.ServerPort = "http"
+		configData.JWTAccessExpires = 0
+		configData.RequestTimeout = -1
+		configData.TracingExporter = "jaeger"
+		configData.OutboxSinks = "broker,nats,kafka"
+
+		err := configData.Validate()
+
+		assert.Equal(t, config.ValidationError{
+			`SERVER_PORT must be a port between 1 and 65535, it is "http"`,
+			"JWT_ACCESS_EXPIRES must be a positive number of seconds, it is 0",
+			"REQUEST_TIMEOUT must not be negative, it is -1",
+			`TRACING_EXPORTER must be none, stdout or otlp, it is "jaeger"`,
+			"NATS_URL is required by the nats outbox sink",
+			`OUTBOX_SINKS has the unknown sink "kafka"`,
+		}, err)
+	})
+
+	t.Run("test case : database only", func(t *testing.T) {
+		configData := validConfig()
+		configData.JWTSecretKey = ""
+		assert.NoError(t, configData.ValidateDatabase())
+
+		configData.TimeZone = "Mars/Olympus"
+		assert.Equal(t, config.ValidationError{`TIMEZONE "Mars/Olympus" is not a known time zone`}, configData.ValidateDatabase())
+	})
+
+	t.Run("test case : fail database connection settings", func(t *testing.T) {
+		configData := validConfig()
+		configData.DBSSLMode = "on"
+		configData.DBMaxOpenConns = 5
+		configData.DBMaxIdleConns = 10
+		configData.DBReadRetries = -1
+
+		assert.Equal(t, config.ValidationError{
+			`POSTGRES_SSLMODE must be disable, allow, prefer, require, verify-ca or verify-full, it is "on"`,
+			"DB_READ_RETRIES must not be negative, it is -1",
+			"DB_MAX_IDLE_CONNS (10) must not be more than DB_MAX_OPEN_CONNS (5)",
+		}, configData.ValidateDatabase())
+	})
+
+	t.Run("test case : database drivers", func(t *testing.T) {
+		configData := validConfig()
+		configData.DBHost = ""
+		configData.DBDriver = "memory"
+		assert.NoError(t, configData.ValidateDatabase())
+
+		configData.DBDriver = "sqlite"
+		configData.SQLitePath = "fiber-test.db"
+		assert.NoError(t, configData.ValidateDatabase())
+
+		configData.SQLitePath = ""
+		configData.DBReadReplicas = "replica:5432"
+		assert.Equal(t, config.ValidationError{
+			"SQLITE_PATH is required by the sqlite driver",
+			`DB_READ_REPLICAS needs the postgres driver, DB_DRIVER is "sqlite"`,
+		}, configData.ValidateDatabase())
+
+		configData.DBDriver = "mysql"
+		configData.DBReadReplicas = ""
+		assert.Equal(t, config.ValidationError{`DB_DRIVER must be postgres, sqlite or memory, it is "mysql"`}, configData.ValidateDatabase())
+	})
+}
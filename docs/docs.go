@@ -23,6 +23,228 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/producttypes/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently delete producttype in trash by id (Admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Purge ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Purge ProductType Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the settings the server applies without a restart: LOG_LEVEL, CORS_ALLOW_ORIGINS, RATE_LIMIT_MAX, RATE_LIMIT_WINDOW, JWT_ACCESS_EXPIRES and JWT_REFRESH_EXPIRES",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Settings",
+                "responses": {
+                    "200": {
+                        "description": "Get Settings Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.SettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change settings of the running server by key, all of them or none. Settings only read at startup are refused with the reason",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update Settings",
+                "parameters": [
+                    {
+                        "description": "Settings to change e.g. {\\",
+                        "name": "Settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SettingsUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update Settings Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.SettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get audit logs newest first, filtered by actor, action, resource, request id and time range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get Audit Logs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Actor user id",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action e.g. create, update, delete, login",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource type e.g. producttype, user",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource id",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request id",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 50, max 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Audit Logs Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.AuditLogsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/auths/": {
             "post": {
                 "description": "Register user",
@@ -217,22 +439,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/healthcheck": {
+        "/livez": {
             "get": {
-                "description": "Health check",
+                "description": "The process is up and able to answer, restart it when this fails",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "healthcheck"
+                    "health"
+                ],
+                "summary": "Liveness Probe",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Give errors and durations of the checks, needs the ops token",
+                        "name": "verbose",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health Check",
-                "operationId": "HealthCheckHandler",
                 "responses": {
                     "200": {
-                        "description": "Welcome to ProductType Server",
+                        "description": "Every check passed",
                         "schema": {
-                            "$ref": "#/definitions/model.StringResponse"
+                            "$ref": "#/definitions/model.HealthReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Error Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "A check failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthReport"
                         }
                     }
                 }
@@ -330,26 +571,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/producttypes/count": {
-            "get": {
+        "/producttypes/bulk": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get producttype's count from database",
+                "description": "Create, update and delete producttypes in one request, mode is transactional (all-or-nothing) or best_effort",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "producttypes"
                 ],
-                "summary": "Get ProductType Count",
+                "summary": "Bulk ProductTypes",
+                "parameters": [
+                    {
+                        "description": "ProductType operations to be apply",
+                        "name": "ProductTypes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeBulkRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Get ProductType'Count Successfully",
+                        "description": "Bulk ProductTypes Successfully",
                         "schema": {
-                            "$ref": "#/definitions/model.CountResponse"
+                            "$ref": "#/definitions/model.ProductTypeBulkResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Bulk ProductTypes With Failed Operations",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
                         }
                     },
                     "500": {
@@ -361,35 +625,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/producttypes/{id}": {
+        "/producttypes/changes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get producttype by id",
+                "description": "Get inserts, updates and deletes of producttypes after a cursor in order, deletes are tombstones with only the id. Pass the returned cursor as since on the next call.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "producttypes"
                 ],
-                "summary": "Get ProductType",
+                "summary": "Get ProductType Changes",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ProductType ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor from the previous response, 0 starts from the beginning",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 100, max 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Get ProductType Successfully",
+                        "description": "Get ProductType Changes Successfully",
                         "schema": {
-                            "$ref": "#/definitions/model.ProductTypeResponse"
+                            "$ref": "#/definitions/model.ProductTypeChangesResponse"
                         }
                     },
                     "400": {
@@ -398,12 +667,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Error Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/errs.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Error Unexpected Error",
                         "schema": {
@@ -411,54 +674,305 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/producttypes/count": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update producttype by id",
+                "description": "Get producttype's count from database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "producttypes"
                 ],
-                "summary": "Update ProductType",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ProductType ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "ProductType data to be update",
-                        "name": "ProductType",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ProductTypeUpdate"
-                        }
-                    }
-                ],
+                "summary": "Get ProductType Count",
                 "responses": {
                     "200": {
-                        "description": "Update ProductType Successfully",
+                        "description": "Get ProductType'Count Successfully",
                         "schema": {
-                            "$ref": "#/definitions/model.StringResponse"
+                            "$ref": "#/definitions/model.CountResponse"
                         }
                     },
-                    "400": {
-                        "description": "Error Bad Request",
+                    "500": {
+                        "description": "Error Unexpected Error",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Error Not Found",
+                    }
+                }
+            }
+        },
+        "/producttypes/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream all producttype as csv, xlsx or jsonl file",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Export ProductTypes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format (csv, xlsx, jsonl)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ProductTypes file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import producttype from uploaded csv, xlsx or jsonl file, nothing is applied when a row is invalid",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Import ProductTypes",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "ProductTypes file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format (csv, xlsx, jsonl), default from file extension",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "skip",
+                        "description": "Existing ID behaviour (upsert, skip)",
+                        "name": "on_conflict",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate only, nothing is applied",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column mapping as JSON, e.g. {\\",
+                        "name": "mapping",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import ProductTypes Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Import ProductTypes With Invalid Rows",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeImportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events of producttype writes, the event is created, updated or deleted and the data is the producttype. Reconnect with Last-Event-ID to receive missed events, a reset event means they are gone and the client should refetch.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Stream ProductType Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all deleted producttype",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Get ProductType Trash",
+                "responses": {
+                    "200": {
+                        "description": "Get ProductTypes In Trash Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypesTrashResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/tree": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all producttype nested under their parent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Get ProductType Tree",
+                "responses": {
+                    "200": {
+                        "description": "Find ProductType Tree Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeTreeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get producttype by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Get ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return the producttype as it was at this time (RFC3339)",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get ProductType Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
                         "schema": {
                             "$ref": "#/definitions/errs.ErrorResponse"
                         }
@@ -471,20 +985,20 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete producttype by id",
+                "description": "Update producttype by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "producttypes"
                 ],
-                "summary": "Delete ProductType",
+                "summary": "Update ProductType",
                 "parameters": [
                     {
                         "type": "integer",
@@ -492,11 +1006,20 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "ProductType data to be update",
+                        "name": "ProductType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Delete ProductType Successfully",
+                        "description": "Update ProductType Successfully",
                         "schema": {
                             "$ref": "#/definitions/model.StringResponse"
                         }
@@ -520,96 +1043,1386 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete producttype by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Delete ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "reject",
+                        "description": "Children behaviour (reject, cascade, reparent)",
+                        "name": "children",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete ProductType Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}/ancestors": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get breadcrumb of producttype by id, ordered from the root down",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Get ProductType Ancestors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Find ProductType Ancestors Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}/children": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get direct children of producttype by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Get ProductType Children",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Find ProductType Children Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}/move": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move producttype and its subtree under a new parent, null parent moves it to the root",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Move ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New parent of producttype",
+                        "name": "ProductType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeMove"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Move ProductType Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore deleted producttype by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Restore ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restore ProductType Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every revision of producttype, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Get ProductType Revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get ProductType Revisions Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductTypeRevisionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/producttypes/{id}/revisions/{rev}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revert name and parent of producttype to an earlier revision, the revert is recorded as a new revision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "producttypes"
+                ],
+                "summary": "Revert ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revert ProductType Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "The instance can serve traffic, route no requests to it when this fails",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness Probe",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Give errors and durations of the checks, needs the ops token",
+                        "name": "verbose",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every check passed",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Error Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "A check failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/startupz": {
+            "get": {
+                "description": "The instance has finished starting, the other probes apply once this passes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Startup Probe",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Give errors and durations of the checks, needs the ops token",
+                        "name": "verbose",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every check passed",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Error Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "A check failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all webhooks, secrets are never returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get All Webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Get Webhooks Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhooksResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to producttype events, every delivery is signed with the secret in X-Webhook-Signature",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook data to be create",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Create Webhook Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get webhook by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Webhook Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update webhook by id, an empty secret keeps the current one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook data to be update",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update Webhook Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete webhook by id together with its delivery log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete Webhook Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the delivery log of a webhook newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get Webhook Deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (pending, succeeded, failed, dead)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 50, max 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Webhook Deliveries Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a delivery again with a fresh set of attempts, also brings back a dead delivery",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver Webhook Delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Redeliver Webhook Delivery Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.StringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error Unexpected Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "errs.ErrorMessage": {
+            "type": "object",
+            "properties": {
+                "failed_field": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "errs.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_system": {
+                    "type": "string"
+                },
+                "actor_user_id": {
+                    "type": "integer"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "audit_id": {
+                    "type": "integer"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditLogPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditLog"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AuditLogsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.AuditLogPage"
+                }
+            }
+        },
+        "model.AuthPassportResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.UserPassport"
+                }
+            }
+        },
+        "model.CountResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pass"
+                }
+            }
+        },
+        "model.HealthReport": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.HealthCheck"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pass"
+                }
+            }
+        },
+        "model.LoginRequest": {
+            "type": "object",
+            "required": [
+                "user_email",
+                "user_password"
+            ],
+            "properties": {
+                "user_email": {
+                    "type": "string",
+                    "maxLength": 40
+                },
+                "user_password": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "model.ProductType": {
+            "type": "object",
+            "properties": {
+                "prodtype_id": {
+                    "type": "integer"
+                },
+                "prodtype_name": {
+                    "type": "string"
+                },
+                "prodtype_parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProductTypeBulkOperation": {
+            "type": "object",
+            "required": [
+                "op",
+                "prodtype_id"
+            ],
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ]
+                },
+                "prodtype_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "prodtype_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProductTypeBulkRequest": {
+            "type": "object",
+            "required": [
+                "mode",
+                "operations"
+            ],
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "transactional",
+                        "best_effort"
+                    ]
+                },
+                "operations": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeBulkOperation"
+                    }
+                }
+            }
+        },
+        "model.ProductTypeBulkResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.ProductTypeBulkSummary"
+                }
+            }
+        },
+        "model.ProductTypeBulkResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errs.ErrorMessage"
+                    }
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "prodtype_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProductTypeBulkSummary": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeBulkResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProductTypeChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "prodtype_id": {
+                    "type": "integer"
+                },
+                "prodtype_name": {
+                    "type": "string"
+                },
+                "prodtype_parent_id": {
+                    "type": "integer"
+                },
+                "seq": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProductTypeChangeFeed": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeChange"
+                    }
+                },
+                "cursor": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ProductTypeChangesResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.ProductTypeChangeFeed"
+                }
+            }
+        },
+        "model.ProductTypeCreate": {
+            "type": "object",
+            "required": [
+                "prodtype_id",
+                "prodtype_name"
+            ],
+            "properties": {
+                "prodtype_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "prodtype_name": {
+                    "type": "string",
+                    "maxLength": 40
+                },
+                "prodtype_parent_id": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "model.ProductTypeImportReport": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeImportRow"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
             }
-        }
-    },
-    "definitions": {
-        "errs.ErrorResponse": {
+        },
+        "model.ProductTypeImportResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
                 "message": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.ProductTypeImportReport"
                 }
             }
         },
-        "model.AuthPassportResponse": {
+        "model.ProductTypeImportRow": {
             "type": "object",
             "properties": {
-                "code": {
+                "action": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errs.ErrorMessage"
+                    }
+                },
+                "prodtype_id": {
                     "type": "integer"
                 },
-                "message": {
-                    "$ref": "#/definitions/model.UserPassport"
+                "prodtype_name": {
+                    "type": "string"
+                },
+                "prodtype_parent_id": {
+                    "type": "integer"
+                },
+                "row": {
+                    "type": "integer"
                 }
             }
         },
-        "model.CountResponse": {
+        "model.ProductTypeMove": {
             "type": "object",
             "properties": {
-                "code": {
+                "prodtype_parent_id": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "model.ProductTypeNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeNode"
+                    }
+                },
+                "prodtype_id": {
                     "type": "integer"
                 },
-                "message": {
+                "prodtype_name": {
+                    "type": "string"
+                },
+                "prodtype_parent_id": {
                     "type": "integer"
                 }
             }
         },
-        "model.LoginRequest": {
+        "model.ProductTypeResponse": {
             "type": "object",
-            "required": [
-                "user_email",
-                "user_password"
-            ],
             "properties": {
-                "user_email": {
-                    "type": "string",
-                    "maxLength": 40
+                "code": {
+                    "type": "integer"
                 },
-                "user_password": {
-                    "type": "string",
-                    "maxLength": 255
+                "message": {
+                    "$ref": "#/definitions/model.ProductType"
                 }
             }
         },
-        "model.ProductType": {
+        "model.ProductTypeRevision": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "prodtype_deleted_at": {
+                    "type": "string"
+                },
                 "prodtype_id": {
                     "type": "integer"
                 },
                 "prodtype_name": {
                     "type": "string"
+                },
+                "prodtype_parent_id": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
                 }
             }
         },
-        "model.ProductTypeCreate": {
+        "model.ProductTypeRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeRevision"
+                    }
+                }
+            }
+        },
+        "model.ProductTypeTrash": {
             "type": "object",
-            "required": [
-                "prodtype_id",
-                "prodtype_name"
-            ],
             "properties": {
+                "prodtype_deleted_at": {
+                    "type": "string"
+                },
                 "prodtype_id": {
-                    "type": "integer",
-                    "minimum": 0
+                    "type": "integer"
                 },
                 "prodtype_name": {
-                    "type": "string",
-                    "maxLength": 40
+                    "type": "string"
                 }
             }
         },
-        "model.ProductTypeResponse": {
+        "model.ProductTypeTreeResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
                 "message": {
-                    "$ref": "#/definitions/model.ProductType"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeNode"
+                    }
                 }
             }
         },
@@ -639,6 +2452,20 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ProductTypesTrashResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductTypeTrash"
+                    }
+                }
+            }
+        },
         "model.RefreshToken": {
             "type": "object",
             "properties": {
@@ -647,6 +2474,27 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Settings": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "model.SettingsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.Settings"
+                }
+            }
+        },
+        "model.SettingsUpdate": {
+            "type": "object",
+            "additionalProperties": true
+        },
         "model.StringResponse": {
             "type": "object",
             "properties": {
@@ -729,6 +2577,183 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookCreate": {
+            "type": "object",
+            "required": [
+                "events",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "model.WebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.WebhookDeliveryPage"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "integer"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookDeliveryPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDelivery"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.Webhook"
+                }
+            }
+        },
+        "model.WebhookUpdate": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "model.WebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Webhook"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"github.com/gofiber/fiber/v2"
+
+	"time"
+)
+
+type AuditHandler struct {
+	auditSrv service.AuditService
+}
+
+func NewAuditHandler(auditSrv service.AuditService) *AuditHandler {
+	return &AuditHandler{auditSrv: auditSrv}
+}
+
+// GetAuditLogs godoc
+// @Summary Get Audit Logs
+// @Description Get audit logs newest first, filtered by actor, action, resource, request id and time range
+// @Tags audit
+// @Security BearerAuth
+// @Produce  json
+// @param actor_id query int false "Actor user id"
+// @param action query string false "Action e.g. create, update, delete, login"
+// @param resource_type query string false "Resource type e.g. producttype, user"
+// @param resource_id query string false "Resource id"
+// @param request_id query string false "Request id"
+// @param from query string false "Created at or after (RFC3339)"
+// @param to query string false "Created before (RFC3339)"
+// @param limit query int false "Page size, default 50, max 500"
+// @param offset query int false "Page offset"
+// @response 200 {object} model.AuditLogsResponse "Get Audit Logs Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /audit/ [get]
+func (h *AuditHandler) FindAll(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	filter := new(model.AuditLogFilter)
+	if err := ctx.QueryParser(filter); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	from, err := parseAuditTime(ctx.Query("from"))
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError("from must be RFC3339"))
+	}
+	to, err := parseAuditTime(ctx.Query("to"))
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError("to must be RFC3339"))
+	}
+	filter.From, filter.To = from, to
+
+	auditPage, err := h.auditSrv.FindAll(helper.UserContext(ctx), filter)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Get Audit Logs Successfully")
+	webResponse := model.AuditLogsResponse{
+		Code: 		200,
+		Message: 	auditPage,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+func parseAuditTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,100 @@
+package handler_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yoshikrit/fiber-test/handler"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/testutils"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+)
+
+const (
+	AuditEndpointPath = "/audit"
+)
+
+func TestAuditFindAll(t *testing.T) {
+	mockService := testutils.NewAuditServiceMock()
+	auditHandler := handler.NewAuditHandler(mockService)
+
+	app := fiber.New()
+	app.Get(AuditEndpointPath, auditHandler.FindAll)
+
+	actorID := 7
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	auditPageMock := &model.AuditLogPage{
+		Total: 	1,
+		Limit: 	10,
+		Items: 	[]model.AuditLog{
+			{
+				ID: 			1,
+				ActorID: 		&actorID,
+				Action: 		model.AuditActionCreate,
+				ResourceType: 	model.AuditResourceProductType,
+				ResourceID: 	"1",
+				After: 			json.RawMessage(`{"prodtype_id":1,"prodtype_name":"A"}`),
+				CreatedAt: 		from,
+			},
+		},
+	}
+
+	auditPageJSON, _ := json.Marshal(auditPageMock)
+
+	t.Run("test case : find all audit logs success", func(t *testing.T) {
+		filterMock := &model.AuditLogFilter{ActorID: &actorID, ResourceType: "producttype", From: &from, Limit: 10}
+		mockService.On("FindAll", mock.Anything, filterMock).Return(auditPageMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, AuditEndpointPath + "?actor_id=7&resource_type=producttype&from=2024-01-01T00:00:00Z&limit=10", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":` + string(auditPageJSON) + `}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find all audit logs fail invalid time", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+
+		req := httptest.NewRequest(fiber.MethodGet, AuditEndpointPath + "?to=yesterday", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"to must be RFC3339"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("test case : find all audit logs fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("FindAll", mock.Anything, &model.AuditLogFilter{}).Return((*model.AuditLogPage)(nil), errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodGet, AuditEndpointPath, nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		expectedBody := `{"code":500,"message":""}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
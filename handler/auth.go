@@ -35,17 +35,17 @@ func (h *AuthHandler) Register(ctx *fiber.Ctx) error {
 
 	userCreateReq := new(model.UserCreate)
 	if err := ctx.BodyParser(userCreateReq); err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
 	}
 
-	err := h.authSrv.Register(userCreateReq)
+	err := h.authSrv.Register(helper.UserContext(ctx), userCreateReq)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Register User Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Register User Successfully")
 	webResponse := model.StringResponse{
 		Code: 		201,
 		Message: 	"Register User Successfully",
@@ -70,17 +70,17 @@ func (h *AuthHandler) Login(ctx *fiber.Ctx) error {
 
 	loginReq := new(model.LoginRequest)
 	if err := ctx.BodyParser(loginReq); err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
 	}
 
-	response, err := h.authSrv.Login(loginReq)
+	response, err := h.authSrv.Login(helper.UserContext(ctx), loginReq)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Login User Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Login User Successfully")
 	webResponse := &model.AuthPassportResponse{
 		Code: 		200,
 		Message: 	response,
@@ -105,17 +105,17 @@ func (h *AuthHandler) Reflesh(ctx *fiber.Ctx) error {
 
 	refleshReq := new(model.RefreshToken)
 	if err := ctx.BodyParser(refleshReq); err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
 	}
 
-	response, err := h.authSrv.RefreshPassport(refleshReq)
+	response, err := h.authSrv.RefreshPassport(helper.UserContext(ctx), refleshReq)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Reflesh Token Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Reflesh Token Successfully")
 	webResponse := &model.AuthPassportResponse{
 		Code: 		200,
 		Message: 	response,
@@ -139,16 +139,16 @@ func (h *AuthHandler) Logout(ctx *fiber.Ctx) error {
 
 	id, err := helper.ParamsInt(ctx)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)
 	}
 
-	if err := h.authSrv.Delete(id); err != nil {
-		logger.Error(err.Error())
+	if err := h.authSrv.Delete(helper.UserContext(ctx), id); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Logout User Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Logout User Successfully")
 	webResponse := model.StringResponse{
 		Code: 		200,
 		Message: 	"Logout User Successfully",
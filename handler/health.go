@@ -3,30 +3,84 @@ package handler
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/Yoshikrit/fiber-test/health"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
 	"github.com/Yoshikrit/fiber-test/helper/logger"
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
-type HealthCheckHandler struct {
+type HealthHandler struct {
+	registry 	*health.Registry
+	opsToken 	string
 }
 
-func NewHealthCheckHandler() *HealthCheckHandler {
-	return &HealthCheckHandler{}
+// NewHealthHandler serves the probes of registry, the verbose report is given to callers
+// sending opsToken as their Bearer token.
+func NewHealthHandler(registry *health.Registry, opsToken string) *HealthHandler {
+	return &HealthHandler{
+		registry: 	registry,
+		opsToken: 	opsToken,
+	}
+}
+
+// Livez godoc
+// @Summary Liveness Probe
+// @Description The process is up and able to answer, restart it when this fails
+// @Tags health
+// @Produce  json
+// @Param        verbose   query      bool  false  "Give errors and durations of the checks, needs the ops token"
+// @response 200 {object} model.HealthReport "Every check passed"
+// @response 401 {object} errs.ErrorResponse "Error Unauthorized"
+// @response 503 {object} model.HealthReport "A check failed"
+// @Router /livez [get]
+func (h *HealthHandler) Livez(ctx *fiber.Ctx) error {
+	return h.probe(ctx, health.Liveness)
+}
+
+// Readyz godoc
+// @Summary Readiness Probe
+// @Description The instance can serve traffic, route no requests to it when this fails
+// @Tags health
+// @Produce  json
+// @Param        verbose   query      bool  false  "Give errors and durations of the checks, needs the ops token"
+// @response 200 {object} model.HealthReport "Every check passed"
+// @response 401 {object} errs.ErrorResponse "Error Unauthorized"
+// @response 503 {object} model.HealthReport "A check failed"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(ctx *fiber.Ctx) error {
+	return h.probe(ctx, health.Readiness)
 }
 
-// HealthCheckHandler godoc
-// @Summary Health Check
-// @Description Health check
-// @id HealthCheckHandler
-// @Tags healthcheck
+// Startupz godoc
+// @Summary Startup Probe
+// @Description The instance has finished starting, the other probes apply once this passes
+// @Tags health
 // @Produce  json
-// @response 200 {object} model.StringResponse "Welcome to ProductType Server"
-// @Router /healthcheck [get]
-func (h *HealthCheckHandler) HealthCheck(ctx *fiber.Ctx) error {
-	logger.Info("Handler: ProductType service is running")
-	webResponse := model.StringResponse{
-		Code: 		200,
-		Message: 	"Welcome to ProductType Server",
+// @Param        verbose   query      bool  false  "Give errors and durations of the checks, needs the ops token"
+// @response 200 {object} model.HealthReport "Every check passed"
+// @response 401 {object} errs.ErrorResponse "Error Unauthorized"
+// @response 503 {object} model.HealthReport "A check failed"
+// @Router /startupz [get]
+func (h *HealthHandler) Startupz(ctx *fiber.Ctx) error {
+	return h.probe(ctx, health.Startup)
+}
+
+func (h *HealthHandler) probe(ctx *fiber.Ctx, probe health.Probe) error {
+	verbose := ctx.QueryBool("verbose")
+	if verbose && !helper.BearerTokenMatches(ctx, h.opsToken) {
+		logger.Error(ctx.UserContext(), "Verbose probe without ops credentials")
+		return helper.HandleError(ctx, errs.NewUnauthorizedError("Unauthorized"))
+	}
+
+	report := h.registry.Run(ctx.UserContext(), probe)
+	status := fiber.StatusOK
+	if report.Status == model.HealthStatusFail {
+		logger.Error(ctx.UserContext(), "Handler: Probe " + string(probe) + " failed", "checks", report.Checks)
+		status = fiber.StatusServiceUnavailable
+	}
+	if !verbose {
+		report = health.Summary(report)
 	}
-	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+	return ctx.Status(status).JSON(report)
 }
@@ -6,24 +6,63 @@ import (
 	"net/http/httptest"
 	"net/http"
 	"testing"
+	"context"
+	"errors"
 	"io"
 
 	"github.com/Yoshikrit/fiber-test/handler"
+	"github.com/Yoshikrit/fiber-test/health"
 )
 
-func TestHealthCheck(t *testing.T) {
-	healthHandler := handler.NewHealthCheckHandler()
+func TestProbes(t *testing.T) {
+	registry := health.NewRegistry(0)
+	registry.Register("database", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}, health.Readiness)
+	healthHandler := handler.NewHealthHandler(registry, "ops-secret")
 
 	app := fiber.New()
-  	app.Get("/health", healthHandler.HealthCheck)
+	app.Get("/livez", healthHandler.Livez)
+	app.Get("/readyz", healthHandler.Readyz)
+	app.Get("/startupz", healthHandler.Startupz)
 	t.Run("test case : pass", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req := httptest.NewRequest(http.MethodGet, "/livez", nil)
 
 		resp, _ := app.Test(req, -1)
 		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 
-		expectedBody := `{"code":200,"message":"Welcome to ProductType Server"}`
+		expectedBody := `{"status":"pass","checks":{}}`
 		body, _ := io.ReadAll(resp.Body)
 		assert.Equal(t, expectedBody, string(body))
 	})
-}
\ No newline at end of file
+
+	t.Run("test case : fail without details", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+		resp, _ := app.Test(req, -1)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+		expectedBody := `{"status":"fail","checks":{"database":{"status":"fail"},"draining":{"status":"pass"}}}`
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, expectedBody, string(body))
+	})
+
+	t.Run("test case : fail verbose", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/readyz?verbose=true", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer ops-secret")
+
+		resp, _ := app.Test(req, -1)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), `"error":"connection refused"`)
+	})
+
+	t.Run("test case : verbose with wrong token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/startupz?verbose=true", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer guess")
+
+		resp, _ := app.Test(req, -1)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -1,15 +1,29 @@
 package handler
 
 import (
+	"github.com/Yoshikrit/fiber-test/broker"
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/service"
 	"github.com/Yoshikrit/fiber-test/helper"
 	"github.com/Yoshikrit/fiber-test/helper/logger"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/spreadsheet"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/goccy/go-json"
+
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// StreamHeartbeatInterval is how often an idle event stream sends a comment to keep proxies from closing it.
+var StreamHeartbeatInterval = 15 * time.Second
+
 type ProductTypeHandler struct {
 	productTypeSrv service.ProductTypeService
 }
@@ -36,17 +50,17 @@ func (h *ProductTypeHandler) Create(ctx *fiber.Ctx) error {
 
 	prodTypeReq := new(model.ProductTypeCreate)
 	if err := ctx.BodyParser(prodTypeReq); err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
 	}
 
-	err := h.productTypeSrv.Create(prodTypeReq)
+	err := h.productTypeSrv.Create(helper.UserContext(ctx), prodTypeReq)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Create ProductType Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Create ProductType Successfully")
 	webResponse := model.StringResponse{
 		Code: 		201,
 		Message: 	"Create ProductType Successfully",
@@ -67,13 +81,13 @@ func (h *ProductTypeHandler) Create(ctx *fiber.Ctx) error {
 func (h *ProductTypeHandler) FindAll(ctx *fiber.Ctx) error {
     ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
 	
-	prodTypesRes, err := h.productTypeSrv.FindAll()
+	prodTypesRes, err := h.productTypeSrv.FindAll(helper.UserContext(ctx))
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Find All ProductTypes Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Find All ProductTypes Successfully")
 	webResponse := model.ProductTypesResponse{
 		Code: 		200,
 		Message: 	prodTypesRes,
@@ -88,6 +102,7 @@ func (h *ProductTypeHandler) FindAll(ctx *fiber.Ctx) error {
 // @Security BearerAuth
 // @Produce  json
 // @Param        id   path      int  true  "ProductType ID"
+// @Param        as_of   query      string  false  "Return the producttype as it was at this time (RFC3339)"
 // @response 200 {object} model.ProductTypeResponse "Get ProductType Successfully"
 // @response 400 {object} errs.ErrorResponse "Error Bad Request"
 // @response 404 {object} errs.ErrorResponse "Error Not Found"
@@ -98,17 +113,27 @@ func (h *ProductTypeHandler) FindByID(ctx *fiber.Ctx) error {
 	
 	id, err := helper.ParamsInt(ctx)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)
 	}
 
-	prodTypeRes, err := h.productTypeSrv.FindByID(id)
+	var prodTypeRes *model.ProductType
+	if asOf := ctx.Query("as_of"); asOf != "" {
+		asOfTime, parseErr := time.Parse(time.RFC3339, asOf)
+		if parseErr != nil {
+			logger.Error(ctx.UserContext(), parseErr.Error())
+			return helper.HandleError(ctx, errs.NewBadRequestError("as_of must be RFC3339"))
+		}
+		prodTypeRes, err = h.productTypeSrv.FindByIDAsOf(helper.UserContext(ctx), id, asOfTime)
+	} else {
+		prodTypeRes, err = h.productTypeSrv.FindByID(helper.UserContext(ctx), id)
+	}
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Find ProductType By ID Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Find ProductType By ID Successfully")
 	webResponse := model.ProductTypeResponse{
 		Code: 		200,
 		Message: 	prodTypeRes,
@@ -135,22 +160,22 @@ func (h *ProductTypeHandler) Update(ctx *fiber.Ctx) error {
 
 	id, err := helper.ParamsInt(ctx)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)
 	}
 
 	prodTypeReq := new(model.ProductTypeUpdate)
 	if err := ctx.BodyParser(prodTypeReq); err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
 	}
 
-	if err := h.productTypeSrv.Update(id, prodTypeReq); err != nil {
-		logger.Error(err.Error())
+	if err := h.productTypeSrv.Update(helper.UserContext(ctx), id, prodTypeReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Update ProductType Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Update ProductType Successfully")
 	webResponse := model.StringResponse{
 		Code: 		200,
 		Message: 	"Update ProductType Successfully",
@@ -164,28 +189,30 @@ func (h *ProductTypeHandler) Update(ctx *fiber.Ctx) error {
 // @Tags producttypes
 // @Security BearerAuth
 // @Produce  json
-// @Param        id   path      int  true  "ProductType ID"
+// @Param        id         path      int     true   "ProductType ID"
+// @Param        children   query     string  false  "Children behaviour (reject, cascade, reparent)"  default(reject)
 // @response 200 {object} model.StringResponse "Delete ProductType Successfully"
 // @response 400 {object} errs.ErrorResponse "Error Bad Request"
 // @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 409 {object} errs.ErrorResponse "Error Conflict"
 // @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
 // @Router /producttypes/{id} [delete]
 func (h *ProductTypeHandler) Delete(ctx *fiber.Ctx) error {
     ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
-	logger.Info("yes")
+	logger.Info(ctx.UserContext(), "yes")
 
 	id, err := helper.ParamsInt(ctx)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)
 	}
 
-	if err := h.productTypeSrv.Delete(id); err != nil {
-		logger.Error(err.Error())
+	if err := h.productTypeSrv.Delete(helper.UserContext(ctx), id, ctx.Query("children", model.DeleteChildrenReject)); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Delete ProductType Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Delete ProductType Successfully")
 	webResponse := model.StringResponse{
 		Code: 		200,
 		Message: 	"Delete ProductType Successfully",
@@ -205,16 +232,586 @@ func (h *ProductTypeHandler) Delete(ctx *fiber.Ctx) error {
 func (h *ProductTypeHandler) Count(ctx *fiber.Ctx) error {
     ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
 
-	count, err := h.productTypeSrv.Count()
+	count, err := h.productTypeSrv.Count(helper.UserContext(ctx))
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx.UserContext(), err.Error())
 		return helper.HandleError(ctx, err)	
 	}
 
-	logger.Info("Handler: Get ProductType'Count Successfully")
+	logger.Info(ctx.UserContext(), "Handler: Get ProductType'Count Successfully")
 	webResponse := model.CountResponse{
 		Code: 		200,
 		Message: 	int(count),
 	}
 	return ctx.Status(fiber.StatusOK).JSON(webResponse)
-}
\ No newline at end of file
+}
+
+// GetProductTypeTrash godoc
+// @Summary Get ProductType Trash
+// @Description Get all deleted producttype
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @response 200 {object} model.ProductTypesTrashResponse "Get ProductTypes In Trash Successfully"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/trash [get]
+func (h *ProductTypeHandler) FindAllTrash(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	prodTypesRes, err := h.productTypeSrv.FindAllTrash(helper.UserContext(ctx))
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Find All ProductTypes In Trash Successfully")
+	webResponse := model.ProductTypesTrashResponse{
+		Code: 		200,
+		Message: 	prodTypesRes,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// RestoreProductTypeByID godoc
+// @Summary Restore ProductType
+// @Description Restore deleted producttype by id
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @response 200 {object} model.StringResponse "Restore ProductType Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/{id}/restore [post]
+func (h *ProductTypeHandler) Restore(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	if err := h.productTypeSrv.Restore(helper.UserContext(ctx), id); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Restore ProductType Successfully")
+	webResponse := model.StringResponse{
+		Code: 		200,
+		Message: 	"Restore ProductType Successfully",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// PurgeProductTypeByID godoc
+// @Summary Purge ProductType
+// @Description Permanently delete producttype in trash by id (Admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @response 200 {object} model.StringResponse "Purge ProductType Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /admin/producttypes/{id} [delete]
+func (h *ProductTypeHandler) Purge(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	if err := h.productTypeSrv.Purge(helper.UserContext(ctx), id); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Purge ProductType Successfully")
+	webResponse := model.StringResponse{
+		Code: 		200,
+		Message: 	"Purge ProductType Successfully",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// BulkProductTypes godoc
+// @Summary Bulk ProductTypes
+// @Description Create, update and delete producttypes in one request, mode is transactional (all-or-nothing) or best_effort
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @param ProductTypes body model.ProductTypeBulkRequest true "ProductType operations to be apply"
+// @response 200 {object} model.ProductTypeBulkResponse "Bulk ProductTypes Successfully"
+// @response 207 {object} model.ProductTypeBulkResponse "Bulk ProductTypes With Failed Operations"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/bulk [post]
+func (h *ProductTypeHandler) Bulk(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	bulkReq := new(model.ProductTypeBulkRequest)
+	if err := ctx.BodyParser(bulkReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	summary, err := h.productTypeSrv.Bulk(helper.UserContext(ctx), bulkReq)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	status := fiber.StatusOK
+	if summary.Failed > 0 {
+		status = fiber.StatusMultiStatus
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Bulk ProductTypes Successfully")
+	webResponse := model.ProductTypeBulkResponse{
+		Code: 		status,
+		Message: 	summary,
+	}
+	return ctx.Status(status).JSON(webResponse)
+}
+
+// ExportProductTypes godoc
+// @Summary Export ProductTypes
+// @Description Stream all producttype as csv, xlsx or jsonl file
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  octet-stream
+// @Param        format   query      string  false  "File format (csv, xlsx, jsonl)"  default(csv)
+// @response 200 {file} file "ProductTypes file"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/export [get]
+func (h *ProductTypeHandler) Export(ctx *fiber.Ctx) error {
+	format := strings.ToLower(ctx.Query("format", spreadsheet.FormatCSV))
+	if !spreadsheet.IsSupported(format) {
+		logger.Error(ctx.UserContext(), "Format " + format + " is not supported")
+		return helper.HandleError(ctx, errs.NewBadRequestError("Format " + format + " is not supported"))
+	}
+
+	exportCtx, cancel := helper.StreamContext(ctx)
+	writeExport, err := h.productTypeSrv.Export(exportCtx, format)
+	if err != nil {
+		cancel()
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, spreadsheet.ContentType(format))
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="producttypes.` + format + `"`)
+	conn := ctx.Context().Conn()
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		if err := writeExport(w); err != nil {
+			// The status is already sent, dropping the connection before the end of the body
+			// keeps the client from taking the cut file as complete.
+			logger.Error(exportCtx, err.Error())
+			conn.Close()
+			return
+		}
+		logger.Info(exportCtx, "Handler: Export ProductTypes Successfully")
+	})
+	return nil
+}
+
+// ImportProductTypes godoc
+// @Summary Import ProductTypes
+// @Description Import producttype from uploaded csv, xlsx or jsonl file, nothing is applied when a row is invalid
+// @Tags producttypes
+// @Security BearerAuth
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param        file          formData   file    true   "ProductTypes file"
+// @Param        format        formData   string  false  "File format (csv, xlsx, jsonl), default from file extension"
+// @Param        on_conflict   formData   string  false  "Existing ID behaviour (upsert, skip)"  default(skip)
+// @Param        dry_run       formData   bool    false  "Validate only, nothing is applied"
+// @Param        mapping       formData   string  false  "Column mapping as JSON, e.g. {\"prodtype_id\":\"Code\",\"prodtype_name\":\"Name\"}"
+// @response 200 {object} model.ProductTypeImportResponse "Import ProductTypes Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 422 {object} model.ProductTypeImportResponse "Import ProductTypes With Invalid Rows"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/import [post]
+func (h *ProductTypeHandler) Import(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderAccept, fiber.MIMEMultipartForm)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	opts := &model.ProductTypeImportOptions{
+		Format: 	strings.ToLower(ctx.FormValue("format", strings.TrimPrefix(filepath.Ext(fileHeader.Filename), "."))),
+		OnConflict: ctx.FormValue("on_conflict", model.ImportOnConflictSkip),
+		DryRun: 	ctx.FormValue("dry_run") == "true",
+	}
+	if mapping := ctx.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			logger.Error(ctx.UserContext(), err.Error())
+			return helper.HandleError(ctx, errs.NewBadRequestError("Invalid mapping: " + err.Error()))
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+	defer file.Close()
+
+	report, err := h.productTypeSrv.Import(helper.UserContext(ctx), opts, file)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	status := fiber.StatusOK
+	if report.Invalid > 0 {
+		status = fiber.StatusUnprocessableEntity
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Import ProductTypes Successfully")
+	webResponse := model.ProductTypeImportResponse{
+		Code: 		status,
+		Message: 	report,
+	}
+	return ctx.Status(status).JSON(webResponse)
+}
+
+
+// GetProductTypeTree godoc
+// @Summary Get ProductType Tree
+// @Description Get all producttype nested under their parent
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @response 200 {object} model.ProductTypeTreeResponse "Find ProductType Tree Successfully"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/tree [get]
+func (h *ProductTypeHandler) FindTree(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	tree, err := h.productTypeSrv.FindTree(helper.UserContext(ctx))
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Find ProductType Tree Successfully")
+	webResponse := model.ProductTypeTreeResponse{
+		Code: 		200,
+		Message: 	tree,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// GetProductTypeChildren godoc
+// @Summary Get ProductType Children
+// @Description Get direct children of producttype by id
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @response 200 {object} model.ProductTypesResponse "Find ProductType Children Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/{id}/children [get]
+func (h *ProductTypeHandler) FindChildren(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	prodTypesRes, err := h.productTypeSrv.FindChildren(helper.UserContext(ctx), id)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Find ProductType Children Successfully")
+	webResponse := model.ProductTypesResponse{
+		Code: 		200,
+		Message: 	prodTypesRes,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// GetProductTypeAncestors godoc
+// @Summary Get ProductType Ancestors
+// @Description Get breadcrumb of producttype by id, ordered from the root down
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @response 200 {object} model.ProductTypesResponse "Find ProductType Ancestors Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/{id}/ancestors [get]
+func (h *ProductTypeHandler) FindAncestors(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	prodTypesRes, err := h.productTypeSrv.FindAncestors(helper.UserContext(ctx), id)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Find ProductType Ancestors Successfully")
+	webResponse := model.ProductTypesResponse{
+		Code: 		200,
+		Message: 	prodTypesRes,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// MoveProductType godoc
+// @Summary Move ProductType
+// @Description Move producttype and its subtree under a new parent, null parent moves it to the root
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @param ProductType body model.ProductTypeMove true "New parent of producttype"
+// @response 200 {object} model.StringResponse "Move ProductType Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 409 {object} errs.ErrorResponse "Error Conflict"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/{id}/move [put]
+func (h *ProductTypeHandler) Move(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	prodTypeReq := new(model.ProductTypeMove)
+	if err := ctx.BodyParser(prodTypeReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	if err := h.productTypeSrv.Move(helper.UserContext(ctx), id, prodTypeReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Move ProductType Successfully")
+	webResponse := model.StringResponse{
+		Code: 		200,
+		Message: 	"Move ProductType Successfully",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// GetProductTypeRevisions godoc
+// @Summary Get ProductType Revisions
+// @Description Get every revision of producttype, newest first
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @response 200 {object} model.ProductTypeRevisionsResponse "Get ProductType Revisions Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/{id}/revisions [get]
+func (h *ProductTypeHandler) FindRevisions(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	revisionsRes, err := h.productTypeSrv.FindRevisions(helper.UserContext(ctx), id)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Get ProductType Revisions Successfully")
+	webResponse := model.ProductTypeRevisionsResponse{
+		Code: 		200,
+		Message: 	revisionsRes,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// RevertProductType godoc
+// @Summary Revert ProductType
+// @Description Revert name and parent of producttype to an earlier revision, the revert is recorded as a new revision
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "ProductType ID"
+// @Param        rev   path      int  true  "Revision number"
+// @response 200 {object} model.StringResponse "Revert ProductType Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 409 {object} errs.ErrorResponse "Error Conflict"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/{id}/revisions/{rev}/revert [post]
+func (h *ProductTypeHandler) Revert(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	revParam := ctx.Params("rev")
+	revision, err := strconv.Atoi(revParam)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError("Invalid Revision: " + revParam + " is not integer"))
+	}
+
+	if err := h.productTypeSrv.Revert(helper.UserContext(ctx), id, revision); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Revert ProductType Successfully")
+	webResponse := model.StringResponse{
+		Code: 		200,
+		Message: 	"Revert ProductType Successfully",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// GetProductTypeChanges godoc
+// @Summary Get ProductType Changes
+// @Description Get inserts, updates and deletes of producttypes after a cursor in order, deletes are tombstones with only the id. Pass the returned cursor as since on the next call.
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  json
+// @Param        since   query      int  false  "Cursor from the previous response, 0 starts from the beginning"
+// @Param        limit   query      int  false  "Page size, default 100, max 1000"
+// @response 200 {object} model.ProductTypeChangesResponse "Get ProductType Changes Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /producttypes/changes [get]
+func (h *ProductTypeHandler) FindChanges(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	changeQuery := new(model.ProductTypeChangeQuery)
+	if err := ctx.QueryParser(changeQuery); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	changeFeed, err := h.productTypeSrv.FindChanges(helper.UserContext(ctx), changeQuery)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)	
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Get ProductType Changes Successfully")
+	webResponse := model.ProductTypeChangesResponse{
+		Code: 		200,
+		Message: 	changeFeed,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// StreamProductTypes godoc
+// @Summary Stream ProductType Events
+// @Description Server-Sent Events of producttype writes, the event is created, updated or deleted and the data is the producttype. Reconnect with Last-Event-ID to receive missed events, a reset event means they are gone and the client should refetch.
+// @Tags producttypes
+// @Security BearerAuth
+// @Produce  text/event-stream
+// @Param        Last-Event-ID   header      string  false  "ID of the last event received"
+// @response 200 {string} string "Event stream"
+// @Router /producttypes/stream [get]
+func (h *ProductTypeHandler) Stream(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	subscription := h.productTypeSrv.Subscribe(helper.UserContext(ctx), ctx.Get("Last-Event-ID"))
+	streamCtx, cancel := helper.StreamContext(ctx)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer subscription.Close()
+
+		if subscription.Reset {
+			if err := writeEvent(streamCtx, w, broker.Event{Type: "reset"}); err != nil {
+				return
+			}
+		}
+		for _, event := range subscription.Replay {
+			if err := writeEvent(streamCtx, w, event); err != nil {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(StreamHeartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+				if err := writeEvent(streamCtx, w, event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+				if err := w.Flush(); err != nil {
+					logger.Info(streamCtx, "Handler: ProductType Stream Closed")
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// writeEvent writes one SSE frame and flushes it, an error means the client has gone.
+func writeEvent(ctx context.Context, w *bufio.Writer, event broker.Event) error {
+	if event.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\n", event.Type)
+
+	data := []byte("{}")
+	if event.Data != nil {
+		var err error
+		if data, err = json.Marshal(event.Data); err != nil {
+			logger.Error(ctx, err.Error())
+			return err
+		}
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	return w.Flush()
+}
@@ -1,15 +1,22 @@
 package handler_test
 
 import (
+	"bytes"
 	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/mock"
 
+	"github.com/Yoshikrit/fiber-test/broker"
 	"github.com/Yoshikrit/fiber-test/handler"
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/testutils"
@@ -35,7 +42,7 @@ func TestCreate(t *testing.T) {
 	prodTypeReqJSON, _ := json.Marshal(prodTypeReqMock)
 
 	t.Run("test case : create success", func(t *testing.T) {
-		mockService.On("Create", prodTypeReqMock).Return(nil)
+		mockService.On("Create", mock.Anything, prodTypeReqMock).Return(nil)
 
 		req := httptest.NewRequest(fiber.MethodPost, EndpointPath, strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -68,7 +75,7 @@ func TestCreate(t *testing.T) {
 
 	t.Run("test case : create fail from service", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
-		mockService.On("Create", prodTypeReqMock).Return(errs.NewInternalServerError(""))
+		mockService.On("Create", mock.Anything, prodTypeReqMock).Return(errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodPost, EndpointPath, strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -106,7 +113,7 @@ func TestFindAll(t *testing.T) {
 	prodTypesResJSON, _ := json.Marshal(prodTypesResMock)
 
 	t.Run("test case : find all success", func(t *testing.T) {
-		mockService.On("FindAll").Return(prodTypesResMock, nil)
+		mockService.On("FindAll", mock.Anything).Return(prodTypesResMock, nil)
 
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath, nil)
 
@@ -123,7 +130,7 @@ func TestFindAll(t *testing.T) {
 	
 	t.Run("test case : find all fail from service", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
-		mockService.On("FindAll").Return([]model.ProductType{}, errs.NewInternalServerError(""))
+		mockService.On("FindAll", mock.Anything).Return([]model.ProductType{}, errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath, nil)
 
@@ -154,7 +161,7 @@ func TestFindByID(t *testing.T) {
 	prodTypeResJSON, _ := json.Marshal(prodTypeResMock)
 
 	t.Run("test case : find by id success", func(t *testing.T) {
-		mockService.On("FindByID", 1).Return(&prodTypeResMock, nil)
+		mockService.On("FindByID", mock.Anything, 1).Return(&prodTypeResMock, nil)
 
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/1", nil)
 
@@ -169,6 +176,36 @@ func TestFindByID(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("test case : find by id as of success", func(t *testing.T) {
+		asOf := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		mockService.On("FindByIDAsOf", mock.Anything, 1, asOf).Return(&prodTypeResMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/1?as_of=2024-01-02T00:00:00Z", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":` + string(prodTypeResJSON) + `}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find by id as of fail invalid time", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/1?as_of=yesterday", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"as_of must be RFC3339"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+
 	t.Run("test case : find by id fail param", func(t *testing.T) {
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/a", nil)
 
@@ -184,7 +221,7 @@ func TestFindByID(t *testing.T) {
 	
 	t.Run("test case : find by id fail from service", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
-		mockService.On("FindByID", 1).Return(&model.ProductType{}, errs.NewInternalServerError(""))
+		mockService.On("FindByID", mock.Anything, 1).Return(&model.ProductType{}, errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/1", nil)
 
@@ -214,7 +251,7 @@ func TestUpdate(t *testing.T) {
 	prodTypeReqJSON, _ := json.Marshal(prodTypeReqMock)
 
 	t.Run("test case : update success", func(t *testing.T) {
-		mockService.On("Update", 1, prodTypeReqMock).Return(nil)
+		mockService.On("Update", mock.Anything, 1, prodTypeReqMock).Return(nil)
 
 		req := httptest.NewRequest(fiber.MethodPut, EndpointPath + "/1", strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -261,7 +298,7 @@ func TestUpdate(t *testing.T) {
 	
 	t.Run("test case : update fail from service", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
-		mockService.On("Update", 1, prodTypeReqMock).Return(errs.NewInternalServerError(""))
+		mockService.On("Update", mock.Anything, 1, prodTypeReqMock).Return(errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodPut, EndpointPath + "/1", strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -286,7 +323,7 @@ func TestDelete(t *testing.T) {
 	app.Delete(EndpointPath  + "/:id", prodTypeHandler.Delete)
 
 	t.Run("test case : delete success", func(t *testing.T) {
-		mockService.On("Delete", 1).Return(nil)
+		mockService.On("Delete", mock.Anything, 1, "reject").Return(nil)
 
 		req := httptest.NewRequest(fiber.MethodDelete, EndpointPath + "/1", nil)
 
@@ -301,6 +338,34 @@ func TestDelete(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("test case : delete cascade success", func(t *testing.T) {
+		mockService.On("Delete", mock.Anything, 1, "cascade").Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodDelete, EndpointPath + "/1?children=cascade", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : delete fail has children", func(t *testing.T) {
+		mockService.On("Delete", mock.Anything, 2, "reject").Return(errs.NewConflictError("ProductType has children"))
+
+		req := httptest.NewRequest(fiber.MethodDelete, EndpointPath + "/2", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusConflict, resp.StatusCode)
+
+		expectedBody := `{"code":409,"message":"ProductType has children"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("test case : delete fail param", func(t *testing.T) {
 		req := httptest.NewRequest(fiber.MethodDelete, EndpointPath + "/a", nil)
 
@@ -316,7 +381,7 @@ func TestDelete(t *testing.T) {
 	
 	t.Run("test case : delete fail from service", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
-		mockService.On("Delete", 1).Return(errs.NewInternalServerError(""))
+		mockService.On("Delete", mock.Anything, 1, "reject").Return(errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodDelete, EndpointPath + "/1", nil)
 
@@ -340,7 +405,7 @@ func TestCount(t *testing.T) {
 	app.Get(EndpointPath, prodTypeHandler.Count)
 
 	t.Run("test case : get count success", func(t *testing.T) {
-		mockService.On("Count").Return(int64(5), nil)
+		mockService.On("Count", mock.Anything).Return(int64(5), nil)
 
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath, nil)
 
@@ -357,7 +422,7 @@ func TestCount(t *testing.T) {
 	
 	t.Run("test case : get count fail from service", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
-		mockService.On("Count").Return(int64(0), errs.NewInternalServerError(""))
+		mockService.On("Count", mock.Anything).Return(int64(0), errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodGet, EndpointPath, nil)
 
@@ -371,4 +436,864 @@ func TestCount(t *testing.T) {
 		utils.AssertEqual(t, expectedBody, string(body))
 		mockService.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestFindAllTrash(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/trash", prodTypeHandler.FindAllTrash)
+
+	prodTypesResMock := []model.ProductTypeTrash {
+		{
+			ID:   		1,
+			Name: 		"A",
+			DeletedAt: 	time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	prodTypesResJSON, _ := json.Marshal(prodTypesResMock)
+
+	t.Run("test case : find all trash success", func(t *testing.T) {
+		mockService.On("FindAllTrash", mock.Anything).Return(prodTypesResMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/trash", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":` + string(prodTypesResJSON) + `}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+	
+	t.Run("test case : find all trash fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("FindAllTrash", mock.Anything).Return([]model.ProductTypeTrash{}, errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/trash", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		expectedBody := `{"code":500,"message":""}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Post(EndpointPath  + "/:id/restore", prodTypeHandler.Restore)
+
+	t.Run("test case : restore success", func(t *testing.T) {
+		mockService.On("Restore", mock.Anything, 1).Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/1/restore", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":"Restore ProductType Successfully"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : restore fail param", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/a/restore", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"Invalid ID: a is not integer"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+	
+	t.Run("test case : restore fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("Restore", mock.Anything, 1).Return(errs.NewNotFoundError("record not found"))
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/1/restore", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+
+		expectedBody := `{"code":404,"message":"record not found"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestPurge(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Delete("/admin" + EndpointPath  + "/:id", prodTypeHandler.Purge)
+
+	t.Run("test case : purge success", func(t *testing.T) {
+		mockService.On("Purge", mock.Anything, 1).Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodDelete, "/admin" + EndpointPath + "/1", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":"Purge ProductType Successfully"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : purge fail param", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodDelete, "/admin" + EndpointPath + "/a", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"Invalid ID: a is not integer"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+	
+	t.Run("test case : purge fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("Purge", mock.Anything, 1).Return(errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodDelete, "/admin" + EndpointPath + "/1", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		expectedBody := `{"code":500,"message":""}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestBulk(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Post(EndpointPath + "/bulk", prodTypeHandler.Bulk)
+
+	bulkReqMock := &model.ProductTypeBulkRequest{
+		Mode: model.BulkModeBestEffort,
+		Operations: []model.ProductTypeBulkOperation{
+			{Op: "create", ID: 1, Name: "A"},
+			{Op: "delete", ID: 2},
+		},
+	}
+
+	bulkReqJSON, _ := json.Marshal(bulkReqMock)
+
+	t.Run("test case : bulk success", func(t *testing.T) {
+		mockService.On("Bulk", mock.Anything, bulkReqMock).Return(&model.ProductTypeBulkSummary{
+			Mode: model.BulkModeBestEffort,
+			Succeeded: 2,
+			Results: []model.ProductTypeBulkResult{
+				{Index: 0, Op: "create", ID: 1, Status: 201},
+				{Index: 1, Op: "delete", ID: 2, Status: 200},
+			},
+		}, nil)
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/bulk", strings.NewReader(string(bulkReqJSON)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":{"mode":"best_effort","succeeded":2,"failed":0,"results":[{"index":0,"op":"create","prodtype_id":1,"status":201},{"index":1,"op":"delete","prodtype_id":2,"status":200}]}}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk partial success", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("Bulk", mock.Anything, bulkReqMock).Return(&model.ProductTypeBulkSummary{
+			Mode: model.BulkModeBestEffort,
+			Succeeded: 1,
+			Failed: 1,
+			Results: []model.ProductTypeBulkResult{
+				{Index: 0, Op: "create", ID: 1, Status: 201},
+				{Index: 1, Op: "delete", ID: 2, Status: 404, Message: "record not found"},
+			},
+		}, nil)
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/bulk", strings.NewReader(string(bulkReqJSON)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusMultiStatus, resp.StatusCode)
+
+		expectedBody := `{"code":207,"message":{"mode":"best_effort","succeeded":1,"failed":1,"results":[{"index":0,"op":"create","prodtype_id":1,"status":201},{"index":1,"op":"delete","prodtype_id":2,"status":404,"message":"record not found"}]}}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk fail body parser", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/bulk", strings.NewReader(`invalid json`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"invalid character 'i' looking for beginning of value"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+
+	t.Run("test case : bulk fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("Bulk", mock.Anything, bulkReqMock).Return((*model.ProductTypeBulkSummary)(nil), errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/bulk", strings.NewReader(string(bulkReqJSON)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		expectedBody := `{"code":500,"message":""}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestExport(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/export", prodTypeHandler.Export)
+
+	t.Run("test case : export success", func(t *testing.T) {
+		mockService.On("Export", mock.Anything, "csv").Return("prodtype_id,prodtype_name,prodtype_parent_code\n1,A,\n", nil, nil).Once()
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/export?format=csv", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+		utils.AssertEqual(t, "text/csv", resp.Header.Get(fiber.HeaderContentType))
+		utils.AssertEqual(t, `attachment; filename="producttypes.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+		expectedBody := "prodtype_id,prodtype_name,prodtype_parent_code\n1,A,\n"
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : export fail from service", func(t *testing.T) {
+		mockService.On("Export", mock.Anything, "xlsx").Return(nil, nil, errs.NewInternalServerError("")).Once()
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/export?format=xlsx", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+		utils.AssertEqual(t, "", resp.Header.Get(fiber.HeaderContentDisposition))
+
+		expectedBody := `{"code":500,"message":""}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : export fail while streaming", func(t *testing.T) {
+		mockService.On("Export", mock.Anything, "jsonl").Return(`{"prodtype_id":1}`, errs.NewInternalServerError(""), nil).Once()
+
+		// app.Test can not drop its connection, so the cut body is read over a listener
+		streamApp := fiber.New(fiber.Config{DisableStartupMessage: true})
+		streamApp.Get(EndpointPath + "/export", prodTypeHandler.Export)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		utils.AssertEqual(t, nil, err)
+		go streamApp.Listener(listener)
+		defer streamApp.Shutdown()
+
+		// the connection can drop before or after the status went out, the client sees an error either way
+		resp, err := http.Get("http://" + listener.Addr().String() + EndpointPath + "/export?format=jsonl")
+		if err == nil {
+			defer resp.Body.Close()
+			_, err = io.ReadAll(resp.Body)
+		}
+
+		utils.AssertEqual(t, true, err != nil)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : export fail unsupported format", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/export?format=pdf", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"Format pdf is not supported"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+}
+
+func newImportRequest(filename string, content string, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", filename)
+	io.WriteString(part, content)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/import", body)
+	req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	return req
+}
+
+func TestImport(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Post(EndpointPath + "/import", prodTypeHandler.Import)
+
+	t.Run("test case : import success", func(t *testing.T) {
+		optsMock := &model.ProductTypeImportOptions{
+			Format: 	"csv",
+			OnConflict: "upsert",
+			Mapping: 	map[string]string{"prodtype_name": "Name"},
+		}
+		mockService.On("Import", mock.Anything, optsMock, mock.Anything).Return(&model.ProductTypeImportReport{
+			Applied: true,
+			Created: 1,
+			Rows: 	 []model.ProductTypeImportRow{{Row: 2, ID: 1, Name: "A", Action: "create"}},
+		}, nil)
+
+		req := newImportRequest("producttypes.CSV", "prodtype_id,Name\n1,A\n", map[string]string{
+			"on_conflict": "upsert",
+			"mapping":     `{"prodtype_name":"Name"}`,
+		})
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":{"dry_run":false,"applied":true,"created":1,"updated":0,"skipped":0,"invalid":0,"rows":[{"row":2,"prodtype_id":1,"prodtype_name":"A","action":"create"}]}}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : import invalid rows", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		optsMock := &model.ProductTypeImportOptions{
+			Format: 	"jsonl",
+			OnConflict: "skip",
+			DryRun: 	true,
+		}
+		mockService.On("Import", mock.Anything, optsMock, mock.Anything).Return(&model.ProductTypeImportReport{
+			DryRun:  true,
+			Invalid: 1,
+			Rows: 	 []model.ProductTypeImportRow{{Row: 2, ID: 1, Errors: []errs.ErrorMessage{{FailedField: "ProductTypeCreate.Name", Tag: "required"}}}},
+		}, nil)
+
+		req := newImportRequest("producttypes.txt", `{"prodtype_id":1}`, map[string]string{
+			"format":  "jsonl",
+			"dry_run": "true",
+		})
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+		expectedBody := `{"code":422,"message":{"dry_run":true,"applied":false,"created":0,"updated":0,"skipped":0,"invalid":1,"rows":[{"row":2,"prodtype_id":1,"prodtype_name":"","errors":[{"failed_field":"ProductTypeCreate.Name","tag":"required","value":""}]}]}}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : import fail missing file", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/import", strings.NewReader(""))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("test case : import fail invalid mapping", func(t *testing.T) {
+		req := newImportRequest("producttypes.csv", "prodtype_id,prodtype_name\n1,A\n", map[string]string{
+			"mapping": `{"prodtype_name":`,
+		})
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("test case : import fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		optsMock := &model.ProductTypeImportOptions{
+			Format: 	"csv",
+			OnConflict: "skip",
+		}
+		mockService.On("Import", mock.Anything, optsMock, mock.Anything).Return(&model.ProductTypeImportReport{}, errs.NewInternalServerError("Unexpected Error"))
+
+		req := newImportRequest("producttypes.csv", "prodtype_id,prodtype_name\n1,A\n", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		expectedBody := `{"code":500,"message":"Unexpected Error"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestFindTree(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/tree", prodTypeHandler.FindTree)
+
+	t.Run("test case : find tree success", func(t *testing.T) {
+		parentID := 1
+		mockService.On("FindTree", mock.Anything).Return([]*model.ProductTypeNode{
+			{ID: 1, Name: "Food", Children: []*model.ProductTypeNode{
+				{ID: 2, Name: "Snack", ParentID: &parentID, Children: []*model.ProductTypeNode{}},
+			}},
+		}, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/tree", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":[{"prodtype_id":1,"prodtype_name":"Food","children":[{"prodtype_id":2,"prodtype_name":"Snack","prodtype_parent_id":1,"children":[]}]}]}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find tree fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("FindTree", mock.Anything).Return([]*model.ProductTypeNode{}, errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/tree", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestFindChildren(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/:id/children", prodTypeHandler.FindChildren)
+
+	t.Run("test case : find children success", func(t *testing.T) {
+		parentID := 1
+		mockService.On("FindChildren", mock.Anything, 1).Return([]model.ProductType{{ID: 2, Name: "Snack", ParentID: &parentID}}, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/1/children", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":[{"prodtype_id":2,"prodtype_name":"Snack","prodtype_parent_id":1}]}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find children fail param", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/a/children", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("test case : find children fail not found", func(t *testing.T) {
+		mockService.On("FindChildren", mock.Anything, 9).Return([]model.ProductType{}, errs.NewNotFoundError("record not found"))
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/9/children", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestFindAncestors(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/:id/ancestors", prodTypeHandler.FindAncestors)
+
+	t.Run("test case : find ancestors success", func(t *testing.T) {
+		parentID := 1
+		mockService.On("FindAncestors", mock.Anything, 3).Return([]model.ProductType{{ID: 1, Name: "Food"}, {ID: 2, Name: "Snack", ParentID: &parentID}}, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/3/ancestors", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":[{"prodtype_id":1,"prodtype_name":"Food"},{"prodtype_id":2,"prodtype_name":"Snack","prodtype_parent_id":1}]}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find ancestors fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("FindAncestors", mock.Anything, 3).Return([]model.ProductType{}, errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/3/ancestors", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestMove(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Put(EndpointPath + "/:id/move", prodTypeHandler.Move)
+
+	t.Run("test case : move success", func(t *testing.T) {
+		parentID := 4
+		mockService.On("Move", mock.Anything, 2, &model.ProductTypeMove{ParentID: &parentID}).Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodPut, EndpointPath + "/2/move", strings.NewReader(`{"prodtype_parent_id":4}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":"Move ProductType Successfully"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : move fail body parser", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPut, EndpointPath + "/2/move", strings.NewReader(`invalid`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("test case : move fail cycle", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		parentID := 3
+		mockService.On("Move", mock.Anything, 1, &model.ProductTypeMove{ParentID: &parentID}).Return(errs.NewConflictError("ProductType can not be moved under its descendant"))
+
+		req := httptest.NewRequest(fiber.MethodPut, EndpointPath + "/1/move", strings.NewReader(`{"prodtype_parent_id":3}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusConflict, resp.StatusCode)
+
+		expectedBody := `{"code":409,"message":"ProductType can not be moved under its descendant"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestFindRevisions(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/:id/revisions", prodTypeHandler.FindRevisions)
+
+	revisionsResMock := []model.ProductTypeRevision{
+		{ID: 1, Revision: 2, Name: "B", Operation: "update", CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: 1, Revision: 1, Name: "A", Operation: "create", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	revisionsResJSON, _ := json.Marshal(revisionsResMock)
+
+	t.Run("test case : find revisions success", func(t *testing.T) {
+		mockService.On("FindRevisions", mock.Anything, 1).Return(revisionsResMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/1/revisions", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":` + string(revisionsResJSON) + `}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find revisions fail not found", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("FindRevisions", mock.Anything, 9).Return([]model.ProductTypeRevision(nil), errs.NewNotFoundError("record not found"))
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/9/revisions", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+
+		expectedBody := `{"code":404,"message":"record not found"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRevert(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Post(EndpointPath + "/:id/revisions/:rev/revert", prodTypeHandler.Revert)
+
+	t.Run("test case : revert success", func(t *testing.T) {
+		mockService.On("Revert", mock.Anything, 1, 2).Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/1/revisions/2/revert", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":"Revert ProductType Successfully"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : revert fail revision param", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/1/revisions/latest/revert", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"Invalid Revision: latest is not integer"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+
+	t.Run("test case : revert fail conflict from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("Revert", mock.Anything, 1, 1).Return(errs.NewConflictError("Parent ProductType is not available"))
+
+		req := httptest.NewRequest(fiber.MethodPost, EndpointPath + "/1/revisions/1/revert", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusConflict, resp.StatusCode)
+
+		expectedBody := `{"code":409,"message":"Parent ProductType is not available"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestFindChanges(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/changes", prodTypeHandler.FindChanges)
+
+	changeFeedMock := &model.ProductTypeChangeFeed{
+		Changes: []model.ProductTypeChange{
+			{Seq: 11, Op: "update", ID: 1, Name: "B", ChangedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Seq: 12, Op: "delete", ID: 2, ChangedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		Cursor: 12,
+	}
+
+	t.Run("test case : find changes success", func(t *testing.T) {
+		mockService.On("FindChanges", mock.Anything, &model.ProductTypeChangeQuery{Since: 10, Limit: 2}).Return(changeFeedMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/changes?since=10&limit=2", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":{"changes":[` +
+			`{"seq":11,"op":"update","prodtype_id":1,"prodtype_name":"B","changed_at":"2024-01-01T00:00:00Z"},` +
+			`{"seq":12,"op":"delete","prodtype_id":2,"changed_at":"2024-01-01T00:00:00Z"}],"cursor":12,"has_more":false}}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : find changes fail query parser", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/changes?since=abc", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("test case : find changes fail from service", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("FindChanges", mock.Anything, &model.ProductTypeChangeQuery{}).Return((*model.ProductTypeChangeFeed)(nil), errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/changes", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		expectedBody := `{"code":500,"message":""}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestStream(t *testing.T) {
+	mockService := testutils.NewProductTypeServiceMock()
+	prodTypeHandler := handler.NewProductTypeHandler(mockService)
+	
+	app := fiber.New()
+	app.Get(EndpointPath + "/stream", prodTypeHandler.Stream)
+
+	t.Run("test case : stream replays and sends live events", func(t *testing.T) {
+		events := make(chan broker.Event, 1)
+		events <- broker.Event{ID: "e-3", Type: "deleted", Data: &model.ProductType{ID: 2, Name: "B"}}
+		close(events)
+		mockService.On("Subscribe", mock.Anything, "e-1").Return(&broker.Subscription{
+			Replay: []broker.Event{{ID: "e-2", Type: "updated", Data: &model.ProductType{ID: 1, Name: "A"}}},
+			Events: events,
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/stream", nil)
+		req.Header.Set("Last-Event-ID", "e-1")
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+		utils.AssertEqual(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+
+		expectedBody := "id: e-2\nevent: updated\ndata: {\"prodtype_id\":1,\"prodtype_name\":\"A\"}\n\n" +
+			"id: e-3\nevent: deleted\ndata: {\"prodtype_id\":2,\"prodtype_name\":\"B\"}\n\n"
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : stream sends reset and heartbeat", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		heartbeatInterval := handler.StreamHeartbeatInterval
+		handler.StreamHeartbeatInterval = 10 * time.Millisecond
+		defer func() { handler.StreamHeartbeatInterval = heartbeatInterval }()
+
+		events := make(chan broker.Event)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(events)
+		}()
+		mockService.On("Subscribe", mock.Anything, "old-9").Return(&broker.Subscription{Reset: true, Events: events})
+
+		req := httptest.NewRequest(fiber.MethodGet, EndpointPath + "/stream", nil)
+		req.Header.Set("Last-Event-ID", "old-9")
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, true, strings.HasPrefix(string(body), "event: reset\ndata: {}\n\n"))
+		utils.AssertEqual(t, true, strings.Contains(string(body), ": heartbeat\n\n"))
+		mockService.AssertExpectations(t)
+	})
+}
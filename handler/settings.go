@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"github.com/gofiber/fiber/v2"
+
+	"fmt"
+)
+
+type SettingsHandler struct {
+	settingsSrv service.SettingsService
+}
+
+func NewSettingsHandler(settingsSrv service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{settingsSrv: settingsSrv}
+}
+
+// GetSettings godoc
+// @Summary Get Settings
+// @Description Get the settings the server applies without a restart: LOG_LEVEL, CORS_ALLOW_ORIGINS, RATE_LIMIT_MAX, RATE_LIMIT_WINDOW, JWT_ACCESS_EXPIRES and JWT_REFRESH_EXPIRES
+// @Tags admin
+// @Security BearerAuth
+// @Produce  json
+// @response 200 {object} model.SettingsResponse "Get Settings Successfully"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /admin/settings [get]
+func (h *SettingsHandler) FindAll(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	settings, err := h.settingsSrv.FindAll(helper.UserContext(ctx))
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Get Settings Successfully")
+	webResponse := model.SettingsResponse{
+		Code: 		200,
+		Message: 	settings,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// UpdateSettings godoc
+// @Summary Update Settings
+// @Description Change settings of the running server by key, all of them or none. Settings only read at startup are refused with the reason
+// @Tags admin
+// @Security BearerAuth
+// @Produce  json
+// @param Settings body model.SettingsUpdate true "Settings to change e.g. {\"RATE_LIMIT_MAX\": 50}"
+// @response 200 {object} model.SettingsResponse "Update Settings Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /admin/settings [patch]
+func (h *SettingsHandler) Update(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	settingsReq := model.SettingsUpdate{}
+	if err := ctx.BodyParser(&settingsReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+	changes := model.Settings{}
+	for key, value := range settingsReq {
+		switch value.(type) {
+		case string, float64, bool:
+			changes[key] = fmt.Sprint(value)
+		default:
+			logger.Error(ctx.UserContext(), "Setting is not a string, a number or a boolean")
+			return helper.HandleError(ctx, errs.NewBadRequestError(key + " must be a string, a number or a boolean"))
+		}
+	}
+
+	settings, err := h.settingsSrv.Update(helper.UserContext(ctx), changes)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Update Settings Successfully")
+	webResponse := model.SettingsResponse{
+		Code: 		200,
+		Message: 	settings,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yoshikrit/fiber-test/handler"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/testutils"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+)
+
+const (
+	SettingsEndpointPath = "/admin/settings"
+)
+
+func TestSettingsUpdate(t *testing.T) {
+	mockService := testutils.NewSettingsServiceMock()
+	settingsHandler := handler.NewSettingsHandler(mockService)
+
+	app := fiber.New()
+	app.Patch(SettingsEndpointPath, settingsHandler.Update)
+
+	patch := func(body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPatch, SettingsEndpointPath, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(respBody)
+	}
+
+	t.Run("test case : update settings success", func(t *testing.T) {
+		mockService.On("Update", mock.Anything, model.Settings{"RATE_LIMIT_MAX": "50", "LOG_LEVEL": "warn"}).Return(model.Settings{"RATE_LIMIT_MAX": "50", "LOG_LEVEL": "warn"}, nil).Once()
+
+		status, body := patch(`{"RATE_LIMIT_MAX": 50, "LOG_LEVEL": "warn"}`)
+
+		utils.AssertEqual(t, fiber.StatusOK, status)
+		utils.AssertEqual(t, `{"code":200,"message":{"LOG_LEVEL":"warn","RATE_LIMIT_MAX":"50"}}`, body)
+	})
+
+	t.Run("test case : update settings fail not reloadable", func(t *testing.T) {
+		message := "SERVER_PORT is only read at startup, restart the server to change it"
+		mockService.On("Update", mock.Anything, model.Settings{"SERVER_PORT": "9000"}).Return(model.Settings(nil), errs.NewBadRequestError(message)).Once()
+
+		status, body := patch(`{"SERVER_PORT": "9000"}`)
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, status)
+		utils.AssertEqual(t, `{"code":400,"message":"` + message + `"}`, body)
+	})
+
+	t.Run("test case : update settings fail value is an object", func(t *testing.T) {
+		status, body := patch(`{"RATE_LIMIT_MAX": {"value": 50}}`)
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, status)
+		utils.AssertEqual(t, `{"code":400,"message":"RATE_LIMIT_MAX must be a string, a number or a boolean"}`, body)
+	})
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"github.com/gofiber/fiber/v2"
+
+	"strconv"
+)
+
+type WebhookHandler struct {
+	webhookSrv service.WebhookService
+}
+
+func NewWebhookHandler(webhookSrv service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookSrv: webhookSrv}
+}
+
+// CreateWebhook godoc
+// @Summary Create Webhook
+// @Description Subscribe a URL to producttype events, every delivery is signed with the secret in X-Webhook-Signature
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @param Webhook body model.WebhookCreate true "Webhook data to be create"
+// @response 201 {object} model.WebhookResponse "Create Webhook Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/ [post]
+func (h *WebhookHandler) Create(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	webhookReq := new(model.WebhookCreate)
+	if err := ctx.BodyParser(webhookReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	webhookRes, err := h.webhookSrv.Create(helper.UserContext(ctx), webhookReq)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Create Webhook Successfully")
+	webResponse := model.WebhookResponse{
+		Code: 		201,
+		Message: 	webhookRes,
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(webResponse)
+}
+
+// GetAllWebhooks godoc
+// @Summary Get All Webhooks
+// @Description Get all webhooks, secrets are never returned
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @response 200 {object} model.WebhooksResponse "Get Webhooks Successfully"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/ [get]
+func (h *WebhookHandler) FindAll(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	webhooksRes, err := h.webhookSrv.FindAll(helper.UserContext(ctx))
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Find All Webhooks Successfully")
+	webResponse := model.WebhooksResponse{
+		Code: 		200,
+		Message: 	webhooksRes,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// GetWebhookByID godoc
+// @Summary Get Webhook
+// @Description Get webhook by id
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "Webhook ID"
+// @response 200 {object} model.WebhookResponse "Get Webhook Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) FindByID(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	webhookRes, err := h.webhookSrv.FindByID(helper.UserContext(ctx), id)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Find Webhook By ID Successfully")
+	webResponse := model.WebhookResponse{
+		Code: 		200,
+		Message: 	webhookRes,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// UpdateWebhookByID godoc
+// @Summary Update Webhook
+// @Description Update webhook by id, an empty secret keeps the current one
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "Webhook ID"
+// @param Webhook body model.WebhookUpdate true "Webhook data to be update"
+// @response 200 {object} model.StringResponse "Update Webhook Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	webhookReq := new(model.WebhookUpdate)
+	if err := ctx.BodyParser(webhookReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	if err := h.webhookSrv.Update(helper.UserContext(ctx), id, webhookReq); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Update Webhook Successfully")
+	webResponse := model.StringResponse{
+		Code: 		200,
+		Message: 	"Update Webhook Successfully",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// DeleteWebhookByID godoc
+// @Summary Delete Webhook
+// @Description Delete webhook by id together with its delivery log
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "Webhook ID"
+// @response 200 {object} model.StringResponse "Delete Webhook Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	if err := h.webhookSrv.Delete(helper.UserContext(ctx), id); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Delete Webhook Successfully")
+	webResponse := model.StringResponse{
+		Code: 		200,
+		Message: 	"Delete Webhook Successfully",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get Webhook Deliveries
+// @Description Get the delivery log of a webhook newest first
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param        id   path      int  true  "Webhook ID"
+// @param status query string false "Status (pending, succeeded, failed, dead)"
+// @param limit query int false "Page size, default 50, max 500"
+// @param offset query int false "Page offset"
+// @response 200 {object} model.WebhookDeliveriesResponse "Get Webhook Deliveries Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) FindDeliveries(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	filter := new(model.WebhookDeliveryFilter)
+	if err := ctx.QueryParser(filter); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError(err.Error()))
+	}
+
+	deliveryPage, err := h.webhookSrv.FindDeliveries(helper.UserContext(ctx), id, filter)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Get Webhook Deliveries Successfully")
+	webResponse := model.WebhookDeliveriesResponse{
+		Code: 		200,
+		Message: 	deliveryPage,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(webResponse)
+}
+
+// RedeliverWebhookDelivery godoc
+// @Summary Redeliver Webhook Delivery
+// @Description Send a delivery again with a fresh set of attempts, also brings back a dead delivery
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param        id            path      int  true  "Webhook ID"
+// @Param        delivery_id   path      int  true  "Delivery ID"
+// @response 202 {object} model.StringResponse "Redeliver Webhook Delivery Successfully"
+// @response 400 {object} errs.ErrorResponse "Error Bad Request"
+// @response 404 {object} errs.ErrorResponse "Error Not Found"
+// @response 500 {object} errs.ErrorResponse "Error Unexpected Error"
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(ctx *fiber.Ctx) error {
+    ctx.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+
+	id, err := helper.ParamsInt(ctx)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	deliveryParam := ctx.Params("delivery_id")
+	deliveryID, err := strconv.ParseInt(deliveryParam, 10, 64)
+	if err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, errs.NewBadRequestError("Invalid Delivery ID: " + deliveryParam + " is not integer"))
+	}
+
+	if err := h.webhookSrv.Redeliver(helper.UserContext(ctx), id, deliveryID); err != nil {
+		logger.Error(ctx.UserContext(), err.Error())
+		return helper.HandleError(ctx, err)
+	}
+
+	logger.Info(ctx.UserContext(), "Handler: Redeliver Webhook Delivery Successfully")
+	webResponse := model.StringResponse{
+		Code: 		202,
+		Message: 	"Redeliver Webhook Delivery Successfully",
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(webResponse)
+}
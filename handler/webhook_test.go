@@ -0,0 +1,236 @@
+package handler_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yoshikrit/fiber-test/handler"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/testutils"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+)
+
+const (
+	WebhookEndpointPath = "/webhooks"
+)
+
+func TestWebhookCreate(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Post(WebhookEndpointPath, webhookHandler.Create)
+
+	webhookReqMock := &model.WebhookCreate{
+		URL: 	"https://example.com/hook",
+		Events: []string{"producttype.created"},
+		Secret: "0123456789abcdef",
+	}
+	webhookReqJSON, _ := json.Marshal(webhookReqMock)
+
+	t.Run("test case : create webhook success", func(t *testing.T) {
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		webhookMock := &model.Webhook{ID: 1, URL: "https://example.com/hook", Events: []string{"producttype.created"}, Active: true, CreatedAt: createdAt, UpdatedAt: createdAt}
+		mockService.On("Create", mock.Anything, webhookReqMock).Return(webhookMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodPost, WebhookEndpointPath, strings.NewReader(string(webhookReqJSON)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusCreated, resp.StatusCode)
+
+		expectedBody := `{"code":201,"message":{"webhook_id":1,"url":"https://example.com/hook","events":["producttype.created"],"active":true,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : create webhook fail body parser", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+
+		req := httptest.NewRequest(fiber.MethodPost, WebhookEndpointPath, strings.NewReader(`invalid json`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+		mockService.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestWebhookFindAll(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Get(WebhookEndpointPath, webhookHandler.FindAll)
+
+	t.Run("test case : find all webhooks fail from service", func(t *testing.T) {
+		mockService.On("FindAll", mock.Anything).Return([]model.Webhook(nil), errs.NewInternalServerError(""))
+
+		req := httptest.NewRequest(fiber.MethodGet, WebhookEndpointPath, nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookFindByID(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Get(WebhookEndpointPath + "/:id", webhookHandler.FindByID)
+
+	t.Run("test case : find webhook fail not found", func(t *testing.T) {
+		mockService.On("FindByID", mock.Anything, 1).Return((*model.Webhook)(nil), errs.NewNotFoundError("record not found"))
+
+		req := httptest.NewRequest(fiber.MethodGet, WebhookEndpointPath + "/1", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+
+		expectedBody := `{"code":404,"message":"record not found"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookUpdate(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Put(WebhookEndpointPath + "/:id", webhookHandler.Update)
+
+	t.Run("test case : update webhook success", func(t *testing.T) {
+		active := false
+		webhookReqMock := &model.WebhookUpdate{URL: "https://example.com/hook", Events: []string{"producttype.deleted"}, Active: &active}
+		webhookReqJSON, _ := json.Marshal(webhookReqMock)
+		mockService.On("Update", mock.Anything, 1, webhookReqMock).Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodPut, WebhookEndpointPath + "/1", strings.NewReader(string(webhookReqJSON)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":"Update Webhook Successfully"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookDelete(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Delete(WebhookEndpointPath + "/:id", webhookHandler.Delete)
+
+	t.Run("test case : delete webhook fail invalid id", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodDelete, WebhookEndpointPath + "/abc", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"Invalid ID: abc is not integer"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestWebhookFindDeliveries(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Get(WebhookEndpointPath + "/:id/deliveries", webhookHandler.FindDeliveries)
+
+	t.Run("test case : find deliveries success", func(t *testing.T) {
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		deliveryPageMock := &model.WebhookDeliveryPage{
+			Total: 	1,
+			Limit: 	10,
+			Items: 	[]model.WebhookDelivery{
+				{ID: 5, WebhookID: 1, EventID: "e1", EventType: "producttype.created", Payload: json.RawMessage(`{"id":"e1"}`), Status: "dead", Attempts: 8, NextAttemptAt: createdAt, CreatedAt: createdAt},
+			},
+		}
+		deliveryPageJSON, _ := json.Marshal(deliveryPageMock)
+		mockService.On("FindDeliveries", mock.Anything, 1, &model.WebhookDeliveryFilter{Status: "dead", Limit: 10}).Return(deliveryPageMock, nil)
+
+		req := httptest.NewRequest(fiber.MethodGet, WebhookEndpointPath + "/1/deliveries?status=dead&limit=10", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+		expectedBody := `{"code":200,"message":` + string(deliveryPageJSON) + `}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	mockService := testutils.NewWebhookServiceMock()
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	app := fiber.New()
+	app.Post(WebhookEndpointPath + "/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+	t.Run("test case : redeliver success", func(t *testing.T) {
+		mockService.On("Redeliver", mock.Anything, 1, int64(5)).Return(nil)
+
+		req := httptest.NewRequest(fiber.MethodPost, WebhookEndpointPath + "/1/deliveries/5/redeliver", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusAccepted, resp.StatusCode)
+
+		expectedBody := `{"code":202,"message":"Redeliver Webhook Delivery Successfully"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : redeliver fail invalid delivery id", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+
+		req := httptest.NewRequest(fiber.MethodPost, WebhookEndpointPath + "/1/deliveries/x/redeliver", nil)
+
+		resp, _ := app.Test(req)
+		defer resp.Body.Close()
+
+		utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		expectedBody := `{"code":400,"message":"Invalid Delivery ID: x is not integer"}`
+		body, _ := io.ReadAll(resp.Body)
+		utils.AssertEqual(t, expectedBody, string(body))
+	})
+}
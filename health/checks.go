@@ -0,0 +1,55 @@
+package health
+
+import (
+	"gorm.io/gorm"
+
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DBPing checks the database answers a ping within the timeout of the check.
+func DBPing(sqlDB *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+type schemaMigration struct {
+	Version int64
+	Dirty 	bool
+}
+
+// MigrationVersion checks the schema is at expected, the version of the latest migration the code
+// was built with, and that no migration was left half applied.
+func MigrationVersion(db *gorm.DB, expected uint) CheckFunc {
+	return func(ctx context.Context) error {
+		var migration schemaMigration
+		result := db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&migration)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no migration applied")
+		}
+		if migration.Dirty {
+			return fmt.Errorf("migration %d is dirty", migration.Version)
+		}
+		if migration.Version != int64(expected) {
+			return fmt.Errorf("schema is at version %d, expected %d", migration.Version, expected)
+		}
+		return nil
+	}
+}
+
+// SigningKeys checks a key to sign and verify tokens is loaded, key is read on every check
+// as the config can change at runtime.
+func SigningKeys(key func() string) CheckFunc {
+	return func(ctx context.Context) error {
+		if key() == "" {
+			return errors.New("no token signing key is loaded")
+		}
+		return nil
+	}
+}
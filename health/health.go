@@ -0,0 +1,148 @@
+package health
+
+import (
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Probe string
+
+const (
+	Liveness 	Probe = "livez"
+	Readiness 	Probe = "readyz"
+	Startup 	Probe = "startupz"
+)
+
+const DefaultCheckTimeout = 2 * time.Second
+
+// CheckFunc reports a dependency as healthy by returning nil, ctx carries the timeout of the check.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name 	string
+	fn 		CheckFunc
+}
+
+// Registry holds the checks of every probe. The checks of a probe run concurrently and each one
+// gets timeout, a check still running when it passes fails. Once Drain is called the readiness
+// probe fails so load balancers stop routing to the instance.
+type Registry struct {
+	mu 			sync.RWMutex
+	checks 		map[Probe][]check
+	timeout 	time.Duration
+	draining 	atomic.Bool
+}
+
+// NewRegistry creates a registry, zero timeout means DefaultCheckTimeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Registry{
+		checks: 	map[Probe][]check{},
+		timeout: 	timeout,
+	}
+}
+
+// Register adds the check named name to each of probes.
+func (r *Registry) Register(name string, fn CheckFunc, probes ...Probe) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, probe := range probes {
+		r.checks[probe] = append(r.checks[probe], check{name: name, fn: fn})
+	}
+}
+
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// Run runs the checks of probe, the report always carries errors and durations.
+func (r *Registry) Run(ctx context.Context, probe Probe) *model.HealthReport {
+	r.mu.RLock()
+	checks := append([]check(nil), r.checks[probe]...)
+	r.mu.RUnlock()
+	if probe == Readiness {
+		checks = append(checks, check{name: "draining", fn: r.checkDraining})
+	}
+
+	report := &model.HealthReport{
+		Status: model.HealthStatusPass,
+		Checks: make(map[string]model.HealthCheck, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			result := r.run(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.name] = result
+			if result.Status == model.HealthStatusFail {
+				report.Status = model.HealthStatusFail
+			}
+		}(c)
+	}
+	wg.Wait()
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c check) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := model.HealthCheck{
+		Status: 	model.HealthStatusPass,
+		Duration: 	time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = model.HealthStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (r *Registry) checkDraining(ctx context.Context) error {
+	if r.Draining() {
+		return errors.New("shutting down")
+	}
+	return nil
+}
+
+// Summary is report without errors and durations, what callers without ops credentials see.
+func Summary(report *model.HealthReport) *model.HealthReport {
+	summary := &model.HealthReport{
+		Status: report.Status,
+		Checks: make(map[string]model.HealthCheck, len(report.Checks)),
+	}
+	for name, result := range report.Checks {
+		summary.Checks[name] = model.HealthCheck{Status: result.Status}
+	}
+	return summary
+}
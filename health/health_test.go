@@ -0,0 +1,119 @@
+package health_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/health"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("test case : every check passes", func(t *testing.T) {
+		registry := health.NewRegistry(0)
+		registry.Register("database", func(ctx context.Context) error { return nil }, health.Readiness, health.Startup)
+
+		report := registry.Run(context.Background(), health.Startup)
+
+		assert.Equal(t, model.HealthStatusPass, report.Status)
+		assert.Equal(t, model.HealthStatusPass, report.Checks["database"].Status)
+		assert.NotEmpty(t, report.Checks["database"].Duration)
+	})
+
+	t.Run("test case : one check fails", func(t *testing.T) {
+		registry := health.NewRegistry(0)
+		registry.Register("database", func(ctx context.Context) error { return nil }, health.Startup)
+		registry.Register("signing_keys", func(ctx context.Context) error { return errors.New("missing") }, health.Startup)
+
+		report := registry.Run(context.Background(), health.Startup)
+
+		assert.Equal(t, model.HealthStatusFail, report.Status)
+		assert.Equal(t, model.HealthStatusPass, report.Checks["database"].Status)
+		assert.Equal(t, model.HealthCheck{Status: model.HealthStatusFail, Error: "missing", Duration: report.Checks["signing_keys"].Duration}, report.Checks["signing_keys"])
+	})
+
+	t.Run("test case : slow check times out", func(t *testing.T) {
+		registry := health.NewRegistry(10 * time.Millisecond)
+		registry.Register("database", func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}, health.Liveness)
+
+		report := registry.Run(context.Background(), health.Liveness)
+
+		assert.Equal(t, model.HealthStatusFail, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+	})
+
+	t.Run("test case : readiness fails while draining", func(t *testing.T) {
+		registry := health.NewRegistry(0)
+		assert.Equal(t, model.HealthStatusPass, registry.Run(context.Background(), health.Readiness).Status)
+
+		registry.Drain()
+
+		report := registry.Run(context.Background(), health.Readiness)
+		assert.Equal(t, model.HealthStatusFail, report.Status)
+		assert.Equal(t, "shutting down", report.Checks["draining"].Error)
+		assert.Equal(t, model.HealthStatusPass, registry.Run(context.Background(), health.Liveness).Status)
+	})
+
+	t.Run("test case : summary drops details", func(t *testing.T) {
+		registry := health.NewRegistry(0)
+		registry.Register("database", func(ctx context.Context) error { return errors.New("down") }, health.Startup)
+
+		summary := health.Summary(registry.Run(context.Background(), health.Startup))
+
+		assert.Equal(t, &model.HealthReport{
+			Status: model.HealthStatusFail,
+			Checks: map[string]model.HealthCheck{"database": {Status: model.HealthStatusFail}},
+		}, summary)
+	})
+}
+
+func TestMigrationVersion(t *testing.T) {
+	query := "SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1"
+
+	t.Run("test case : pass", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(7, false))
+
+		assert.NoError(t, health.MigrationVersion(db, 7)(context.Background()))
+	})
+
+	t.Run("test case : fail version mismatch", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(6, false))
+
+		assert.EqualError(t, health.MigrationVersion(db, 7)(context.Background()), "schema is at version 6, expected 7")
+	})
+
+	t.Run("test case : fail dirty", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(7, true))
+
+		assert.EqualError(t, health.MigrationVersion(db, 7)(context.Background()), "migration 7 is dirty")
+	})
+
+	t.Run("test case : fail nothing applied", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}))
+
+		assert.EqualError(t, health.MigrationVersion(db, 7)(context.Background()), "no migration applied")
+	})
+}
+
+func TestSigningKeys(t *testing.T) {
+	t.Run("test case : pass", func(t *testing.T) {
+		assert.NoError(t, health.SigningKeys(func() string { return "secret" })(context.Background()))
+	})
+
+	t.Run("test case : fail", func(t *testing.T) {
+		assert.EqualError(t, health.SigningKeys(func() string { return "" })(context.Background()), "no token signing key is loaded")
+	})
+}
@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocalsUserID is the fiber.Ctx locals key the JWT middleware stores the authenticated user ID under.
+const LocalsUserID = "user_id"
+
+type actorKey struct{}
+
+func ContextWithActor(ctx context.Context, actor *model.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext never returns nil, a context without an actor yields an anonymous one.
+func ActorFromContext(ctx context.Context) *model.Actor {
+	if actor, ok := ctx.Value(actorKey{}).(*model.Actor); ok && actor != nil {
+		return actor
+	}
+	return &model.Actor{}
+}
+
+// ActorTrashRetention is the system actor of the worker that purges expired trash.
+const ActorTrashRetention = "trash_retention"
+
+// SystemContext carries the named background job as the actor, so what it writes is audited
+// as the job's rather than an anonymous user's.
+func SystemContext(ctx context.Context, system string) context.Context {
+	return ContextWithActor(ctx, &model.Actor{System: system})
+}
+
+// UserContext builds the context handed to the service layer, carrying the authenticated user,
+// client IP and request ID of the request.
+func UserContext(ctx *fiber.Ctx) context.Context {
+	actor := &model.Actor{
+		IP: 		ctx.IP(),
+		RequestID: 	ctx.Get(fiber.HeaderXRequestID),
+	}
+	if userID, ok := ctx.Locals(LocalsUserID).(int); ok {
+		actor.UserID = &userID
+	}
+	return ContextWithActor(ctx.UserContext(), actor)
+}
+
+
+// StreamContext is UserContext for a body stream writer, which runs after the handler has returned
+// and its context was cancelled. The deadline of the request is kept, call cancel once the writer is done.
+func StreamContext(ctx *fiber.Ctx) (context.Context, context.CancelFunc) {
+	userCtx := UserContext(ctx)
+	if deadline, ok := userCtx.Deadline(); ok {
+		return context.WithDeadline(context.WithoutCancel(userCtx), deadline)
+	}
+	return context.WithCancel(context.WithoutCancel(userCtx))
+}
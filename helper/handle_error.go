@@ -6,6 +6,8 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/go-playground/validator/v10"
+
+	"errors"
 )
 
 func HandleError(ctx *fiber.Ctx, err error) error {
@@ -28,6 +30,19 @@ func HandleError(ctx *fiber.Ctx, err error) error {
 	}
 }
 
+// ErrorHandler is the error handler of the app, it renders an error no handler has rendered,
+// a recovered panic or an unknown route, with the same JSON body as HandleError.
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return ctx.Status(fiberErr.Code).JSON(fiber.Map{
+			"code":    fiberErr.Code,
+			"message": fiberErr.Message,
+		})
+	}
+	return HandleError(ctx, err)
+}
+
 func ValidateUserCreate(userCreateReq *model.UserCreate) []errs.ErrorMessage {
     var errors []errs.ErrorMessage
     validate := validator.New()
@@ -91,3 +106,147 @@ func ValidateProductTypeUpdate(prod *model.ProductTypeUpdate) []errs.ErrorMessag
     }
     return errors
 }
+
+func ValidateProductTypeMove(prod *model.ProductTypeMove) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(prod)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateProductTypeChangeQuery(query *model.ProductTypeChangeQuery) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(query)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateProductTypeBulk(bulkReq *model.ProductTypeBulkRequest) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(bulkReq)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateProductTypeBulkOperation(op *model.ProductTypeBulkOperation) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(op)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateProductTypeImportOptions(opts *model.ProductTypeImportOptions) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(opts)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateAuditLogFilter(filter *model.AuditLogFilter) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(filter)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateWebhookCreate(webhookCreateReq *model.WebhookCreate) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(webhookCreateReq)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateWebhookUpdate(webhookUpdateReq *model.WebhookUpdate) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(webhookUpdateReq)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
+
+func ValidateWebhookDeliveryFilter(filter *model.WebhookDeliveryFilter) []errs.ErrorMessage {
+    var errors []errs.ErrorMessage
+    validate := validator.New()
+    err := validate.Struct(filter)
+    if err != nil {
+        for _, err := range err.(validator.ValidationErrors) {
+            var element errs.ErrorMessage
+            element.FailedField = err.StructNamespace()
+            element.Tag = err.Tag()
+            element.Value = err.Param()
+            errors = append(errors, element)
+        }
+    }
+    return errors
+}
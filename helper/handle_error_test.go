@@ -268,3 +268,301 @@ func TestValidateProductTypeUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateProductTypeMove(t *testing.T) {
+	parentID, negativeID := 1, -1
+	tests := []struct {
+		name     string
+		input    *model.ProductTypeMove
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid move - to root",
+			input: &model.ProductTypeMove{},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Valid move - under parent",
+			input: &model.ProductTypeMove{ParentID: &parentID},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid move - negative ParentID",
+			input: &model.ProductTypeMove{ParentID: &negativeID},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeMove.ParentID", 
+					Tag: "gte", Value: "0",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateProductTypeMove(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateProductTypeBulk(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *model.ProductTypeBulkRequest
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid bulk request",
+			input: &model.ProductTypeBulkRequest{
+				Mode: "transactional",
+				Operations: []model.ProductTypeBulkOperation{{Op: "create", ID: 1, Name: "A"}},
+			},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid bulk request - missing Operations",
+			input: &model.ProductTypeBulkRequest{Mode: "best_effort"},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeBulkRequest.Operations", 
+					Tag: "required", Value: "",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateProductTypeBulk(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateProductTypeBulkOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *model.ProductTypeBulkOperation
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid bulk operation",
+			input: &model.ProductTypeBulkOperation{Op: "delete", ID: 1},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid bulk operation - unknown Op",
+			input: &model.ProductTypeBulkOperation{Op: "upsert", ID: 1},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeBulkOperation.Op", 
+					Tag: "oneof", Value: "create update delete",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateProductTypeBulkOperation(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateProductTypeImportOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *model.ProductTypeImportOptions
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:     "Valid import options",
+			input:    &model.ProductTypeImportOptions{Format: "xlsx", OnConflict: "upsert"},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid import options - unsupported Format",
+			input: &model.ProductTypeImportOptions{Format: "pdf", OnConflict: "skip"},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeImportOptions.Format", 
+					Tag: "oneof", Value: "csv xlsx jsonl",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateProductTypeImportOptions(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateAuditLogFilter(t *testing.T) {
+	actorID, zeroID := 7, 0
+	tests := []struct {
+		name     string
+		input    *model.AuditLogFilter
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid filter - empty",
+			input: &model.AuditLogFilter{},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Valid filter - actor and page",
+			input: &model.AuditLogFilter{ActorID: &actorID, Limit: 500, Offset: 10},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid filter - zero ActorID",
+			input: &model.AuditLogFilter{ActorID: &zeroID},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "AuditLogFilter.ActorID", 
+					Tag: "gt", Value: "0",
+				},
+			},
+		},
+		{
+			name:  "Invalid filter - Limit too large",
+			input: &model.AuditLogFilter{Limit: 501},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "AuditLogFilter.Limit", 
+					Tag: "max", Value: "500",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateAuditLogFilter(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateProductTypeChangeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *model.ProductTypeChangeQuery
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid query - from the beginning",
+			input: &model.ProductTypeChangeQuery{},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Valid query - since and limit",
+			input: &model.ProductTypeChangeQuery{Since: 10, Limit: 1000},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid query - Limit too large",
+			input: &model.ProductTypeChangeQuery{Limit: 1001},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeChangeQuery.Limit", 
+					Tag: "max", Value: "1000",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateProductTypeChangeQuery(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateWebhookCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *model.WebhookCreate
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid input",
+			input: &model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"producttype.created"}, Secret: "0123456789abcdef"},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid input - unknown event",
+			input: &model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"producttype.purged"}, Secret: "0123456789abcdef"},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "WebhookCreate.Events[0]", 
+					Tag: "oneof", Value: "producttype.created producttype.updated producttype.deleted",
+				},
+			},
+		},
+		{
+			name:  "Invalid input - short secret",
+			input: &model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"producttype.created"}, Secret: "short"},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "WebhookCreate.Secret", 
+					Tag: "min", Value: "16",
+				},
+			},
+		},
+		{
+			name:  "Invalid input - not url",
+			input: &model.WebhookCreate{URL: "example", Events: []string{"producttype.created"}, Secret: "0123456789abcdef"},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "WebhookCreate.URL", 
+					Tag: "url", Value: "",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateWebhookCreate(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateWebhookDeliveryFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *model.WebhookDeliveryFilter
+		expected []errs.ErrorMessage
+	}{
+		{
+			name:  "Valid filter - empty",
+			input: &model.WebhookDeliveryFilter{},
+			expected: []errs.ErrorMessage(nil),
+		},
+		{
+			name:  "Invalid filter - unknown status",
+			input: &model.WebhookDeliveryFilter{Status: "sent"},
+			expected: []errs.ErrorMessage{
+				{
+					FailedField: "WebhookDeliveryFilter.Status", 
+					Tag: "oneof", Value: "pending succeeded failed dead",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := helper.ValidateWebhookDeliveryFilter(tc.input)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
 	
+	"crypto/subtle"
 	"strconv"
+	"strings"
 )
 
 func ParamsInt(ctx *fiber.Ctx) (int, error) {
@@ -31,4 +33,11 @@ func CompareHashAndPassword(passwordFromDB, password []byte) error {
 		return errs.NewNotFoundError("Email or Password is incorrect")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// BearerTokenMatches reports whether the request carries token as its Bearer token,
+// an empty token never matches.
+func BearerTokenMatches(ctx *fiber.Ctx, token string) bool {
+	presented := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	return token != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
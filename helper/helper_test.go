@@ -6,10 +6,12 @@ import (
 
 	"github.com/Yoshikrit/fiber-test/helper"
 
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"io"
+	"time"
 )
 
 
@@ -44,3 +46,80 @@ func TestParamsInt(t *testing.T) {
 		assert.JSONEq(t, expectedBody, string(body))
 	})
 }
+
+func TestUserContext(t *testing.T) {
+	app := fiber.New()
+
+	t.Run("test case : user context carries authenticated actor", func(t *testing.T) {
+		app.Get("/actor", func(ctx *fiber.Ctx) error {
+			ctx.Locals(helper.LocalsUserID, 7)
+			actor := helper.ActorFromContext(helper.UserContext(ctx))
+			assert.Equal(t, 7, *actor.UserID)
+			assert.Equal(t, "req-1", actor.RequestID)
+			assert.Equal(t, "0.0.0.0", actor.IP)
+			return nil
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/actor", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "req-1")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("test case : user context without authenticated user", func(t *testing.T) {
+		app.Get("/anonymous", func(ctx *fiber.Ctx) error {
+			actor := helper.ActorFromContext(helper.UserContext(ctx))
+			assert.Nil(t, actor.UserID)
+			return nil
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/anonymous", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestStreamContext(t *testing.T) {
+	app := fiber.New()
+
+	t.Run("test case : stream context outlives handler and keeps deadline", func(t *testing.T) {
+		streamCtxs := make(chan context.Context, 1)
+		app.Get("/stream", func(ctx *fiber.Ctx) error {
+			userCtx, cancel := context.WithTimeout(ctx.UserContext(), time.Minute)
+			defer cancel()
+			ctx.SetUserContext(userCtx)
+
+			streamCtx, _ := helper.StreamContext(ctx)
+			streamCtxs <- streamCtx
+			return nil
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/stream", nil))
+		streamCtx := <-streamCtxs
+		deadline, ok := streamCtx.Deadline()
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.NoError(t, streamCtx.Err())
+		assert.True(t, ok)
+		assert.InDelta(t, time.Minute, time.Until(deadline), float64(time.Second))
+	})
+}
+
+func TestSignWebhook(t *testing.T) {
+	t.Run("test case : sign payload with timestamp", func(t *testing.T) {
+		signature := helper.SignWebhook("0123456789abcdef", 1700000000, []byte(`{"id":"e1"}`))
+
+		assert.Equal(t, "sha256=35709ca2ecce8f3d15c806503243fd398425fec4a2629cd90ef9a7a8e7ce56b3", signature)
+	})
+
+	t.Run("test case : signature changes with timestamp", func(t *testing.T) {
+		first := helper.SignWebhook("0123456789abcdef", 1700000000, []byte(`{"id":"e1"}`))
+		second := helper.SignWebhook("0123456789abcdef", 1700000001, []byte(`{"id":"e1"}`))
+
+		assert.NotEqual(t, first, second)
+	})
+}
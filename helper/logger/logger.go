@@ -3,6 +3,10 @@ package logger
 import (
 	"github.com/rs/zerolog"
     "github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"context"
+	"errors"
 )
 
 func init() {
@@ -10,19 +14,71 @@ func init() {
 	// zerolog.SetGlobalLevel(zerolog.DebugLevel)
 }
 
-func Info(msg string, keysAndValues ...interface{}) {
-	log.Info().Fields(keysAndValues).Msg(msg)
+// SetLevel drops the lines below level, one of trace, debug, info, warn or error, for the whole process.
+// An empty level logs everything.
+func SetLevel(level string) error {
+	if level == "" {
+		zerolog.SetGlobalLevel(zerolog.TraceLevel)
+		return nil
+	}
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(parsed)
+	return nil
 }
 
-func Debug(msg string, keysAndValues ...interface{}) {
-	log.Debug().Fields(keysAndValues).Msg(msg)
+type requestIDKey struct{}
+
+// ContextWithRequestID tags ctx with the ID of the request it serves, every line logged with it carries the ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
 }
 
-func Error(msg interface{}, keysAndValues ...interface{}) {
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func withContext(ctx context.Context, event *zerolog.Event) *zerolog.Event {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		event = event.Str("request_id", requestID)
+	}
+	if ctx != nil {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			event = event.Str("trace_id", spanContext.TraceID().String()).Str("span_id", spanContext.SpanID().String())
+		}
+	}
+	return event
+}
+
+func Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	withContext(ctx, log.Info()).Fields(keysAndValues).Msg(msg)
+}
+
+func Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	withContext(ctx, log.Debug()).Fields(keysAndValues).Msg(msg)
+}
+
+// Error also records the error as an event of the span in ctx, so the trace shows the layer it came from.
+// The status of the span is left to its owner, a 404 is not a failed server span.
+func Error(ctx context.Context, msg interface{}, keysAndValues ...interface{}) {
+	var err error
 	switch v := msg.(type) {
 	case error:
-		log.Error().Fields(keysAndValues).Msg(v.Error())
+		err = v
 	case string:
-		log.Error().Fields(keysAndValues).Msg(v)
+		err = errors.New(v)
+	default:
+		return
+	}
+
+	withContext(ctx, log.Error()).Fields(keysAndValues).Msg(err.Error())
+	if ctx != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
 	}
 }
@@ -0,0 +1,233 @@
+package spreadsheet
+
+import (
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"github.com/goccy/go-json"
+	"github.com/xuri/excelize/v2"
+
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+const (
+	FormatCSV 	= "csv"
+	FormatXLSX 	= "xlsx"
+	FormatJSONL = "jsonl"
+
+	sheetName = "Sheet1"
+)
+
+// Writer writes a header followed by rows, Close must be called to flush the output.
+type Writer interface {
+	WriteHeader(header []string) error
+	WriteRow(row []interface{}) error
+	Close() error
+}
+
+func IsSupported(format string) bool {
+	return format == FormatCSV || format == FormatXLSX || format == FormatJSONL
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter(sheetName)
+		if err != nil {
+			return nil, errs.NewInternalServerError(err.Error())
+		}
+		return &xlsxWriter{out: w, file: file, stream: stream}, nil
+	case FormatJSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, errs.NewBadRequestError("Format " + format + " is not supported")
+	}
+}
+
+// ReadAll reads every row of the file, the first row (or the keys of the first object for jsonl) is the header.
+func ReadAll(format string, r io.Reader) ([]string, [][]string, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatXLSX:
+		return readXLSX(r)
+	case FormatJSONL:
+		return readJSONL(r)
+	default:
+		return nil, nil, errs.NewBadRequestError("Format " + format + " is not supported")
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteHeader(header []string) error {
+	return c.w.Write(header)
+}
+
+func (c *csvWriter) WriteRow(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = fmt.Sprint(value)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxWriter struct {
+	out 	io.Writer
+	file 	*excelize.File
+	stream 	*excelize.StreamWriter
+	row 	int
+}
+
+func (x *xlsxWriter) WriteHeader(header []string) error {
+	values := make([]interface{}, len(header))
+	for i, value := range header {
+		values[i] = value
+	}
+	return x.WriteRow(values)
+}
+
+func (x *xlsxWriter) WriteRow(row []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, row)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+type jsonlWriter struct {
+	w 		*bufio.Writer
+	header 	[]string
+}
+
+func (j *jsonlWriter) WriteHeader(header []string) error {
+	j.header = header
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(row []interface{}) error {
+	object := make(map[string]interface{}, len(row))
+	for i, value := range row {
+		if i < len(j.header) {
+			object[j.header[i]] = value
+		}
+	}
+
+	line, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(line); err != nil {
+		return err
+	}
+	return j.w.WriteByte('\n')
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.w.Flush()
+}
+
+func readCSV(r io.Reader) ([]string, [][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, errs.NewBadRequestError("Invalid csv file: " + err.Error())
+	}
+	if len(records) == 0 {
+		return nil, nil, errs.NewBadRequestError("File is empty")
+	}
+	return records[0], records[1:], nil
+}
+
+func readXLSX(r io.Reader) ([]string, [][]string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, nil, errs.NewBadRequestError("Invalid xlsx file: " + err.Error())
+	}
+	defer file.Close()
+
+	records, err := file.GetRows(file.GetSheetName(0))
+	if err != nil {
+		return nil, nil, errs.NewBadRequestError("Invalid xlsx file: " + err.Error())
+	}
+	if len(records) == 0 {
+		return nil, nil, errs.NewBadRequestError("File is empty")
+	}
+	return records[0], records[1:], nil
+}
+
+func readJSONL(r io.Reader) ([]string, [][]string, error) {
+	var header []string
+	var records [][]string
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var object map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return nil, nil, errs.NewBadRequestError(fmt.Sprintf("Invalid jsonl file at line %d: %s", line, err.Error()))
+		}
+
+		if header == nil {
+			for key := range object {
+				header = append(header, key)
+			}
+		}
+
+		record := make([]string, len(header))
+		for i, key := range header {
+			if value, ok := object[key]; ok && value != nil {
+				record[i] = fmt.Sprint(value)
+			}
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errs.NewBadRequestError("Invalid jsonl file: " + err.Error())
+	}
+	if header == nil {
+		return nil, nil, errs.NewBadRequestError("File is empty")
+	}
+	return header, records, nil
+}
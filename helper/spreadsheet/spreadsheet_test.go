@@ -0,0 +1,58 @@
+package spreadsheet_test
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/helper/spreadsheet"
+
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndReadAll(t *testing.T) {
+	for _, format := range []string{spreadsheet.FormatCSV, spreadsheet.FormatXLSX, spreadsheet.FormatJSONL} {
+		t.Run("test case : round trip " + format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := spreadsheet.NewWriter(format, &buf)
+			assert.NoError(t, err)
+
+			assert.NoError(t, writer.WriteHeader([]string{"prodtype_id", "prodtype_name"}))
+			assert.NoError(t, writer.WriteRow([]interface{}{1, "Food"}))
+			assert.NoError(t, writer.WriteRow([]interface{}{2, "Drink"}))
+			assert.NoError(t, writer.Close())
+
+			header, records, err := spreadsheet.ReadAll(format, &buf)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"prodtype_id", "prodtype_name"}, header)
+			assert.Len(t, records, 2)
+
+			for i, expected := range [][]string{{"1", "Food"}, {"2", "Drink"}} {
+				row := map[string]string{}
+				for j, column := range header {
+					row[column] = records[i][j]
+				}
+				assert.Equal(t, expected[0], row["prodtype_id"])
+				assert.Equal(t, expected[1], row["prodtype_name"])
+			}
+		})
+	}
+}
+
+func TestReadAllFail(t *testing.T) {
+	t.Run("test case : unsupported format", func(t *testing.T) {
+		_, _, err := spreadsheet.ReadAll("pdf", strings.NewReader(""))
+		assert.EqualError(t, err, "Format pdf is not supported")
+	})
+
+	t.Run("test case : empty csv", func(t *testing.T) {
+		_, _, err := spreadsheet.ReadAll(spreadsheet.FormatCSV, strings.NewReader(""))
+		assert.EqualError(t, err, "File is empty")
+	})
+
+	t.Run("test case : invalid jsonl", func(t *testing.T) {
+		_, _, err := spreadsheet.ReadAll(spreadsheet.FormatJSONL, strings.NewReader("{\"prodtype_id\":1}\nnot json\n"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
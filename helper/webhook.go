@@ -0,0 +1,27 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	HeaderWebhookID 		= "X-Webhook-ID"
+	HeaderWebhookEvent 		= "X-Webhook-Event"
+	HeaderWebhookDelivery 	= "X-Webhook-Delivery"
+	HeaderWebhookTimestamp 	= "X-Webhook-Timestamp"
+	HeaderWebhookSignature 	= "X-Webhook-Signature"
+)
+
+// SignWebhook returns the X-Webhook-Signature value for a payload sent at timestamp (unix seconds).
+// The receiver recomputes the HMAC-SHA256 of "<timestamp>.<payload>" with the shared secret and
+// should reject a stale timestamp to stop replays.
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
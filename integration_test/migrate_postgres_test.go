@@ -0,0 +1,121 @@
+package integration_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func tableExists(t *testing.T, db *gorm.DB, table string) bool {
+	var exists bool
+	require.NoError(t, db.Raw("SELECT to_regclass(?) IS NOT NULL", table).Scan(&exists).Error)
+	return exists
+}
+
+func TestMigratePostgres(t *testing.T) {
+	db := startPostgres(t)
+	ctx := context.Background()
+
+	migrations, err := migrate.Load(config.Migrations, config.MigrationDir)
+	require.NoError(t, err)
+	migrator := migrate.NewMigrator(db, migrations)
+	latest := migrate.Latest(migrations)
+
+	t.Run("test case : up applies every migration", func(t *testing.T) {
+		require.NoError(t, migrator.Up(ctx))
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Len(t, statuses, len(migrations))
+		for _, status := range statuses {
+			assert.Equal(t, migrate.StateApplied, status.State, status.Name)
+			assert.NotNil(t, status.AppliedAt)
+		}
+		assert.True(t, tableExists(t, db, "outbox"))
+
+		// nothing is pending, up again is a no-op
+		require.NoError(t, migrator.Up(ctx))
+	})
+
+	t.Run("test case : down rolls back the last migration", func(t *testing.T) {
+		require.NoError(t, migrator.Down(ctx))
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, migrate.StatePending, statuses[len(statuses) - 1].State)
+		assert.False(t, tableExists(t, db, "outbox"))
+		assert.True(t, tableExists(t, db, "webhook"))
+
+		require.NoError(t, migrator.Up(ctx))
+		assert.True(t, tableExists(t, db, "outbox"))
+	})
+
+	t.Run("test case : to rolls back to a version and applies again", func(t *testing.T) {
+		require.NoError(t, migrator.To(ctx, 3))
+		assert.False(t, tableExists(t, db, "audit_log"))
+		assert.True(t, tableExists(t, db, "producttype"))
+
+		require.NoError(t, migrator.To(ctx, 0))
+		assert.False(t, tableExists(t, db, "producttype"))
+		var applied int64
+		require.NoError(t, db.Raw("SELECT COUNT(*) FROM schema_migrations").Scan(&applied).Error)
+		assert.Equal(t, int64(0), applied)
+
+		require.NoError(t, migrator.To(ctx, latest))
+		assert.True(t, tableExists(t, db, "outbox"))
+	})
+
+	t.Run("test case : replicas migrating together wait on the lock", func(t *testing.T) {
+		require.NoError(t, migrator.To(ctx, 0))
+
+		var wg sync.WaitGroup
+		errs := make([]error, 3)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = migrate.NewMigrator(db, migrations).Up(ctx)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.Equal(t, migrate.StateApplied, status.State, status.Name)
+		}
+	})
+
+	t.Run("test case : baseline adopts a schema created by hand", func(t *testing.T) {
+		require.NoError(t, migrator.To(ctx, 0))
+		require.NoError(t, db.Exec(migrations[0].Up).Error)
+		assert.Error(t, migrator.Up(ctx))
+
+		require.NoError(t, migrator.Baseline(ctx, 1))
+		require.NoError(t, migrator.Up(ctx))
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.Equal(t, migrate.StateApplied, status.State, status.Name)
+		}
+	})
+
+	t.Run("test case : a changed script is refused", func(t *testing.T) {
+		require.NoError(t, db.Exec("UPDATE schema_migrations SET checksum = 'changed' WHERE version = 1").Error)
+
+		err := migrator.Up(ctx)
+
+		assert.EqualError(t, err, "migration 1 was changed after it was applied")
+	})
+}
@@ -0,0 +1,124 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxPostgres(t *testing.T) {
+	db := setupPostgresDB(t)
+
+	prodTypeService := service.NewProductTypeServiceImpl(repository.NewProductTypeRepositoryImpl(db, 0), repository.NewTxManagerImpl(db), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+	eventSink := testutils.NewEventSinkMock()
+	outboxService := service.NewOutboxServiceImpl(repository.NewOutboxRepositoryImpl(db), 2, time.Millisecond, eventSink)
+	ctx := context.Background()
+
+	t.Run("test case : failed write leaves no event", func(t *testing.T) {
+		parentID := 999
+		err := prodTypeService.Create(ctx, &model.ProductTypeCreate{ID: 40, Name: "Juice", ParentID: &parentID})
+		require.Error(t, err)
+
+		var count int64
+		require.NoError(t, db.Model(&model.OutboxEventEntity{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("test case : events are relayed in order and retried after a failure", func(t *testing.T) {
+		require.NoError(t, prodTypeService.Create(ctx, &model.ProductTypeCreate{ID: 41, Name: "Soda"}))
+		require.NoError(t, prodTypeService.Create(ctx, &model.ProductTypeCreate{ID: 42, Name: "Milk"}))
+		require.NoError(t, prodTypeService.Update(ctx, 41, &model.ProductTypeUpdate{Name: "Cola"}))
+
+		eventSink.FailAggregates["41"] = errors.New("unavailable")
+		relayed, err := outboxService.Relay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, relayed)
+
+		var failed model.OutboxEventEntity
+		require.NoError(t, db.Where("outbox_aggregate_id = ?", "41").Order("outbox_id").First(&failed).Error)
+		assert.Equal(t, 1, failed.Attempts)
+		assert.Equal(t, "mock: unavailable", failed.LastError)
+
+		delete(eventSink.FailAggregates, "41")
+		time.Sleep(10 * time.Millisecond)
+		relayed, err = outboxService.Relay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, relayed)
+
+		require.Len(t, eventSink.Events, 3)
+		assert.Equal(t, "42", eventSink.Events[0].AggregateID)
+		assert.Equal(t, []string{model.ProductTypeEventCreated, model.ProductTypeEventUpdated}, []string{eventSink.Events[1].Type, eventSink.Events[2].Type})
+		assert.JSONEq(t, `{"prodtype_id":41,"prodtype_name":"Cola"}`, string(eventSink.Events[2].Payload))
+	})
+
+	t.Run("test case : relays of several instances publish each event once", func(t *testing.T) {
+		for id := 43; id < 53; id++ {
+			require.NoError(t, prodTypeService.Create(ctx, &model.ProductTypeCreate{ID: id, Name: "Tea"}))
+		}
+		published := len(eventSink.IDs())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					if _, err := outboxService.Relay(ctx); err != nil {
+						return
+					}
+					var unpublished int64
+					db.Model(&model.OutboxEventEntity{}).Where("outbox_published_at IS NULL").Count(&unpublished)
+					if unpublished == 0 {
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		ids := eventSink.IDs()[published:]
+		assert.Len(t, ids, 10)
+		for i := 1; i < len(ids); i++ {
+			assert.Greater(t, ids[i], ids[i-1])
+		}
+	})
+	t.Run("test case : tails of several instances feed their brokers the same event ids", func(t *testing.T) {
+		brokers := []broker.Broker{broker.NewBrokerImpl(10, 10), broker.NewBrokerImpl(10, 10)}
+		var tails []service.OutboxTailService
+		for _, eventBroker := range brokers {
+			tail := service.NewOutboxTailServiceImpl(repository.NewOutboxRepositoryImpl(db), model.AuditResourceProductType, eventBroker)
+			_, err := tail.Tail(ctx)
+			require.NoError(t, err)
+			tails = append(tails, tail)
+		}
+		live := brokers[0].Subscribe("")
+		defer live.Close()
+
+		require.NoError(t, prodTypeService.Create(ctx, &model.ProductTypeCreate{ID: 60, Name: "Coffee"}))
+		require.NoError(t, prodTypeService.Update(ctx, 60, &model.ProductTypeUpdate{Name: "Espresso"}))
+		for _, tail := range tails {
+			published, err := tail.Tail(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 2, published)
+		}
+
+		seen := <-live.Events
+		resumed := brokers[1].Subscribe(seen.ID)
+		defer resumed.Close()
+
+		require.False(t, resumed.Reset)
+		require.Len(t, resumed.Replay, 1)
+		assert.Equal(t, model.ProductTypeEventUpdated, resumed.Replay[0].Type)
+	})
+}
@@ -0,0 +1,64 @@
+package integration_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductTypeChangesPostgres(t *testing.T) {
+	db := setupPostgresDB(t)
+
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, repository.NewTxManagerImpl(db), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+	ctx := context.Background()
+
+	start, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{})
+	require.NoError(t, err)
+	for start.HasMore {
+		start, err = service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Since: start.Cursor})
+		require.NoError(t, err)
+	}
+
+	t.Run("test case : delta since cursor with tombstone", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &model.ProductTypeCreate{ID: 20, Name: "Juice"}))
+		require.NoError(t, service.Update(ctx, 20, &model.ProductTypeUpdate{Name: "Orange Juice"}))
+		require.NoError(t, service.Delete(ctx, 20, ""))
+
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Since: start.Cursor})
+
+		assert.NoError(t, err)
+		assert.Len(t, changeFeed.Changes, 3)
+		assert.Equal(t, []string{"insert", "update", "delete"}, []string{changeFeed.Changes[0].Op, changeFeed.Changes[1].Op, changeFeed.Changes[2].Op})
+		assert.Equal(t, changeFeed.Changes[2].Seq, changeFeed.Cursor)
+		start = changeFeed
+	})
+
+	t.Run("test case : concurrent writers appear in sequence order", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				assert.NoError(t, service.Create(ctx, &model.ProductTypeCreate{ID: id, Name: "Concurrent"}))
+			}(100 + i)
+		}
+		wg.Wait()
+
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Since: start.Cursor})
+
+		assert.NoError(t, err)
+		assert.Len(t, changeFeed.Changes, 10)
+		for i := 1; i < len(changeFeed.Changes); i++ {
+			assert.Greater(t, changeFeed.Changes[i].Seq, changeFeed.Changes[i-1].Seq)
+		}
+	})
+}
@@ -33,8 +33,8 @@ func TestCreateHandlerServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db, 0)
+	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository, testutils.NewTxManagerMock(prodTypeRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -68,8 +68,14 @@ func TestCreateHandlerServiceRepository(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(`INSERT INTO "producttype"`).
-			WithArgs("A", 1).
+			WithArgs("A", nil, nil, 1).
 			WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("create", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		req := httptest.NewRequest(fiber.MethodPost, endpointPath, strings.NewReader(string(prodTypeReqJSON)))
@@ -162,7 +168,7 @@ func TestCreateHandlerServiceRepository(t *testing.T) {
 	t.Run("test case : create fail from repository", func(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectQuery(`INSERT INTO "producttype"`).
-			WithArgs("A", 1).
+			WithArgs("A", nil, nil, 1).
 			WillReturnError(errs.NewInternalServerError(""))
 		mock.ExpectRollback()
 
@@ -187,8 +193,8 @@ func TestFindAllHandlerServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db, 0)
+	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository, testutils.NewTxManagerMock(prodTypeRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -248,8 +254,8 @@ func TestFindByIDHandlerServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db, 0)
+	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository, testutils.NewTxManagerMock(prodTypeRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -332,8 +338,8 @@ func TestUpdateHandlerServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db, 0)
+	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository, testutils.NewTxManagerMock(prodTypeRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -358,6 +364,12 @@ func TestUpdateHandlerServiceRepository(t *testing.T) {
 		mock.ExpectExec(`UPDATE`).
 			WithArgs(1, "B", 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("update", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		req := httptest.NewRequest(fiber.MethodPut, endpointPath + "/1", strings.NewReader(string(prodTypeReqJSON)))
@@ -464,8 +476,8 @@ func TestDeleteHandlerServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db, 0)
+	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository, testutils.NewTxManagerMock(prodTypeRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -475,10 +487,19 @@ func TestDeleteHandlerServiceRepository(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A")
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE prodtype_parent_code`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"ID", "Name"}))
 
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE").
-			WithArgs(1).
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("delete", sqlmock.AnyArg(), 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -528,8 +549,8 @@ func TestDeleteHandlerServiceRepository(t *testing.T) {
 			WillReturnError(errs.NewInternalServerError(""))
 
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE").
-			WithArgs(1).
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -553,8 +574,8 @@ func TestCountHandlerServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db, 0)
+	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository, testutils.NewTxManagerMock(prodTypeRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
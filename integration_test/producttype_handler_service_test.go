@@ -9,6 +9,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/Yoshikrit/fiber-test/handler"
 	"github.com/Yoshikrit/fiber-test/service"
@@ -19,7 +20,7 @@ import (
 
 func TestCreateHandlerService(t *testing.T) {
 	mockRepository := testutils.NewProductTypeRepositoryMock()
-	prodTypeService := service.NewProductTypeServiceImpl(mockRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -33,8 +34,9 @@ func TestCreateHandlerService(t *testing.T) {
 	prodTypeReqJSON, _ := json.Marshal(prodTypeReqMock)
 
 	t.Run("test case : create success", func(t *testing.T) {
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, nil)
-		mockRepository.On("Save", &model.ProductTypeEntity{ID:1,Name:"A"}).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"A"}).Return(nil)
 
 		req := httptest.NewRequest(fiber.MethodPost, endpointPath, strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -67,8 +69,9 @@ func TestCreateHandlerService(t *testing.T) {
 
 	t.Run("test case : create fail from repository", func(t *testing.T) {
 		mockRepository.ExpectedCalls = nil
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, nil)
-		mockRepository.On("Save", &model.ProductTypeEntity{ID:1,Name:"A"}).Return(errs.NewInternalServerError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"A"}).Return(errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodPost, endpointPath, strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -87,7 +90,7 @@ func TestCreateHandlerService(t *testing.T) {
 
 func TestFindAllHandlerService(t *testing.T) {
 	mockRepository := testutils.NewProductTypeRepositoryMock()
-	prodTypeService := service.NewProductTypeServiceImpl(mockRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -107,7 +110,7 @@ func TestFindAllHandlerService(t *testing.T) {
 	prodTypesResJSON, _ := json.Marshal(prodTypesResMock)
 
 	t.Run("test case : find all success", func(t *testing.T) {
-		mockRepository.On("FindAll").Return([]model.ProductTypeEntity{{ID:1,Name:"A",},{ID:2,Name:"B",}}, nil)
+		mockRepository.On("FindAll", mock.Anything).Return([]model.ProductTypeEntity{{ID:1,Name:"A",},{ID:2,Name:"B",}}, nil)
 
 		req := httptest.NewRequest(fiber.MethodGet, endpointPath, nil)
 
@@ -124,7 +127,7 @@ func TestFindAllHandlerService(t *testing.T) {
 	
 	t.Run("test case : find all fail from repository", func(t *testing.T) {
 		mockRepository.ExpectedCalls = nil
-		mockRepository.On("FindAll").Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+		mockRepository.On("FindAll", mock.Anything).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodGet, endpointPath, nil)
 
@@ -142,7 +145,7 @@ func TestFindAllHandlerService(t *testing.T) {
 
 func TestFindByIDHandlerService(t *testing.T) {
 	mockRepository := testutils.NewProductTypeRepositoryMock()
-	prodTypeService := service.NewProductTypeServiceImpl(mockRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -156,7 +159,7 @@ func TestFindByIDHandlerService(t *testing.T) {
 	prodTypeResJSON, _ := json.Marshal(prodTypeResMock)
 
 	t.Run("test case : find by id success", func(t *testing.T) {
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
 
 		req := httptest.NewRequest(fiber.MethodGet, endpointPath + "/1", nil)
 
@@ -186,7 +189,7 @@ func TestFindByIDHandlerService(t *testing.T) {
 	
 	t.Run("test case : find by id fail from repository", func(t *testing.T) {
 		mockRepository.ExpectedCalls = nil
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodGet, endpointPath + "/1", nil)
 
@@ -204,7 +207,7 @@ func TestFindByIDHandlerService(t *testing.T) {
 
 func TestUpdateHandlerService(t *testing.T) {
 	mockRepository := testutils.NewProductTypeRepositoryMock()
-	prodTypeService := service.NewProductTypeServiceImpl(mockRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
@@ -217,8 +220,8 @@ func TestUpdateHandlerService(t *testing.T) {
 	prodTypeReqJSON, _ := json.Marshal(prodTypeReqMock)
 
 	t.Run("test case : update success", func(t *testing.T) {
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Update", &model.ProductTypeEntity{ID:1,Name:"B"}).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Update", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"B"}).Return(nil)
 
 		req := httptest.NewRequest(fiber.MethodPut, endpointPath + "/1", strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -265,8 +268,8 @@ func TestUpdateHandlerService(t *testing.T) {
 	
 	t.Run("test case : update fail from repository", func(t *testing.T) {
 		mockRepository.ExpectedCalls = nil
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Update", &model.ProductTypeEntity{ID:1,Name:"B"}).Return(errs.NewInternalServerError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Update", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"B"}).Return(errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodPut, endpointPath + "/1", strings.NewReader(string(prodTypeReqJSON)))
 		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
@@ -285,15 +288,16 @@ func TestUpdateHandlerService(t *testing.T) {
 
 func TestDeleteHandlerService(t *testing.T) {
 	mockRepository := testutils.NewProductTypeRepositoryMock()
-	prodTypeService := service.NewProductTypeServiceImpl(mockRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
 	app.Delete(endpointPath  + "/:id", prodTypeHandler.Delete)
 
 	t.Run("test case : delete success", func(t *testing.T) {
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Delete", 1).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Delete", mock.Anything, 1).Return(nil)
 
 		req := httptest.NewRequest(fiber.MethodDelete, endpointPath + "/1", nil)
 
@@ -323,8 +327,9 @@ func TestDeleteHandlerService(t *testing.T) {
 	
 	t.Run("test case : delete fail from repository", func(t *testing.T) {
 		mockRepository.ExpectedCalls = nil
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Delete", 1).Return(errs.NewInternalServerError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Delete", mock.Anything, 1).Return(errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodDelete, endpointPath + "/1", nil)
 
@@ -342,14 +347,14 @@ func TestDeleteHandlerService(t *testing.T) {
 
 func TestCountHandlerService(t *testing.T) {
 	mockRepository := testutils.NewProductTypeRepositoryMock()
-	prodTypeService := service.NewProductTypeServiceImpl(mockRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 	
 	app := fiber.New()
 	app.Get(endpointPath, prodTypeHandler.Count)
 
 	t.Run("test case : get count success", func(t *testing.T) {
-		mockRepository.On("Count").Return(int64(5), nil)
+		mockRepository.On("Count", mock.Anything).Return(int64(5), nil)
 
 		req := httptest.NewRequest(fiber.MethodGet, endpointPath, nil)
 
@@ -366,7 +371,7 @@ func TestCountHandlerService(t *testing.T) {
 	
 	t.Run("test case : get count fail from repository", func(t *testing.T) {
 		mockRepository.ExpectedCalls = nil
-		mockRepository.On("Count").Return(int64(0), errs.NewInternalServerError(""))
+		mockRepository.On("Count", mock.Anything).Return(int64(0), errs.NewInternalServerError(""))
 
 		req := httptest.NewRequest(fiber.MethodGet, endpointPath, nil)
 
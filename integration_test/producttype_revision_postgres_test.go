@@ -0,0 +1,69 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductTypeRevisionPostgres(t *testing.T) {
+	db := setupPostgresDB(t)
+
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, repository.NewTxManagerImpl(db), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+	ctx := context.Background()
+
+	require.NoError(t, service.Create(ctx, &model.ProductTypeCreate{ID: 10, Name: "Tea"}))
+	beforeRename := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, service.Update(ctx, 10, &model.ProductTypeUpdate{Name: "Green Tea"}))
+	_, err := service.Bulk(ctx, &model.ProductTypeBulkRequest{
+		Mode: model.BulkModeTransactional,
+		Operations: []model.ProductTypeBulkOperation{{Op: model.BulkOpUpdate, ID: 10, Name: "Matcha"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("test case : every write path records a revision", func(t *testing.T) {
+		revisions, err := service.FindRevisions(context.Background(), 10)
+
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 3)
+		assert.Equal(t, []string{"Matcha", "Green Tea", "Tea"}, []string{revisions[0].Name, revisions[1].Name, revisions[2].Name})
+	})
+
+	t.Run("test case : seeded product types start at revision 1", func(t *testing.T) {
+		revisions, err := service.FindRevisions(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 1)
+		assert.Equal(t, 1, revisions[0].Revision)
+	})
+
+	t.Run("test case : find by id as of returns the historical view", func(t *testing.T) {
+		prodType, err := service.FindByIDAsOf(context.Background(), 10, beforeRename)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Tea", prodType.Name)
+	})
+
+	t.Run("test case : revert writes a new revision", func(t *testing.T) {
+		require.NoError(t, service.Revert(ctx, 10, 1))
+
+		prodType, err := service.FindByID(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Tea", prodType.Name)
+
+		revisions, err := service.FindRevisions(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, revisions[0].Revision)
+		assert.Equal(t, model.RevisionOpRevert, revisions[0].Operation)
+	})
+}
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"context"
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/repository"
 	"github.com/Yoshikrit/fiber-test/service"
@@ -23,19 +24,25 @@ func TestCreateServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	repo := repository.NewProductTypeRepositoryImpl(db)
-	service := service.NewProductTypeServiceImpl(repo)
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, testutils.NewTxManagerMock(repo), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 
 	t.Run("test case : create success", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"Id", "Name"}).AddRow(1, "A")
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(`INSERT INTO "producttype"`).
-			WithArgs("A", 1).
+			WithArgs("A", nil, nil, 1).
 			WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("create", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:"A"})
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A"})
 
 		assert.NoError(t, err)
 	})
@@ -51,7 +58,7 @@ func TestCreateServiceRepository(t *testing.T) {
 			  	},
 			},
 		}
-		err := service.Create(&model.ProductTypeCreate{ID:0,Name:"A"})
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:0,Name:"A"})
 
 		expectedBody := valError
 		assert.Error(t, err)
@@ -69,7 +76,7 @@ func TestCreateServiceRepository(t *testing.T) {
 			  	},
 			},
 		}
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:""})
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:""})
 
 		expectedBody := valError
 		assert.Error(t, err)
@@ -92,7 +99,7 @@ func TestCreateServiceRepository(t *testing.T) {
 			  	},
 			},
 		}
-		err := service.Create(&model.ProductTypeCreate{ID:0,Name:""})
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:0,Name:""})
 
 		expectedBody := valError
 		assert.Error(t, err)
@@ -104,7 +111,7 @@ func TestCreateServiceRepository(t *testing.T) {
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnRows(rows)
 
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:"A"})
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A"})
 
 		expectedBody := errs.NewConflictError("ProductType with this ID already exists")
 		assert.Error(t, err)
@@ -114,11 +121,11 @@ func TestCreateServiceRepository(t *testing.T) {
 	t.Run("test case : create fail from repository", func(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectQuery(`INSERT INTO "producttype"`).
-        	WithArgs("A", 1).
+        	WithArgs("A", nil, nil, 1).
         	WillReturnError(errs.NewInternalServerError(""))
     	mock.ExpectRollback()
 
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:"A"})
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A"})
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -133,15 +140,15 @@ func TestFindAllServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	repo := repository.NewProductTypeRepositoryImpl(db)
-	service := service.NewProductTypeServiceImpl(repo)
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, testutils.NewTxManagerMock(repo), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 
 	t.Run("test case : find all success", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A").AddRow(2, "B")
 		mock.ExpectQuery(`SELECT \* FROM "producttype"`).
 			WillReturnRows(rows)
 
-		prodTypeRes, err := service.FindAll()
+		prodTypeRes, err := service.FindAll(context.Background())
 
 		expectedBody := []model.ProductType{{ID:1,Name:"A",},{ID:2,Name:"B",}}
 		assert.NoError(t, err)
@@ -153,7 +160,7 @@ func TestFindAllServiceRepository(t *testing.T) {
 		mock.ExpectQuery(`SELECT \* FROM "producttype"`).
 			WillReturnError(errs.NewInternalServerError(""))
 
-		prodTypesRes, err := service.FindAll()
+		prodTypesRes, err := service.FindAll(context.Background())
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -169,15 +176,15 @@ func TestFindByIDServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	repo := repository.NewProductTypeRepositoryImpl(db)
-	service := service.NewProductTypeServiceImpl(repo)
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, testutils.NewTxManagerMock(repo), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 
 	t.Run("test case : find by ID success", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A")
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnRows(rows)
 
-		prodTypeRes, err := service.FindByID(1)
+		prodTypeRes, err := service.FindByID(context.Background(), 1)
 
 		expectedBody := &model.ProductType{ID:1,Name:"A"}
 		assert.NoError(t, err)
@@ -188,7 +195,7 @@ func TestFindByIDServiceRepository(t *testing.T) {
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnError(gorm.ErrRecordNotFound)
 
-		prodTypesRes, err := service.FindByID(1)
+		prodTypesRes, err := service.FindByID(context.Background(), 1)
 
 		expectedBody := errs.NewNotFoundError(recordNotFound)
 		assert.Error(t, err)
@@ -201,7 +208,7 @@ func TestFindByIDServiceRepository(t *testing.T) {
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnError(errs.NewInternalServerError(""))
 
-		prodTypesRes, err := service.FindByID(1)
+		prodTypesRes, err := service.FindByID(context.Background(), 1)
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -217,8 +224,8 @@ func TestUpdateServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	repo := repository.NewProductTypeRepositoryImpl(db)
-	service := service.NewProductTypeServiceImpl(repo)
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, testutils.NewTxManagerMock(repo), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 
 	t.Run("test case : update success", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A")
@@ -229,9 +236,15 @@ func TestUpdateServiceRepository(t *testing.T) {
 		mock.ExpectExec(`UPDATE`).
 			WithArgs(1, "B", 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("update", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := service.Update(1, &model.ProductTypeUpdate{Name:"B"})
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:"B"})
 
 		assert.NoError(t, err)
 	})
@@ -248,7 +261,7 @@ func TestUpdateServiceRepository(t *testing.T) {
 			},
 		}
 
-		err := service.Update(1, &model.ProductTypeUpdate{Name:""})
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:""})
 
 		expectedBody := errs.ValErrorResponse(valError)
 		assert.Error(t, err)
@@ -259,7 +272,7 @@ func TestUpdateServiceRepository(t *testing.T) {
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnError(gorm.ErrRecordNotFound)
 
-		err := service.Update(1, &model.ProductTypeUpdate{Name:"B"})
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:"B"})
 
 		expectedBody := errs.NewNotFoundError(recordNotFound)
 		assert.Error(t, err)
@@ -277,7 +290,7 @@ func TestUpdateServiceRepository(t *testing.T) {
         	WillReturnError(errs.NewInternalServerError(""))
     	mock.ExpectRollback()
 		
-		err := service.Update(1, &model.ProductTypeUpdate{Name:"B"})
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:"B"})
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -292,21 +305,30 @@ func TestDeleteServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	repo := repository.NewProductTypeRepositoryImpl(db)
-	service := service.NewProductTypeServiceImpl(repo)
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, testutils.NewTxManagerMock(repo), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 
 	t.Run("test case : delete success", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A")
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE prodtype_parent_code`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"ID", "Name"}))
 
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE").
-    		WithArgs(1).
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+    		WithArgs(sqlmock.AnyArg(), 1).
     		WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("delete", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := service.Delete(1)
+		err := service.Delete(context.Background(), 1, "")
 
 		assert.NoError(t, err)
 	})
@@ -315,7 +337,7 @@ func TestDeleteServiceRepository(t *testing.T) {
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnError(gorm.ErrRecordNotFound)
 			
-		err := service.Delete(1)
+		err := service.Delete(context.Background(), 1, "")
 
 		expectedBody := errs.NewNotFoundError(recordNotFound)
 		assert.Error(t, err)
@@ -327,12 +349,12 @@ func TestDeleteServiceRepository(t *testing.T) {
 			WillReturnError(errs.NewInternalServerError(""))
 
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE").
-			WithArgs(1).
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := service.Delete(1)
+		err := service.Delete(context.Background(), 1, "")
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -347,14 +369,14 @@ func TestCountServiceRepository(t *testing.T) {
 		sqlDB.Close()
 	}()
 
-	repo := repository.NewProductTypeRepositoryImpl(db)
-	service := service.NewProductTypeServiceImpl(repo)
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, testutils.NewTxManagerMock(repo), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
 
 	t.Run("test case : getcount success", func(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "producttype"`)).
       		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		count, err := service.Count()
+		count, err := service.Count(context.Background())
 
 		expectedBody := int64(1)
 		assert.NoError(t, err)
@@ -366,7 +388,7 @@ func TestCountServiceRepository(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "producttype"`)).
 			WillReturnError(errs.NewInternalServerError(""))
 
-		count, err := service.Count()
+		count, err := service.Count(context.Background())
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -0,0 +1,145 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/testutils"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupPostgresDB starts a throwaway Postgres, applies every migration and returns
+// a gorm connection to it. The test is skipped when Docker is not available.
+func setupPostgresDB(t *testing.T) *gorm.DB {
+	db := startPostgres(t)
+
+	migrations, err := migrate.Load(config.Migrations, config.MigrationDir)
+	require.NoError(t, err)
+	require.NoError(t, migrate.NewMigrator(db, migrations).Up(context.Background()))
+
+	return db
+}
+
+// startPostgres starts a throwaway Postgres without any schema.
+func startPostgres(t *testing.T) *gorm.DB {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:latest",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "postgres",
+			"POSTGRES_PASSWORD": "postgres",
+			"POSTGRES_DB":       "postgres",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Skipf("Postgres container is not available: %v", err)
+	}
+	t.Cleanup(func() { container.Terminate(ctx) })
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "5432")
+	require.NoError(t, err)
+
+	dsn := fmt.Sprintf("user=postgres password=postgres dbname=postgres sslmode=disable host=%s port=%s", host, port.Port())
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	return db
+}
+
+func TestProductTypeTreePostgres(t *testing.T) {
+	db := setupPostgresDB(t)
+
+	repo := repository.NewProductTypeRepositoryImpl(db, 0)
+	service := service.NewProductTypeServiceImpl(repo, repository.NewTxManagerImpl(db), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+
+	food, snack, chips, drink := 1, 3, 10, 2
+	require.NoError(t, service.Move(context.Background(), snack, &model.ProductTypeMove{ParentID: &food}))
+	require.NoError(t, service.Create(context.Background(), &model.ProductTypeCreate{ID: chips, Name: "Chips", ParentID: &snack}))
+	require.NoError(t, service.Create(context.Background(), &model.ProductTypeCreate{ID: 11, Name: "Nuts", ParentID: &snack}))
+
+	t.Run("test case : find ancestors with recursive cte", func(t *testing.T) {
+		ancestors, err := service.FindAncestors(context.Background(), chips)
+
+		expectedBody := []model.ProductType{{ID: food, Name: "Food"}, {ID: snack, Name: "Snack", ParentID: &food}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, ancestors)
+	})
+
+	t.Run("test case : find tree", func(t *testing.T) {
+		tree, err := service.FindTree(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, tree, 2)
+		for _, root := range tree {
+			if root.ID == food {
+				assert.Len(t, root.Children, 1)
+				assert.Len(t, root.Children[0].Children, 2)
+			}
+		}
+	})
+
+	t.Run("test case : move fail under descendant", func(t *testing.T) {
+		err := service.Move(context.Background(), food, &model.ProductTypeMove{ParentID: &chips})
+
+		expectedBody := errs.NewConflictError("ProductType can not be moved under its descendant")
+		assert.Equal(t, expectedBody, err)
+	})
+
+	t.Run("test case : delete reject with children", func(t *testing.T) {
+		err := service.Delete(context.Background(), snack, model.DeleteChildrenReject)
+
+		expectedBody := errs.NewConflictError("ProductType has children")
+		assert.Equal(t, expectedBody, err)
+	})
+
+	t.Run("test case : delete reparent moves children up", func(t *testing.T) {
+		require.NoError(t, service.Delete(context.Background(), snack, model.DeleteChildrenReparent))
+
+		children, err := service.FindChildren(context.Background(), food)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductType{
+			{ID: chips, Name: "Chips", ParentID: &food},
+			{ID: 11, Name: "Nuts", ParentID: &food},
+		}, children)
+	})
+
+	t.Run("test case : delete cascade removes subtree", func(t *testing.T) {
+		require.NoError(t, service.Delete(context.Background(), food, model.DeleteChildrenCascade))
+
+		count, err := service.Count(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		_, err = service.FindByID(context.Background(), drink)
+		assert.NoError(t, err)
+	})
+}
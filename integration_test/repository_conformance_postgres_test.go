@@ -0,0 +1,29 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/repository/conformance"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The Postgres run of the suite the memory and SQLite repositories are checked with, every case
+// starts from the seeded schema again by migrating down and up.
+func TestRepositoryConformancePostgres(t *testing.T) {
+	db := startPostgres(t)
+	migrations, err := migrate.Load(config.Migrations, config.MigrationDir)
+	require.NoError(t, err)
+	migrator := migrate.NewMigrator(db, migrations)
+
+	conformance.Run(t, func(t *testing.T) *repository.Store {
+		ctx := context.Background()
+		require.NoError(t, migrator.To(ctx, 0))
+		require.NoError(t, migrator.Up(ctx))
+		return repository.NewGormStore(db, 0)
+	})
+}
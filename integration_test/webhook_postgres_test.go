@@ -0,0 +1,91 @@
+package integration_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/sink"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPostgres(t *testing.T) {
+	db := setupPostgresDB(t)
+
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	signatures := make(chan bool, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(helper.HeaderWebhookTimestamp), 10, 64)
+		signatures <- r.Header.Get(helper.HeaderWebhookSignature) == helper.SignWebhook("0123456789abcdef", timestamp, body)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer receiver.Close()
+
+	webhookService := service.NewWebhookServiceImpl(repository.NewWebhookRepositoryImpl(db, 0), time.Second, 2, time.Millisecond)
+	prodTypeService := service.NewProductTypeServiceImpl(repository.NewProductTypeRepositoryImpl(db, 0), repository.NewTxManagerImpl(db), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+	outboxService := service.NewOutboxServiceImpl(repository.NewOutboxRepositoryImpl(db), 0, 0, sink.NewWebhookSink(webhookService))
+	ctx := context.Background()
+
+	webhook, err := webhookService.Create(context.Background(), &model.WebhookCreate{URL: receiver.URL, Events: []string{"producttype.created"}, Secret: "0123456789abcdef"})
+	require.NoError(t, err)
+
+	t.Run("test case : write is delivered signed after retries and dead letter", func(t *testing.T) {
+		require.NoError(t, prodTypeService.Create(ctx, &model.ProductTypeCreate{ID: 30, Name: "Tea"}))
+		require.NoError(t, prodTypeService.Update(ctx, 30, &model.ProductTypeUpdate{Name: "Green Tea"}))
+		relayed, err := outboxService.Relay(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, relayed)
+
+		_, err = webhookService.DeliverDue(ctx)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+		_, err = webhookService.DeliverDue(ctx)
+		require.NoError(t, err)
+
+		deliveryPage, err := webhookService.FindDeliveries(context.Background(), webhook.ID, &model.WebhookDeliveryFilter{})
+		require.NoError(t, err)
+		require.Len(t, deliveryPage.Items, 1)
+		assert.Equal(t, model.WebhookDeliveryDead, deliveryPage.Items[0].Status)
+		assert.Equal(t, 2, deliveryPage.Items[0].Attempts)
+		assert.True(t, <-signatures)
+
+		status.Store(http.StatusOK)
+		require.NoError(t, webhookService.Redeliver(context.Background(), webhook.ID, deliveryPage.Items[0].ID))
+		delivered, err := webhookService.DeliverDue(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("test case : event handed over again is queued once", func(t *testing.T) {
+		require.NoError(t, webhookService.Enqueue(ctx, "9001", "producttype.created", map[string]int{"prodtype_id": 31}))
+		require.NoError(t, webhookService.Enqueue(ctx, "9001", "producttype.created", map[string]int{"prodtype_id": 31}))
+
+		deliveryPage, err := webhookService.FindDeliveries(context.Background(), webhook.ID, &model.WebhookDeliveryFilter{})
+
+		require.NoError(t, err)
+		assert.Len(t, deliveryPage.Items, 2)
+	})
+
+	t.Run("test case : deleting webhook removes its deliveries", func(t *testing.T) {
+		require.NoError(t, webhookService.Delete(context.Background(), webhook.ID))
+
+		_, err := webhookService.FindDeliveries(context.Background(), webhook.ID, &model.WebhookDeliveryFilter{})
+
+		assert.Error(t, err)
+	})
+}
@@ -1,13 +1,9 @@
 package main
 
 import (
-	"github.com/Yoshikrit/fiber-test/router"
-	"github.com/Yoshikrit/fiber-test/config"
-	"github.com/Yoshikrit/fiber-test/middleware"
-	// "github.com/Yoshikrit/fiber-test/model"
-	
-	"github.com/gofiber/fiber/v2"
-	"github.com/goccy/go-json"
+	"os"
+
+	"github.com/Yoshikrit/fiber-test/cmd"
 )
 
 // @title ProductType API for Fiber-Test
@@ -31,38 +27,7 @@ import (
 // @name Authorization
 // @description "Type 'Bearer' followed by a space and your JWT token."
 func main() {
-	app := fiber.New(fiber.Config{
-		JSONEncoder: json.Marshal,
-		JSONDecoder: json.Unmarshal,
-	})
-	
-	//config
-	err := config.LoadConfig()
-	if err != nil {
-		panic(err)
-	}
-
-	configData, err := config.GetConfig()
-	if err != nil {
-		panic(err)
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
 	}
-
-	//Database
-	db := config.ConnectionDB(&configData)
-	// db.AutoMigrate(&model.ProductTypeEntity{}, &models.UserEntity{}, &models.RoleEntity{}, &models.OauthEntity{})
-
-	//Routes
-	router.NewRouter(app, db)
-
-	//middleware
-	app.Use(
-		middleware.Cors(), 
-		middleware.Recover(), 
-		middleware.Logger(), 
-		middleware.Health(),
-		middleware.Limiter(),
-		middleware.Helmet(),
-	)
-
-	app.Listen(":" +  configData.ServerPort)
-}
\ No newline at end of file
+}
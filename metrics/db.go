@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"context"
+	"database/sql"
+	"math"
+	"time"
+)
+
+const activeSessionsTimeout = 5 * time.Second
+
+// RegisterDBStats exposes the connection pool gauges of sqlDB as go_sql_* labelled with dbName.
+func RegisterDBStats(sqlDB *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
+
+// RegisterActiveSessions exposes the number of stored token pairs, counted on every scrape
+// so it stays right across restarts and replicas. A failed count is reported as NaN.
+func RegisterActiveSessions(oauthRepo repository.OauthRepository) error {
+	return Registry.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: 	namespace,
+		Name: 		"auth_active_sessions",
+		Help: 		"Number of sessions with a stored token pair.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), activeSessionsTimeout)
+		defer cancel()
+
+		count, err := oauthRepo.Count(ctx)
+		if err != nil {
+			logger.Error(ctx, err)
+			return math.NaN()
+		}
+		return float64(count)
+	}))
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"gorm.io/gorm"
+
+	"time"
+)
+
+const gormStartKey = "metrics:start"
+
+type GormPlugin struct{}
+
+// NewGormPlugin times every query run through the db it is registered on with db.Use.
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	registrations := []func() error{
+		func() error { return db.Callback().Create().Before("*").Register("metrics:before_create", before) },
+		func() error { return db.Callback().Create().After("*").Register("metrics:after_create", after("create")) },
+		func() error { return db.Callback().Query().Before("*").Register("metrics:before_query", before) },
+		func() error { return db.Callback().Query().After("*").Register("metrics:after_query", after("query")) },
+		func() error { return db.Callback().Update().Before("*").Register("metrics:before_update", before) },
+		func() error { return db.Callback().Update().After("*").Register("metrics:after_update", after("update")) },
+		func() error { return db.Callback().Delete().Before("*").Register("metrics:before_delete", before) },
+		func() error { return db.Callback().Delete().After("*").Register("metrics:after_delete", after("delete")) },
+		func() error { return db.Callback().Row().Before("*").Register("metrics:before_row", before) },
+		func() error { return db.Callback().Row().After("*").Register("metrics:after_row", after("row")) },
+		func() error { return db.Callback().Raw().Before("*").Register("metrics:before_raw", before) },
+		func() error { return db.Callback().Raw().After("*").Register("metrics:after_raw", after("raw")) },
+	}
+	for _, register := range registrations {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		DBQueryDuration.WithLabelValues(operation, db.Statement.Table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"net/http"
+)
+
+const namespace = "fibertest"
+
+// Registry holds every metric of the service, it is what /metrics exposes.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: 	namespace,
+		Name: 		"http_requests_total",
+		Help: 		"Number of HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: 	namespace,
+		Name: 		"http_request_duration_seconds",
+		Help: 		"Latency of HTTP requests by method, route template and status.",
+		Buckets: 	prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: 	namespace,
+		Name: 		"db_query_duration_seconds",
+		Help: 		"Duration of GORM queries by operation and table.",
+		Buckets: 	[]float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	LoginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: 	namespace,
+		Name: 		"auth_logins_total",
+		Help: 		"Number of login attempts by result, success or failure.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		LoginsTotal,
+	)
+}
+
+// Handler serves Registry in the Prometheus text exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
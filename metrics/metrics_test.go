@@ -0,0 +1,82 @@
+package metrics_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/metrics"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestGormPlugin(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+	assert.NoError(t, db.Use(metrics.NewGormPlugin()))
+
+	sampleCount := func(operation, table string) uint64 {
+		metric := &dto.Metric{}
+		assert.NoError(t, metrics.DBQueryDuration.WithLabelValues(operation, table).(prometheus.Metric).Write(metric))
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	t.Run("test case : observe query duration", func(t *testing.T) {
+		before := sampleCount("query", "oauth")
+		mock.ExpectQuery(`SELECT \* FROM "oauth"`).
+			WillReturnRows(sqlmock.NewRows([]string{"oauth_id"}).AddRow(1))
+
+		var oauthEntities []model.OauthEntity
+		err := db.Find(&oauthEntities).Error
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, before + 1, sampleCount("query", "oauth"))
+	})
+}
+
+func TestRegisterActiveSessions(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+	assert.NoError(t, metrics.RegisterActiveSessions(repository.NewOauthRepositoryImpl(db, 0)))
+
+	activeSessions := func() float64 {
+		families, err := metrics.Registry.Gather()
+		assert.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "fibertest_auth_active_sessions" {
+				return family.GetMetric()[0].GetGauge().GetValue()
+			}
+		}
+		t.Fatal("fibertest_auth_active_sessions is not registered")
+		return 0
+	}
+
+	t.Run("test case : count stored sessions", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "oauth"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		assert.Equal(t, float64(3), activeSessions())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : failed count is NaN", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "oauth"`).
+			WillReturnError(errors.New("connection refused"))
+
+		assert.True(t, math.IsNaN(activeSessions()))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
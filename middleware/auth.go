@@ -5,38 +5,44 @@ import (
 	"github.com/Yoshikrit/fiber-test/helper"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
 	"github.com/gofiber/fiber/v2"
 
 	"strings"
 )
 
-func NewJWTMiddleware(userRepo repository.UserRepository, oauthRepo repository.OauthRepository, roleRepo repository.RoleRepository) fiber.Handler {
+func NewJWTMiddleware(userRepo repository.UserRepository, oauthRepo repository.OauthRepository, roleRepo repository.RoleRepository, role string) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
+		authCtx, span := tracing.Start(ctx.UserContext(), "JWTMiddleware")
+		defer span.End()
+
 		tokenString := strings.TrimPrefix(ctx.Get("Authorization"), "Bearer ")
-		logger.Info("idk " + tokenString)
 		claims, err := helper.ParseToken(tokenString)
 		if err != nil {
-			logger.Error(err.Error())
+			logger.Error(authCtx, err.Error())
 			return helper.HandleError(ctx, err)
 		}
 
-		_, err = oauthRepo.FindByAccessToken(claims.Claims.ID, tokenString)
+		_, err = oauthRepo.FindByAccessToken(authCtx, claims.Claims.ID, tokenString)
         if err != nil {
-			logger.Error(err.Error())
+			logger.Error(authCtx, err.Error())
 			return helper.HandleError(ctx, err)
 		}
 
-		roleEntity, err := roleRepo.FindByID(claims.Claims.RoleID)
+		roleEntity, err := roleRepo.FindByID(authCtx, claims.Claims.RoleID)
         if err != nil {
-			logger.Error(err.Error())
+			logger.Error(authCtx, err.Error())
 			return helper.HandleError(ctx, err)
 		}
 
-		if (roleEntity.Title != "Manager") {
-			logger.Error("Unauthorized")
+		if (roleEntity.Title != role) {
+			logger.Error(authCtx, "Unauthorized")
 			return helper.HandleError(ctx, errs.NewUnauthorizedError("Unauthorized"))
 		}
 
+		ctx.Locals(helper.LocalsUserID, claims.Claims.ID)
+		// the span only times the checks, a second End from the defer is a no-op
+		span.End()
 		return ctx.Next()
 	}
 }
@@ -5,9 +5,9 @@ import (
     "github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func Cors() fiber.Handler {
+func Cors(allowOrigins string) fiber.Handler {
 	config := cors.Config{
-		AllowOrigins: "http://localhost:8081, https://localhost:8081",
+		AllowOrigins: allowOrigins,
 		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
 		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
 		AllowCredentials: true,
@@ -7,10 +7,12 @@ import (
 	"time"
 )
 
-func Limiter() fiber.Handler {
+// Limiter allows max requests per client IP in a sliding window of expiration,
+// each call keeps its own counters so route groups are limited separately.
+func Limiter(max int, expiration time.Duration) fiber.Handler {
 	return limiter.New(limiter.Config{
-		Max:            20,
-		Expiration:     1 * time.Minute,
+		Max:            max,
+		Expiration:     expiration,
 		LimiterMiddleware: limiter.SlidingWindow{},
 		LimitReached: func(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
@@ -1,14 +1,52 @@
 package middleware
 
 import (
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/rs/zerolog/log"
+
+	"time"
 )
 
+// Logger writes one JSON line per request. An error returned by the handlers is rendered here
+// through the error handler of the app so the line has the status the client receives.
 func Logger() fiber.Handler {
-	return logger.New(logger.Config{
-		Format:     "${pid} ${time} ${status} - ${method} ${path}\n",
-		TimeFormat: "02-Jan-2006 15:04:05",
-		TimeZone:   "Asia/Bangkok",
-	})
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := ctx.Next(); err != nil {
+			renderError(ctx, err)
+		}
+
+		status := ctx.Response().StatusCode()
+		event := log.Info()
+		if status >= fiber.StatusInternalServerError {
+			event = log.Error()
+		}
+
+		event = event.
+			Str("request_id", logger.RequestIDFromContext(ctx.UserContext())).
+			Str("method", ctx.Method()).
+			Str("route", ctx.Route().Path).
+			Int("status", status).
+			Dur("latency", time.Since(start))
+		// Body would read a stream to its end before the headers are sent, its size is not known here
+		if !ctx.Response().IsBodyStream() {
+			event = event.Int("bytes", len(ctx.Response().Body()))
+		}
+		if userID, ok := ctx.Locals(helper.LocalsUserID).(int); ok {
+			event = event.Int("user_id", userID)
+		}
+		event.Msg("Request")
+		return nil
+	}
+}
+
+// renderError writes the response for an error returned down the chain, so a middleware
+// recording the request sees the status the client receives.
+func renderError(ctx *fiber.Ctx, err error) {
+	if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+		_ = ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 }
@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/gofiber/fiber/v2"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/middleware"
+
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = defaultLogger }()
+
+	app := fiber.New()
+	app.Use(middleware.RequestID(), middleware.Logger())
+	app.Get("/items/:id", func(ctx *fiber.Ctx) error {
+		ctx.Locals(helper.LocalsUserID, 7)
+		logger.Info(ctx.UserContext(), "Handler: Find Item Successfully")
+		return ctx.SendString("item")
+	})
+	app.Get("/stream", func(ctx *fiber.Ctx) error {
+		ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			w.WriteString("event")
+		})
+		return nil
+	})
+	app.Get("/fail", func(ctx *fiber.Ctx) error {
+		return errs.NewInternalServerError("boom")
+	})
+
+	lines := func() []map[string]interface{} {
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			entry := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+			entries = append(entries, entry)
+		}
+		buf.Reset()
+		return entries
+	}
+
+	t.Run("test case : log request with request id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "req-1")
+
+		resp, err := app.Test(req)
+		entries := lines()
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, "Handler: Find Item Successfully", entries[0]["message"])
+		assert.Equal(t, "req-1", entries[0]["request_id"])
+		assert.Equal(t, "info", entries[1]["level"])
+		assert.Equal(t, "req-1", entries[1]["request_id"])
+		assert.Equal(t, "GET", entries[1]["method"])
+		assert.Equal(t, "/items/:id", entries[1]["route"])
+		assert.Equal(t, float64(200), entries[1]["status"])
+		assert.Equal(t, float64(4), entries[1]["bytes"])
+		assert.Equal(t, float64(7), entries[1]["user_id"])
+		assert.Contains(t, entries[1], "latency")
+	})
+
+	t.Run("test case : log status of returned error", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fail", nil))
+		entries := lines()
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, float64(500), entries[0]["status"])
+		assert.NotContains(t, entries[0], "user_id")
+	})
+
+	t.Run("test case : log stream without reading it", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/stream", nil))
+		entries := lines()
+
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "event", string(body))
+		assert.Len(t, entries, 1)
+		assert.Equal(t, float64(200), entries[0]["status"])
+		assert.NotContains(t, entries[0], "bytes")
+	})
+}
@@ -1,10 +1,40 @@
 package middleware
 
 import (
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/metrics"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/monitor"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"strconv"
+	"time"
 )
 
-func Metrics() fiber.Handler {
-	return monitor.New()
-}
\ No newline at end of file
+// Metrics serves the Prometheus metrics to a scraper sending token as its Bearer token.
+// An empty token refuses every scrape, serve them on a separate bind address instead.
+func Metrics(token string) fiber.Handler {
+	handler := adaptor.HTTPHandler(metrics.Handler())
+	return func(ctx *fiber.Ctx) error {
+		if !helper.BearerTokenMatches(ctx, token) {
+			return helper.HandleError(ctx, errs.NewUnauthorizedError("Unauthorized"))
+		}
+		return handler(ctx)
+	}
+}
+
+// RequestMetrics counts and times every request by method, route template and status.
+func RequestMetrics() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := ctx.Next(); err != nil {
+			renderError(ctx, err)
+		}
+
+		labels := []string{ctx.Method(), ctx.Route().Path, strconv.Itoa(ctx.Response().StatusCode())}
+		metrics.HTTPRequestsTotal.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/metrics"
+	"github.com/Yoshikrit/fiber-test/middleware"
+
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New()
+	app.Get("/metrics", middleware.Metrics("secret"))
+
+	t.Run("test case : scrape with token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer secret")
+
+		resp, err := app.Test(req)
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "text/plain")
+		assert.Contains(t, string(body), "go_goroutines")
+	})
+
+	t.Run("test case : scrape with wrong token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer wrong")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("test case : scrape without token", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("test case : empty token refuses scrape", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/metrics", middleware.Metrics(""))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestRequestMetrics(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestMetrics())
+	app.Get("/metered/:id", func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+	app.Get("/metered-fail", func(ctx *fiber.Ctx) error {
+		return errs.NewInternalServerError("boom")
+	})
+
+	t.Run("test case : count request by route template", func(t *testing.T) {
+		counter := metrics.HTTPRequestsTotal.WithLabelValues("GET", "/metered/:id", "200")
+		before := testutil.ToFloat64(counter)
+
+		_, err := app.Test(httptest.NewRequest(http.MethodGet, "/metered/1", nil))
+		assert.NoError(t, err)
+		_, err = app.Test(httptest.NewRequest(http.MethodGet, "/metered/2", nil))
+		assert.NoError(t, err)
+
+		assert.Equal(t, before + 2, testutil.ToFloat64(counter))
+	})
+
+	t.Run("test case : count status of returned error", func(t *testing.T) {
+		counter := metrics.HTTPRequestsTotal.WithLabelValues("GET", "/metered-fail", "500")
+		before := testutil.ToFloat64(counter)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metered-fail", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, before + 1, testutil.ToFloat64(counter))
+	})
+}
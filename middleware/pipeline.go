@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/gofiber/fiber/v2"
+
+	"time"
+)
+
+const (
+	defaultCorsAllowOrigins = "http://localhost:8081, https://localhost:8081"
+	defaultRateLimitMax 	= 20
+	defaultRateLimitWindow 	= 60
+)
+
+// Chain is middleware in the order it runs.
+type Chain []fiber.Handler
+
+// With returns c followed by handlers in a new slice, a chain is shared by several groups.
+func (c Chain) With(handlers ...fiber.Handler) Chain {
+	chain := make(Chain, 0, len(c) + len(handlers))
+	chain = append(chain, c...)
+	return append(chain, handlers...)
+}
+
+// Pipeline is the middleware of the app, Global is installed on it before any route and every
+// group of routes adds its own chain on top: Public for the auth routes, API for the routes behind
+// the JWT middleware, which the router appends as it needs the repositories, and Ops for swagger,
+// metrics and the probes, which scrapers and probes call without a rate limit.
+type Pipeline struct {
+	Global 		Chain
+	Public 		Chain
+	API 		Chain
+	Ops 		Chain
+
+	// LongTimeout replaces the request deadline of the API chain on bulk writes and file transfers.
+	LongTimeout fiber.Handler
+}
+
+// NewPipeline builds the middleware of configData, the CORS origins and the rate limit follow the
+// changes of their settings at runtime.
+func NewPipeline(configData *config.Config) *Pipeline {
+	requestTimeout := time.Duration(configData.RequestTimeout) * time.Second
+
+	return &Pipeline{
+		Global: Chain{
+			RequestID(),
+			Tracing(),
+			RequestMetrics(),
+			Logger(),
+			Recover(),
+			Reloadable(configData, corsOf, "CORS_ALLOW_ORIGINS"),
+			Helmet(),
+		},
+		Public: Chain{
+			Reloadable(configData, rateLimitOf, "RATE_LIMIT_MAX", "RATE_LIMIT_WINDOW"),
+			Timeout(requestTimeout),
+		},
+		API: Chain{
+			Reloadable(configData, rateLimitOf, "RATE_LIMIT_MAX", "RATE_LIMIT_WINDOW"),
+			Timeout(requestTimeout),
+		},
+		Ops: Chain{
+			Timeout(requestTimeout),
+		},
+		LongTimeout: Timeout(time.Duration(configData.LongRequestTimeout) * time.Second),
+	}
+}
+
+func corsOf(configData config.Config) fiber.Handler {
+	corsAllowOrigins := configData.CorsAllowOrigins
+	if corsAllowOrigins == "" {
+		corsAllowOrigins = defaultCorsAllowOrigins
+	}
+	return Cors(corsAllowOrigins)
+}
+
+func rateLimitOf(configData config.Config) fiber.Handler {
+	rateLimitMax := configData.RateLimitMax
+	if rateLimitMax <= 0 {
+		rateLimitMax = defaultRateLimitMax
+	}
+	rateLimitWindow := configData.RateLimitWindow
+	if rateLimitWindow <= 0 {
+		rateLimitWindow = defaultRateLimitWindow
+	}
+	return Limiter(rateLimitMax, time.Duration(rateLimitWindow) * time.Second)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/gofiber/fiber/v2"
+
+	"slices"
+	"sync/atomic"
+)
+
+// Reloadable runs the handler build makes of the configuration and builds it again when one of keys
+// changes at runtime. Requests already in the old handler finish there and the next ones take the new
+// one, so nothing is dropped, but a handler with state such as the limiter starts counting again.
+func Reloadable(configData *config.Config, build func(configData config.Config) fiber.Handler, keys ...string) fiber.Handler {
+	var current atomic.Pointer[fiber.Handler]
+	handler := build(*configData)
+	current.Store(&handler)
+
+	config.Subscribe(func(next config.Config, changed []string) {
+		for _, key := range changed {
+			if slices.Contains(keys, key) {
+				handler := build(next)
+				current.Store(&handler)
+				return
+			}
+		}
+	})
+
+	return func(c *fiber.Ctx) error {
+		return (*current.Load())(c)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/middleware"
+
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReloadable(t *testing.T) {
+	require.NoError(t, config.LoadConfig(config.Options{Overrides: map[string]string{
+		"POSTGRES_HOST": 	"db",
+		"POSTGRES_USER": 	"user",
+		"POSTGRES_DB": 		"fiber",
+		"JWT_SECRET_KEY": 	strings.Repeat("k", config.JWTSecretMinLength),
+		"RATE_LIMIT_MAX": 	"1",
+	}}))
+	configData, err := config.GetConfig()
+	require.NoError(t, err)
+
+	app := fiber.New()
+	pipeline := middleware.NewPipeline(&configData)
+	app.Get("/", append(pipeline.Global.With(pipeline.API...), func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusOK)
+	})...)
+	request := func(origin string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("test case : rate limit follows its setting", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, request("").StatusCode)
+		assert.Equal(t, fiber.StatusTooManyRequests, request("").StatusCode)
+
+		_, err := config.Update(map[string]string{"RATE_LIMIT_MAX": "100"})
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, request("").StatusCode)
+		assert.Equal(t, fiber.StatusOK, request("").StatusCode)
+	})
+
+	t.Run("test case : cors follows its setting", func(t *testing.T) {
+		assert.Empty(t, request("https://shop.example").Header.Get(fiber.HeaderAccessControlAllowOrigin))
+
+		_, err := config.Update(map[string]string{"CORS_ALLOW_ORIGINS": "https://shop.example"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://shop.example", request("https://shop.example").Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	})
+}
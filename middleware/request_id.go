@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID keeps the X-Request-ID sent by the client or generates one, echoes it on the response
+// and tags the user context with it so every log line of the request carries the ID.
+func RequestID() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requestID := ctx.Get(fiber.HeaderXRequestID)
+		if !validRequestID(requestID) {
+			requestID = utils.UUIDv4()
+		}
+
+		ctx.Request().Header.Set(fiber.HeaderXRequestID, requestID)
+		ctx.Set(fiber.HeaderXRequestID, requestID)
+		ctx.SetUserContext(logger.ContextWithRequestID(ctx.UserContext(), requestID))
+		return ctx.Next()
+	}
+}
+
+// validRequestID only accepts short printable ASCII, a client supplied ID ends up in the logs.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
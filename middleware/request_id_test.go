@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/middleware"
+
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		return ctx.SendString(logger.RequestIDFromContext(ctx.UserContext()))
+	})
+
+	t.Run("test case : generate request id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Header.Get(fiber.HeaderXRequestID), 36)
+		assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), string(body[:n]))
+	})
+
+	t.Run("test case : keep request id of client", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "client-id-1")
+
+		resp, err := app.Test(req)
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "client-id-1", resp.Header.Get(fiber.HeaderXRequestID))
+		assert.Equal(t, "client-id-1", string(body[:n]))
+	})
+
+	t.Run("test case : replace invalid request id of client", func(t *testing.T) {
+		for _, requestID := range []string{"has space", strings.Repeat("a", 129)} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderXRequestID, requestID)
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Len(t, resp.Header.Get(fiber.HeaderXRequestID), 36)
+		}
+	})
+}
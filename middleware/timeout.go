@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"context"
+	"time"
+)
+
+const localsBaseContext = "timeout_base_context"
+
+// Timeout puts a deadline on the context the handlers hand to the services, queries still running
+// when it passes are cancelled. A Timeout on a route replaces the one of its group instead of
+// being capped by it, so a slow route can be given more time. Zero leaves the request without one.
+func Timeout(timeout time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if timeout <= 0 {
+			return ctx.Next()
+		}
+
+		base, ok := ctx.Locals(localsBaseContext).(context.Context)
+		if !ok {
+			base = ctx.UserContext()
+			ctx.Locals(localsBaseContext, base)
+		}
+
+		userCtx, cancel := context.WithTimeout(base, timeout)
+		defer cancel()
+
+		ctx.SetUserContext(userCtx)
+		return ctx.Next()
+	}
+}
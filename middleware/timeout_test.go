@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Yoshikrit/fiber-test/middleware"
+
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	remaining := func(ctx *fiber.Ctx) time.Duration {
+		deadline, ok := ctx.UserContext().Deadline()
+		if !ok {
+			return 0
+		}
+		return time.Until(deadline)
+	}
+
+	t.Run("test case : timeout sets deadline", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/", middleware.Timeout(time.Minute), func(ctx *fiber.Ctx) error {
+			assert.InDelta(t, time.Minute, remaining(ctx), float64(time.Second))
+			return nil
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("test case : route timeout replaces group timeout", func(t *testing.T) {
+		app := fiber.New()
+		group := app.Group("/group")
+		group.Use(middleware.Timeout(time.Second))
+		group.Get("/long", middleware.Timeout(time.Hour), func(ctx *fiber.Ctx) error {
+			assert.InDelta(t, time.Hour, remaining(ctx), float64(time.Second))
+			return nil
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/group/long", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("test case : zero timeout leaves no deadline", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/", middleware.Timeout(0), func(ctx *fiber.Ctx) error {
+			_, ok := ctx.UserContext().Deadline()
+			assert.False(t, ok)
+			return nil
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing opens the server span of the request, continuing the trace of a W3C traceparent header
+// the client sent. It is named after the route template once the route is known.
+func Tracing() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		carrier := propagation.HeaderCarrier{}
+		ctx.Request().Header.VisitAll(func(key, value []byte) {
+			carrier.Set(string(key), string(value))
+		})
+		parent := otel.GetTextMapPropagator().Extract(ctx.UserContext(), carrier)
+
+		spanCtx, span := tracing.Start(parent, ctx.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Method()),
+				semconv.URLPath(ctx.Path()),
+				semconv.ServerAddress(ctx.Hostname()),
+				semconv.ClientAddress(ctx.IP()),
+			),
+		)
+		defer span.End()
+
+		ctx.SetUserContext(spanCtx)
+		if err := ctx.Next(); err != nil {
+			renderError(ctx, err)
+		}
+
+		status := ctx.Response().StatusCode()
+		span.SetName(ctx.Method() + " " + ctx.Route().Path)
+		span.SetAttributes(
+			semconv.HTTPRoute(ctx.Route().Path),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return nil
+	}
+}
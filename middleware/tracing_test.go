@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/gofiber/fiber/v2"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/middleware"
+	"github.com/Yoshikrit/fiber-test/testutils"
+	"github.com/Yoshikrit/fiber-test/tracing"
+
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Get("/traced/:id", func(ctx *fiber.Ctx) error {
+		_, span := tracing.Start(ctx.UserContext(), "ProductTypeService.FindByID")
+		span.End()
+		logger.Info(ctx.UserContext(), "Handler: Find Traced Successfully")
+		return ctx.SendString("ok")
+	})
+	app.Get("/traced-fail", func(ctx *fiber.Ctx) error {
+		return errs.NewInternalServerError("boom")
+	})
+	app.Get("/traced-missing", func(ctx *fiber.Ctx) error {
+		return helper.HandleError(ctx, errs.NewNotFoundError("missing"))
+	})
+
+	t.Run("test case : server span continues traceparent", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+		var buf bytes.Buffer
+		defaultLogger := log.Logger
+		log.Logger = zerolog.New(&buf)
+		defer func() { log.Logger = defaultLogger }()
+
+		req := httptest.NewRequest(http.MethodGet, "/traced/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		resp, err := app.Test(req)
+		entry := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		serverSpan := spans[1]
+		assert.Equal(t, "GET /traced/:id", serverSpan.Name())
+		assert.Equal(t, trace.SpanKindServer, serverSpan.SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverSpan.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", serverSpan.Parent().SpanID().String())
+		assert.True(t, serverSpan.Parent().IsRemote())
+		assert.Equal(t, serverSpan.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+		assert.Equal(t, serverSpan.SpanContext().SpanID().String(), entry["span_id"])
+	})
+
+	t.Run("test case : server error sets error status", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/traced-fail", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "GET /traced-fail", recorder.Ended()[0].Name())
+		assert.Equal(t, codes.Error, recorder.Ended()[0].Status().Code)
+	})
+
+	t.Run("test case : client error leaves status unset", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/traced-missing", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, codes.Unset, recorder.Ended()[0].Status().Code)
+	})
+}
@@ -0,0 +1,397 @@
+package migrate
+
+import (
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"gorm.io/gorm"
+
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockKey is the Postgres advisory lock held while migrating, replicas migrating on start
+// wait for each other on it. SQLite has no such lock, its writers queue up on the database file.
+const lockKey = 72057594037927
+
+// createTable takes the type of applied_at, SQLite only reads DATETIME columns back as a time.
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version 	BIGINT PRIMARY KEY,
+	name 		TEXT NOT NULL,
+	checksum 	TEXT NOT NULL,
+	dirty 		BOOLEAN NOT NULL DEFAULT FALSE,
+	applied_at 	%s NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Migration is a pair of scripts named <version>_<name>.up.sql and <version>_<name>.down.sql,
+// Checksum is the sha256 of the up script.
+type Migration struct {
+	Version 	uint
+	Name 		string
+	Up 			string
+	Down 		string
+	Checksum 	string
+}
+
+type State string
+
+const (
+	StatePending 	State = "pending"
+	StateApplied 	State = "applied"
+	// StateDirty is a migration that failed or was interrupted half way, the schema has to be repaired by hand.
+	StateDirty 		State = "dirty"
+	// StateModified is an applied migration whose script changed since.
+	StateModified 	State = "modified"
+	// StateUnknown is an applied migration this build does not have.
+	StateUnknown 	State = "unknown"
+)
+
+type Status struct {
+	Version 	uint
+	Name 		string
+	State 		State
+	AppliedAt 	*time.Time
+}
+
+type appliedMigration struct {
+	Version 	uint
+	Name 		string
+	Checksum 	string
+	Dirty 		bool
+	AppliedAt 	time.Time
+}
+
+// Load reads the migrations in dir of fsys sorted by version, every up script needs its down script.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, name := range names {
+		base := path.Base(name)
+		direction := ""
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s is neither up nor down", name)
+		}
+		versionString, migrationName, found := strings.Cut(strings.TrimSuffix(base, "." + direction + ".sql"), "_")
+		version, err := strconv.ParseUint(versionString, 10, 64)
+		if !found || err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s has no version", name)
+		}
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: migrationName}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != migrationName {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, migrationName)
+		}
+		if direction == "up" {
+			sum := sha256.Sum256(script)
+			migration.Up = string(script)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down script", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Latest is the version of the last migration, the schema version the code expects.
+func Latest(migrations []Migration) uint {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations) - 1].Version
+}
+
+// Migrator applies and rolls back migrations, recording them in schema_migrations. Each script
+// manages its own transaction, so a migration is marked dirty while it runs and a failure leaves
+// it dirty until the schema is repaired by hand, or taken as in place with Baseline.
+type Migrator struct {
+	db 			*gorm.DB
+	migrations 	[]Migration
+}
+
+// NewMigrator creates a migrator of migrations, which Load returns sorted.
+func NewMigrator(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db: 		db,
+		migrations: migrations,
+	}
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, Latest(m.migrations))
+}
+
+// Down rolls back the last applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.check(conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return errors.New("no migration applied")
+		}
+		return m.rollback(ctx, conn, *m.find(applied[len(applied) - 1].Version))
+	})
+}
+
+// To applies or rolls back migrations until the schema is at version, 0 rolls back everything.
+func (m *Migrator) To(ctx context.Context, version uint) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("migration %d does not exist", version)
+	}
+	return m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.check(conn)
+		if err != nil {
+			return err
+		}
+		return m.migrate(ctx, conn, applied, version)
+	})
+}
+
+// Baseline records the migrations up to version as applied without running them, for adopting a
+// database whose schema was created by hand or by another tool. A dirty migration up to version is
+// marked clean too, its schema is taken to be in place.
+func (m *Migrator) Baseline(ctx context.Context, version uint) error {
+	if m.find(version) == nil {
+		return fmt.Errorf("migration %d does not exist", version)
+	}
+	return m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		byVersion := map[uint]appliedMigration{}
+		for _, row := range applied {
+			migration := m.find(row.Version)
+			switch {
+			case migration == nil:
+				return fmt.Errorf("migration %d is applied but unknown to this build", row.Version)
+			case row.Version > version:
+				return fmt.Errorf("migration %d is already applied, the baseline can not be before it", row.Version)
+			case stateOf(row, *migration) == StateModified:
+				return fmt.Errorf("migration %d was changed after it was applied", row.Version)
+			}
+			byVersion[row.Version] = row
+		}
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+				row, ok := byVersion[migration.Version]
+				switch {
+				case !ok:
+					err = tx.Exec("INSERT INTO schema_migrations (version, name, checksum, dirty) VALUES (?, ?, ?, FALSE)", migration.Version, migration.Name, migration.Checksum).Error
+				case row.Dirty:
+					err = tx.Exec("UPDATE schema_migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = ?", migration.Version).Error
+				default:
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				logger.Info(ctx, "Migrate: Baselined migration", "version", migration.Version, "name", migration.Name)
+			}
+			return nil
+		})
+	})
+}
+
+// Status lists the migrations of this build and the applied ones it does not have, by version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		byVersion := map[uint]appliedMigration{}
+		for _, migration := range applied {
+			byVersion[migration.Version] = migration
+		}
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name, State: StatePending}
+			if row, ok := byVersion[migration.Version]; ok {
+				appliedAt := row.AppliedAt
+				status.AppliedAt = &appliedAt
+				status.State = stateOf(row, migration)
+				delete(byVersion, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, row := range byVersion {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, Status{Version: row.Version, Name: row.Name, State: StateUnknown, AppliedAt: &appliedAt})
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+		return nil
+	})
+	return statuses, err
+}
+
+func stateOf(row appliedMigration, migration Migration) State {
+	switch {
+	case row.Dirty:
+		return StateDirty
+	case row.Checksum != migration.Checksum:
+		return StateModified
+	default:
+		return StateApplied
+	}
+}
+
+// locked runs fn on one connection holding the advisory lock on Postgres, with schema_migrations created.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	postgres := m.db.Dialector.Name() == "postgres"
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		appliedAtType := "DATETIME"
+		if postgres {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				return err
+			}
+			// the lock and the settings scripts change belong to the session, which goes back to the pool,
+			// they have to be released even when ctx is done
+			defer func() {
+				release := conn.WithContext(context.WithoutCancel(ctx))
+				release.Exec("RESET ALL")
+				release.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+			}()
+			appliedAtType = "TIMESTAMPTZ"
+		}
+
+		if err := conn.Exec(fmt.Sprintf(createTable, appliedAtType)).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) applied(conn *gorm.DB) ([]appliedMigration, error) {
+	var applied []appliedMigration
+	err := conn.Raw("SELECT version, name, checksum, dirty, applied_at FROM schema_migrations ORDER BY version").Scan(&applied).Error
+	return applied, err
+}
+
+// check returns the applied migrations once they are all clean, known and unchanged.
+func (m *Migrator) check(conn *gorm.DB) ([]appliedMigration, error) {
+	applied, err := m.applied(conn)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range applied {
+		migration := m.find(row.Version)
+		if migration == nil {
+			return nil, fmt.Errorf("migration %d is applied but unknown to this build", row.Version)
+		}
+		switch stateOf(row, *migration) {
+		case StateDirty:
+			return nil, fmt.Errorf("migration %d is dirty, repair the schema by hand and run migrate baseline %d", row.Version, row.Version)
+		case StateModified:
+			return nil, fmt.Errorf("migration %d was changed after it was applied", row.Version)
+		}
+	}
+	return applied, nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, conn *gorm.DB, applied []appliedMigration, version uint) error {
+	isApplied := map[uint]bool{}
+	for _, row := range applied {
+		isApplied[row.Version] = true
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version <= version && !isApplied[migration.Version] {
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > version && isApplied[migration.Version] {
+			if err := m.rollback(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *gorm.DB, migration Migration) error {
+	if err := conn.Exec("INSERT INTO schema_migrations (version, name, checksum, dirty) VALUES (?, ?, ?, TRUE)", migration.Version, migration.Name, migration.Checksum).Error; err != nil {
+		return err
+	}
+	if err := conn.Exec(migration.Up).Error; err != nil {
+		return fmt.Errorf("migration %d failed: %w", migration.Version, err)
+	}
+	if err := conn.Exec("UPDATE schema_migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = ?", migration.Version).Error; err != nil {
+		return err
+	}
+	logger.Info(ctx, "Migrate: Applied migration", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *gorm.DB, migration Migration) error {
+	if err := conn.Exec("UPDATE schema_migrations SET dirty = TRUE WHERE version = ?", migration.Version).Error; err != nil {
+		return err
+	}
+	if err := conn.Exec(migration.Down).Error; err != nil {
+		return fmt.Errorf("rolling back migration %d failed: %w", migration.Version, err)
+	}
+	if err := conn.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error; err != nil {
+		return err
+	}
+	logger.Info(ctx, "Migrate: Rolled back migration", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+func (m *Migrator) find(version uint) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
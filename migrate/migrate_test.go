@@ -0,0 +1,184 @@
+package migrate_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("test case : pass sorted by version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migration/00010_outbox.up.sql": 	{Data: []byte("CREATE TABLE outbox ();")},
+			"migration/00010_outbox.down.sql": 	{Data: []byte("DROP TABLE outbox;")},
+			"migration/00002_users.up.sql": 	{Data: []byte("CREATE TABLE users ();")},
+			"migration/00002_users.down.sql": 	{Data: []byte("DROP TABLE users;")},
+		}
+
+		migrations, err := migrate.Load(fsys, "migration")
+
+		assert.NoError(t, err)
+		assert.Len(t, migrations, 2)
+		assert.Equal(t, uint(2), migrations[0].Version)
+		assert.Equal(t, "users", migrations[0].Name)
+		assert.Equal(t, "DROP TABLE users;", migrations[0].Down)
+		assert.Len(t, migrations[0].Checksum, 64)
+		assert.Equal(t, uint(10), migrate.Latest(migrations))
+	})
+
+	t.Run("test case : fail without down script", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migration/00001_users.up.sql": {Data: []byte("CREATE TABLE users ();")},
+		}
+
+		_, err := migrate.Load(fsys, "migration")
+
+		assert.EqualError(t, err, "migration 1 needs both an up and a down script")
+	})
+
+	t.Run("test case : fail without version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migration/users.up.sql": {Data: []byte("CREATE TABLE users ();")},
+		}
+
+		_, err := migrate.Load(fsys, "migration")
+
+		assert.EqualError(t, err, "migration migration/users.up.sql has no version")
+	})
+
+	t.Run("test case : embedded migrations load", func(t *testing.T) {
+		migrations, err := migrate.Load(config.Migrations, config.MigrationDir)
+
+		assert.NoError(t, err)
+		for i, migration := range migrations {
+			assert.Equal(t, uint(i + 1), migration.Version)
+		}
+	})
+}
+
+func TestTo(t *testing.T) {
+	migrations := []migrate.Migration{
+		{Version: 1, Name: "users", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;", Checksum: "abc"},
+	}
+	columns := []string{"version", "name", "checksum", "dirty", "applied_at"}
+
+	expectLock := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("SELECT pg_advisory_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	expectUnlock := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("RESET ALL").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	t.Run("test case : pass applies pending", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(1, "users", "abc").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("CREATE TABLE users").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE schema_migrations SET dirty = FALSE").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectUnlock(mock)
+
+		err := migrate.NewMigrator(db, migrations).Up(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : fail dirty", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "users", "abc", true, time.Now()))
+		expectUnlock(mock)
+
+		err := migrate.NewMigrator(db, migrations).Up(context.Background())
+
+		assert.EqualError(t, err, "migration 1 is dirty, repair the schema by hand and run migrate baseline 1")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : fail modified", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "users", "def", false, time.Now()))
+		expectUnlock(mock)
+
+		err := migrate.NewMigrator(db, migrations).To(context.Background(), 0)
+
+		assert.EqualError(t, err, "migration 1 was changed after it was applied")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : fail unknown version", func(t *testing.T) {
+		db, _ := testutils.SetupMockDB(t)
+
+		err := migrate.NewMigrator(db, migrations).To(context.Background(), 5)
+
+		assert.EqualError(t, err, "migration 5 does not exist")
+	})
+}
+
+func TestBaseline(t *testing.T) {
+	migrations := []migrate.Migration{
+		{Version: 1, Name: "users", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;", Checksum: "abc"},
+		{Version: 2, Name: "orders", Up: "CREATE TABLE orders ();", Down: "DROP TABLE orders;", Checksum: "def"},
+		{Version: 3, Name: "items", Up: "CREATE TABLE items ();", Down: "DROP TABLE items;", Checksum: "ghi"},
+	}
+	columns := []string{"version", "name", "checksum", "dirty", "applied_at"}
+
+	expectLock := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("SELECT pg_advisory_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	expectUnlock := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("RESET ALL").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	t.Run("test case : pass records without running", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "users", "abc", true, time.Now()))
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE schema_migrations SET dirty = FALSE").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "orders", "def").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectUnlock(mock)
+
+		err := migrate.NewMigrator(db, migrations).Baseline(context.Background(), 2)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : fail applied after the baseline", func(t *testing.T) {
+		db, mock := testutils.SetupMockDB(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "users", "abc", false, time.Now()).
+			AddRow(2, "orders", "def", false, time.Now()))
+		expectUnlock(mock)
+
+		err := migrate.NewMigrator(db, migrations).Baseline(context.Background(), 1)
+
+		assert.EqualError(t, err, "migration 2 is already applied, the baseline can not be before it")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : fail unknown version", func(t *testing.T) {
+		db, _ := testutils.SetupMockDB(t)
+
+		err := migrate.NewMigrator(db, migrations).Baseline(context.Background(), 5)
+
+		assert.EqualError(t, err, "migration 5 does not exist")
+	})
+}
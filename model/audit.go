@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	AuditActionCreate 		= "create"
+	AuditActionUpdate 		= "update"
+	AuditActionDelete 		= "delete"
+	AuditActionRestore 		= "restore"
+	AuditActionPurge 		= "purge"
+	AuditActionMove 		= "move"
+	AuditActionRevert 		= "revert"
+	AuditActionRegister 	= "register"
+	AuditActionLogin 		= "login"
+	AuditActionLoginFailed 	= "login_failed"
+	AuditActionLogout 		= "logout"
+	AuditActionRefresh 		= "refresh"
+	AuditActionSetRole 		= "set_role"
+	AuditActionResetPassword 	= "reset_password"
+	AuditActionDisable 		= "disable"
+	AuditActionRevoke 		= "revoke"
+
+	AuditResourceProductType 	= "producttype"
+	AuditResourceUser 			= "user"
+	AuditResourceOauth 			= "oauth"
+	AuditResourceSettings 		= "settings"
+)
+
+type AuditLogEntity struct {
+	ID   			int64    	`gorm:"primaryKey; column:audit_id;"`
+	ActorID 		*int 		`gorm:"index;      column:audit_actor_user_id;"`
+	ActorSystem 	string 		`gorm:"            column:audit_actor_system;    size:40;"`
+	Action 			string 		`gorm:"not null;   column:audit_action;          size:40;"`
+	ResourceType 	string 		`gorm:"not null;   column:audit_resource_type;   size:40;"`
+	ResourceID 		string 		`gorm:"            column:audit_resource_id;     size:64;"`
+	Before 			*string 	`gorm:"            column:audit_before;          type:jsonb;"`
+	After 			*string 	`gorm:"            column:audit_after;           type:jsonb;"`
+	RequestID 		string 		`gorm:"            column:audit_request_id;      size:64;"`
+	IP 				string 		`gorm:"            column:audit_ip;              size:45;"`
+	CreatedAt 		time.Time 	`gorm:"not null;   column:audit_created_at;"`
+}
+
+func (a AuditLogEntity) TableName() string {
+	return "audit_log"
+}
+
+type AuditLog struct {
+	ID   			int64    		`json:"audit_id"`
+	ActorID 		*int 			`json:"actor_user_id"`
+	ActorSystem 	string 			`json:"actor_system,omitempty"`
+	Action 			string 			`json:"action"`
+	ResourceType 	string 			`json:"resource_type"`
+	ResourceID 		string 			`json:"resource_id"`
+	Before 			json.RawMessage `json:"before"       swaggertype:"object"`
+	After 			json.RawMessage `json:"after"        swaggertype:"object"`
+	RequestID 		string 			`json:"request_id"`
+	IP 				string 			`json:"ip"`
+	CreatedAt 		time.Time 		`json:"created_at"`
+}
+
+type AuditLogFilter struct {
+	ActorID 		*int 		`query:"actor_id"         validate:"omitempty,gt=0"`
+	Action 			string 		`query:"action"           validate:"omitempty,max=40"`
+	ResourceType 	string 		`query:"resource_type"    validate:"omitempty,max=40"`
+	ResourceID 		string 		`query:"resource_id"      validate:"omitempty,max=64"`
+	RequestID 		string 		`query:"request_id"       validate:"omitempty,max=64"`
+	From 			*time.Time 	`query:"-"`
+	To 				*time.Time 	`query:"-"`
+	Limit 			int 		`query:"limit"            validate:"omitempty,min=1,max=500"`
+	Offset 			int 		`query:"offset"           validate:"omitempty,gte=0"`
+}
+
+type AuditLogPage struct {
+	Total 	int64 		`json:"total"`
+	Limit 	int 		`json:"limit"`
+	Offset 	int 		`json:"offset"`
+	Items 	[]AuditLog 	`json:"items"`
+}
+
+// Actor is who is behind the current request, UserID is nil for anonymous requests
+// and for background jobs, which name themselves in System instead.
+type Actor struct {
+	UserID 		*int
+	System 		string
+	IP 			string
+	RequestID 	string
+}
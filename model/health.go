@@ -0,0 +1,19 @@
+package model
+
+const (
+	HealthStatusPass = "pass"
+	HealthStatusFail = "fail"
+)
+
+// HealthReport is the answer of a probe, Status is fail as soon as one check fails.
+type HealthReport struct {
+	Status 	string 					`json:"status" example:"pass"`
+	Checks 	map[string]HealthCheck 	`json:"checks"`
+}
+
+// HealthCheck is the result of one check, Error and Duration are only given in verbose mode.
+type HealthCheck struct {
+	Status 		string 	`json:"status" example:"pass"`
+	Error 		string 	`json:"error,omitempty"`
+	Duration 	string 	`json:"duration,omitempty" example:"1.2ms"`
+}
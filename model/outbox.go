@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// OutboxEventEntity is a domain event stored in the same transaction as the change it describes,
+// PublishedAt is set once every sink has accepted it. PublishedSinks is the comma separated list of
+// sinks that accepted it so far, DeadAt is set when it failed too often to be retried.
+type OutboxEventEntity struct {
+	ID   			int64    	`gorm:"primaryKey; column:outbox_id;"`
+	AggregateType 	string 		`gorm:"not null;   column:outbox_aggregate_type;     size:40;"`
+	AggregateID 	string 		`gorm:"not null;   column:outbox_aggregate_id;       size:64;"`
+	EventType 		string 		`gorm:"not null;   column:outbox_event_type;         size:40;"`
+	Payload 		*string 	`gorm:"            column:outbox_payload;            type:jsonb;"`
+	Attempts 		int 		`gorm:"not null;   column:outbox_attempts;"`
+	LastError 		string 		`gorm:"            column:outbox_last_error;         size:1024;"`
+	CreatedAt 		time.Time 	`gorm:"not null;   column:outbox_created_at;"`
+	PublishedAt 	*time.Time 	`gorm:"index;      column:outbox_published_at;"`
+	PublishedSinks 	string 		`gorm:"not null;   column:outbox_published_sinks;    size:255;"`
+	NextAttemptAt 	*time.Time 	`gorm:"            column:outbox_next_attempt_at;"`
+	DeadAt 			*time.Time 	`gorm:"            column:outbox_dead_at;"`
+}
+
+func (o OutboxEventEntity) TableName() string {
+	return "outbox"
+}
+
+// OutboxEvent is what the relay hands to a sink, ID is stable across redeliveries
+// so a consumer can drop duplicates.
+type OutboxEvent struct {
+	ID 				int64 			`json:"id"`
+	AggregateType 	string 			`json:"aggregate_type"`
+	AggregateID 	string 			`json:"aggregate_id"`
+	Type 			string 			`json:"type"`
+	Payload 		json.RawMessage `json:"payload"`
+	CreatedAt 		time.Time 		`json:"created_at"`
+}
@@ -1,8 +1,18 @@
 package model
 
+import (
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
 type ProductTypeEntity struct {
-	ID   int    `gorm:"primaryKey; column:prodtype_code;"`
-	Name string `gorm:"not null;   column:prodtype_name;"`
+	ID   		int    			`gorm:"primaryKey; column:prodtype_code;"`
+	Name 		string 			`gorm:"not null;   column:prodtype_name;"`
+	ParentID 	*int 			`gorm:"index;      column:prodtype_parent_code;"`
+	DeletedAt 	gorm.DeletedAt 	`gorm:"index;      column:prodtype_deleted_at;"`
 }
 
 func (p ProductTypeEntity) TableName() string {
@@ -10,15 +20,186 @@ func (p ProductTypeEntity) TableName() string {
 }
 
 type ProductType struct {
-	ID   int    `json:"prodtype_id"`
-	Name string `json:"prodtype_name"`
+	ID   		int    	`json:"prodtype_id"`
+	Name 		string 	`json:"prodtype_name"`
+	ParentID 	*int 	`json:"prodtype_parent_id,omitempty"`
+}
+
+type ProductTypeTrash struct {
+	ID   		int    		`json:"prodtype_id"`
+	Name 		string 		`json:"prodtype_name"`
+	DeletedAt 	time.Time 	`json:"prodtype_deleted_at"`
 }
 
 type ProductTypeCreate struct {
-	ID   int    `json:"prodtype_id"      validate:"required,gte=0"`
-	Name string `json:"prodtype_name"    validate:"required,max=40"`
+	ID   		int    	`json:"prodtype_id"           validate:"required,gte=0"`
+	Name 		string 	`json:"prodtype_name"         validate:"required,max=40"`
+	ParentID 	*int 	`json:"prodtype_parent_id"    validate:"omitempty,gte=0"`
 }
 
 type ProductTypeUpdate struct {
 	Name string `json:"prodtype_name"    validate:"required,max=40"`
 }
+
+type ProductTypeMove struct {
+	ParentID *int `json:"prodtype_parent_id"    validate:"omitempty,gte=0"`
+}
+
+const (
+	RevisionOpCreate 	= "create"
+	RevisionOpUpdate 	= "update"
+	RevisionOpDelete 	= "delete"
+	RevisionOpRestore 	= "restore"
+	RevisionOpMove 		= "move"
+	RevisionOpRevert 	= "revert"
+)
+
+// ProductTypeRevisionEntity is an immutable snapshot of a product type taken after each write,
+// Revision counts up from 1 per product type.
+type ProductTypeRevisionEntity struct {
+	ID   			int64    	`gorm:"primaryKey; column:rev_id;"`
+	ProductTypeID 	int 		`gorm:"not null;   column:prodtype_code;"`
+	Revision 		int 		`gorm:"not null;   column:rev_number;"`
+	Name 			string 		`gorm:"not null;   column:prodtype_name;"`
+	ParentID 		*int 		`gorm:"            column:prodtype_parent_code;"`
+	DeletedAt 		*time.Time 	`gorm:"            column:prodtype_deleted_at;"`
+	Operation 		string 		`gorm:"not null;   column:rev_operation;"`
+	CreatedAt 		time.Time 	`gorm:"not null;   column:rev_created_at;"`
+}
+
+func (r ProductTypeRevisionEntity) TableName() string {
+	return "producttype_revision"
+}
+
+type ProductTypeRevision struct {
+	ID   		int    		`json:"prodtype_id"`
+	Revision 	int 		`json:"revision"`
+	Name 		string 		`json:"prodtype_name"`
+	ParentID 	*int 		`json:"prodtype_parent_id,omitempty"`
+	DeletedAt 	*time.Time 	`json:"prodtype_deleted_at,omitempty"`
+	Operation 	string 		`json:"operation"`
+	CreatedAt 	time.Time 	`json:"created_at"`
+}
+
+const (
+	ProductTypeEventCreated = "created"
+	ProductTypeEventUpdated = "updated"
+	ProductTypeEventDeleted = "deleted"
+)
+
+const (
+	ChangeOpInsert = "insert"
+	ChangeOpUpdate = "update"
+	ChangeOpDelete = "delete"
+)
+
+type ProductTypeChangeQuery struct {
+	Since int64 `query:"since"    validate:"gte=0"`
+	Limit int 	`query:"limit"    validate:"omitempty,min=1,max=1000"`
+}
+
+// ProductTypeChange is one entry of the change feed, a delete is a tombstone carrying only the ID.
+type ProductTypeChange struct {
+	Seq 		int64 		`json:"seq"`
+	Op 			string 		`json:"op"`
+	ID   		int    		`json:"prodtype_id"`
+	Name 		string 		`json:"prodtype_name,omitempty"`
+	ParentID 	*int 		`json:"prodtype_parent_id,omitempty"`
+	ChangedAt 	time.Time 	`json:"changed_at"`
+}
+
+type ProductTypeChangeFeed struct {
+	Changes []ProductTypeChange `json:"changes"`
+	Cursor 	int64 				`json:"cursor"`
+	HasMore bool 				`json:"has_more"`
+}
+
+type ProductTypeNode struct {
+	ID   		int    				`json:"prodtype_id"`
+	Name 		string 				`json:"prodtype_name"`
+	ParentID 	*int 				`json:"prodtype_parent_id,omitempty"`
+	Children 	[]*ProductTypeNode 	`json:"children"`
+}
+
+const (
+	DeleteChildrenReject 	= "reject"
+	DeleteChildrenCascade 	= "cascade"
+	DeleteChildrenReparent 	= "reparent"
+)
+
+const (
+	BulkModeTransactional 	= "transactional"
+	BulkModeBestEffort 		= "best_effort"
+
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+)
+
+type ProductTypeBatch struct {
+	Creates []ProductTypeEntity
+	Updates []ProductTypeEntity
+	Deletes []int
+}
+
+type ProductTypeBulkRequest struct {
+	Mode 		string 						`json:"mode"          validate:"required,oneof=transactional best_effort"`
+	Operations 	[]ProductTypeBulkOperation 	`json:"operations"    validate:"required,min=1,max=1000"`
+}
+
+type ProductTypeBulkOperation struct {
+	Op   string `json:"op"               validate:"required,oneof=create update delete"`
+	ID   int    `json:"prodtype_id"      validate:"required,gte=0"`
+	Name string `json:"prodtype_name"`
+}
+
+type ProductTypeBulkResult struct {
+	Index 	int 				`json:"index"`
+	Op 		string 				`json:"op"`
+	ID 		int 				`json:"prodtype_id"`
+	Status 	int 				`json:"status"`
+	Message string 				`json:"message,omitempty"`
+	Errors 	[]errs.ErrorMessage `json:"errors,omitempty"`
+}
+
+type ProductTypeBulkSummary struct {
+	Mode 		string 					`json:"mode"`
+	Succeeded 	int 					`json:"succeeded"`
+	Failed 		int 					`json:"failed"`
+	Results 	[]ProductTypeBulkResult `json:"results"`
+}
+
+const (
+	ImportOnConflictUpsert 	= "upsert"
+	ImportOnConflictSkip 	= "skip"
+
+	ImportActionCreate 	= "create"
+	ImportActionUpdate 	= "update"
+	ImportActionSkip 	= "skip"
+)
+
+type ProductTypeImportOptions struct {
+	Format 		string 				`json:"format"         validate:"required,oneof=csv xlsx jsonl"`
+	OnConflict 	string 				`json:"on_conflict"    validate:"required,oneof=upsert skip"`
+	DryRun 		bool 				`json:"dry_run"`
+	Mapping 	map[string]string 	`json:"mapping"`
+}
+
+type ProductTypeImportRow struct {
+	Row 		int 				`json:"row"`
+	ID 			int 				`json:"prodtype_id"`
+	Name 		string 				`json:"prodtype_name"`
+	ParentID 	*int 				`json:"prodtype_parent_id,omitempty"`
+	Action 		string 				`json:"action,omitempty"`
+	Errors 		[]errs.ErrorMessage `json:"errors,omitempty"`
+}
+
+type ProductTypeImportReport struct {
+	DryRun 	bool 					`json:"dry_run"`
+	Applied bool 					`json:"applied"`
+	Created int 					`json:"created"`
+	Updated int 					`json:"updated"`
+	Skipped int 					`json:"skipped"`
+	Invalid int 					`json:"invalid"`
+	Rows 	[]ProductTypeImportRow 	`json:"rows"`
+}
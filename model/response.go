@@ -10,6 +10,56 @@ type ProductTypesResponse struct {
 	Message []ProductType 	`json:"message"`
 }
 
+type ProductTypeTreeResponse struct {
+	Code 	int 				`json:"code"`
+	Message []*ProductTypeNode 	`json:"message"`
+}
+
+type ProductTypeRevisionsResponse struct {
+	Code 	int 					`json:"code"`
+	Message []ProductTypeRevision 	`json:"message"`
+}
+
+type ProductTypeChangesResponse struct {
+	Code 	int 					`json:"code"`
+	Message *ProductTypeChangeFeed 	`json:"message"`
+}
+
+type ProductTypesTrashResponse struct {
+	Code 	int 				`json:"code"`
+	Message []ProductTypeTrash 	`json:"message"`
+}
+
+type ProductTypeBulkResponse struct {
+	Code 	int 					`json:"code"`
+	Message *ProductTypeBulkSummary `json:"message"`
+}
+
+type ProductTypeImportResponse struct {
+	Code 	int 					`json:"code"`
+	Message *ProductTypeImportReport `json:"message"`
+}
+
+type AuditLogsResponse struct {
+	Code 	int 			`json:"code"`
+	Message *AuditLogPage 	`json:"message"`
+}
+
+type WebhookResponse struct {
+	Code 	int 		`json:"code"`
+	Message *Webhook 	`json:"message"`
+}
+
+type WebhooksResponse struct {
+	Code 	int 		`json:"code"`
+	Message []Webhook 	`json:"message"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Code 	int 					`json:"code"`
+	Message *WebhookDeliveryPage 	`json:"message"`
+}
+
 type AuthPassportResponse struct {
 	Code 	int 			`json:"code"`
 	Message *UserPassport 	`json:"message"`
@@ -26,3 +76,8 @@ type StringResponse struct {
 }
 
 
+
+type SettingsResponse struct {
+	Code 	int 		`json:"code"`
+	Message Settings 	`json:"message"`
+}
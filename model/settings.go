@@ -0,0 +1,7 @@
+package model
+
+// Settings are the settings the server applies without a restart by key, e.g. RATE_LIMIT_MAX.
+type Settings map[string]string
+
+// SettingsUpdate are the settings to change by key, a value is a string, a number or a boolean.
+type SettingsUpdate map[string]interface{}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"time"
 )
 
 type UserEntity struct {
@@ -9,6 +10,7 @@ type UserEntity struct {
 	Name 			string 		`gorm:"not null;   column:user_name;     size:40;"`
 	Email 			string 		`gorm:"not null;   column:user_email;    size:50;  unique;"`
 	Password 		string 		`gorm:"not null;   column:user_password;"`
+	DisabledAt 		*time.Time 	`gorm:"            column:user_disabled_at;"`
 }
 
 func (u UserEntity) TableName() string {
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	WebhookEventProductTypeCreated = AuditResourceProductType + "." + ProductTypeEventCreated
+	WebhookEventProductTypeUpdated = AuditResourceProductType + "." + ProductTypeEventUpdated
+	WebhookEventProductTypeDeleted = AuditResourceProductType + "." + ProductTypeEventDeleted
+
+	WebhookDeliveryPending 		= "pending"
+	WebhookDeliverySucceeded 	= "succeeded"
+	WebhookDeliveryFailed 		= "failed"
+	WebhookDeliveryDead 		= "dead"
+)
+
+// WebhookEntity is a subscription of a URL to event types, Events holds the types comma separated.
+type WebhookEntity struct {
+	ID   		int    		`gorm:"primaryKey; column:webhook_id;"`
+	URL 		string 		`gorm:"not null;   column:webhook_url;       size:255;"`
+	Events 		string 		`gorm:"not null;   column:webhook_events;    size:255;"`
+	Secret 		string 		`gorm:"not null;   column:webhook_secret;    size:128;"`
+	Active 		bool 		`gorm:"not null;   column:webhook_active;"`
+	CreatedAt 	time.Time 	`gorm:"not null;   column:webhook_created_at;"`
+	UpdatedAt 	time.Time 	`gorm:"not null;   column:webhook_updated_at;"`
+}
+
+func (w WebhookEntity) TableName() string {
+	return "webhook"
+}
+
+// WebhookDeliveryEntity is one event to send to one webhook. A failed delivery is retried at NextAttemptAt
+// until Attempts reaches the limit, then it is dead and only sent again by a manual redelivery.
+type WebhookDeliveryEntity struct {
+	ID   			int64    	`gorm:"primaryKey; column:delivery_id;"`
+	WebhookID 		int 		`gorm:"index;      column:webhook_id;"`
+	EventID 		string 		`gorm:"not null;   column:delivery_event_id;         size:64;"`
+	EventType 		string 		`gorm:"not null;   column:delivery_event_type;       size:64;"`
+	Payload 		string 		`gorm:"not null;   column:delivery_payload;          type:jsonb;"`
+	Status 			string 		`gorm:"not null;   column:delivery_status;           size:16;"`
+	Attempts 		int 		`gorm:"not null;   column:delivery_attempts;"`
+	NextAttemptAt 	time.Time 	`gorm:"not null;   column:delivery_next_attempt_at;"`
+	LastStatusCode 	*int 		`gorm:"            column:delivery_last_status_code;"`
+	LastError 		string 		`gorm:"            column:delivery_last_error;       size:1024;"`
+	DeliveredAt 	*time.Time 	`gorm:"            column:delivery_delivered_at;"`
+	CreatedAt 		time.Time 	`gorm:"not null;   column:delivery_created_at;"`
+}
+
+func (d WebhookDeliveryEntity) TableName() string {
+	return "webhook_delivery"
+}
+
+type Webhook struct {
+	ID   		int    		`json:"webhook_id"`
+	URL 		string 		`json:"url"`
+	Events 		[]string 	`json:"events"`
+	Active 		bool 		`json:"active"`
+	CreatedAt 	time.Time 	`json:"created_at"`
+	UpdatedAt 	time.Time 	`json:"updated_at"`
+}
+
+type WebhookCreate struct {
+	URL 	string 		`json:"url"       validate:"required,url,max=255"`
+	Events 	[]string 	`json:"events"    validate:"required,min=1,dive,oneof=producttype.created producttype.updated producttype.deleted"`
+	Secret 	string 		`json:"secret"    validate:"required,min=16,max=128"`
+}
+
+// WebhookUpdate replaces the URL and events, the secret is kept when it is empty.
+type WebhookUpdate struct {
+	URL 	string 		`json:"url"       validate:"required,url,max=255"`
+	Events 	[]string 	`json:"events"    validate:"required,min=1,dive,oneof=producttype.created producttype.updated producttype.deleted"`
+	Secret 	string 		`json:"secret"    validate:"omitempty,min=16,max=128"`
+	Active 	*bool 		`json:"active"`
+}
+
+type WebhookDelivery struct {
+	ID   			int64    		`json:"delivery_id"`
+	WebhookID 		int 			`json:"webhook_id"`
+	EventID 		string 			`json:"event_id"`
+	EventType 		string 			`json:"event_type"`
+	Payload 		json.RawMessage `json:"payload"                   swaggertype:"object"`
+	Status 			string 			`json:"status"`
+	Attempts 		int 			`json:"attempts"`
+	NextAttemptAt 	time.Time 		`json:"next_attempt_at"`
+	LastStatusCode 	*int 			`json:"last_status_code,omitempty"`
+	LastError 		string 			`json:"last_error,omitempty"`
+	DeliveredAt 	*time.Time 		`json:"delivered_at,omitempty"`
+	CreatedAt 		time.Time 		`json:"created_at"`
+}
+
+type WebhookDeliveryFilter struct {
+	Status 	string 	`query:"status"    validate:"omitempty,oneof=pending succeeded failed dead"`
+	Limit 	int 	`query:"limit"     validate:"omitempty,min=1,max=500"`
+	Offset 	int 	`query:"offset"    validate:"omitempty,gte=0"`
+}
+
+type WebhookDeliveryPage struct {
+	Total 	int64 				`json:"total"`
+	Limit 	int 				`json:"limit"`
+	Offset 	int 				`json:"offset"`
+	Items 	[]WebhookDelivery 	`json:"items"`
+}
+
+// WebhookPayload is the body posted to a webhook.
+type WebhookPayload struct {
+	ID 			string 		`json:"id"`
+	Type 		string 		`json:"type"`
+	CreatedAt 	time.Time 	`json:"created_at"`
+	Data 		interface{} `json:"data"`
+}
@@ -0,0 +1,215 @@
+package replica
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"gorm.io/gorm"
+
+	"context"
+	"database/sql"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCheckTimeout bounds the ping of one replica.
+const DefaultCheckTimeout = 2 * time.Second
+
+type routeKey struct{}
+
+type primaryKey struct{}
+
+// Route marks ctx so the queries run with it may be served by a replica, when method is one of the
+// routes of the Resolver. Method is named <Repository>.<Method>, e.g. ProductTypeRepository.FindAll.
+func Route(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, routeKey{}, method)
+}
+
+// Primary keeps the queries run with ctx on the primary whatever their route, for the reads a write
+// depends on, which a replica lagging behind would answer with an older state.
+func Primary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Replica is a read only copy of the primary database.
+type Replica struct {
+	Name 	string
+	DB 		*sql.DB
+}
+
+type member struct {
+	Replica
+	healthy atomic.Bool
+}
+
+// Resolver sends the queries of the routed methods to a healthy replica, taking turns, and the
+// others to the primary: writes, transactions and reads that must see the writes before them.
+// With no healthy replica the routed queries fail over to the primary too.
+type Resolver struct {
+	members 	[]*member
+	routes 		map[string]bool
+	next 		atomic.Uint64
+	interval 	time.Duration
+	stop 		chan struct{}
+	done 		chan struct{}
+}
+
+// NewResolver routes methods to replicas, which are checked every interval once started.
+// The replicas count as healthy until the first check says otherwise.
+func NewResolver(methods []string, interval time.Duration, replicas ...Replica) *Resolver {
+	routes := map[string]bool{}
+	for _, method := range methods {
+		if method = strings.TrimSpace(method); method != "" {
+			routes[method] = true
+		}
+	}
+	members := make([]*member, 0, len(replicas))
+	for _, replica := range replicas {
+		member := &member{Replica: replica}
+		member.healthy.Store(true)
+		members = append(members, member)
+	}
+	return &Resolver{
+		members: 	members,
+		routes: 	routes,
+		interval: 	interval,
+		stop: 		make(chan struct{}),
+		done: 		make(chan struct{}),
+	}
+}
+
+func (r *Resolver) Name() string {
+	return "replica"
+}
+
+func (r *Resolver) Initialize(db *gorm.DB) error {
+	registrations := []func() error{
+		func() error { return db.Callback().Query().Before("gorm:query").Register("replica:before_query", r.route) },
+		func() error { return db.Callback().Query().After("gorm:query").Register("replica:after_query", r.failover) },
+		func() error { return db.Callback().Row().Before("gorm:row").Register("replica:before_row", r.route) },
+		func() error { return db.Callback().Row().After("gorm:row").Register("replica:after_row", r.failover) },
+	}
+	for _, register := range registrations {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// route swaps the connection of the statement for a replica one when its method is routed.
+func (r *Resolver) route(db *gorm.DB) {
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	ctx := db.Statement.Context
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return
+	}
+	method, _ := ctx.Value(routeKey{}).(string)
+	if !r.routes[method] {
+		return
+	}
+	if member := r.pick(); member != nil {
+		db.Statement.ConnPool = member.DB
+	}
+}
+
+// failover takes a replica out of the turns when it fails to answer, until a check finds it back.
+func (r *Resolver) failover(db *gorm.DB) {
+	if db.Error == nil || !config.IsTransientError(db.Error) {
+		return
+	}
+	for _, member := range r.members {
+		if db.Statement.ConnPool == gorm.ConnPool(member.DB) && member.healthy.Swap(false) {
+			logger.Error(db.Statement.Context, db.Error, "replica", member.Name)
+		}
+	}
+}
+
+func (r *Resolver) pick() *member {
+	for range r.members {
+		member := r.members[r.next.Add(1) % uint64(len(r.members))]
+		if member.healthy.Load() {
+			return member
+		}
+	}
+	return nil
+}
+
+// Served names the replica whose connection pool is pool, or primary.
+func (r *Resolver) Served(pool gorm.ConnPool) string {
+	for _, member := range r.members {
+		if pool == gorm.ConnPool(member.DB) {
+			return member.Name
+		}
+	}
+	return "primary"
+}
+
+// Healthy lists the replicas taking reads.
+func (r *Resolver) Healthy() []string {
+	var names []string
+	for _, member := range r.members {
+		if member.healthy.Load() {
+			names = append(names, member.Name)
+		}
+	}
+	return names
+}
+
+// Check pings every replica and takes the ones that do not answer out of the turns.
+func (r *Resolver) Check(ctx context.Context) {
+	for _, member := range r.members {
+		pingCtx, cancel := context.WithTimeout(ctx, DefaultCheckTimeout)
+		err := member.DB.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if member.healthy.Swap(healthy) != healthy {
+			if healthy {
+				logger.Info(ctx, "Replica: Back in service", "replica", member.Name)
+			} else {
+				logger.Error(ctx, err, "replica", member.Name)
+			}
+		}
+	}
+}
+
+// Start checks the replicas on every interval until Stop is called. Without replicas or
+// an interval there is nothing to check.
+func (r *Resolver) Start() {
+	if len(r.members) == 0 || r.interval <= 0 {
+		close(r.done)
+		return
+	}
+	r.Check(context.Background())
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.Check(context.Background())
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the checks and closes the replica connections.
+func (r *Resolver) Stop() {
+	close(r.stop)
+	<-r.done
+	for _, member := range r.members {
+		if err := member.DB.Close(); err != nil {
+			logger.Error(context.Background(), err, "replica", member.Name)
+		}
+	}
+}
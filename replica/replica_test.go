@@ -0,0 +1,128 @@
+package replica_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Yoshikrit/fiber-test/replica"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/testutils"
+)
+
+const countQuery = `SELECT count\(\*\) FROM "producttype"`
+
+func countRows(count int) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"count"}).AddRow(count)
+}
+
+// setupResolver returns the primary with a resolver of one replica routing ProductTypeRepository.Count
+// and OauthRepository.FindByAccessToken, each connection with its own mock.
+func setupResolver(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, sqlmock.Sqlmock, *replica.Resolver) {
+	primary, primaryMock := testutils.SetupMockDB(t)
+	replicaDB, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+
+	resolver := replica.NewResolver([]string{"ProductTypeRepository.Count", " OauthRepository.FindByAccessToken"}, time.Minute, replica.Replica{Name: "replica-1", DB: replicaDB})
+	require.NoError(t, primary.Use(resolver))
+	return primary, primaryMock, replicaMock, resolver
+}
+
+func TestResolver(t *testing.T) {
+	t.Run("test case : routed read is served by the replica", func(t *testing.T) {
+		primary, primaryMock, replicaMock, _ := setupResolver(t)
+		replicaMock.ExpectQuery(countQuery).WillReturnRows(countRows(3))
+
+		count, err := repository.NewProductTypeRepositoryImpl(primary, 1).Count(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : read that is not routed is served by the primary", func(t *testing.T) {
+		primary, primaryMock, replicaMock, _ := setupResolver(t)
+		primaryMock.ExpectQuery(`SELECT \* FROM "producttype"`).WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name"}))
+
+		_, err := repository.NewProductTypeRepositoryImpl(primary, 1).FindAll(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : read for a write is served by the primary", func(t *testing.T) {
+		primary, primaryMock, replicaMock, _ := setupResolver(t)
+		primaryMock.ExpectQuery(countQuery).WillReturnRows(countRows(3))
+
+		_, err := repository.NewProductTypeRepositoryImpl(primary, 1).Count(replica.Primary(context.Background()))
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : read in a transaction is served by the primary", func(t *testing.T) {
+		primary, primaryMock, replicaMock, _ := setupResolver(t)
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectQuery(countQuery).WillReturnRows(countRows(3))
+		primaryMock.ExpectCommit()
+
+		err := repository.NewTxManagerImpl(primary).Do(context.Background(), func(tx repository.Tx) error {
+			_, err := tx.ProductTypes().Count(context.Background())
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : failing replica fails over to the primary", func(t *testing.T) {
+		primary, primaryMock, replicaMock, resolver := setupResolver(t)
+		replicaMock.ExpectQuery(countQuery).WillReturnError(testutils.SQLStateError("57P01"))
+		primaryMock.ExpectQuery(countQuery).WillReturnRows(countRows(3))
+
+		count, err := repository.NewProductTypeRepositoryImpl(primary, 1).Count(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.Empty(t, resolver.Healthy())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : check takes the replica out and back in", func(t *testing.T) {
+		_, _, replicaMock, resolver := setupResolver(t)
+		replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		replicaMock.ExpectPing()
+
+		resolver.Check(context.Background())
+		assert.Empty(t, resolver.Healthy())
+
+		resolver.Check(context.Background())
+		assert.Equal(t, []string{"replica-1"}, resolver.Healthy())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : token missing on the replica is confirmed on the primary", func(t *testing.T) {
+		primary, primaryMock, replicaMock, _ := setupResolver(t)
+		tokenQuery := `SELECT \* FROM "oauth" WHERE oauth_id = \$1 AND access_token = \$2`
+		replicaMock.ExpectQuery(tokenQuery).WillReturnRows(sqlmock.NewRows([]string{"oauth_id"}))
+		primaryMock.ExpectQuery(tokenQuery).WillReturnRows(sqlmock.NewRows([]string{"oauth_id"}).AddRow(1))
+
+		oauthEntity, err := repository.NewOauthRepositoryImpl(primary, 1).FindByAccessToken(context.Background(), 1, "token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, oauthEntity.ID)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+}
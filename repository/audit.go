@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+type AuditRepository interface {
+	Create(context.Context, *model.AuditLogEntity) error
+	FindAll(context.Context, *model.AuditLogFilter) ([]model.AuditLogEntity, int64, error)
+}
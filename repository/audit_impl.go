@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryImpl struct {
+	db 			*gorm.DB
+	readRetries int
+}
+
+func NewAuditRepositoryImpl(db *gorm.DB, readRetries int) AuditRepository {
+	return &AuditRepositoryImpl{db: db, readRetries: readRetries}
+}
+
+func (r *AuditRepositoryImpl) Create(ctx context.Context, auditEntity *model.AuditLogEntity) error {
+	if err := r.db.WithContext(ctx).Create(auditEntity).Error; err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// FindAll returns one page of audit entries, newest first, together with the total matching the filter.
+func (r *AuditRepositoryImpl) FindAll(ctx context.Context, filter *model.AuditLogFilter) ([]model.AuditLogEntity, int64, error) {
+	var total int64
+	err := read(ctx, r.db, r.readRetries, "AuditRepository.FindAll", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.AuditLogEntity{}).Scopes(auditLogFilterScope(filter)).Count(&total).Error
+	})
+	if err != nil {
+		return nil, 0, errs.NewInternalServerError(err.Error())
+	}
+
+	var auditEntities []model.AuditLogEntity
+	err = read(ctx, r.db, r.readRetries, "AuditRepository.FindAll", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Scopes(auditLogFilterScope(filter)).
+			Order("audit_created_at DESC, audit_id DESC").
+			Limit(filter.Limit).
+			Offset(filter.Offset).
+			Find(&auditEntities).Error
+	})
+	if err != nil {
+		return nil, 0, errs.NewInternalServerError(err.Error())
+	}
+	return auditEntities, total, nil
+}
+
+func auditLogFilterScope(filter *model.AuditLogFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.ActorID != nil {
+			db = db.Where("audit_actor_user_id = ?", *filter.ActorID)
+		}
+		if filter.Action != "" {
+			db = db.Where("audit_action = ?", filter.Action)
+		}
+		if filter.ResourceType != "" {
+			db = db.Where("audit_resource_type = ?", filter.ResourceType)
+		}
+		if filter.ResourceID != "" {
+			db = db.Where("audit_resource_id = ?", filter.ResourceID)
+		}
+		if filter.RequestID != "" {
+			db = db.Where("audit_request_id = ?", filter.RequestID)
+		}
+		if filter.From != nil {
+			db = db.Where("audit_created_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("audit_created_at < ?", *filter.To)
+		}
+		return db
+	}
+}
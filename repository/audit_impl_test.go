@@ -0,0 +1,115 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+)
+
+func TestAuditCreate(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	actorID, after := 7, `{"prodtype_id":1,"prodtype_name":"A"}`
+	auditMock := &model.AuditLogEntity{
+		ActorID: 		&actorID,
+		Action: 		model.AuditActionCreate,
+		ResourceType: 	model.AuditResourceProductType,
+		ResourceID: 	"1",
+		After: 			&after,
+		RequestID: 		"req-1",
+		IP: 			"127.0.0.1",
+		CreatedAt: 		time.Now(),
+	}
+
+	t.Run("test case : create audit log success", func(t *testing.T) {
+		repo := repository.NewAuditRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "audit_log"`).
+			WithArgs(&actorID, "", "create", "producttype", "1", nil, &after, "req-1", "127.0.0.1", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"audit_id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		err := repo.Create(context.Background(), auditMock)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), auditMock.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : create audit log fail", func(t *testing.T) {
+		repo := repository.NewAuditRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "audit_log"`).
+			WillReturnError(errors.New("Unexpected Error"))
+		mock.ExpectRollback()
+
+		err := repo.Create(context.Background(), &model.AuditLogEntity{Action: model.AuditActionCreate, ResourceType: model.AuditResourceProductType})
+
+		expectedErr := errs.NewInternalServerError("Unexpected Error")
+		assert.Error(t, err)
+		assert.Equal(t, expectedErr, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAuditFindAll(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find all audit logs with filter success", func(t *testing.T) {
+		repo := repository.NewAuditRepositoryImpl(db, 0)
+		actorID := 7
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		filter := &model.AuditLogFilter{ActorID: &actorID, ResourceType: "producttype", From: &from, Limit: 10, Offset: 20}
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "audit_log" WHERE audit_actor_user_id = $1 AND audit_resource_type = $2 AND audit_created_at >= $3`)).
+			WithArgs(7, "producttype", from).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_log" WHERE audit_actor_user_id = $1 AND audit_resource_type = $2 AND audit_created_at >= $3 ORDER BY audit_created_at DESC, audit_id DESC LIMIT $4 OFFSET $5`)).
+			WithArgs(7, "producttype", from, 10, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"audit_id", "audit_actor_user_id", "audit_action", "audit_resource_type", "audit_resource_id"}).
+				AddRow(21, 7, "delete", "producttype", "1"))
+
+		auditEntities, total, err := repo.FindAll(context.Background(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(21), total)
+		assert.Equal(t, []model.AuditLogEntity{{ID: 21, ActorID: &actorID, Action: "delete", ResourceType: "producttype", ResourceID: "1"}}, auditEntities)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find all audit logs fail count", func(t *testing.T) {
+		repo := repository.NewAuditRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "audit_log"`)).
+			WillReturnError(errors.New("Unexpected Error"))
+
+		auditEntities, total, err := repo.FindAll(context.Background(), &model.AuditLogFilter{Limit: 50})
+
+		expectedErr := errs.NewInternalServerError("Unexpected Error")
+		assert.Error(t, err)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, auditEntities)
+		assert.Equal(t, int64(0), total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,817 @@
+// Package conformance checks that every storage backend of the repositories behaves the same,
+// the errors included, so the services can not tell Postgres, SQLite and memory apart.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	notFound 		= "record not found"
+	duplicatedKey 	= "duplicated key not allowed"
+)
+
+// Run runs the suite on the store open returns, which must be a new database holding only the rows
+// the migrations insert: the roles Manager, Admin and Customer, the user 1 and the product types
+// 1 Food, 2 Drink and 3 Snack with their first revision.
+func Run(t *testing.T, open func(t *testing.T) *repository.Store) {
+	t.Run("product types", func(t *testing.T) {
+		runProductTypes(t, open)
+	})
+	t.Run("product type tree", func(t *testing.T) {
+		runProductTypeTree(t, open)
+	})
+	t.Run("product type revisions", func(t *testing.T) {
+		runProductTypeRevisions(t, open)
+	})
+	t.Run("users", func(t *testing.T) {
+		runUsers(t, open)
+	})
+	t.Run("roles", func(t *testing.T) {
+		runRoles(t, open)
+	})
+	t.Run("oauths", func(t *testing.T) {
+		runOauths(t, open)
+	})
+	t.Run("transactions", func(t *testing.T) {
+		runTransactions(t, open)
+	})
+}
+
+func runProductTypes(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+	food, drink, snack := model.ProductTypeEntity{ID: 1, Name: "Food"}, model.ProductTypeEntity{ID: 2, Name: "Drink"}, model.ProductTypeEntity{ID: 3, Name: "Snack"}
+
+	t.Run("test case : find all and count the seeded rows", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		prodTypesEntity, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductTypeEntity{food, drink, snack}, prodTypesEntity)
+
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("test case : find by id", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		prodTypeEntity, err := repo.FindByID(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &drink, prodTypeEntity)
+
+		_, err = repo.FindByID(ctx, 99)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : save", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		parentID := 1
+
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit", ParentID: &parentID}))
+
+		prodTypeEntity, err := repo.FindByID(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, &model.ProductTypeEntity{ID: 10, Name: "Fruit", ParentID: &parentID}, prodTypeEntity)
+	})
+
+	t.Run("test case : save fail duplicated id is a conflict", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		err := repo.Save(ctx, &model.ProductTypeEntity{ID: 1, Name: "Other"})
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), err)
+
+		// a soft deleted row keeps its key
+		require.NoError(t, repo.Delete(ctx, 2))
+		err = repo.Save(ctx, &model.ProductTypeEntity{ID: 2, Name: "Other"})
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), err)
+	})
+
+	t.Run("test case : save fail unknown parent", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		parentID := 99
+
+		err := repo.Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit", ParentID: &parentID})
+		assert.Equal(t, http.StatusInternalServerError, statusOf(err))
+
+		_, err = repo.FindByID(ctx, 10)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : update sets the given fields", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		require.NoError(t, repo.Update(ctx, &model.ProductTypeEntity{ID: 3, Name: "Snacks"}))
+
+		prodTypeEntity, err := repo.FindByID(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, &model.ProductTypeEntity{ID: 3, Name: "Snacks"}, prodTypeEntity)
+	})
+
+	t.Run("test case : delete keeps the row in the trash until restored", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		require.NoError(t, repo.Delete(ctx, 1))
+
+		_, err := repo.FindByID(ctx, 1)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		deleted, err := repo.FindDeletedByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Food", deleted.Name)
+		assert.True(t, deleted.DeletedAt.Valid)
+		trash, err := repo.FindAllDeleted(ctx)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, 1, trash[0].ID)
+
+		_, err = repo.FindDeletedByID(ctx, 2)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+
+		require.NoError(t, repo.Restore(ctx, 1))
+		prodTypeEntity, err := repo.FindByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &food, prodTypeEntity)
+	})
+
+	t.Run("test case : purge detaches the children", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		parentID := 1
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit", ParentID: &parentID}))
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: 11, Name: "Rice", ParentID: &parentID}))
+		require.NoError(t, repo.Delete(ctx, 11))
+		require.NoError(t, repo.Delete(ctx, 1))
+
+		children, err := repo.Purge(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, children, 2)
+		assert.Equal(t, []int{10, 11}, []int{children[0].ID, children[1].ID})
+		assert.Equal(t, &parentID, children[0].ParentID)
+		assert.True(t, children[1].DeletedAt.Valid)
+
+		prodTypesEntity, err := repo.FindByIDsUnscoped(ctx, []int{1, 10, 11})
+		require.NoError(t, err)
+		require.Len(t, prodTypesEntity, 2)
+		assert.Nil(t, prodTypesEntity[0].ParentID)
+		assert.Nil(t, prodTypesEntity[1].ParentID)
+	})
+
+	t.Run("test case : purge deleted before", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Delete(ctx, 1))
+		require.NoError(t, repo.Delete(ctx, 2))
+
+		purged, _, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, purged)
+
+		purged, _, err = repo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, purged, 2)
+		assert.Equal(t, []int{1, 2}, []int{purged[0].ID, purged[1].ID})
+
+		trash, err := repo.FindAllDeleted(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("test case : find by ids unscoped", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Delete(ctx, 2))
+
+		prodTypesEntity, err := repo.FindByIDsUnscoped(ctx, []int{2, 3, 99})
+		require.NoError(t, err)
+		require.Len(t, prodTypesEntity, 2)
+		ids := []int{prodTypesEntity[0].ID, prodTypesEntity[1].ID}
+		assert.ElementsMatch(t, []int{2, 3}, ids)
+	})
+
+	t.Run("test case : apply batch", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		err := repo.ApplyBatch(ctx, &model.ProductTypeBatch{
+			Creates: []model.ProductTypeEntity{{ID: 10, Name: "Fruit"}, {ID: 11, Name: "Nuts"}},
+			Updates: []model.ProductTypeEntity{{ID: 1, Name: "Meal"}},
+			Deletes: []int{2},
+		})
+		require.NoError(t, err)
+
+		prodTypesEntity, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductTypeEntity{{ID: 1, Name: "Meal"}, snack, {ID: 10, Name: "Fruit"}, {ID: 11, Name: "Nuts"}}, prodTypesEntity)
+	})
+
+	t.Run("test case : apply batch fail writes nothing", func(t *testing.T) {
+		repo := open(t).ProductTypes
+
+		err := repo.ApplyBatch(ctx, &model.ProductTypeBatch{
+			Creates: []model.ProductTypeEntity{{ID: 10, Name: "Fruit"}, {ID: 1, Name: "Food"}},
+			Deletes: []int{2},
+		})
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), err)
+
+		prodTypesEntity, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductTypeEntity{food, drink, snack}, prodTypesEntity)
+	})
+
+	t.Run("test case : find all after in key order", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit"}))
+		require.NoError(t, repo.Delete(ctx, 2))
+
+		var pages [][]int
+		for afterID := 0; ; {
+			prodTypesEntity, err := repo.FindAllAfter(ctx, afterID, 2)
+			require.NoError(t, err)
+			if len(prodTypesEntity) == 0 {
+				break
+			}
+			var ids []int
+			for _, prodTypeEntity := range prodTypesEntity {
+				ids = append(ids, prodTypeEntity.ID)
+			}
+			pages = append(pages, ids)
+			afterID = ids[len(ids)-1]
+		}
+		assert.Equal(t, [][]int{{1, 3}, {10}}, pages)
+	})
+}
+
+func runProductTypeTree(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+	food, snack, chips, nuts := 1, 3, 10, 11
+
+	// Food > Snack > Chips and Nuts, Drink stays a root
+	setup := func(t *testing.T) repository.ProductTypeRepository {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Move(ctx, snack, &food))
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: chips, Name: "Chips", ParentID: &snack}))
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: nuts, Name: "Nuts", ParentID: &snack}))
+		return repo
+	}
+
+	t.Run("test case : find children", func(t *testing.T) {
+		repo := setup(t)
+
+		children, err := repo.FindChildren(ctx, snack)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductTypeEntity{
+			{ID: chips, Name: "Chips", ParentID: &snack},
+			{ID: nuts, Name: "Nuts", ParentID: &snack},
+		}, children)
+
+		children, err = repo.FindChildren(ctx, chips)
+		require.NoError(t, err)
+		assert.Empty(t, children)
+	})
+
+	t.Run("test case : find ancestors from the root down", func(t *testing.T) {
+		repo := setup(t)
+
+		ancestors, err := repo.FindAncestors(ctx, chips)
+		require.NoError(t, err)
+		assert.Equal(t, []model.ProductTypeEntity{
+			{ID: food, Name: "Food"},
+			{ID: snack, Name: "Snack", ParentID: &food},
+		}, ancestors)
+
+		ancestors, err = repo.FindAncestors(ctx, food)
+		require.NoError(t, err)
+		assert.Empty(t, ancestors)
+	})
+
+	t.Run("test case : move to the root", func(t *testing.T) {
+		repo := setup(t)
+
+		require.NoError(t, repo.Move(ctx, snack, nil))
+
+		prodTypeEntity, err := repo.FindByID(ctx, snack)
+		require.NoError(t, err)
+		assert.Nil(t, prodTypeEntity.ParentID)
+	})
+
+	t.Run("test case : move fail under itself", func(t *testing.T) {
+		repo := setup(t)
+
+		err := repo.Move(ctx, snack, &snack)
+		assert.Equal(t, http.StatusInternalServerError, statusOf(err))
+	})
+
+	t.Run("test case : delete subtree returns the deleted rows top down", func(t *testing.T) {
+		repo := setup(t)
+
+		deleted, err := repo.DeleteSubtree(ctx, snack)
+		require.NoError(t, err)
+		require.Len(t, deleted, 3)
+		assert.Equal(t, model.ProductTypeEntity{ID: snack, Name: "Snack", ParentID: &food}, deleted[0])
+		assert.ElementsMatch(t, []int{chips, nuts}, []int{deleted[1].ID, deleted[2].ID})
+
+		prodTypesEntity, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductTypeEntity{{ID: 1, Name: "Food"}, {ID: 2, Name: "Drink"}}, prodTypesEntity)
+
+		deleted, err = repo.DeleteSubtree(ctx, 99)
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+	})
+
+	t.Run("test case : delete and reparent", func(t *testing.T) {
+		repo := setup(t)
+
+		moved, err := repo.DeleteAndReparent(ctx, snack, &food)
+		require.NoError(t, err)
+		require.Len(t, moved, 2)
+		assert.Equal(t, []int{chips, nuts}, []int{moved[0].ID, moved[1].ID})
+		assert.Equal(t, &snack, moved[0].ParentID)
+
+		children, err := repo.FindChildren(ctx, food)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.ProductTypeEntity{
+			{ID: chips, Name: "Chips", ParentID: &food},
+			{ID: nuts, Name: "Nuts", ParentID: &food},
+		}, children)
+		_, err = repo.FindByID(ctx, snack)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+}
+
+func runProductTypeRevisions(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+
+	t.Run("test case : every write is a revision", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Update(ctx, &model.ProductTypeEntity{ID: 1, Name: "Meal"}))
+		require.NoError(t, repo.Delete(ctx, 1))
+		require.NoError(t, repo.Restore(ctx, 1))
+
+		revisionsEntity, err := repo.FindRevisions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, revisionsEntity, 4)
+		var operations []string
+		for i, revisionEntity := range revisionsEntity {
+			assert.Equal(t, 4 - i, revisionEntity.Revision)
+			operations = append(operations, revisionEntity.Operation)
+		}
+		assert.Equal(t, []string{model.RevisionOpRestore, model.RevisionOpDelete, model.RevisionOpUpdate, model.RevisionOpCreate}, operations)
+		assert.Equal(t, "Meal", revisionsEntity[0].Name)
+		assert.Nil(t, revisionsEntity[0].DeletedAt)
+		assert.NotNil(t, revisionsEntity[1].DeletedAt)
+	})
+
+	t.Run("test case : find revision", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Update(ctx, &model.ProductTypeEntity{ID: 2, Name: "Drinks"}))
+
+		revisionEntity, err := repo.FindRevision(ctx, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Drinks", revisionEntity.Name)
+		assert.Equal(t, model.RevisionOpUpdate, revisionEntity.Operation)
+
+		_, err = repo.FindRevision(ctx, 2, 3)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : find revision as of", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		before := time.Now()
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, repo.Update(ctx, &model.ProductTypeEntity{ID: 2, Name: "Drinks"}))
+
+		revisionEntity, err := repo.FindRevisionAsOf(ctx, 2, before)
+		require.NoError(t, err)
+		assert.Equal(t, 1, revisionEntity.Revision)
+
+		revisionEntity, err = repo.FindRevisionAsOf(ctx, 2, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 2, revisionEntity.Revision)
+
+		_, err = repo.FindRevisionAsOf(ctx, 2, before.Add(-24 * time.Hour))
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : revert records a new revision", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Update(ctx, &model.ProductTypeEntity{ID: 2, Name: "Drinks"}))
+		first, err := repo.FindRevision(ctx, 2, 1)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Revert(ctx, first))
+
+		prodTypeEntity, err := repo.FindByID(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Drink", prodTypeEntity.Name)
+		latest, err := repo.FindRevision(ctx, 2, 3)
+		require.NoError(t, err)
+		assert.Equal(t, model.RevisionOpRevert, latest.Operation)
+	})
+
+	t.Run("test case : purge records a move for the children only", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		parentID := 1
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit", ParentID: &parentID}))
+		require.NoError(t, repo.Delete(ctx, 1))
+
+		_, err := repo.Purge(ctx, 1)
+		require.NoError(t, err)
+
+		revisionsEntity, err := repo.FindRevisions(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, revisionsEntity, 2)
+		assert.Equal(t, model.RevisionOpMove, revisionsEntity[0].Operation)
+		assert.Nil(t, revisionsEntity[0].ParentID)
+
+		revisionsEntity, err = repo.FindRevisions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, revisionsEntity, 2)
+		assert.Equal(t, model.RevisionOpDelete, revisionsEntity[0].Operation)
+	})
+
+	t.Run("test case : find changes after the cursor", func(t *testing.T) {
+		repo := open(t).ProductTypes
+		require.NoError(t, repo.Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit"}))
+		require.NoError(t, repo.Delete(ctx, 10))
+
+		seeded, err := repo.FindChanges(ctx, 0, 100)
+		require.NoError(t, err)
+		require.Len(t, seeded, 5)
+		for i := 1; i < len(seeded); i++ {
+			assert.Greater(t, seeded[i].ID, seeded[i - 1].ID)
+		}
+
+		changes, err := repo.FindChanges(ctx, seeded[2].ID, 1)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, 10, changes[0].ProductTypeID)
+		assert.Equal(t, model.RevisionOpCreate, changes[0].Operation)
+
+		changes, err = repo.FindChanges(ctx, seeded[4].ID, 100)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+func runUsers(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+	alyx := model.UserEntity{ID: 2, RoleID: 2, Name: "Alyx Vance", Email: "alyx_vance@gmail.com", Password: "hash"}
+
+	t.Run("test case : create and find", func(t *testing.T) {
+		repo := open(t).Users
+		userCreateReq := alyx
+
+		require.NoError(t, repo.Create(ctx, &userCreateReq))
+
+		userEntity, err := repo.FindByID(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &alyx, userEntity)
+		userEntity, err = repo.FindByEmail(ctx, "alyx_vance@gmail.com")
+		require.NoError(t, err)
+		assert.Equal(t, &alyx, userEntity)
+	})
+
+	t.Run("test case : find fail not found", func(t *testing.T) {
+		repo := open(t).Users
+
+		_, err := repo.FindByID(ctx, 99)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+		_, err = repo.FindByEmail(ctx, "nobody@gmail.com")
+		assert.Equal(t, errs.NewNotFoundError("Email or Password is incorrect"), err)
+	})
+
+	t.Run("test case : create fail duplicated id or email is a conflict", func(t *testing.T) {
+		repo := open(t).Users
+
+		duplicatedID := alyx
+		duplicatedID.ID = 1
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), repo.Create(ctx, &duplicatedID))
+
+		duplicatedEmail := alyx
+		duplicatedEmail.Email = "gordon_freeman@gmail.com"
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), repo.Create(ctx, &duplicatedEmail))
+	})
+
+	t.Run("test case : create fail unknown role", func(t *testing.T) {
+		repo := open(t).Users
+		userCreateReq := alyx
+		userCreateReq.RoleID = 99
+
+		assert.Equal(t, http.StatusInternalServerError, statusOf(repo.Create(ctx, &userCreateReq)))
+	})
+
+	t.Run("test case : update saves every column", func(t *testing.T) {
+		repo := open(t).Users
+		userEntity, err := repo.FindByID(ctx, 1)
+		require.NoError(t, err)
+		disabledAt := time.Now()
+		userEntity.Name = "Dr. Freeman"
+		userEntity.DisabledAt = &disabledAt
+
+		require.NoError(t, repo.Update(ctx, userEntity))
+
+		updated, err := repo.FindByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Dr. Freeman", updated.Name)
+		require.NotNil(t, updated.DisabledAt)
+		assert.WithinDuration(t, disabledAt, *updated.DisabledAt, time.Second)
+
+		updated.DisabledAt = nil
+		require.NoError(t, repo.Update(ctx, updated))
+		enabled, err := repo.FindByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, enabled.DisabledAt)
+	})
+
+	t.Run("test case : update fail email of another user is a conflict", func(t *testing.T) {
+		repo := open(t).Users
+		userCreateReq := alyx
+		require.NoError(t, repo.Create(ctx, &userCreateReq))
+
+		userCreateReq.Email = "gordon_freeman@gmail.com"
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), repo.Update(ctx, &userCreateReq))
+	})
+}
+
+func runRoles(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+
+	t.Run("test case : find seeded roles", func(t *testing.T) {
+		repo := open(t).Roles
+
+		roleEntity, err := repo.FindByTitle(ctx, "Admin")
+		require.NoError(t, err)
+		assert.Equal(t, &model.RoleEntity{ID: 2, Title: "Admin"}, roleEntity)
+
+		roleEntity, err = repo.FindByID(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, &model.RoleEntity{ID: 3, Title: "Customer"}, roleEntity)
+	})
+
+	t.Run("test case : find fail not found", func(t *testing.T) {
+		repo := open(t).Roles
+
+		_, err := repo.FindByID(ctx, 99)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+		_, err = repo.FindByTitle(ctx, "Owner")
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+}
+
+func runOauths(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+
+	t.Run("test case : create gives the next id", func(t *testing.T) {
+		repo := open(t).Oauths
+		first := model.OauthEntity{UserID: 1, AccessToken: "access-1", RefreshToken: "refresh-1"}
+		second := model.OauthEntity{UserID: 1, AccessToken: "access-2", RefreshToken: "refresh-2"}
+
+		require.NoError(t, repo.Create(ctx, &first))
+		require.NoError(t, repo.Create(ctx, &second))
+
+		assert.Equal(t, 1, first.ID)
+		assert.Equal(t, 2, second.ID)
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("test case : create fail unknown user", func(t *testing.T) {
+		repo := open(t).Oauths
+
+		err := repo.Create(ctx, &model.OauthEntity{UserID: 99, AccessToken: "access", RefreshToken: "refresh"})
+		assert.Equal(t, http.StatusInternalServerError, statusOf(err))
+	})
+
+	t.Run("test case : find by tokens", func(t *testing.T) {
+		repo := open(t).Oauths
+		oauthEntity := model.OauthEntity{UserID: 1, AccessToken: "access", RefreshToken: "refresh"}
+		require.NoError(t, repo.Create(ctx, &oauthEntity))
+
+		found, err := repo.FindByAccessToken(ctx, oauthEntity.ID, "access")
+		require.NoError(t, err)
+		assert.Equal(t, &oauthEntity, found)
+		found, err = repo.FindByRefleshToken(ctx, "refresh")
+		require.NoError(t, err)
+		assert.Equal(t, &oauthEntity, found)
+		found, err = repo.FindByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &oauthEntity, found)
+		found, err = repo.FindByID(ctx, oauthEntity.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &oauthEntity, found)
+	})
+
+	t.Run("test case : find fail maps to the error of the method", func(t *testing.T) {
+		repo := open(t).Oauths
+		oauthEntity := model.OauthEntity{UserID: 1, AccessToken: "access", RefreshToken: "refresh"}
+		require.NoError(t, repo.Create(ctx, &oauthEntity))
+
+		_, err := repo.FindByAccessToken(ctx, oauthEntity.ID, "forged")
+		assert.Equal(t, errs.NewUnauthorizedError(notFound), err)
+		_, err = repo.FindByRefleshToken(ctx, "forged")
+		assert.Equal(t, errs.NewUnauthorizedError("Reflesh Token is incorrect"), err)
+		_, err = repo.FindByRefleshTokenForUpdate(ctx, "forged")
+		assert.Equal(t, errs.NewUnauthorizedError("Reflesh Token is incorrect"), err)
+		_, err = repo.FindByUserID(ctx, 99)
+		assert.Equal(t, errs.NewNotFoundError("Email or Password is incorrect"), err)
+		_, err = repo.FindByID(ctx, 99)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : update sets the given tokens", func(t *testing.T) {
+		repo := open(t).Oauths
+		oauthEntity := model.OauthEntity{UserID: 1, AccessToken: "access", RefreshToken: "refresh"}
+		require.NoError(t, repo.Create(ctx, &oauthEntity))
+
+		require.NoError(t, repo.Update(ctx, &model.OauthEntity{ID: oauthEntity.ID, AccessToken: "access-2"}))
+
+		found, err := repo.FindByID(ctx, oauthEntity.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &model.OauthEntity{ID: oauthEntity.ID, UserID: 1, AccessToken: "access-2", RefreshToken: "refresh"}, found)
+	})
+
+	t.Run("test case : delete", func(t *testing.T) {
+		repo := open(t).Oauths
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repo.Create(ctx, &model.OauthEntity{UserID: 1, AccessToken: "access", RefreshToken: "refresh"}))
+		}
+
+		require.NoError(t, repo.Delete(ctx, 1))
+		_, err := repo.FindByID(ctx, 1)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+
+		deleted, err := repo.DeleteByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func runTransactions(t *testing.T, open func(t *testing.T) *repository.Store) {
+	ctx := context.Background()
+
+	t.Run("test case : commit writes together", func(t *testing.T) {
+		store := open(t)
+
+		err := store.TxManager.Do(ctx, func(tx repository.Tx) error {
+			if err := tx.ProductTypes().Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit"}); err != nil {
+				return err
+			}
+			return tx.Outbox().Save(ctx, []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "10", EventType: "created"}})
+		})
+		require.NoError(t, err)
+
+		_, err = store.ProductTypes.FindByID(ctx, 10)
+		assert.NoError(t, err)
+		events, err := store.Outbox.FindUnpublished(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "10", events[0].AggregateID)
+	})
+
+	t.Run("test case : failed event keeps its retry state until it is dead", func(t *testing.T) {
+		store := open(t)
+		require.NoError(t, store.Outbox.Save(ctx, []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "10", EventType: "created"}}))
+		events, err := store.Outbox.FindUnpublished(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		nextAttemptAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+		events[0].Attempts = 1
+		events[0].LastError = "webhook: unavailable"
+		events[0].PublishedSinks = "broker"
+		events[0].NextAttemptAt = &nextAttemptAt
+		require.NoError(t, store.Outbox.MarkFailed(ctx, &events[0]))
+
+		events, err = store.Outbox.FindUnpublished(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, 1, events[0].Attempts)
+		assert.Equal(t, "broker", events[0].PublishedSinks)
+		require.NotNil(t, events[0].NextAttemptAt)
+		assert.True(t, nextAttemptAt.Equal(*events[0].NextAttemptAt))
+
+		deadAt := time.Now()
+		events[0].NextAttemptAt = nil
+		events[0].DeadAt = &deadAt
+		require.NoError(t, store.Outbox.MarkFailed(ctx, &events[0]))
+
+		events, err = store.Outbox.FindUnpublished(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("test case : events of an aggregate type are found after an id", func(t *testing.T) {
+		store := open(t)
+		lastID, err := store.Outbox.FindLastID(ctx, "producttype")
+		require.NoError(t, err)
+		assert.Zero(t, lastID)
+
+		require.NoError(t, store.Outbox.Save(ctx, []model.OutboxEventEntity{
+			{AggregateType: "producttype", AggregateID: "10", EventType: "created"},
+			{AggregateType: "user", AggregateID: "1", EventType: "created"},
+			{AggregateType: "producttype", AggregateID: "10", EventType: "updated"},
+		}))
+		events, err := store.Outbox.FindUnpublished(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		require.NoError(t, store.Outbox.MarkPublished(ctx, events[0].ID, time.Now()))
+
+		lastID, err = store.Outbox.FindLastID(ctx, "producttype")
+		require.NoError(t, err)
+		assert.Equal(t, events[2].ID, lastID)
+
+		after, err := store.Outbox.FindAfter(ctx, "producttype", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, after, 2)
+		assert.Equal(t, []string{"created", "updated"}, []string{after[0].EventType, after[1].EventType})
+
+		after, err = store.Outbox.FindAfter(ctx, "producttype", events[0].ID, 10)
+		require.NoError(t, err)
+		require.Len(t, after, 1)
+		assert.Equal(t, events[2].ID, after[0].ID)
+	})
+
+	t.Run("test case : error rolls every write back", func(t *testing.T) {
+		store := open(t)
+
+		err := store.TxManager.Do(ctx, func(tx repository.Tx) error {
+			if err := tx.ProductTypes().Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit"}); err != nil {
+				return err
+			}
+			return tx.ProductTypes().Save(ctx, &model.ProductTypeEntity{ID: 1, Name: "Food"})
+		})
+		assert.Equal(t, errs.NewConflictError(duplicatedKey), err)
+
+		_, err = store.ProductTypes.FindByID(ctx, 10)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : savepoint rolls back its own writes only", func(t *testing.T) {
+		store := open(t)
+
+		err := store.TxManager.Do(ctx, func(tx repository.Tx) error {
+			if err := tx.ProductTypes().Save(ctx, &model.ProductTypeEntity{ID: 10, Name: "Fruit"}); err != nil {
+				return err
+			}
+			err := tx.Do(func(tx repository.Tx) error {
+				if err := tx.ProductTypes().Save(ctx, &model.ProductTypeEntity{ID: 11, Name: "Nuts"}); err != nil {
+					return err
+				}
+				return errs.NewConflictError("rejected")
+			})
+			assert.Equal(t, errs.NewConflictError("rejected"), err)
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = store.ProductTypes.FindByID(ctx, 10)
+		assert.NoError(t, err)
+		_, err = store.ProductTypes.FindByID(ctx, 11)
+		assert.Equal(t, errs.NewNotFoundError(notFound), err)
+	})
+
+	t.Run("test case : users and sessions", func(t *testing.T) {
+		store := open(t)
+
+		err := store.TxManager.Do(ctx, func(tx repository.Tx) error {
+			role, err := tx.Roles().FindByTitle(ctx, "Customer")
+			if err != nil {
+				return err
+			}
+			if err := tx.Users().Create(ctx, &model.UserEntity{ID: 2, RoleID: role.ID, Name: "Alyx Vance", Email: "alyx_vance@gmail.com", Password: "hash"}); err != nil {
+				return err
+			}
+			return tx.Oauths().Create(ctx, &model.OauthEntity{UserID: 2, AccessToken: "access", RefreshToken: "refresh"})
+		})
+		require.NoError(t, err)
+
+		found, err := store.Oauths.FindByUserID(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "access", found.AccessToken)
+	})
+}
+
+func statusOf(err error) int {
+	var errorResponse errs.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.Code
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package conformance_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/migrate"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/repository/conformance"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMemory(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) *repository.Store {
+		return repository.NewMemoryStore(repository.NewMemoryDB())
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) *repository.Store {
+		configData := &config.Config{DBDriver: "sqlite", SQLitePath: filepath.Join(t.TempDir(), "fiber-test.db")}
+		db, err := gorm.Open(configData.Dialector(), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		})
+
+		migrations, err := migrate.Load(config.Migrations, config.SQLiteMigrationDir)
+		require.NoError(t, err)
+		require.NoError(t, migrate.NewMigrator(db, migrations).Up(context.Background()))
+
+		return repository.NewGormStore(db, 0)
+	})
+}
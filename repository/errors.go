@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+// writeError maps the error of a write the way every backend does, a row clashing with the key
+// of another is a conflict and anything else an internal server error.
+func writeError(err error) error {
+	if config.IsDuplicateKeyError(err) {
+		return errs.NewConflictError(gorm.ErrDuplicatedKey.Error())
+	}
+	return errs.NewInternalServerError(err.Error())
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+// memoryData is one version of a MemoryDB. It is never changed once committed, a write changes a
+// copy, so a reader may go on with the version it got while the next one is written.
+type memoryData struct {
+	roles 			map[int]model.RoleEntity
+	users 			map[int]model.UserEntity
+	oauths 			map[int]model.OauthEntity
+	productTypes 	map[int]model.ProductTypeEntity
+	revisions 		[]model.ProductTypeRevisionEntity
+	outbox 			[]model.OutboxEventEntity
+
+	// the next values of the SERIAL columns
+	nextRoleID 		int
+	nextOauthID 	int
+	nextRevisionID 	int64
+	nextOutboxID 	int64
+}
+
+func (d *memoryData) clone() *memoryData {
+	next := *d
+	next.roles = make(map[int]model.RoleEntity, len(d.roles))
+	for id, roleEntity := range d.roles {
+		next.roles[id] = roleEntity
+	}
+	next.users = make(map[int]model.UserEntity, len(d.users))
+	for id, userEntity := range d.users {
+		next.users[id] = userEntity
+	}
+	next.oauths = make(map[int]model.OauthEntity, len(d.oauths))
+	for id, oauthEntity := range d.oauths {
+		next.oauths[id] = oauthEntity
+	}
+	next.productTypes = make(map[int]model.ProductTypeEntity, len(d.productTypes))
+	for id, prodTypeEntity := range d.productTypes {
+		next.productTypes[id] = prodTypeEntity
+	}
+	next.revisions = append([]model.ProductTypeRevisionEntity(nil), d.revisions...)
+	next.outbox = append([]model.OutboxEventEntity(nil), d.outbox...)
+	return &next
+}
+
+// memorySession is what the memory repositories read and write, the MemoryDB itself or a transaction on it.
+type memorySession interface {
+	// view runs fn on the current version, fn must not change it.
+	view(fn func(data *memoryData) error) error
+	// update runs fn on a copy of the current version, which replaces it when fn returns no error.
+	update(fn func(data *memoryData) error) error
+}
+
+// MemoryDB keeps the rows of the memory repositories in the process, for running the server and the
+// tests without a database. It starts with the rows the migrations insert. The stored entities are
+// values whose pointers are never written through, so versions can share them.
+type MemoryDB struct {
+	mu 		sync.RWMutex
+	data 	*memoryData
+	// relay is the lock of OutboxRepository.RelayLocked
+	relay 	sync.Mutex
+}
+
+func NewMemoryDB() *MemoryDB {
+	data := &memoryData{
+		roles: 			map[int]model.RoleEntity{},
+		users: 			map[int]model.UserEntity{},
+		oauths: 		map[int]model.OauthEntity{},
+		productTypes: 	map[int]model.ProductTypeEntity{},
+		nextRoleID: 	1,
+		nextOauthID: 	1,
+		nextRevisionID: 1,
+		nextOutboxID: 	1,
+	}
+
+	for _, title := range []string{"Manager", "Admin", "Customer"} {
+		data.roles[data.nextRoleID] = model.RoleEntity{ID: data.nextRoleID, Title: title}
+		data.nextRoleID++
+	}
+	data.users[1] = model.UserEntity{
+		ID: 		1,
+		RoleID: 	1,
+		Name: 		"Gordon Freeman",
+		Email: 		"gordon_freeman@gmail.com",
+		Password: 	"$2a$10$DAi7ije26J6vGiZ8EknTK.Go8VsH3/CerbE9QJTEbk3HnF6S0/h9O",
+	}
+	for id, name := range []string{"Food", "Drink", "Snack"} {
+		data.productTypes[id + 1] = model.ProductTypeEntity{ID: id + 1, Name: name}
+	}
+	data.recordRevisions(model.RevisionOpCreate, []int{1, 2, 3})
+
+	return &MemoryDB{data: data}
+}
+
+func (m *MemoryDB) current() *memoryData {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data
+}
+
+func (m *MemoryDB) view(fn func(data *memoryData) error) error {
+	return fn(m.current())
+}
+
+func (m *MemoryDB) update(fn func(data *memoryData) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	next := m.data.clone()
+	if err := fn(next); err != nil {
+		return err
+	}
+	m.data = next
+	return nil
+}
+
+// commit makes data, written on top of base, the current version unless another write committed
+// since base was read. Without writes there is nothing to commit.
+func (m *MemoryDB) commit(base, data *memoryData) bool {
+	if data == base {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.data != base {
+		return false
+	}
+	m.data = data
+	return true
+}
+
+// memoryTx is a transaction on a MemoryDB, it reads the version it started with and its own writes.
+type memoryTx struct {
+	data *memoryData
+}
+
+func (t *memoryTx) view(fn func(data *memoryData) error) error {
+	return fn(t.data)
+}
+
+func (t *memoryTx) update(fn func(data *memoryData) error) error {
+	next := t.data.clone()
+	if err := fn(next); err != nil {
+		return err
+	}
+	t.data = next
+	return nil
+}
+
+// recordRevisions snapshots the product types of ids as their next revision, soft deleted ones included.
+func (d *memoryData) recordRevisions(operation string, ids []int) {
+	now := time.Now()
+	for _, id := range ids {
+		prodTypeEntity, ok := d.productTypes[id]
+		if !ok {
+			continue
+		}
+		revision := 0
+		for _, revisionEntity := range d.revisions {
+			if revisionEntity.ProductTypeID == id && revisionEntity.Revision > revision {
+				revision = revisionEntity.Revision
+			}
+		}
+		var deletedAt *time.Time
+		if prodTypeEntity.DeletedAt.Valid {
+			deletedAt = copyTime(&prodTypeEntity.DeletedAt.Time)
+		}
+		d.revisions = append(d.revisions, model.ProductTypeRevisionEntity{
+			ID: 			d.nextRevisionID,
+			ProductTypeID: 	id,
+			Revision: 		revision + 1,
+			Name: 			prodTypeEntity.Name,
+			ParentID: 		copyInt(prodTypeEntity.ParentID),
+			DeletedAt: 		deletedAt,
+			Operation: 		operation,
+			CreatedAt: 		now,
+		})
+		d.nextRevisionID++
+	}
+}
+
+func copyInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func copyTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func softDeleted(at time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: at, Valid: true}
+}
+
+// foreignKeyError is what the databases answer to a reference to a row that does not exist.
+func foreignKeyError(table string) error {
+	return errs.NewInternalServerError(fmt.Sprintf("insert or update on table %q violates foreign key constraint", table))
+}
@@ -1,15 +1,20 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
 type OauthRepository interface {
-	Create(oauthEntity *model.OauthEntity) error
-	FindByID(int) (*model.OauthEntity, error)
-	FindByUserID(id int) (*model.OauthEntity, error)
-	FindByAccessToken(id int, accessToken string) (*model.OauthEntity, error)
-	FindByRefleshToken(refleshToken string) (*model.OauthEntity, error)
-	Update(*model.OauthEntity) error
-	Delete(id int) error
+	Create(ctx context.Context, oauthEntity *model.OauthEntity) error
+	FindByID(context.Context, int) (*model.OauthEntity, error)
+	FindByUserID(ctx context.Context, id int) (*model.OauthEntity, error)
+	FindByAccessToken(ctx context.Context, id int, accessToken string) (*model.OauthEntity, error)
+	FindByRefleshToken(ctx context.Context, refleshToken string) (*model.OauthEntity, error)
+	FindByRefleshTokenForUpdate(ctx context.Context, refleshToken string) (*model.OauthEntity, error)
+	Update(context.Context, *model.OauthEntity) error
+	Delete(ctx context.Context, id int) error
+	DeleteByUserID(ctx context.Context, userID int) (int64, error)
+	Count(ctx context.Context) (int64, error)
 }
\ No newline at end of file
@@ -1,30 +1,37 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/replica"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OauthRepositoryImpl struct {
-	db *gorm.DB
+	db 			*gorm.DB
+	readRetries int
 }
 
-func NewOauthRepositoryImpl(db *gorm.DB) OauthRepository {
-	return &OauthRepositoryImpl{db: db}
+func NewOauthRepositoryImpl(db *gorm.DB, readRetries int) OauthRepository {
+	return &OauthRepositoryImpl{db: db, readRetries: readRetries}
 }
 
-func (r *OauthRepositoryImpl) Create(oauthReq *model.OauthEntity) error {
-	if err := r.db.Create(&oauthReq).Error; err != nil {
-		return errs.NewInternalServerError(err.Error())
+func (r *OauthRepositoryImpl) Create(ctx context.Context, oauthReq *model.OauthEntity) error {
+	if err := r.db.WithContext(ctx).Create(&oauthReq).Error; err != nil {
+		return writeError(err)
 	}
 	return nil
 }
 
-func (r *OauthRepositoryImpl) FindByID(id int) (*model.OauthEntity, error) {
+func (r *OauthRepositoryImpl) FindByID(ctx context.Context, id int) (*model.OauthEntity, error) {
 	var oauthEntity model.OauthEntity
-	err := r.db.First(&oauthEntity, id).Error
+	err := read(ctx, r.db, r.readRetries, "OauthRepository.FindByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&oauthEntity, id).Error
+	})
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewNotFoundError(err.Error())
@@ -35,9 +42,11 @@ func (r *OauthRepositoryImpl) FindByID(id int) (*model.OauthEntity, error) {
 	return &oauthEntity, nil
 }
 
-func (r *OauthRepositoryImpl) FindByUserID(id int) (*model.OauthEntity, error) {
+func (r *OauthRepositoryImpl) FindByUserID(ctx context.Context, id int) (*model.OauthEntity, error) {
 	var oauthEntity model.OauthEntity
-	err := r.db.Where("user_id = ?", id).First(&oauthEntity).Error
+	err := read(ctx, r.db, r.readRetries, "OauthRepository.FindByUserID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("oauth_user_id = ?", id).First(&oauthEntity).Error
+	})
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewNotFoundError("Email or Password is incorrect")
@@ -47,9 +56,18 @@ func (r *OauthRepositoryImpl) FindByUserID(id int) (*model.OauthEntity, error) {
 	return &oauthEntity, nil
 }
 
-func (r *OauthRepositoryImpl) FindByAccessToken(id int, accessToken string) (*model.OauthEntity, error) {
+func (r *OauthRepositoryImpl) FindByAccessToken(ctx context.Context, id int, accessToken string) (*model.OauthEntity, error) {
 	var oauthEntity model.OauthEntity
-	err := r.db.Where("oauth_id = ? AND access_token = ?", id, accessToken).First(&oauthEntity).Error
+	servedByReplica := false
+	err := read(ctx, r.db, r.readRetries, "OauthRepository.FindByAccessToken", func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Where("oauth_id = ? AND access_token = ?", id, accessToken).First(&oauthEntity)
+		servedByReplica = result.Statement.ConnPool != r.db.Statement.ConnPool
+		return result.Error
+	})
+	// a token issued a moment ago may not have reached the replica yet, a miss there is confirmed on the primary
+	if servedByReplica && gorm.ErrRecordNotFound == err {
+		err = r.db.WithContext(replica.Primary(ctx)).Where("oauth_id = ? AND access_token = ?", id, accessToken).First(&oauthEntity).Error
+	}
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewUnauthorizedError(err.Error())
@@ -59,9 +77,11 @@ func (r *OauthRepositoryImpl) FindByAccessToken(id int, accessToken string) (*mo
 	return &oauthEntity, nil
 }
 
-func (r *OauthRepositoryImpl) FindByRefleshToken(refleshToken string) (*model.OauthEntity, error) {
+func (r *OauthRepositoryImpl) FindByRefleshToken(ctx context.Context, refleshToken string) (*model.OauthEntity, error) {
 	var oauthEntity model.OauthEntity
-	err := r.db.Where("reflesh_token = ?", refleshToken).First(&oauthEntity).Error
+	err := read(ctx, r.db, r.readRetries, "OauthRepository.FindByRefleshToken", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("reflesh_token = ?", refleshToken).First(&oauthEntity).Error
+	})
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewUnauthorizedError("Reflesh Token is incorrect")
@@ -71,16 +91,49 @@ func (r *OauthRepositoryImpl) FindByRefleshToken(refleshToken string) (*model.Oa
 	return &oauthEntity, nil
 }
 
-func (r *OauthRepositoryImpl) Update(oauthUpdateReq *model.OauthEntity) error{
-	if err := r.db.Model(&oauthUpdateReq).Updates(oauthUpdateReq).Error; err != nil {
+// FindByRefleshTokenForUpdate locks the row until the transaction ends, it is only useful inside TxManager.
+func (r *OauthRepositoryImpl) FindByRefleshTokenForUpdate(ctx context.Context, refleshToken string) (*model.OauthEntity, error) {
+	var oauthEntity model.OauthEntity
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("reflesh_token = ?", refleshToken).First(&oauthEntity).Error
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewUnauthorizedError("Reflesh Token is incorrect")
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return &oauthEntity, nil
+}
+
+func (r *OauthRepositoryImpl) Update(ctx context.Context, oauthUpdateReq *model.OauthEntity) error{
+	if err := r.db.WithContext(ctx).Model(&oauthUpdateReq).Updates(oauthUpdateReq).Error; err != nil {
 		return errs.NewInternalServerError(err.Error())
 	}
 	return nil
 }
 
-func (r *OauthRepositoryImpl) Delete(id int) error{
-	if err := r.db.Delete(&model.OauthEntity{}, id).Error; err != nil {
+func (r *OauthRepositoryImpl) Delete(ctx context.Context, id int) error{
+	if err := r.db.WithContext(ctx).Delete(&model.OauthEntity{}, id).Error; err != nil {
 		return errs.NewInternalServerError(err.Error())
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// DeleteByUserID revokes every session of the user and returns how many there were.
+func (r *OauthRepositoryImpl) DeleteByUserID(ctx context.Context, userID int) (int64, error) {
+	result := r.db.WithContext(ctx).Where("oauth_user_id = ?", userID).Delete(&model.OauthEntity{})
+	if result.Error != nil {
+		return 0, errs.NewInternalServerError(result.Error.Error())
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *OauthRepositoryImpl) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := read(ctx, r.db, r.readRetries, "OauthRepository.Count", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.OauthEntity{}).Count(&count).Error
+	})
+	if err != nil {
+		return 0, errs.NewInternalServerError(err.Error())
+	}
+	return count, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+type OauthRepositoryMemory struct {
+	db memorySession
+}
+
+func NewOauthRepositoryMemory(db *MemoryDB) OauthRepository {
+	return &OauthRepositoryMemory{db: db}
+}
+
+// Create gives oauthReq the next ID when it has none, as the SERIAL column does.
+func (r *OauthRepositoryMemory) Create(ctx context.Context, oauthReq *model.OauthEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		oauthEntity := *oauthReq
+		if oauthEntity.ID == 0 {
+			oauthEntity.ID = data.nextOauthID
+			data.nextOauthID++
+		} else if _, ok := data.oauths[oauthEntity.ID]; ok {
+			return writeError(gorm.ErrDuplicatedKey)
+		}
+		if _, ok := data.users[oauthEntity.UserID]; !ok {
+			return foreignKeyError("oauth")
+		}
+		data.oauths[oauthEntity.ID] = oauthEntity
+		oauthReq.ID = oauthEntity.ID
+		return nil
+	})
+}
+
+func (r *OauthRepositoryMemory) FindByID(ctx context.Context, id int) (*model.OauthEntity, error) {
+	return r.first(errs.NewNotFoundError(gorm.ErrRecordNotFound.Error()), func(oauthEntity model.OauthEntity) bool {
+		return oauthEntity.ID == id
+	})
+}
+
+func (r *OauthRepositoryMemory) FindByUserID(ctx context.Context, id int) (*model.OauthEntity, error) {
+	return r.first(errs.NewNotFoundError("Email or Password is incorrect"), func(oauthEntity model.OauthEntity) bool {
+		return oauthEntity.UserID == id
+	})
+}
+
+func (r *OauthRepositoryMemory) FindByAccessToken(ctx context.Context, id int, accessToken string) (*model.OauthEntity, error) {
+	return r.first(errs.NewUnauthorizedError(gorm.ErrRecordNotFound.Error()), func(oauthEntity model.OauthEntity) bool {
+		return oauthEntity.ID == id && oauthEntity.AccessToken == accessToken
+	})
+}
+
+func (r *OauthRepositoryMemory) FindByRefleshToken(ctx context.Context, refleshToken string) (*model.OauthEntity, error) {
+	return r.first(errs.NewUnauthorizedError("Reflesh Token is incorrect"), func(oauthEntity model.OauthEntity) bool {
+		return oauthEntity.RefreshToken == refleshToken
+	})
+}
+
+// FindByRefleshTokenForUpdate needs no lock, a TxManagerMemory transaction that read a session
+// another one changed in the meantime is run again.
+func (r *OauthRepositoryMemory) FindByRefleshTokenForUpdate(ctx context.Context, refleshToken string) (*model.OauthEntity, error) {
+	return r.FindByRefleshToken(ctx, refleshToken)
+}
+
+// Update sets the fields of oauthUpdateReq that are not zero, as gorm Updates does with a struct.
+func (r *OauthRepositoryMemory) Update(ctx context.Context, oauthUpdateReq *model.OauthEntity) error {
+	if oauthUpdateReq.ID == 0 {
+		return errs.NewInternalServerError(gorm.ErrMissingWhereClause.Error())
+	}
+	return r.db.update(func(data *memoryData) error {
+		oauthEntity, ok := data.oauths[oauthUpdateReq.ID]
+		if !ok {
+			return nil
+		}
+		if oauthUpdateReq.UserID != 0 {
+			if _, ok := data.users[oauthUpdateReq.UserID]; !ok {
+				return foreignKeyError("oauth")
+			}
+			oauthEntity.UserID = oauthUpdateReq.UserID
+		}
+		if oauthUpdateReq.AccessToken != "" {
+			oauthEntity.AccessToken = oauthUpdateReq.AccessToken
+		}
+		if oauthUpdateReq.RefreshToken != "" {
+			oauthEntity.RefreshToken = oauthUpdateReq.RefreshToken
+		}
+		data.oauths[oauthEntity.ID] = oauthEntity
+		return nil
+	})
+}
+
+func (r *OauthRepositoryMemory) Delete(ctx context.Context, id int) error {
+	return r.db.update(func(data *memoryData) error {
+		delete(data.oauths, id)
+		return nil
+	})
+}
+
+// DeleteByUserID revokes every session of the user and returns how many there were.
+func (r *OauthRepositoryMemory) DeleteByUserID(ctx context.Context, userID int) (int64, error) {
+	var deleted int64
+	r.db.update(func(data *memoryData) error {
+		for id, oauthEntity := range data.oauths {
+			if oauthEntity.UserID == userID {
+				delete(data.oauths, id)
+				deleted++
+			}
+		}
+		return nil
+	})
+	return deleted, nil
+}
+
+func (r *OauthRepositoryMemory) Count(ctx context.Context) (int64, error) {
+	var count int64
+	r.db.view(func(data *memoryData) error {
+		count = int64(len(data.oauths))
+		return nil
+	})
+	return count, nil
+}
+
+// first returns the session with the lowest ID matching match, as gorm First does, or notFound.
+func (r *OauthRepositoryMemory) first(notFound error, match func(model.OauthEntity) bool) (*model.OauthEntity, error) {
+	var oauthsEntity []model.OauthEntity
+	r.db.view(func(data *memoryData) error {
+		for _, oauthEntity := range data.oauths {
+			if match(oauthEntity) {
+				oauthsEntity = append(oauthsEntity, oauthEntity)
+			}
+		}
+		return nil
+	})
+	if len(oauthsEntity) == 0 {
+		return nil, notFound
+	}
+	sort.Slice(oauthsEntity, func(i, j int) bool {
+		return oauthsEntity[i].ID < oauthsEntity[j].ID
+	})
+	return &oauthsEntity[0], nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+type OutboxRepository interface {
+	Save(context.Context, []model.OutboxEventEntity) error
+	// RelayLocked runs fn with the relay lock held, on a repository that keeps holding it, so one relay
+	// at a time publishes and the events of an aggregate go out in order. It returns false without
+	// running fn when another relay holds the lock.
+	RelayLocked(ctx context.Context, fn func(outboxRepo OutboxRepository) error) (bool, error)
+	// FindUnpublished leaves out dead events, they wait for an operator.
+	FindUnpublished(ctx context.Context, limit int) ([]model.OutboxEventEntity, error)
+	// FindAfter returns the events of aggregateType after afterID in ID order, published or not.
+	FindAfter(ctx context.Context, aggregateType string, afterID int64, limit int) ([]model.OutboxEventEntity, error)
+	// FindLastID returns the ID of the latest event of aggregateType, 0 when there is none.
+	FindLastID(ctx context.Context, aggregateType string) (int64, error)
+	MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error
+	// MarkFailed writes the attempts, last error, published sinks, next attempt and dead time of the event.
+	MarkFailed(ctx context.Context, outboxEntity *model.OutboxEventEntity) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
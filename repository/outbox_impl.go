@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+// outboxRelayLockKey names the advisory lock a relay run holds, released when the run ends.
+const outboxRelayLockKey = 5311
+
+type OutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepositoryImpl(db *gorm.DB) OutboxRepository {
+	return &OutboxRepositoryImpl{db: db}
+}
+
+func (r *OutboxRepositoryImpl) Save(ctx context.Context, outboxEntities []model.OutboxEventEntity) error {
+	if len(outboxEntities) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Create(&outboxEntities).Error; err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// RelayLocked holds a session advisory lock on Postgres rather than a transaction, so an event marked
+// published stays published when the run fails later. SQLite has no advisory lock, its database file
+// belongs to a single instance.
+func (r *OutboxRepositoryImpl) RelayLocked(ctx context.Context, fn func(outboxRepo OutboxRepository) error) (bool, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		return true, fn(r)
+	}
+
+	locked := false
+	err := r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", outboxRelayLockKey).Scan(&locked).Error; err != nil {
+			return errs.NewInternalServerError(err.Error())
+		}
+		if !locked {
+			return nil
+		}
+		// the lock belongs to the session, which goes back to the pool, it has to be released even when ctx is done
+		defer conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", outboxRelayLockKey)
+		return fn(NewOutboxRepositoryImpl(conn))
+	})
+	var errorResponse errs.ErrorResponse
+	if err != nil && !errors.As(err, &errorResponse) {
+		err = errs.NewInternalServerError(err.Error())
+	}
+	return locked, err
+}
+
+// FindUnpublished returns the oldest unpublished events in the order they were written.
+func (r *OutboxRepositoryImpl) FindUnpublished(ctx context.Context, limit int) ([]model.OutboxEventEntity, error) {
+	var outboxEntities []model.OutboxEventEntity
+	err := r.db.WithContext(ctx).Where("outbox_published_at IS NULL AND outbox_dead_at IS NULL").Order("outbox_id").Limit(limit).Find(&outboxEntities).Error
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return outboxEntities, nil
+}
+
+func (r *OutboxRepositoryImpl) FindAfter(ctx context.Context, aggregateType string, afterID int64, limit int) ([]model.OutboxEventEntity, error) {
+	var outboxEntities []model.OutboxEventEntity
+	err := r.db.WithContext(ctx).
+		Where("outbox_aggregate_type = ? AND outbox_id > ?", aggregateType, afterID).
+		Order("outbox_id").
+		Limit(limit).
+		Find(&outboxEntities).Error
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return outboxEntities, nil
+}
+
+func (r *OutboxRepositoryImpl) FindLastID(ctx context.Context, aggregateType string) (int64, error) {
+	var lastID int64
+	err := r.db.WithContext(ctx).Model(&model.OutboxEventEntity{}).
+		Where("outbox_aggregate_type = ?", aggregateType).
+		Select("COALESCE(MAX(outbox_id), 0)").
+		Scan(&lastID).Error
+	if err != nil {
+		return 0, errs.NewInternalServerError(err.Error())
+	}
+	return lastID, nil
+}
+
+func (r *OutboxRepositoryImpl) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&model.OutboxEventEntity{}).Where("outbox_id = ?", id).Update("outbox_published_at", publishedAt).Error
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+func (r *OutboxRepositoryImpl) MarkFailed(ctx context.Context, outboxEntity *model.OutboxEventEntity) error {
+	err := r.db.WithContext(ctx).Model(&model.OutboxEventEntity{}).Where("outbox_id = ?", outboxEntity.ID).Updates(map[string]interface{}{
+		"outbox_attempts": 			outboxEntity.Attempts,
+		"outbox_last_error": 		outboxEntity.LastError,
+		"outbox_published_sinks": 	outboxEntity.PublishedSinks,
+		"outbox_next_attempt_at": 	outboxEntity.NextAttemptAt,
+		"outbox_dead_at": 			outboxEntity.DeadAt,
+	}).Error
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+func (r *OutboxRepositoryImpl) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("outbox_published_at < ?", before).Delete(&model.OutboxEventEntity{})
+	if result.Error != nil {
+		return 0, errs.NewInternalServerError(result.Error.Error())
+	}
+	return result.RowsAffected, nil
+}
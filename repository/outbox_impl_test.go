@@ -0,0 +1,286 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+)
+
+func TestOutboxSave(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	now := time.Now()
+	payloadMock := `{"prodtype_id":1,"prodtype_name":"A"}`
+
+	t.Run("test case : save outbox success", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+		outboxEntities := []model.OutboxEventEntity{
+			{AggregateType: "producttype", AggregateID: "1", EventType: "created", Payload: &payloadMock, CreatedAt: now},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WithArgs("producttype", "1", "created", payloadMock, 0, "", now, nil, "", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}).AddRow(5))
+		mock.ExpectCommit()
+
+		err := repo.Save(context.Background(), outboxEntities)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : save outbox empty skips query", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		err := repo.Save(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : save outbox fail", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnError(errors.New("Unexpected Error"))
+		mock.ExpectRollback()
+
+		err := repo.Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype"}})
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxFindUnpublished(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find unpublished success", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE outbox_published_at IS NULL AND outbox_dead_at IS NULL ORDER BY outbox_id LIMIT $1`)).
+			WithArgs(100).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id", "outbox_aggregate_type", "outbox_aggregate_id", "outbox_event_type"}).
+				AddRow(1, "producttype", "1", "created").
+				AddRow(2, "producttype", "1", "updated"))
+
+		outboxEntities, err := repo.FindUnpublished(context.Background(), 100)
+
+		assert.NoError(t, err)
+		assert.Len(t, outboxEntities, 2)
+		assert.Equal(t, "updated", outboxEntities[1].EventType)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find unpublished fail", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "outbox"`).
+			WillReturnError(errors.New("Unexpected Error"))
+
+		_, err := repo.FindUnpublished(context.Background(), 100)
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxFindAfter(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find after success", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE outbox_aggregate_type = $1 AND outbox_id > $2 ORDER BY outbox_id LIMIT $3`)).
+			WithArgs("producttype", int64(7), 100).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id", "outbox_aggregate_type", "outbox_aggregate_id", "outbox_event_type"}).
+				AddRow(8, "producttype", "1", "updated"))
+
+		outboxEntities, err := repo.FindAfter(context.Background(), "producttype", 7, 100)
+
+		assert.NoError(t, err)
+		assert.Len(t, outboxEntities, 1)
+		assert.Equal(t, int64(8), outboxEntities[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find after fail", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "outbox"`).
+			WillReturnError(errors.New("Unexpected Error"))
+
+		_, err := repo.FindAfter(context.Background(), "producttype", 7, 100)
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find last id success", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(outbox_id), 0) FROM "outbox" WHERE outbox_aggregate_type = $1`)).
+			WithArgs("producttype").
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(12))
+
+		lastID, err := repo.FindLastID(context.Background(), "producttype")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), lastID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find last id fail", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(`SELECT COALESCE`).
+			WillReturnError(errors.New("Unexpected Error"))
+
+		_, err := repo.FindLastID(context.Background(), "producttype")
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxMark(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : mark published success", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "outbox_published_at"=$1 WHERE outbox_id = $2`)).
+			WithArgs(now, int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.MarkPublished(context.Background(), 1, now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : mark failed writes the retry state", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+		nextAttemptAt := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "outbox_attempts"=$1,"outbox_dead_at"=$2,"outbox_last_error"=$3,"outbox_next_attempt_at"=$4,"outbox_published_sinks"=$5 WHERE outbox_id = $6`)).
+			WithArgs(2, nil, "broker: closed", nextAttemptAt, "webhook", int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.MarkFailed(context.Background(), &model.OutboxEventEntity{ID: 1, Attempts: 2, LastError: "broker: closed", PublishedSinks: "webhook", NextAttemptAt: &nextAttemptAt})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : delete published before", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+		before := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "outbox" WHERE outbox_published_at < $1`)).
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		deleted, err := repo.DeletePublishedBefore(context.Background(), before)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxRelayLocked(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : relay locked runs fn and releases the lock", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_lock($1)`)).
+			WithArgs(5311).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		mock.ExpectQuery(`SELECT \* FROM "outbox"`).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+			WithArgs(5311).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		locked, err := repo.RelayLocked(context.Background(), func(outboxRepo repository.OutboxRepository) error {
+			_, err := outboxRepo.FindUnpublished(context.Background(), 100)
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, locked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : relay locked skips fn while another relay holds the lock", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_lock($1)`)).
+			WithArgs(5311).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+		locked, err := repo.RelayLocked(context.Background(), func(outboxRepo repository.OutboxRepository) error {
+			t.Fatal("fn ran without the lock")
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, locked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : relay locked fail", func(t *testing.T) {
+		repo := repository.NewOutboxRepositoryImpl(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_lock($1)`)).
+			WillReturnError(errors.New("Unexpected Error"))
+
+		_, err := repo.RelayLocked(context.Background(), func(outboxRepo repository.OutboxRepository) error {
+			return nil
+		})
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+type OutboxRepositoryMemory struct {
+	db 		memorySession
+	relay 	*sync.Mutex
+}
+
+func NewOutboxRepositoryMemory(db *MemoryDB) OutboxRepository {
+	return &OutboxRepositoryMemory{db: db, relay: &db.relay}
+}
+
+// RelayLocked takes the lock of the MemoryDB with TryLock. In a transaction there is no MemoryDB to lock
+// and fn runs without it.
+func (r *OutboxRepositoryMemory) RelayLocked(ctx context.Context, fn func(outboxRepo OutboxRepository) error) (bool, error) {
+	if r.relay != nil {
+		if !r.relay.TryLock() {
+			return false, nil
+		}
+		defer r.relay.Unlock()
+	}
+	return true, fn(r)
+}
+
+// Save gives the events their IDs and, when they have none, their creation time.
+func (r *OutboxRepositoryMemory) Save(ctx context.Context, outboxEntities []model.OutboxEventEntity) error {
+	if len(outboxEntities) == 0 {
+		return nil
+	}
+	return r.db.update(func(data *memoryData) error {
+		now := time.Now()
+		for i := range outboxEntities {
+			outboxEntities[i].ID = data.nextOutboxID
+			data.nextOutboxID++
+			if outboxEntities[i].CreatedAt.IsZero() {
+				outboxEntities[i].CreatedAt = now
+			}
+			data.outbox = append(data.outbox, copyOutboxEvent(outboxEntities[i]))
+		}
+		return nil
+	})
+}
+
+// FindUnpublished returns the oldest unpublished events in the order they were written.
+func (r *OutboxRepositoryMemory) FindUnpublished(ctx context.Context, limit int) ([]model.OutboxEventEntity, error) {
+	outboxEntities := []model.OutboxEventEntity{}
+	r.db.view(func(data *memoryData) error {
+		for _, outboxEntity := range data.outbox {
+			if limit >= 0 && len(outboxEntities) == limit {
+				break
+			}
+			if outboxEntity.PublishedAt == nil && outboxEntity.DeadAt == nil {
+				outboxEntities = append(outboxEntities, copyOutboxEvent(outboxEntity))
+			}
+		}
+		return nil
+	})
+	return outboxEntities, nil
+}
+
+func (r *OutboxRepositoryMemory) FindAfter(ctx context.Context, aggregateType string, afterID int64, limit int) ([]model.OutboxEventEntity, error) {
+	outboxEntities := []model.OutboxEventEntity{}
+	r.db.view(func(data *memoryData) error {
+		for _, outboxEntity := range data.outbox {
+			if limit >= 0 && len(outboxEntities) == limit {
+				break
+			}
+			if outboxEntity.AggregateType == aggregateType && outboxEntity.ID > afterID {
+				outboxEntities = append(outboxEntities, copyOutboxEvent(outboxEntity))
+			}
+		}
+		return nil
+	})
+	return outboxEntities, nil
+}
+
+func (r *OutboxRepositoryMemory) FindLastID(ctx context.Context, aggregateType string) (int64, error) {
+	var lastID int64
+	r.db.view(func(data *memoryData) error {
+		for _, outboxEntity := range data.outbox {
+			if outboxEntity.AggregateType == aggregateType && outboxEntity.ID > lastID {
+				lastID = outboxEntity.ID
+			}
+		}
+		return nil
+	})
+	return lastID, nil
+}
+
+func (r *OutboxRepositoryMemory) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	return r.db.update(func(data *memoryData) error {
+		for i := range data.outbox {
+			if data.outbox[i].ID == id {
+				data.outbox[i].PublishedAt = copyTime(&publishedAt)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *OutboxRepositoryMemory) MarkFailed(ctx context.Context, outboxEntity *model.OutboxEventEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		for i := range data.outbox {
+			if data.outbox[i].ID == outboxEntity.ID {
+				data.outbox[i].Attempts = outboxEntity.Attempts
+				data.outbox[i].LastError = outboxEntity.LastError
+				data.outbox[i].PublishedSinks = outboxEntity.PublishedSinks
+				data.outbox[i].NextAttemptAt = copyTime(outboxEntity.NextAttemptAt)
+				data.outbox[i].DeadAt = copyTime(outboxEntity.DeadAt)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *OutboxRepositoryMemory) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	r.db.update(func(data *memoryData) error {
+		kept := data.outbox[:0]
+		for _, outboxEntity := range data.outbox {
+			if outboxEntity.PublishedAt != nil && outboxEntity.PublishedAt.Before(before) {
+				deleted++
+				continue
+			}
+			kept = append(kept, outboxEntity)
+		}
+		data.outbox = kept
+		return nil
+	})
+	return deleted, nil
+}
+
+func copyOutboxEvent(outboxEntity model.OutboxEventEntity) model.OutboxEventEntity {
+	if outboxEntity.Payload != nil {
+		payload := *outboxEntity.Payload
+		outboxEntity.Payload = &payload
+	}
+	outboxEntity.PublishedAt = copyTime(outboxEntity.PublishedAt)
+	outboxEntity.NextAttemptAt = copyTime(outboxEntity.NextAttemptAt)
+	outboxEntity.DeadAt = copyTime(outboxEntity.DeadAt)
+	return outboxEntity
+}
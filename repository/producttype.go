@@ -1,15 +1,42 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
 type ProductTypeRepository interface {
-	Save(*model.ProductTypeEntity) error
-	FindAll() ([]model.ProductTypeEntity, error)
-	FindByID(int) (*model.ProductTypeEntity, error)
-	Update(*model.ProductTypeEntity) error
-	Delete(int) error
-	Count() (int64, error)
+	Save(context.Context, *model.ProductTypeEntity) error
+	FindAll(context.Context) ([]model.ProductTypeEntity, error)
+	FindByID(context.Context, int) (*model.ProductTypeEntity, error)
+	Update(context.Context, *model.ProductTypeEntity) error
+	Delete(context.Context, int) error
+	Count(context.Context) (int64, error)
+	FindAllDeleted(context.Context) ([]model.ProductTypeEntity, error)
+	FindDeletedByID(context.Context, int) (*model.ProductTypeEntity, error)
+	Restore(context.Context, int) error
+	// Purge and PurgeDeletedBefore detach the children of the purged product types and return them
+	// as they were before, PurgeDeletedBefore returns the purged product types first.
+	Purge(context.Context, int) ([]model.ProductTypeEntity, error)
+	PurgeDeletedBefore(context.Context, time.Time) ([]model.ProductTypeEntity, []model.ProductTypeEntity, error)
+	FindByIDsUnscoped(context.Context, []int) ([]model.ProductTypeEntity, error)
+	ApplyBatch(context.Context, *model.ProductTypeBatch) error
+	FindAllAfter(context.Context, int, int) ([]model.ProductTypeEntity, error)
+	FindChildren(context.Context, int) ([]model.ProductTypeEntity, error)
+	FindAncestors(context.Context, int) ([]model.ProductTypeEntity, error)
+	// LockChanges makes the other product type writers wait until the transaction it runs in ends,
+	// so what the transaction reads after it stays true until it commits.
+	LockChanges(context.Context) error
+	Move(context.Context, int, *int) error
+	DeleteSubtree(context.Context, int) ([]model.ProductTypeEntity, error)
+	// DeleteAndReparent returns the children it moved as they were before.
+	DeleteAndReparent(context.Context, int, *int) ([]model.ProductTypeEntity, error)
+	FindRevisions(context.Context, int) ([]model.ProductTypeRevisionEntity, error)
+	FindRevision(context.Context, int, int) (*model.ProductTypeRevisionEntity, error)
+	FindRevisionAsOf(context.Context, int, time.Time) (*model.ProductTypeRevisionEntity, error)
+	Revert(context.Context, *model.ProductTypeRevisionEntity) error
+	FindChanges(context.Context, int64, int) ([]model.ProductTypeRevisionEntity, error)
 }
 
@@ -1,39 +1,106 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 
 	"gorm.io/gorm"
 )
 
+const (
+	productTypeInsertBatchSize = 100
+
+	// ProductTypeMaxDepth bounds the recursive queries so a corrupted parent chain
+	// can not loop forever, a chain this long is not followed any further.
+	ProductTypeMaxDepth = 64
+
+	// productTypeChangeLockKey names the advisory lock that serialises revision writers,
+	// so rev_id order is commit order and the change feed never skips a late commit.
+	productTypeChangeLockKey = 5310
+)
+
+const productTypeAncestorsQuery = `
+WITH RECURSIVE ancestors AS (
+	SELECT prodtype_code, prodtype_name, prodtype_parent_code, prodtype_deleted_at, 0 AS depth
+	FROM producttype
+	WHERE prodtype_code = ? AND prodtype_deleted_at IS NULL
+	UNION ALL
+	SELECT p.prodtype_code, p.prodtype_name, p.prodtype_parent_code, p.prodtype_deleted_at, a.depth + 1
+	FROM producttype p
+	JOIN ancestors a ON p.prodtype_code = a.prodtype_parent_code
+	WHERE p.prodtype_deleted_at IS NULL AND a.depth < ?
+)
+SELECT prodtype_code, prodtype_name, prodtype_parent_code, prodtype_deleted_at
+FROM ancestors
+WHERE depth > 0
+ORDER BY depth DESC`
+
+const productTypeDescendantsQuery = `
+WITH RECURSIVE descendants AS (
+	SELECT prodtype_code, prodtype_name, prodtype_parent_code, 0 AS depth
+	FROM producttype
+	WHERE prodtype_code = ? AND prodtype_deleted_at IS NULL
+	UNION ALL
+	SELECT p.prodtype_code, p.prodtype_name, p.prodtype_parent_code, d.depth + 1
+	FROM producttype p
+	JOIN descendants d ON p.prodtype_parent_code = d.prodtype_code
+	WHERE p.prodtype_deleted_at IS NULL AND d.depth < ?
+)
+SELECT prodtype_code, prodtype_name, prodtype_parent_code
+FROM descendants
+ORDER BY depth`
+
+// productTypeRevisionQuery snapshots the current state of the given product types
+// as their next revision, soft deleted rows included.
+const productTypeRevisionQuery = `
+INSERT INTO producttype_revision (prodtype_code, rev_number, prodtype_name, prodtype_parent_code, prodtype_deleted_at, rev_operation, rev_created_at)
+SELECT p.prodtype_code,
+	COALESCE((SELECT MAX(r.rev_number) FROM producttype_revision r WHERE r.prodtype_code = p.prodtype_code), 0) + 1,
+	p.prodtype_name, p.prodtype_parent_code, p.prodtype_deleted_at, ?, ?
+FROM producttype p
+WHERE p.prodtype_code IN ?`
+
 type ProductTypeRepositoryImpl struct {
-	db *gorm.DB
+	db 			*gorm.DB
+	readRetries int
 }
 
-func NewProductTypeRepositoryImpl(db *gorm.DB) ProductTypeRepository {
-	return &ProductTypeRepositoryImpl{db: db}
+func NewProductTypeRepositoryImpl(db *gorm.DB, readRetries int) ProductTypeRepository {
+	return &ProductTypeRepositoryImpl{db: db, readRetries: readRetries}
 }
 
-func (r *ProductTypeRepositoryImpl) Save(prodTypeCreateReq *model.ProductTypeEntity) error{
-	if err := r.db.Create(&prodTypeCreateReq).Error; err != nil {
-		return errs.NewInternalServerError(err.Error())
+func (r *ProductTypeRepositoryImpl) Save(ctx context.Context, prodTypeCreateReq *model.ProductTypeEntity) error{
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&prodTypeCreateReq).Error; err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpCreate, []int{prodTypeCreateReq.ID})
+	})
+	if err != nil {
+		return writeError(err)
 	}
 	return nil
 }
 
-func (r *ProductTypeRepositoryImpl) FindAll() ([]model.ProductTypeEntity, error) {
+func (r *ProductTypeRepositoryImpl) FindAll(ctx context.Context) ([]model.ProductTypeEntity, error) {
 	var prodTypesEntity []model.ProductTypeEntity
-	err := r.db.Find(&prodTypesEntity).Error
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindAll", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Find(&prodTypesEntity).Error
+	})
 	if err != nil {
 		return nil, errs.NewInternalServerError(err.Error())
 	}
 	return prodTypesEntity, nil
 }
 
-func (r *ProductTypeRepositoryImpl) FindByID(id int) (*model.ProductTypeEntity, error) {
+func (r *ProductTypeRepositoryImpl) FindByID(ctx context.Context, id int) (*model.ProductTypeEntity, error) {
 	var prodTypeEntity model.ProductTypeEntity
-	err := r.db.First(&prodTypeEntity, id).Error
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&prodTypeEntity, id).Error
+	})
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewNotFoundError(err.Error())
@@ -44,25 +111,428 @@ func (r *ProductTypeRepositoryImpl) FindByID(id int) (*model.ProductTypeEntity,
 	return &prodTypeEntity, nil
 }
 
-func (r *ProductTypeRepositoryImpl) Update(prodTypeUpdateReq *model.ProductTypeEntity) error{
-	if err := r.db.Model(&prodTypeUpdateReq).Updates(prodTypeUpdateReq).Error; err != nil {
+func (r *ProductTypeRepositoryImpl) Update(ctx context.Context, prodTypeUpdateReq *model.ProductTypeEntity) error{
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&prodTypeUpdateReq).Updates(prodTypeUpdateReq).Error; err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpUpdate, []int{prodTypeUpdateReq.ID})
+	})
+	if err != nil {
 		return errs.NewInternalServerError(err.Error())
 	}
 	return nil
 }
 
-func (r *ProductTypeRepositoryImpl) Delete(id int) error{
-	if err := r.db.Delete(&model.ProductTypeEntity{}, id).Error; err != nil {
+func (r *ProductTypeRepositoryImpl) Delete(ctx context.Context, id int) error{
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ProductTypeEntity{}, id).Error; err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpDelete, []int{id})
+	})
+	if err != nil {
 		return errs.NewInternalServerError(err.Error())
 	}
 	return nil
 }
 
-func (r *ProductTypeRepositoryImpl) Count() (int64, error) {
+func (r *ProductTypeRepositoryImpl) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Table("producttype").Count(&count).Error
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.Count", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.ProductTypeEntity{}).Count(&count).Error
+	})
 	if err != nil {
 		return 0, errs.NewInternalServerError(err.Error())
 	}
 	return count, nil
-}
\ No newline at end of file
+}
+
+func (r *ProductTypeRepositoryImpl) FindAllDeleted(ctx context.Context) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindAllDeleted", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Unscoped().Where("prodtype_deleted_at IS NOT NULL").Find(&prodTypesEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, nil
+}
+
+func (r *ProductTypeRepositoryImpl) FindDeletedByID(ctx context.Context, id int) (*model.ProductTypeEntity, error) {
+	var prodTypeEntity model.ProductTypeEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindDeletedByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Unscoped().Where("prodtype_deleted_at IS NOT NULL").First(&prodTypeEntity, id).Error
+	})
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+
+	return &prodTypeEntity, nil
+}
+
+func (r *ProductTypeRepositoryImpl) Restore(ctx context.Context, id int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&model.ProductTypeEntity{}).
+			Where("prodtype_code = ?", id).
+			Update("prodtype_deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpRestore, []int{id})
+	})
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// Purge removes the product type for good. Its children, soft deleted or not, are detached from it
+// with a move revision in the same transaction. The purged product type itself gets no revision,
+// its history ends with the revision of its delete.
+func (r *ProductTypeRepositoryImpl) Purge(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	var childEntities []model.ProductTypeEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockChanges(tx); err != nil {
+			return err
+		}
+		var err error
+		if childEntities, err = detachChildren(tx, []int{id}); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.ProductTypeEntity{}, id).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return childEntities, nil
+}
+
+// PurgeDeletedBefore removes the product types soft deleted before the given time for good,
+// their children are detached as Purge does and the purged product types get no revision.
+func (r *ProductTypeRepositoryImpl) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]model.ProductTypeEntity, []model.ProductTypeEntity, error) {
+	var prodTypesEntity, childEntities []model.ProductTypeEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockChanges(tx); err != nil {
+			return err
+		}
+		err := tx.Unscoped().Where("prodtype_deleted_at < ?", before).Order("prodtype_code").Find(&prodTypesEntity).Error
+		if err != nil || len(prodTypesEntity) == 0 {
+			return err
+		}
+
+		ids := make([]int, 0, len(prodTypesEntity))
+		for _, prodTypeEntity := range prodTypesEntity {
+			ids = append(ids, prodTypeEntity.ID)
+		}
+		if childEntities, err = detachChildren(tx, ids); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.ProductTypeEntity{}, ids).Error
+	})
+	if err != nil {
+		return nil, nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, childEntities, nil
+}
+
+func (r *ProductTypeRepositoryImpl) FindByIDsUnscoped(ctx context.Context, ids []int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindByIDsUnscoped", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Unscoped().Where("prodtype_code IN ?", ids).Find(&prodTypesEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, nil
+}
+
+// ApplyBatch inserts, updates and soft deletes the batch in a single transaction,
+// nothing is written if any statement fails.
+func (r *ProductTypeRepositoryImpl) ApplyBatch(ctx context.Context, batch *model.ProductTypeBatch) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(batch.Creates) > 0 {
+			if err := tx.CreateInBatches(&batch.Creates, productTypeInsertBatchSize).Error; err != nil {
+				return err
+			}
+			ids := make([]int, 0, len(batch.Creates))
+			for _, prodTypeEntity := range batch.Creates {
+				ids = append(ids, prodTypeEntity.ID)
+			}
+			if err := recordRevisions(tx, model.RevisionOpCreate, ids); err != nil {
+				return err
+			}
+		}
+
+		if len(batch.Updates) > 0 {
+			ids := make([]int, 0, len(batch.Updates))
+			for i := range batch.Updates {
+				if err := tx.Model(&batch.Updates[i]).Updates(batch.Updates[i]).Error; err != nil {
+					return err
+				}
+				ids = append(ids, batch.Updates[i].ID)
+			}
+			if err := recordRevisions(tx, model.RevisionOpUpdate, ids); err != nil {
+				return err
+			}
+		}
+
+		if len(batch.Deletes) > 0 {
+			if err := tx.Delete(&model.ProductTypeEntity{}, batch.Deletes).Error; err != nil {
+				return err
+			}
+			if err := recordRevisions(tx, model.RevisionOpDelete, batch.Deletes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return writeError(err)
+	}
+	return nil
+}
+
+// FindAllAfter returns up to limit product types with a key above afterID in key order,
+// pass the last key of a page to read the next one.
+func (r *ProductTypeRepositoryImpl) FindAllAfter(ctx context.Context, afterID int, limit int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindAllAfter", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("prodtype_code > ?", afterID).Order("prodtype_code").Limit(limit).Find(&prodTypesEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, nil
+}
+
+func (r *ProductTypeRepositoryImpl) FindChildren(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindChildren", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("prodtype_parent_code = ?", id).Find(&prodTypesEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, nil
+}
+
+// FindAncestors returns the parent chain of the product type ordered from the root down,
+// the product type itself is not included.
+func (r *ProductTypeRepositoryImpl) FindAncestors(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindAncestors", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Raw(productTypeAncestorsQuery, id, ProductTypeMaxDepth).Scan(&prodTypesEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, nil
+}
+
+// LockChanges takes the advisory lock the revision writers take on Postgres. SQLite runs one
+// writer at a time and fails a transaction whose reads went stale.
+func (r *ProductTypeRepositoryImpl) LockChanges(ctx context.Context) error {
+	if err := lockChanges(r.db.WithContext(ctx)); err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+func (r *ProductTypeRepositoryImpl) Move(ctx context.Context, id int, parentID *int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.ProductTypeEntity{}).
+			Where("prodtype_code = ?", id).
+			Update("prodtype_parent_code", parentID).Error
+		if err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpMove, []int{id})
+	})
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// DeleteSubtree soft deletes the product type together with all of its descendants
+// and returns what was deleted, the product type itself first.
+func (r *ProductTypeRepositoryImpl) DeleteSubtree(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(productTypeDescendantsQuery, id, ProductTypeMaxDepth).Scan(&prodTypesEntity).Error; err != nil {
+			return err
+		}
+		if len(prodTypesEntity) == 0 {
+			return nil
+		}
+
+		ids := make([]int, 0, len(prodTypesEntity))
+		for _, prodTypeEntity := range prodTypesEntity {
+			ids = append(ids, prodTypeEntity.ID)
+		}
+		if err := tx.Delete(&model.ProductTypeEntity{}, ids).Error; err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpDelete, ids)
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return prodTypesEntity, nil
+}
+
+// DeleteAndReparent moves the children of the product type under parentID
+// and soft deletes the product type in a single transaction.
+func (r *ProductTypeRepositoryImpl) DeleteAndReparent(ctx context.Context, id int, parentID *int) ([]model.ProductTypeEntity, error) {
+	var childEntities []model.ProductTypeEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("prodtype_parent_code = ?", id).Order("prodtype_code").Find(&childEntities).Error; err != nil {
+			return err
+		}
+
+		if len(childEntities) > 0 {
+			childIDs := make([]int, 0, len(childEntities))
+			for _, childEntity := range childEntities {
+				childIDs = append(childIDs, childEntity.ID)
+			}
+			err := tx.Model(&model.ProductTypeEntity{}).
+				Where("prodtype_code IN ?", childIDs).
+				Update("prodtype_parent_code", parentID).Error
+			if err != nil {
+				return err
+			}
+			if err := recordRevisions(tx, model.RevisionOpMove, childIDs); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Delete(&model.ProductTypeEntity{}, id).Error; err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpDelete, []int{id})
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return childEntities, nil
+}
+
+// FindRevisions returns every revision of the product type, newest first.
+func (r *ProductTypeRepositoryImpl) FindRevisions(ctx context.Context, id int) ([]model.ProductTypeRevisionEntity, error) {
+	var revisionsEntity []model.ProductTypeRevisionEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindRevisions", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("prodtype_code = ?", id).Order("rev_number DESC").Find(&revisionsEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return revisionsEntity, nil
+}
+
+func (r *ProductTypeRepositoryImpl) FindRevision(ctx context.Context, id int, revision int) (*model.ProductTypeRevisionEntity, error) {
+	var revisionEntity model.ProductTypeRevisionEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindRevision", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("prodtype_code = ? AND rev_number = ?", id, revision).First(&revisionEntity).Error
+	})
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return &revisionEntity, nil
+}
+
+// FindRevisionAsOf returns the revision that was current at asOf.
+func (r *ProductTypeRepositoryImpl) FindRevisionAsOf(ctx context.Context, id int, asOf time.Time) (*model.ProductTypeRevisionEntity, error) {
+	var revisionEntity model.ProductTypeRevisionEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindRevisionAsOf", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("prodtype_code = ? AND rev_created_at <= ?", id, asOf).
+			Order("rev_number DESC").
+			First(&revisionEntity).Error
+	})
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return &revisionEntity, nil
+}
+
+// Revert writes the name and parent of the revision back onto the product type,
+// the result is recorded as a new revision rather than rewriting history.
+func (r *ProductTypeRepositoryImpl) Revert(ctx context.Context, revisionEntity *model.ProductTypeRevisionEntity) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.ProductTypeEntity{}).
+			Where("prodtype_code = ?", revisionEntity.ProductTypeID).
+			Updates(map[string]interface{}{
+				"prodtype_name": 		revisionEntity.Name,
+				"prodtype_parent_code": revisionEntity.ParentID,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return recordRevisions(tx, model.RevisionOpRevert, []int{revisionEntity.ProductTypeID})
+	})
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// FindChanges returns up to limit revisions after the since cursor in sequence order.
+func (r *ProductTypeRepositoryImpl) FindChanges(ctx context.Context, since int64, limit int) ([]model.ProductTypeRevisionEntity, error) {
+	var revisionsEntity []model.ProductTypeRevisionEntity
+	err := read(ctx, r.db, r.readRetries, "ProductTypeRepository.FindChanges", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("rev_id > ?", since).Order("rev_id").Limit(limit).Find(&revisionsEntity).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return revisionsEntity, nil
+}
+
+func recordRevisions(tx *gorm.DB, operation string, ids []int) error {
+	if err := lockChanges(tx); err != nil {
+		return err
+	}
+	return tx.Exec(productTypeRevisionQuery, operation, time.Now(), ids).Error
+}
+
+// lockChanges takes the change lock for the transaction tx runs in, on Postgres only.
+func lockChanges(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", productTypeChangeLockKey).Error
+}
+
+// detachChildren clears the parent of the product types, soft deleted or not, whose parent is one of ids
+// and records their move. It returns them as they were before, the ones in ids themselves are left alone.
+func detachChildren(tx *gorm.DB, ids []int) ([]model.ProductTypeEntity, error) {
+	var childEntities []model.ProductTypeEntity
+	err := tx.Unscoped().
+		Where("prodtype_parent_code IN ? AND prodtype_code NOT IN ?", ids, ids).
+		Order("prodtype_code").
+		Find(&childEntities).Error
+	if err != nil || len(childEntities) == 0 {
+		return childEntities, err
+	}
+
+	childIDs := make([]int, 0, len(childEntities))
+	for _, childEntity := range childEntities {
+		childIDs = append(childIDs, childEntity.ID)
+	}
+	err = tx.Unscoped().Model(&model.ProductTypeEntity{}).
+		Where("prodtype_code IN ?", childIDs).
+		Update("prodtype_parent_code", nil).Error
+	if err != nil {
+		return nil, err
+	}
+	return childEntities, recordRevisions(tx, model.RevisionOpMove, childIDs)
+}
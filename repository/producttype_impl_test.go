@@ -1,8 +1,10 @@
 package repository_test
 
 import (
+	"context"
 	"testing"
 	"regexp"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -28,31 +30,37 @@ func TestCreate(t *testing.T) {
 	}
 
 	t.Run("test case : create producttype success", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 		rows := sqlmock.NewRows([]string{"Id", "Name"}).AddRow(1, "A")
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(`INSERT INTO "producttype"`).
-			WithArgs("A", 1).
+			WithArgs("A", nil, nil, 1).
 			WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("create", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := repo.Save(prodTypeCreateMock)
+		err := repo.Save(context.Background(), prodTypeCreateMock)
 
 		assert.NoError(t, err)
 		assert.Nil(t, err)
 	})
 
 	t.Run("test case : create producttype fail", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(`INSERT INTO "producttype"`).
-        	WithArgs("A", 1).
+        	WithArgs("A", nil, nil, 1).
         	WillReturnError(errs.NewInternalServerError(""))
     	mock.ExpectRollback()
 
-		err := repo.Save(prodTypeCreateMock)
+		err := repo.Save(context.Background(), prodTypeCreateMock)
 
 		expectedRes := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -78,25 +86,25 @@ func TestFindAll(t *testing.T) {
 		},
 	}
 	t.Run("test case : find all producttype success", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A").AddRow(2, "B")
 		mock.ExpectQuery(`SELECT \* FROM "producttype"`).
 			WillReturnRows(rows)
 
-		result, err := repo.FindAll()
+		result, err := repo.FindAll(context.Background())
 
 		expectedRes := entityRes
 		assert.NoError(t, err)
 		assert.Equal(t, expectedRes, result)
 	})
 	t.Run("test case : find all producttype fail", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectQuery(`SELECT \* FROM "producttype"`).
 			WillReturnError(errs.NewInternalServerError(""))
 
-		_, err := repo.FindAll()
+		_, err := repo.FindAll(context.Background())
 
 		expectedRes := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -116,25 +124,25 @@ func TestFindByID(t *testing.T) {
 			Name: "A",
 		}
 
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 		rows := sqlmock.NewRows([]string{"ID", "Name"}).AddRow(1, "A")
 
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnRows(rows)
 
-		result, err := repo.FindByID(1)
+		result, err := repo.FindByID(context.Background(), 1)
 
 		expectedRes := entityRes
 		assert.NoError(t, err)
 		assert.Equal(t, expectedRes, result)
 	})
 	t.Run("test case : get fail gorm not found", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnError(gorm.ErrRecordNotFound)
 
-		_, err := repo.FindByID(1)
+		_, err := repo.FindByID(context.Background(), 1)
 
 		expectedRes := errs.NewNotFoundError("record not found")
 		assert.Error(t, err)
@@ -142,12 +150,12 @@ func TestFindByID(t *testing.T) {
 	})
 
 	t.Run("test case : get fail get id", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectQuery(`SELECT \* FROM "producttype" WHERE`).
 			WillReturnError(errs.NewInternalServerError(""))
 
-		_, err := repo.FindByID(1)
+		_, err := repo.FindByID(context.Background(), 1)
 
 		expectedRes := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -168,22 +176,28 @@ func TestUpdate(t *testing.T) {
 	}
 
 	t.Run("test case : update producttype success", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectBegin()
 		mock.ExpectExec(`UPDATE`).
 			WithArgs(1, "A", 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("update", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := repo.Update(prodTypeEntityMock)
+		err := repo.Update(context.Background(), prodTypeEntityMock)
 
 		assert.NoError(t, err)
 		assert.Nil(t, err)
 	})
 
 	t.Run("test case : update producttype fail", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectBegin()
 		mock.ExpectExec(`UPDATE`).
@@ -191,11 +205,34 @@ func TestUpdate(t *testing.T) {
         	WillReturnError(errs.NewInternalServerError(""))
     	mock.ExpectRollback()
 
-		err := repo.Update(prodTypeEntityMock)
+		err := repo.Update(context.Background(), prodTypeEntityMock)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+
+	t.Run("test case : update producttype fail record revision", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE`).
+			WithArgs(1, "A", 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("update", sqlmock.AnyArg(), 1).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		err := repo.Update(context.Background(), prodTypeEntityMock)
 
 		expectedRes := errs.NewInternalServerError("")
 		assert.Error(t, err)
 		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -207,29 +244,35 @@ func TestDelete(t *testing.T) {
 	}()
 
 	t.Run("test case : delete producttype success", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE").
-    		WithArgs(1).
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+    		WithArgs(sqlmock.AnyArg(), 1).
     		WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("delete", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := repo.Delete(1)
+		err := repo.Delete(context.Background(), 1)
 
 		assert.NoError(t, err)
 		assert.Nil(t, err)
 	})
 	t.Run("test case : delete producttype fail", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE").
-    		WithArgs(1).
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+    		WithArgs(sqlmock.AnyArg(), 1).
     		WillReturnError(errs.NewInternalServerError(""))
 		mock.ExpectRollback()
 
-		err := repo.Delete(1)
+		err := repo.Delete(context.Background(), 1)
 
 		expectedRes := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -245,27 +288,948 @@ func TestCount(t *testing.T) {
 	}()
 
 	t.Run("test case : get count pass", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "producttype"`)).
       		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-		result, err := repo.Count()
+		result, err := repo.Count(context.Background())
 
 		expectedRes := int64(1)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedRes, result)
 	})
 	t.Run("test case : get count fail", func(t *testing.T) {
-		repo := repository.NewProductTypeRepositoryImpl(db)
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
 
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "producttype"`)).
 			WillReturnError(errs.NewInternalServerError(""))
 
-		_, err := repo.Count()
+		_, err := repo.Count(context.Background())
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestFindAllDeleted(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entityRes := []model.ProductTypeEntity{
+		{
+			ID:   		1,
+			Name: 		"A",
+			DeletedAt: 	gorm.DeletedAt{Time: deletedAt, Valid: true},
+		},
+	}
+	t.Run("test case : find all deleted producttype success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		rows := sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_deleted_at"}).AddRow(1, "A", deletedAt)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at IS NOT NULL`)).
+			WillReturnRows(rows)
+
+		result, err := repo.FindAllDeleted(context.Background())
+
+		expectedRes := entityRes
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, result)
+	})
+	t.Run("test case : find all deleted producttype fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at IS NOT NULL`)).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		_, err := repo.FindAllDeleted(context.Background())
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestFindDeletedByID(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find deleted producttype by id pass", func(t *testing.T) {
+		deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		entityRes := &model.ProductTypeEntity{
+			ID:   		1,
+			Name: 		"A",
+			DeletedAt: 	gorm.DeletedAt{Time: deletedAt, Valid: true},
+		}
+
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+		rows := sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_deleted_at"}).AddRow(1, "A", deletedAt)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at IS NOT NULL`)).
+			WithArgs(1, 1).
+			WillReturnRows(rows)
+
+		result, err := repo.FindDeletedByID(context.Background(), 1)
+
+		expectedRes := entityRes
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, result)
+	})
+	t.Run("test case : get fail gorm not found", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at IS NOT NULL`)).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.FindDeletedByID(context.Background(), 1)
+
+		expectedRes := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+	t.Run("test case : get fail get id", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at IS NOT NULL`)).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		_, err := repo.FindDeletedByID(context.Background(), 1)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : restore producttype success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_deleted_at"=$1 WHERE prodtype_code = $2`)).
+			WithArgs(nil, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("restore", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.Restore(context.Background(), 1)
+
+		assert.NoError(t, err)
+	})
+	t.Run("test case : restore producttype fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_deleted_at"=$1 WHERE prodtype_code = $2`)).
+			WithArgs(nil, 1).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		err := repo.Restore(context.Background(), 1)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestPurge(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : purge producttype success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code IN ($1) AND prodtype_code NOT IN ($2) ORDER BY prodtype_code`)).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).AddRow(2, "B", 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_parent_code"=$1 WHERE prodtype_code IN ($2)`)).
+			WithArgs(nil, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("move", sqlmock.AnyArg(), 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "producttype" WHERE "producttype"."prodtype_code" = $1`)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		result, err := repo.Purge(context.Background(), 1)
+
+		parentID := 1
+		expectedRes := []model.ProductTypeEntity{{ID: 2, Name: "B", ParentID: &parentID}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("test case : purge producttype fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code IN ($1) AND prodtype_code NOT IN ($2) ORDER BY prodtype_code`)).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "producttype" WHERE "producttype"."prodtype_code" = $1`)).
+			WithArgs(1).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		_, err := repo.Purge(context.Background(), 1)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPurgeDeletedBefore(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : purge deleted before success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at < $1 ORDER BY prodtype_code`)).
+			WithArgs(before).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name"}).AddRow(1, "A").AddRow(2, "B"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code IN ($1,$2) AND prodtype_code NOT IN ($3,$4) ORDER BY prodtype_code`)).
+			WithArgs(1, 2, 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name"}))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "producttype" WHERE "producttype"."prodtype_code" IN ($1,$2)`)).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		result, _, err := repo.PurgeDeletedBefore(context.Background(), before)
+
+		expectedRes := []model.ProductTypeEntity{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("test case : purge deleted before nothing expired", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at < $1 ORDER BY prodtype_code`)).
+			WithArgs(before).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name"}))
+		mock.ExpectCommit()
+
+		result, children, err := repo.PurgeDeletedBefore(context.Background(), before)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.Empty(t, children)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("test case : purge deleted before fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_deleted_at < $1 ORDER BY prodtype_code`)).
+			WithArgs(before).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		_, _, err := repo.PurgeDeletedBefore(context.Background(), before)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFindByIDsUnscoped(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find by ids unscoped success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		rows := sqlmock.NewRows([]string{"prodtype_code", "prodtype_name"}).AddRow(1, "A").AddRow(2, "B")
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_code IN ($1,$2)`)).
+			WithArgs(1, 2).
+			WillReturnRows(rows)
+
+		result, err := repo.FindByIDsUnscoped(context.Background(), []int{1, 2})
+
+		expectedRes := []model.ProductTypeEntity{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, result)
+	})
+	t.Run("test case : find by ids unscoped fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_code IN ($1,$2)`)).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		_, err := repo.FindByIDsUnscoped(context.Background(), []int{1, 2})
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestApplyBatch(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	batch := &model.ProductTypeBatch{
+		Creates: []model.ProductTypeEntity{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}},
+		Updates: []model.ProductTypeEntity{{ID: 3, Name: "C"}},
+		Deletes: []int{4},
+	}
+
+	t.Run("test case : apply batch success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "producttype"`).
+			WithArgs("A", nil, nil, 1, "B", nil, nil, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code"}).AddRow(1).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("create", sqlmock.AnyArg(), 1, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE "producttype" SET`).
+			WithArgs(3, "C", 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("update", sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("delete", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.ApplyBatch(context.Background(), batch)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : apply batch fail rollback", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "producttype"`).
+			WithArgs("A", nil, nil, 1, "B", nil, nil, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code"}).AddRow(1).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("create", sqlmock.AnyArg(), 1, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE "producttype" SET`).
+			WithArgs(3, "C", 3).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		err := repo.ApplyBatch(context.Background(), batch)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFindAllAfter(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find all after success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_code > $1 AND "producttype"."prodtype_deleted_at" IS NULL ORDER BY prodtype_code LIMIT $2`)).
+			WithArgs(2, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name"}).AddRow(3, "C").AddRow(4, "D"))
+
+		prodTypesEntity, err := repo.FindAllAfter(context.Background(), 2, 2)
+
+		expectedRes := []model.ProductTypeEntity{{ID: 3, Name: "C"}, {ID: 4, Name: "D"}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, prodTypesEntity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("test case : find all after fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`SELECT \* FROM "producttype"`).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		prodTypesEntity, err := repo.FindAllAfter(context.Background(), 0, 2)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.Nil(t, prodTypesEntity)
+	})
+}
+
+func TestFindChildren(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find children success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code = $1 AND "producttype"."prodtype_deleted_at" IS NULL`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).AddRow(2, "B", 1))
+
+		prodTypes, err := repo.FindChildren(context.Background(), 1)
+
+		parentID := 1
+		expectedRes := []model.ProductTypeEntity{{ID: 2, Name: "B", ParentID: &parentID}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, prodTypes)
+	})
+
+	t.Run("test case : find children fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code = $1`)).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		_, err := repo.FindChildren(context.Background(), 1)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestFindAncestors(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find ancestors success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`WITH RECURSIVE ancestors AS`).
+			WithArgs(3, 64).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).
+				AddRow(1, "Food", nil).
+				AddRow(2, "Snack", 1))
+
+		prodTypes, err := repo.FindAncestors(context.Background(), 3)
+
+		parentID := 1
+		expectedRes := []model.ProductTypeEntity{{ID: 1, Name: "Food"}, {ID: 2, Name: "Snack", ParentID: &parentID}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, prodTypes)
+	})
+
+	t.Run("test case : find ancestors fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`WITH RECURSIVE ancestors AS`).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		_, err := repo.FindAncestors(context.Background(), 3)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestLockChanges(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : lock changes success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.LockChanges(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : lock changes fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		err := repo.LockChanges(context.Background())
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMove(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : move producttype success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		parentID := 2
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_parent_code"=$1 WHERE prodtype_code = $2 AND "producttype"."prodtype_deleted_at" IS NULL`)).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("move", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.Move(context.Background(), 1, &parentID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("test case : move producttype fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_parent_code"=$1 WHERE prodtype_code = $2`)).
+			WithArgs(nil, 1).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		err := repo.Move(context.Background(), 1, nil)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+	})
+}
+
+func TestDeleteSubtree(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : delete subtree success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE descendants AS`).
+			WithArgs(1, 64).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).
+				AddRow(1, "Food", nil).
+				AddRow(2, "Snack", 1).
+				AddRow(3, "Chips", 2))
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 1, 2, 3).
+			WillReturnResult(sqlmock.NewResult(3, 3))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("delete", sqlmock.AnyArg(), 1, 2, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		prodTypes, err := repo.DeleteSubtree(context.Background(), 1)
+
+		food, snack := 1, 2
+		expectedRes := []model.ProductTypeEntity{
+			{ID: 1, Name: "Food"},
+			{ID: 2, Name: "Snack", ParentID: &food},
+			{ID: 3, Name: "Chips", ParentID: &snack},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, prodTypes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : delete subtree fail rollback", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE descendants AS`).
+			WithArgs(1, 64).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).AddRow(1, "Food", nil).AddRow(2, "Snack", 1))
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 1, 2).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		_, err := repo.DeleteSubtree(context.Background(), 1)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteAndReparent(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : delete and reparent success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		parentID := 5
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code = $1 AND "producttype"."prodtype_deleted_at" IS NULL ORDER BY prodtype_code`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).AddRow(2, "B", 1).AddRow(3, "C", 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_parent_code"=$1 WHERE prodtype_code IN ($2,$3)`)).
+			WithArgs(5, 2, 3).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("move", sqlmock.AnyArg(), 2, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE "producttype" SET "prodtype_deleted_at"`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("delete", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		result, err := repo.DeleteAndReparent(context.Background(), 1, &parentID)
+
+		oldParentID := 1
+		expectedRes := []model.ProductTypeEntity{{ID: 2, Name: "B", ParentID: &oldParentID}, {ID: 3, Name: "C", ParentID: &oldParentID}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : delete and reparent fail rollback", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype" WHERE prodtype_parent_code = $1 AND "producttype"."prodtype_deleted_at" IS NULL ORDER BY prodtype_code`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"prodtype_code", "prodtype_name", "prodtype_parent_code"}).AddRow(2, "B", 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_parent_code"=$1 WHERE prodtype_code IN ($2)`)).
+			WithArgs(nil, 2).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		_, err := repo.DeleteAndReparent(context.Background(), 1, nil)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFindRevisions(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	revisionColumns := []string{"rev_id", "prodtype_code", "rev_number", "prodtype_name", "prodtype_parent_code", "prodtype_deleted_at", "rev_operation", "rev_created_at"}
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : find revisions success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype_revision" WHERE prodtype_code = $1 ORDER BY rev_number DESC`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(revisionColumns).
+				AddRow(2, 1, 2, "B", nil, nil, "update", createdAt).
+				AddRow(1, 1, 1, "A", nil, nil, "create", createdAt))
+
+		revisions, err := repo.FindRevisions(context.Background(), 1)
+
+		expectedRes := []model.ProductTypeRevisionEntity{
+			{ID: 2, ProductTypeID: 1, Revision: 2, Name: "B", Operation: "update", CreatedAt: createdAt},
+			{ID: 1, ProductTypeID: 1, Revision: 1, Name: "A", Operation: "create", CreatedAt: createdAt},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, revisions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find revisions fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`SELECT \* FROM "producttype_revision"`).
+			WithArgs(1).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		revisions, err := repo.FindRevisions(context.Background(), 1)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.Nil(t, revisions)
+	})
+}
+
+func TestFindRevision(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find revision success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype_revision" WHERE prodtype_code = $1 AND rev_number = $2 ORDER BY "producttype_revision"."rev_id" LIMIT $3`)).
+			WithArgs(1, 2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"rev_id", "prodtype_code", "rev_number", "prodtype_name", "rev_operation"}).AddRow(2, 1, 2, "B", "update"))
+
+		revision, err := repo.FindRevision(context.Background(), 1, 2)
+
+		expectedRes := &model.ProductTypeRevisionEntity{ID: 2, ProductTypeID: 1, Revision: 2, Name: "B", Operation: "update"}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, revision)
+	})
+
+	t.Run("test case : find revision fail gorm not found", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`SELECT \* FROM "producttype_revision"`).
+			WithArgs(1, 9, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		revision, err := repo.FindRevision(context.Background(), 1, 9)
+
+		expectedRes := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.Nil(t, revision)
+	})
+}
+
+func TestFindRevisionAsOf(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	asOf := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : find revision as of success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype_revision" WHERE prodtype_code = $1 AND rev_created_at <= $2 ORDER BY rev_number DESC,"producttype_revision"."rev_id" LIMIT $3`)).
+			WithArgs(1, asOf, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"rev_id", "prodtype_code", "rev_number", "prodtype_name", "rev_operation"}).AddRow(1, 1, 1, "A", "create"))
+
+		revision, err := repo.FindRevisionAsOf(context.Background(), 1, asOf)
+
+		expectedRes := &model.ProductTypeRevisionEntity{ID: 1, ProductTypeID: 1, Revision: 1, Name: "A", Operation: "create"}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, revision)
+	})
+
+	t.Run("test case : find revision as of fail gorm not found", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`SELECT \* FROM "producttype_revision"`).
+			WithArgs(1, asOf, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		revision, err := repo.FindRevisionAsOf(context.Background(), 1, asOf)
+
+		expectedRes := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.Nil(t, revision)
+	})
+}
+
+func TestRevert(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	parentID := 2
+	revisionMock := &model.ProductTypeRevisionEntity{ProductTypeID: 1, Revision: 1, Name: "A", ParentID: &parentID}
+
+	t.Run("test case : revert producttype success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "producttype" SET "prodtype_name"=$1,"prodtype_parent_code"=$2 WHERE prodtype_code = $3`)).
+			WithArgs("A", 2, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(5310).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO producttype_revision`).
+			WithArgs("revert", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.Revert(context.Background(), revisionMock)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : revert producttype fail rollback", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "producttype" SET`).
+			WithArgs("A", 2, 1).
+			WillReturnError(errs.NewInternalServerError(""))
+		mock.ExpectRollback()
+
+		err := repo.Revert(context.Background(), revisionMock)
+
+		expectedRes := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedRes, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFindChanges(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find changes success", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "producttype_revision" WHERE rev_id > $1 ORDER BY rev_id LIMIT $2`)).
+			WithArgs(10, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"rev_id", "prodtype_code", "rev_number", "prodtype_name", "rev_operation"}).
+				AddRow(11, 1, 2, "B", "update").
+				AddRow(12, 2, 1, "C", "create"))
+
+		changes, err := repo.FindChanges(context.Background(), 10, 3)
+
+		expectedRes := []model.ProductTypeRevisionEntity{
+			{ID: 11, ProductTypeID: 1, Revision: 2, Name: "B", Operation: "update"},
+			{ID: 12, ProductTypeID: 2, Revision: 1, Name: "C", Operation: "create"},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRes, changes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find changes fail", func(t *testing.T) {
+		repo := repository.NewProductTypeRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`SELECT \* FROM "producttype_revision"`).
+			WithArgs(0, 101).
+			WillReturnError(errs.NewInternalServerError(""))
+
+		changes, err := repo.FindChanges(context.Background(), 0, 101)
 
 		expectedRes := errs.NewInternalServerError("")
 		assert.Error(t, err)
 		assert.Equal(t, expectedRes, err)
+		assert.Nil(t, changes)
 	})
 }
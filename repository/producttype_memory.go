@@ -0,0 +1,552 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+type ProductTypeRepositoryMemory struct {
+	db memorySession
+}
+
+func NewProductTypeRepositoryMemory(db *MemoryDB) ProductTypeRepository {
+	return &ProductTypeRepositoryMemory{db: db}
+}
+
+func (r *ProductTypeRepositoryMemory) Save(ctx context.Context, prodTypeCreateReq *model.ProductTypeEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		if err := data.insertProductType(*prodTypeCreateReq); err != nil {
+			return err
+		}
+		data.recordRevisions(model.RevisionOpCreate, []int{prodTypeCreateReq.ID})
+		return nil
+	})
+}
+
+func (r *ProductTypeRepositoryMemory) FindAll(ctx context.Context) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	r.db.view(func(data *memoryData) error {
+		prodTypesEntity = data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return !prodTypeEntity.DeletedAt.Valid
+		})
+		return nil
+	})
+	return prodTypesEntity, nil
+}
+
+func (r *ProductTypeRepositoryMemory) FindByID(ctx context.Context, id int) (*model.ProductTypeEntity, error) {
+	var prodTypeEntity model.ProductTypeEntity
+	err := r.db.view(func(data *memoryData) error {
+		found, ok := data.productTypes[id]
+		if !ok || found.DeletedAt.Valid {
+			return errs.NewNotFoundError(gorm.ErrRecordNotFound.Error())
+		}
+		prodTypeEntity = copyProductType(found)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &prodTypeEntity, nil
+}
+
+// Update sets the fields of prodTypeUpdateReq that are not zero, as gorm Updates does with a struct.
+func (r *ProductTypeRepositoryMemory) Update(ctx context.Context, prodTypeUpdateReq *model.ProductTypeEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		err := data.updateProductType(prodTypeUpdateReq.ID, func(prodTypeEntity *model.ProductTypeEntity) {
+			if prodTypeUpdateReq.Name != "" {
+				prodTypeEntity.Name = prodTypeUpdateReq.Name
+			}
+			if prodTypeUpdateReq.ParentID != nil {
+				prodTypeEntity.ParentID = copyInt(prodTypeUpdateReq.ParentID)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		data.recordRevisions(model.RevisionOpUpdate, []int{prodTypeUpdateReq.ID})
+		return nil
+	})
+}
+
+func (r *ProductTypeRepositoryMemory) Delete(ctx context.Context, id int) error {
+	return r.db.update(func(data *memoryData) error {
+		data.softDeleteProductTypes([]int{id}, time.Now())
+		data.recordRevisions(model.RevisionOpDelete, []int{id})
+		return nil
+	})
+}
+
+func (r *ProductTypeRepositoryMemory) Count(ctx context.Context) (int64, error) {
+	var count int64
+	r.db.view(func(data *memoryData) error {
+		for _, prodTypeEntity := range data.productTypes {
+			if !prodTypeEntity.DeletedAt.Valid {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, nil
+}
+
+func (r *ProductTypeRepositoryMemory) FindAllDeleted(ctx context.Context) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	r.db.view(func(data *memoryData) error {
+		prodTypesEntity = data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return prodTypeEntity.DeletedAt.Valid
+		})
+		return nil
+	})
+	return prodTypesEntity, nil
+}
+
+func (r *ProductTypeRepositoryMemory) FindDeletedByID(ctx context.Context, id int) (*model.ProductTypeEntity, error) {
+	var prodTypeEntity model.ProductTypeEntity
+	err := r.db.view(func(data *memoryData) error {
+		found, ok := data.productTypes[id]
+		if !ok || !found.DeletedAt.Valid {
+			return errs.NewNotFoundError(gorm.ErrRecordNotFound.Error())
+		}
+		prodTypeEntity = copyProductType(found)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &prodTypeEntity, nil
+}
+
+func (r *ProductTypeRepositoryMemory) Restore(ctx context.Context, id int) error {
+	return r.db.update(func(data *memoryData) error {
+		if prodTypeEntity, ok := data.productTypes[id]; ok {
+			prodTypeEntity.DeletedAt = gorm.DeletedAt{}
+			data.productTypes[id] = prodTypeEntity
+		}
+		data.recordRevisions(model.RevisionOpRestore, []int{id})
+		return nil
+	})
+}
+
+// Purge detaches the children with a move revision, the purged product type gets no revision.
+func (r *ProductTypeRepositoryMemory) Purge(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	var childEntities []model.ProductTypeEntity
+	err := r.db.update(func(data *memoryData) error {
+		_, childEntities = data.purgeProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return prodTypeEntity.ID == id
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return childEntities, nil
+}
+
+func (r *ProductTypeRepositoryMemory) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]model.ProductTypeEntity, []model.ProductTypeEntity, error) {
+	var prodTypesEntity, childEntities []model.ProductTypeEntity
+	err := r.db.update(func(data *memoryData) error {
+		prodTypesEntity, childEntities = data.purgeProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return prodTypeEntity.DeletedAt.Valid && prodTypeEntity.DeletedAt.Time.Before(before)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return prodTypesEntity, childEntities, nil
+}
+
+func (r *ProductTypeRepositoryMemory) FindByIDsUnscoped(ctx context.Context, ids []int) ([]model.ProductTypeEntity, error) {
+	wanted := map[int]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var prodTypesEntity []model.ProductTypeEntity
+	r.db.view(func(data *memoryData) error {
+		prodTypesEntity = data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return wanted[prodTypeEntity.ID]
+		})
+		return nil
+	})
+	return prodTypesEntity, nil
+}
+
+// ApplyBatch inserts, updates and soft deletes the batch at once, nothing is written if any of it fails.
+func (r *ProductTypeRepositoryMemory) ApplyBatch(ctx context.Context, batch *model.ProductTypeBatch) error {
+	return r.db.update(func(data *memoryData) error {
+		if len(batch.Creates) > 0 {
+			ids := make([]int, 0, len(batch.Creates))
+			for _, prodTypeEntity := range batch.Creates {
+				if err := data.insertProductType(prodTypeEntity); err != nil {
+					return err
+				}
+				ids = append(ids, prodTypeEntity.ID)
+			}
+			data.recordRevisions(model.RevisionOpCreate, ids)
+		}
+
+		if len(batch.Updates) > 0 {
+			ids := make([]int, 0, len(batch.Updates))
+			for _, prodTypeUpdate := range batch.Updates {
+				err := data.updateProductType(prodTypeUpdate.ID, func(prodTypeEntity *model.ProductTypeEntity) {
+					if prodTypeUpdate.Name != "" {
+						prodTypeEntity.Name = prodTypeUpdate.Name
+					}
+					if prodTypeUpdate.ParentID != nil {
+						prodTypeEntity.ParentID = copyInt(prodTypeUpdate.ParentID)
+					}
+				})
+				if err != nil {
+					return err
+				}
+				ids = append(ids, prodTypeUpdate.ID)
+			}
+			data.recordRevisions(model.RevisionOpUpdate, ids)
+		}
+
+		if len(batch.Deletes) > 0 {
+			data.softDeleteProductTypes(batch.Deletes, time.Now())
+			data.recordRevisions(model.RevisionOpDelete, batch.Deletes)
+		}
+		return nil
+	})
+}
+
+// FindAllAfter returns up to limit product types with a key above afterID in key order.
+func (r *ProductTypeRepositoryMemory) FindAllAfter(ctx context.Context, afterID int, limit int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	r.db.view(func(data *memoryData) error {
+		prodTypesEntity = data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return !prodTypeEntity.DeletedAt.Valid && prodTypeEntity.ID > afterID
+		})
+		return nil
+	})
+	if len(prodTypesEntity) > limit {
+		prodTypesEntity = prodTypesEntity[:limit]
+	}
+	return prodTypesEntity, nil
+}
+
+func (r *ProductTypeRepositoryMemory) FindChildren(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	var prodTypesEntity []model.ProductTypeEntity
+	r.db.view(func(data *memoryData) error {
+		prodTypesEntity = data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return !prodTypeEntity.DeletedAt.Valid && prodTypeEntity.ParentID != nil && *prodTypeEntity.ParentID == id
+		})
+		return nil
+	})
+	return prodTypesEntity, nil
+}
+
+// FindAncestors returns the parent chain of the product type ordered from the root down,
+// the product type itself is not included.
+func (r *ProductTypeRepositoryMemory) FindAncestors(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	prodTypesEntity := []model.ProductTypeEntity{}
+	r.db.view(func(data *memoryData) error {
+		current, ok := data.productTypes[id]
+		if !ok || current.DeletedAt.Valid {
+			return nil
+		}
+		for depth := 0; depth < ProductTypeMaxDepth && current.ParentID != nil; depth++ {
+			parent, ok := data.productTypes[*current.ParentID]
+			if !ok || parent.DeletedAt.Valid {
+				break
+			}
+			prodTypesEntity = append([]model.ProductTypeEntity{copyProductType(parent)}, prodTypesEntity...)
+			current = parent
+		}
+		return nil
+	})
+	return prodTypesEntity, nil
+}
+
+// LockChanges has nothing to lock, a memory transaction is run again when another write committed
+// since it started.
+func (r *ProductTypeRepositoryMemory) LockChanges(ctx context.Context) error {
+	return nil
+}
+
+func (r *ProductTypeRepositoryMemory) Move(ctx context.Context, id int, parentID *int) error {
+	return r.db.update(func(data *memoryData) error {
+		err := data.updateProductType(id, func(prodTypeEntity *model.ProductTypeEntity) {
+			prodTypeEntity.ParentID = copyInt(parentID)
+		})
+		if err != nil {
+			return err
+		}
+		data.recordRevisions(model.RevisionOpMove, []int{id})
+		return nil
+	})
+}
+
+// DeleteSubtree soft deletes the product type together with all of its descendants
+// and returns what was deleted, the product type itself first.
+func (r *ProductTypeRepositoryMemory) DeleteSubtree(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	prodTypesEntity := []model.ProductTypeEntity{}
+	r.db.update(func(data *memoryData) error {
+		root, ok := data.productTypes[id]
+		if !ok || root.DeletedAt.Valid {
+			return nil
+		}
+		level := []model.ProductTypeEntity{root}
+		for depth := 0; len(level) > 0; depth++ {
+			for _, prodTypeEntity := range level {
+				prodTypesEntity = append(prodTypesEntity, model.ProductTypeEntity{
+					ID: 		prodTypeEntity.ID,
+					Name: 		prodTypeEntity.Name,
+					ParentID: 	copyInt(prodTypeEntity.ParentID),
+				})
+			}
+			if depth == ProductTypeMaxDepth {
+				break
+			}
+			var next []model.ProductTypeEntity
+			for _, parent := range level {
+				next = append(next, data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+					return !prodTypeEntity.DeletedAt.Valid && prodTypeEntity.ParentID != nil && *prodTypeEntity.ParentID == parent.ID
+				})...)
+			}
+			level = next
+		}
+
+		ids := make([]int, 0, len(prodTypesEntity))
+		for _, prodTypeEntity := range prodTypesEntity {
+			ids = append(ids, prodTypeEntity.ID)
+		}
+		data.softDeleteProductTypes(ids, time.Now())
+		data.recordRevisions(model.RevisionOpDelete, ids)
+		return nil
+	})
+	return prodTypesEntity, nil
+}
+
+// DeleteAndReparent moves the children of the product type under parentID
+// and soft deletes the product type at once.
+func (r *ProductTypeRepositoryMemory) DeleteAndReparent(ctx context.Context, id int, parentID *int) ([]model.ProductTypeEntity, error) {
+	var children []model.ProductTypeEntity
+	err := r.db.update(func(data *memoryData) error {
+		children = data.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+			return !prodTypeEntity.DeletedAt.Valid && prodTypeEntity.ParentID != nil && *prodTypeEntity.ParentID == id
+		})
+		if len(children) > 0 {
+			childIDs := make([]int, 0, len(children))
+			for _, child := range children {
+				err := data.updateProductType(child.ID, func(prodTypeEntity *model.ProductTypeEntity) {
+					prodTypeEntity.ParentID = copyInt(parentID)
+				})
+				if err != nil {
+					return err
+				}
+				childIDs = append(childIDs, child.ID)
+			}
+			data.recordRevisions(model.RevisionOpMove, childIDs)
+		}
+
+		data.softDeleteProductTypes([]int{id}, time.Now())
+		data.recordRevisions(model.RevisionOpDelete, []int{id})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// FindRevisions returns every revision of the product type, newest first.
+func (r *ProductTypeRepositoryMemory) FindRevisions(ctx context.Context, id int) ([]model.ProductTypeRevisionEntity, error) {
+	var revisionsEntity []model.ProductTypeRevisionEntity
+	r.db.view(func(data *memoryData) error {
+		revisionsEntity = data.findRevisions(func(revisionEntity model.ProductTypeRevisionEntity) bool {
+			return revisionEntity.ProductTypeID == id
+		})
+		return nil
+	})
+	sort.SliceStable(revisionsEntity, func(i, j int) bool {
+		return revisionsEntity[i].Revision > revisionsEntity[j].Revision
+	})
+	return revisionsEntity, nil
+}
+
+func (r *ProductTypeRepositoryMemory) FindRevision(ctx context.Context, id int, revision int) (*model.ProductTypeRevisionEntity, error) {
+	var revisionsEntity []model.ProductTypeRevisionEntity
+	r.db.view(func(data *memoryData) error {
+		revisionsEntity = data.findRevisions(func(revisionEntity model.ProductTypeRevisionEntity) bool {
+			return revisionEntity.ProductTypeID == id && revisionEntity.Revision == revision
+		})
+		return nil
+	})
+	if len(revisionsEntity) == 0 {
+		return nil, errs.NewNotFoundError(gorm.ErrRecordNotFound.Error())
+	}
+	return &revisionsEntity[0], nil
+}
+
+// FindRevisionAsOf returns the revision that was current at asOf.
+func (r *ProductTypeRepositoryMemory) FindRevisionAsOf(ctx context.Context, id int, asOf time.Time) (*model.ProductTypeRevisionEntity, error) {
+	var revisionsEntity []model.ProductTypeRevisionEntity
+	r.db.view(func(data *memoryData) error {
+		revisionsEntity = data.findRevisions(func(revisionEntity model.ProductTypeRevisionEntity) bool {
+			return revisionEntity.ProductTypeID == id && !revisionEntity.CreatedAt.After(asOf)
+		})
+		return nil
+	})
+	if len(revisionsEntity) == 0 {
+		return nil, errs.NewNotFoundError(gorm.ErrRecordNotFound.Error())
+	}
+	latest := revisionsEntity[0]
+	for _, revisionEntity := range revisionsEntity {
+		if revisionEntity.Revision > latest.Revision {
+			latest = revisionEntity
+		}
+	}
+	return &latest, nil
+}
+
+// Revert writes the name and parent of the revision back onto the product type,
+// the result is recorded as a new revision rather than rewriting history.
+func (r *ProductTypeRepositoryMemory) Revert(ctx context.Context, revisionEntity *model.ProductTypeRevisionEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		err := data.updateProductType(revisionEntity.ProductTypeID, func(prodTypeEntity *model.ProductTypeEntity) {
+			prodTypeEntity.Name = revisionEntity.Name
+			prodTypeEntity.ParentID = copyInt(revisionEntity.ParentID)
+		})
+		if err != nil {
+			return err
+		}
+		data.recordRevisions(model.RevisionOpRevert, []int{revisionEntity.ProductTypeID})
+		return nil
+	})
+}
+
+// FindChanges returns up to limit revisions after the since cursor in sequence order.
+func (r *ProductTypeRepositoryMemory) FindChanges(ctx context.Context, since int64, limit int) ([]model.ProductTypeRevisionEntity, error) {
+	var revisionsEntity []model.ProductTypeRevisionEntity
+	r.db.view(func(data *memoryData) error {
+		revisionsEntity = data.findRevisions(func(revisionEntity model.ProductTypeRevisionEntity) bool {
+			return revisionEntity.ID > since
+		})
+		return nil
+	})
+	if limit >= 0 && len(revisionsEntity) > limit {
+		revisionsEntity = revisionsEntity[:limit]
+	}
+	return revisionsEntity, nil
+}
+
+// findProductTypes returns copies of the product types matching keep in primary key order.
+func (d *memoryData) findProductTypes(keep func(model.ProductTypeEntity) bool) []model.ProductTypeEntity {
+	prodTypesEntity := []model.ProductTypeEntity{}
+	for _, prodTypeEntity := range d.productTypes {
+		if keep(prodTypeEntity) {
+			prodTypesEntity = append(prodTypesEntity, copyProductType(prodTypeEntity))
+		}
+	}
+	sort.Slice(prodTypesEntity, func(i, j int) bool {
+		return prodTypesEntity[i].ID < prodTypesEntity[j].ID
+	})
+	return prodTypesEntity
+}
+
+// findRevisions returns copies of the revisions matching keep in sequence order.
+func (d *memoryData) findRevisions(keep func(model.ProductTypeRevisionEntity) bool) []model.ProductTypeRevisionEntity {
+	revisionsEntity := []model.ProductTypeRevisionEntity{}
+	for _, revisionEntity := range d.revisions {
+		if keep(revisionEntity) {
+			revisionEntity.ParentID = copyInt(revisionEntity.ParentID)
+			revisionEntity.DeletedAt = copyTime(revisionEntity.DeletedAt)
+			revisionsEntity = append(revisionsEntity, revisionEntity)
+		}
+	}
+	return revisionsEntity
+}
+
+func (d *memoryData) insertProductType(prodTypeEntity model.ProductTypeEntity) error {
+	if _, ok := d.productTypes[prodTypeEntity.ID]; ok {
+		return writeError(gorm.ErrDuplicatedKey)
+	}
+	if err := d.checkParent(prodTypeEntity.ID, prodTypeEntity.ParentID); err != nil {
+		return err
+	}
+	d.productTypes[prodTypeEntity.ID] = copyProductType(prodTypeEntity)
+	return nil
+}
+
+// updateProductType changes the product type of id unless it is soft deleted, a missing one is no error.
+func (d *memoryData) updateProductType(id int, change func(*model.ProductTypeEntity)) error {
+	prodTypeEntity, ok := d.productTypes[id]
+	if !ok || prodTypeEntity.DeletedAt.Valid {
+		return nil
+	}
+	change(&prodTypeEntity)
+	if err := d.checkParent(id, prodTypeEntity.ParentID); err != nil {
+		return err
+	}
+	d.productTypes[id] = prodTypeEntity
+	return nil
+}
+
+// checkParent keeps the constraints of the parent column, it refers to a product type, soft deleted
+// or not, other than the product type itself.
+func (d *memoryData) checkParent(id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return errs.NewInternalServerError(`new row for relation "producttype" violates check constraint "chk_producttype_parent_not_self"`)
+	}
+	if _, ok := d.productTypes[*parentID]; !ok {
+		return foreignKeyError("producttype")
+	}
+	return nil
+}
+
+func (d *memoryData) softDeleteProductTypes(ids []int, at time.Time) {
+	for _, id := range ids {
+		if prodTypeEntity, ok := d.productTypes[id]; ok && !prodTypeEntity.DeletedAt.Valid {
+			prodTypeEntity.DeletedAt = softDeleted(at)
+			d.productTypes[id] = prodTypeEntity
+		}
+	}
+}
+
+// purgeProductTypes removes the product types matching purge for good and detaches their children,
+// soft deleted or not, with a move revision. It returns the purged product types and the children
+// as they were before.
+func (d *memoryData) purgeProductTypes(purge func(model.ProductTypeEntity) bool) ([]model.ProductTypeEntity, []model.ProductTypeEntity) {
+	purged := d.findProductTypes(purge)
+	ids := make(map[int]bool, len(purged))
+	for _, prodTypeEntity := range purged {
+		ids[prodTypeEntity.ID] = true
+	}
+
+	children := d.findProductTypes(func(prodTypeEntity model.ProductTypeEntity) bool {
+		return prodTypeEntity.ParentID != nil && ids[*prodTypeEntity.ParentID] && !ids[prodTypeEntity.ID]
+	})
+	if len(children) > 0 {
+		childIDs := make([]int, 0, len(children))
+		for _, child := range children {
+			prodTypeEntity := d.productTypes[child.ID]
+			prodTypeEntity.ParentID = nil
+			d.productTypes[child.ID] = prodTypeEntity
+			childIDs = append(childIDs, child.ID)
+		}
+		d.recordRevisions(model.RevisionOpMove, childIDs)
+	}
+
+	for id := range ids {
+		delete(d.productTypes, id)
+	}
+	return purged, children
+}
+
+func copyProductType(prodTypeEntity model.ProductTypeEntity) model.ProductTypeEntity {
+	prodTypeEntity.ParentID = copyInt(prodTypeEntity.ParentID)
+	return prodTypeEntity
+}
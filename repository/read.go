@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/replica"
+
+	"gorm.io/gorm"
+)
+
+const readRetryBackoff = 50 * time.Millisecond
+
+// read runs the read only query fn of method, named <Repository>.<Method>, with a ctx that lets a
+// read replica serve it when the method is routed to replicas. It runs fn again up to retries
+// times on a transient error such as a broken connection. In a transaction it runs once on the
+// primary, the error aborted the transaction and fails it. The TxManager runs a transaction again
+// only on a serialization failure or a deadlock, after a lost connection it can not tell whether
+// the commit went through.
+func read(ctx context.Context, db *gorm.DB, retries int, method string, fn func(ctx context.Context) error) error {
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		retries = 0
+	}
+	routedCtx := replica.Route(ctx, method)
+
+	err := fn(routedCtx)
+	for attempt := 1; attempt <= retries && config.IsTransientError(err); attempt++ {
+		logger.Error(ctx, err, "attempt", attempt)
+		select {
+		case <-time.After(time.Duration(attempt) * readRetryBackoff):
+		case <-ctx.Done():
+			return err
+		}
+		err = fn(routedCtx)
+	}
+	return err
+}
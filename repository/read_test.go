@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/testutils"
+)
+
+func TestReadRetry(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+	countQuery := `SELECT count\(\*\) FROM "producttype"`
+
+	t.Run("test case : read retries a transient error", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).WillReturnError(testutils.SQLStateError("57P01"))
+		mock.ExpectQuery(countQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		count, err := repository.NewProductTypeRepositoryImpl(db, 2).Count(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : read gives up after the retries", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery(countQuery).WillReturnError(testutils.SQLStateError("08006"))
+		}
+
+		_, err := repository.NewProductTypeRepositoryImpl(db, 2).Count(context.Background())
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : read does not retry other errors", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).WillReturnError(testutils.SQLStateError("57014"))
+
+		_, err := repository.NewProductTypeRepositoryImpl(db, 2).Count(context.Background())
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : read in a transaction runs once", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(countQuery).WillReturnError(testutils.SQLStateError("57P01"))
+		mock.ExpectRollback()
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			_, err := repository.NewProductTypeRepositoryImpl(tx, 2).Count(context.Background())
+			return err
+		})
+
+		// a second query would fail on sqlmock instead
+		assert.EqualError(t, err, "SQLSTATE 57P01")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
 type RoleRepository interface {
-	FindByID(id int) (*model.RoleEntity, error)
+	FindByID(ctx context.Context, id int) (*model.RoleEntity, error)
+	FindByTitle(ctx context.Context, title string) (*model.RoleEntity, error)
 }
\ No newline at end of file
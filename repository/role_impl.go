@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 
@@ -8,16 +10,33 @@ import (
 )
 
 type RoleRepositoryImpl struct {
-	db *gorm.DB
+	db 			*gorm.DB
+	readRetries int
 }
 
-func NewRoleRepositoryImpl(db *gorm.DB) RoleRepository {
-	return &RoleRepositoryImpl{db: db}
+func NewRoleRepositoryImpl(db *gorm.DB, readRetries int) RoleRepository {
+	return &RoleRepositoryImpl{db: db, readRetries: readRetries}
 }
 
-func (r *RoleRepositoryImpl) FindByID(id int) (*model.RoleEntity, error) {
+func (r *RoleRepositoryImpl) FindByID(ctx context.Context, id int) (*model.RoleEntity, error) {
+	var roleEntity model.RoleEntity
+	err := read(ctx, r.db, r.readRetries, "RoleRepository.FindByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&roleEntity, id).Error
+	})
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+
+	return &roleEntity, nil
+}
+func (r *RoleRepositoryImpl) FindByTitle(ctx context.Context, title string) (*model.RoleEntity, error) {
 	var roleEntity model.RoleEntity
-	err := r.db.First(&roleEntity, id).Error
+	err := read(ctx, r.db, r.readRetries, "RoleRepository.FindByTitle", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("role_title = ?", title).First(&roleEntity).Error
+	})
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewNotFoundError(err.Error())
@@ -26,4 +45,4 @@ func (r *RoleRepositoryImpl) FindByID(id int) (*model.RoleEntity, error) {
 	}
 
 	return &roleEntity, nil
-}
\ No newline at end of file
+}
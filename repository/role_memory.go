@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepositoryMemory struct {
+	db memorySession
+}
+
+func NewRoleRepositoryMemory(db *MemoryDB) RoleRepository {
+	return &RoleRepositoryMemory{db: db}
+}
+
+func (r *RoleRepositoryMemory) FindByID(ctx context.Context, id int) (*model.RoleEntity, error) {
+	return r.find(func(roleEntity model.RoleEntity) bool {
+		return roleEntity.ID == id
+	})
+}
+
+func (r *RoleRepositoryMemory) FindByTitle(ctx context.Context, title string) (*model.RoleEntity, error) {
+	return r.find(func(roleEntity model.RoleEntity) bool {
+		return roleEntity.Title == title
+	})
+}
+
+func (r *RoleRepositoryMemory) find(match func(model.RoleEntity) bool) (*model.RoleEntity, error) {
+	var roleEntity *model.RoleEntity
+	r.db.view(func(data *memoryData) error {
+		for _, found := range data.roles {
+			if match(found) {
+				roleEntity = &found
+				return nil
+			}
+		}
+		return nil
+	})
+	if roleEntity == nil {
+		return nil, errs.NewNotFoundError(gorm.ErrRecordNotFound.Error())
+	}
+	return roleEntity, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Store holds the repositories of the storage backend DB_DRIVER names. Postgres and SQLite share the
+// gorm repositories, memory keeps the rows in the process. The audit log and the webhooks are not
+// part of it, they are always in the gorm database.
+type Store struct {
+	Users 			UserRepository
+	Roles 			RoleRepository
+	Oauths 			OauthRepository
+	ProductTypes 	ProductTypeRepository
+	Outbox 			OutboxRepository
+	TxManager 		TxManager
+}
+
+// NewStore returns the store of driver, postgres, sqlite or memory, with db the database of driver.
+// A gorm read runs again up to readRetries times on a transient error.
+func NewStore(driver string, db *gorm.DB, readRetries int) *Store {
+	if driver == "memory" {
+		return NewMemoryStore(NewMemoryDB())
+	}
+	return NewGormStore(db, readRetries)
+}
+
+func NewGormStore(db *gorm.DB, readRetries int) *Store {
+	return &Store{
+		Users: 			NewUserRepositoryImpl(db, readRetries),
+		Roles: 			NewRoleRepositoryImpl(db, readRetries),
+		Oauths: 		NewOauthRepositoryImpl(db, readRetries),
+		ProductTypes: 	NewProductTypeRepositoryImpl(db, readRetries),
+		Outbox: 		NewOutboxRepositoryImpl(db),
+		TxManager: 		NewTxManagerImpl(db),
+	}
+}
+
+func NewMemoryStore(db *MemoryDB) *Store {
+	return &Store{
+		Users: 			NewUserRepositoryMemory(db),
+		Roles: 			NewRoleRepositoryMemory(db),
+		Oauths: 		NewOauthRepositoryMemory(db),
+		ProductTypes: 	NewProductTypeRepositoryMemory(db),
+		Outbox: 		NewOutboxRepositoryMemory(db),
+		TxManager: 		NewTxManagerMemory(db),
+	}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+)
+
+// TxManager runs fn in one database transaction. The repositories fn gets from Tx join
+// that transaction, so their writes are committed together or rolled back together
+// when fn returns an error. A transaction that fails to serialize is run again, fn must
+// not leave state behind outside the database that a second run would repeat.
+type TxManager interface {
+	Do(ctx context.Context, fn func(Tx) error) error
+}
+
+type Tx interface {
+	Users() UserRepository
+	Oauths() OauthRepository
+	Roles() RoleRepository
+	ProductTypes() ProductTypeRepository
+	Outbox() OutboxRepository
+	// Do runs fn in a savepoint, an error rolls back the writes of fn only
+	// and the rest of the transaction goes on.
+	Do(fn func(Tx) error) error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	txMaxAttempts 	= 3
+	txRetryBackoff 	= 10 * time.Millisecond
+)
+
+// serialization_failure and deadlock_detected, postgres aborts one of the transactions
+// and running it again is expected to succeed.
+var txRetrySQLStates = []string{"40001", "40P01"}
+
+type TxManagerImpl struct {
+	db *gorm.DB
+}
+
+func NewTxManagerImpl(db *gorm.DB) TxManager {
+	return &TxManagerImpl{db: db}
+}
+
+// Do returns the error of fn as it is, a failed begin or commit is an internal server error.
+// The transaction is rolled back when ctx is done and no retry is made after that.
+func (m *TxManagerImpl) Do(ctx context.Context, fn func(Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= txMaxAttempts; attempt++ {
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(&TxImpl{db: tx})
+		})
+		if err == nil || !isRetryableTxError(err) || attempt == txMaxAttempts {
+			break
+		}
+		logger.Error(ctx, err, "attempt", attempt)
+		select {
+		case <-time.After(time.Duration(attempt) * txRetryBackoff):
+		case <-ctx.Done():
+			return errs.NewInternalServerError(ctx.Err().Error())
+		}
+	}
+
+	switch err.(type) {
+	case nil, errs.ErrorResponse, errs.ValErrorResponse:
+		return err
+	default:
+		return errs.NewInternalServerError(err.Error())
+	}
+}
+
+// isRetryableTxError looks at the SQLSTATE of the driver error, the repositories keep
+// only its message so that is checked too.
+func isRetryableTxError(err error) bool {
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		for _, state := range txRetrySQLStates {
+			if stateErr.SQLState() == state {
+				return true
+			}
+		}
+		return false
+	}
+	for _, state := range txRetrySQLStates {
+		if strings.Contains(err.Error(), "(SQLSTATE " + state + ")") {
+			return true
+		}
+	}
+	return false
+}
+
+// TxImpl gives the repositories on a transaction, their reads are never retried alone.
+type TxImpl struct {
+	db *gorm.DB
+}
+
+func (t *TxImpl) Users() UserRepository {
+	return NewUserRepositoryImpl(t.db, 0)
+}
+
+func (t *TxImpl) Oauths() OauthRepository {
+	return NewOauthRepositoryImpl(t.db, 0)
+}
+
+func (t *TxImpl) Roles() RoleRepository {
+	return NewRoleRepositoryImpl(t.db, 0)
+}
+
+func (t *TxImpl) ProductTypes() ProductTypeRepository {
+	return NewProductTypeRepositoryImpl(t.db, 0)
+}
+
+func (t *TxImpl) Outbox() OutboxRepository {
+	return NewOutboxRepositoryImpl(t.db)
+}
+
+func (t *TxImpl) Do(fn func(Tx) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&TxImpl{db: tx})
+	})
+}
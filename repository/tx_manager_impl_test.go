@@ -0,0 +1,167 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+)
+
+func TestTxManagerDo(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	now := time.Now()
+
+	t.Run("test case : do commits writes together", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}).AddRow(2))
+		mock.ExpectCommit()
+
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			if err := tx.Outbox().Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "1", EventType: "created", CreatedAt: now}}); err != nil {
+				return err
+			}
+			return tx.Outbox().Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "2", EventType: "created", CreatedAt: now}})
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : do rolls back on error", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}).AddRow(1))
+		mock.ExpectRollback()
+
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			if err := tx.Outbox().Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "1", EventType: "created", CreatedAt: now}}); err != nil {
+				return err
+			}
+			return errs.NewBadRequestError("Parent ProductType is not found")
+		})
+
+		assert.Equal(t, errs.NewBadRequestError("Parent ProductType is not found"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : do fail to commit", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("Unexpected Error"))
+
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			return nil
+		})
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : do rolls back failed savepoint only", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}).AddRow(1))
+		mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnError(errors.New("Unexpected Error"))
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		var nestedErr error
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			if err := tx.Outbox().Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "1", EventType: "created", CreatedAt: now}}); err != nil {
+				return err
+			}
+			nestedErr = tx.Do(func(tx repository.Tx) error {
+				return tx.Outbox().Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "2", EventType: "created", CreatedAt: now}})
+			})
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), nestedErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : do retries serialization failure", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnError(errors.New("ERROR: could not serialize access due to concurrent update (SQLSTATE 40001)"))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "outbox"`).
+			WillReturnRows(sqlmock.NewRows([]string{"outbox_id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		attempts := 0
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			attempts++
+			return tx.Outbox().Save(context.Background(), []model.OutboxEventEntity{{AggregateType: "producttype", AggregateID: "1", EventType: "created", CreatedAt: now}})
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : do gives up after max attempts", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		for i := 0; i < 3; i++ {
+			mock.ExpectBegin()
+			mock.ExpectCommit().WillReturnError(testutils.SQLStateError("40P01"))
+		}
+
+		attempts := 0
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			attempts++
+			return nil
+		})
+
+		assert.Equal(t, errs.NewInternalServerError("SQLSTATE 40P01"), err)
+		assert.Equal(t, 3, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : do does not retry other errors", func(t *testing.T) {
+		txManager := repository.NewTxManagerImpl(db)
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		attempts := 0
+		err := txManager.Do(context.Background(), func(tx repository.Tx) error {
+			attempts++
+			return testutils.SQLStateError("23505")
+		})
+
+		assert.Equal(t, errs.NewInternalServerError("SQLSTATE 23505"), err)
+		assert.Equal(t, 1, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
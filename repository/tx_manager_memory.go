@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+)
+
+// errMemoryTxConflict is what a transaction gets when other writes kept committing before it, as
+// the serialization failure a database answers with.
+const errMemoryTxConflict = "could not serialize access due to concurrent update"
+
+type TxManagerMemory struct {
+	db *MemoryDB
+}
+
+func NewTxManagerMemory(db *MemoryDB) TxManager {
+	return &TxManagerMemory{db: db}
+}
+
+// Do runs fn on the version of the data current when it starts and commits its writes when fn
+// returns no error and nothing else was committed meanwhile, otherwise fn is run again on the
+// newer data, as a database retries a transaction that failed to serialize.
+func (m *TxManagerMemory) Do(ctx context.Context, fn func(Tx) error) error {
+	for attempt := 1; attempt <= txMaxAttempts; attempt++ {
+		base := m.db.current()
+		tx := &memoryTx{data: base}
+		if err := fn(&TxMemory{tx: tx}); err != nil {
+			switch err.(type) {
+			case errs.ErrorResponse, errs.ValErrorResponse:
+				return err
+			default:
+				return errs.NewInternalServerError(err.Error())
+			}
+		}
+		if m.db.commit(base, tx.data) {
+			return nil
+		}
+
+		logger.Info(ctx, "Tx: Concurrent update, running the transaction again", "attempt", attempt)
+		select {
+		case <-time.After(time.Duration(attempt) * txRetryBackoff):
+		case <-ctx.Done():
+			return errs.NewInternalServerError(ctx.Err().Error())
+		}
+	}
+	return errs.NewInternalServerError(errMemoryTxConflict)
+}
+
+type TxMemory struct {
+	tx *memoryTx
+}
+
+func (t *TxMemory) Users() UserRepository {
+	return &UserRepositoryMemory{db: t.tx}
+}
+
+func (t *TxMemory) Oauths() OauthRepository {
+	return &OauthRepositoryMemory{db: t.tx}
+}
+
+func (t *TxMemory) Roles() RoleRepository {
+	return &RoleRepositoryMemory{db: t.tx}
+}
+
+func (t *TxMemory) ProductTypes() ProductTypeRepository {
+	return &ProductTypeRepositoryMemory{db: t.tx}
+}
+
+func (t *TxMemory) Outbox() OutboxRepository {
+	return &OutboxRepositoryMemory{db: t.tx}
+}
+
+// Do keeps the writes of fn only when it returns no error, as a savepoint.
+func (t *TxMemory) Do(fn func(Tx) error) error {
+	savepoint := &memoryTx{data: t.tx.data}
+	if err := fn(&TxMemory{tx: savepoint}); err != nil {
+		return err
+	}
+	t.tx.data = savepoint.data
+	return nil
+}
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
 type UserRepository interface {
-	Create(userCreateReq *model.UserEntity) error
-	FindByID(id int) (*model.UserEntity, error)
-	FindByEmail(email string) (*model.UserEntity, error)
+	Create(ctx context.Context, userCreateReq *model.UserEntity) error
+	FindByID(ctx context.Context, id int) (*model.UserEntity, error)
+	FindByEmail(ctx context.Context, email string) (*model.UserEntity, error)
+	Update(ctx context.Context, userUpdateReq *model.UserEntity) error
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 
@@ -8,24 +10,27 @@ import (
 )
 
 type UserRepositoryImpl struct {
-	db *gorm.DB
+	db 			*gorm.DB
+	readRetries int
 }
 
-func NewUserRepositoryImpl(db *gorm.DB) UserRepository {
-	return &UserRepositoryImpl{db: db}
+func NewUserRepositoryImpl(db *gorm.DB, readRetries int) UserRepository {
+	return &UserRepositoryImpl{db: db, readRetries: readRetries}
 }
 
-func (r *UserRepositoryImpl) Create(userCreateReq *model.UserEntity) error {
-	if err := r.db.Create(&userCreateReq).Error; err != nil {
-		return errs.NewInternalServerError(err.Error())
+func (r *UserRepositoryImpl) Create(ctx context.Context, userCreateReq *model.UserEntity) error {
+	if err := r.db.WithContext(ctx).Create(&userCreateReq).Error; err != nil {
+		return writeError(err)
 	}
 	return nil
 }
 
 
-func (r *UserRepositoryImpl) FindByID(id int) (*model.UserEntity, error) {
+func (r *UserRepositoryImpl) FindByID(ctx context.Context, id int) (*model.UserEntity, error) {
 	var userEntity model.UserEntity
-	err := r.db.First(&userEntity, id).Error
+	err := read(ctx, r.db, r.readRetries, "UserRepository.FindByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&userEntity, id).Error
+	})
 	if err != nil {
 		if gorm.ErrRecordNotFound == err {
 			return nil, errs.NewNotFoundError(err.Error())
@@ -36,11 +41,21 @@ func (r *UserRepositoryImpl) FindByID(id int) (*model.UserEntity, error) {
 	return &userEntity, nil
 }
 
-func (r *UserRepositoryImpl) FindByEmail(email string) (*model.UserEntity, error) {
+func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*model.UserEntity, error) {
 	var user model.UserEntity
-	err := r.db.Where("user_email = ?", email).First(&user).Error
+	err := read(ctx, r.db, r.readRetries, "UserRepository.FindByEmail", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("user_email = ?", email).First(&user).Error
+	})
 	if err != nil {
 		return nil, errs.NewNotFoundError("Email or Password is incorrect")
 	}
 	return &user, nil
-}
\ No newline at end of file
+}
+
+// Update saves every column of userUpdateReq, a nil DisabledAt enables the user again.
+func (r *UserRepositoryImpl) Update(ctx context.Context, userUpdateReq *model.UserEntity) error {
+	if err := r.db.WithContext(ctx).Save(userUpdateReq).Error; err != nil {
+		return writeError(err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+)
+
+type UserRepositoryMemory struct {
+	db memorySession
+}
+
+func NewUserRepositoryMemory(db *MemoryDB) UserRepository {
+	return &UserRepositoryMemory{db: db}
+}
+
+func (r *UserRepositoryMemory) Create(ctx context.Context, userCreateReq *model.UserEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		if _, ok := data.users[userCreateReq.ID]; ok {
+			return writeError(gorm.ErrDuplicatedKey)
+		}
+		return data.saveUser(*userCreateReq)
+	})
+}
+
+func (r *UserRepositoryMemory) FindByID(ctx context.Context, id int) (*model.UserEntity, error) {
+	var userEntity model.UserEntity
+	err := r.db.view(func(data *memoryData) error {
+		found, ok := data.users[id]
+		if !ok {
+			return errs.NewNotFoundError(gorm.ErrRecordNotFound.Error())
+		}
+		userEntity = copyUser(found)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userEntity, nil
+}
+
+func (r *UserRepositoryMemory) FindByEmail(ctx context.Context, email string) (*model.UserEntity, error) {
+	var userEntity *model.UserEntity
+	r.db.view(func(data *memoryData) error {
+		for _, found := range data.users {
+			if found.Email == email {
+				user := copyUser(found)
+				userEntity = &user
+				return nil
+			}
+		}
+		return nil
+	})
+	if userEntity == nil {
+		return nil, errs.NewNotFoundError("Email or Password is incorrect")
+	}
+	return userEntity, nil
+}
+
+// Update saves every column of userUpdateReq, a nil DisabledAt enables the user again.
+func (r *UserRepositoryMemory) Update(ctx context.Context, userUpdateReq *model.UserEntity) error {
+	return r.db.update(func(data *memoryData) error {
+		return data.saveUser(*userUpdateReq)
+	})
+}
+
+// saveUser inserts or replaces the user, keeping the email unique and the role a known one.
+func (d *memoryData) saveUser(userEntity model.UserEntity) error {
+	for id, other := range d.users {
+		if id != userEntity.ID && other.Email == userEntity.Email {
+			return writeError(gorm.ErrDuplicatedKey)
+		}
+	}
+	if _, ok := d.roles[userEntity.RoleID]; !ok {
+		return foreignKeyError("user")
+	}
+	d.users[userEntity.ID] = copyUser(userEntity)
+	return nil
+}
+
+func copyUser(userEntity model.UserEntity) model.UserEntity {
+	userEntity.DisabledAt = copyTime(userEntity.DisabledAt)
+	return userEntity
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+type WebhookRepository interface {
+	Save(context.Context, *model.WebhookEntity) error
+	FindAll(ctx context.Context) ([]model.WebhookEntity, error)
+	FindByID(ctx context.Context, id int) (*model.WebhookEntity, error)
+	FindActive(ctx context.Context) ([]model.WebhookEntity, error)
+	Update(context.Context, *model.WebhookEntity) error
+	Delete(ctx context.Context, id int) error
+
+	SaveDeliveries(context.Context, []model.WebhookDeliveryEntity) error
+	ClaimDueDelivery(ctx context.Context, now time.Time, until time.Time) (*model.WebhookDeliveryEntity, error)
+	FindDeliveries(ctx context.Context, webhookID int, filter *model.WebhookDeliveryFilter) ([]model.WebhookDeliveryEntity, int64, error)
+	FindDeliveryByID(ctx context.Context, webhookID int, deliveryID int64) (*model.WebhookDeliveryEntity, error)
+	UpdateDelivery(context.Context, *model.WebhookDeliveryEntity) error
+	FinishDelivery(ctx context.Context, deliveryEntity *model.WebhookDeliveryEntity, claimedUntil time.Time) (bool, error)
+}
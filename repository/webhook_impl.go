@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepositoryImpl struct {
+	db 			*gorm.DB
+	readRetries int
+}
+
+func NewWebhookRepositoryImpl(db *gorm.DB, readRetries int) WebhookRepository {
+	return &WebhookRepositoryImpl{db: db, readRetries: readRetries}
+}
+
+func (r *WebhookRepositoryImpl) Save(ctx context.Context, webhookEntity *model.WebhookEntity) error {
+	if err := r.db.WithContext(ctx).Create(webhookEntity).Error; err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) FindAll(ctx context.Context) ([]model.WebhookEntity, error) {
+	var webhookEntities []model.WebhookEntity
+	err := read(ctx, r.db, r.readRetries, "WebhookRepository.FindAll", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Order("webhook_id").Find(&webhookEntities).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return webhookEntities, nil
+}
+
+func (r *WebhookRepositoryImpl) FindByID(ctx context.Context, id int) (*model.WebhookEntity, error) {
+	var webhookEntity model.WebhookEntity
+	err := read(ctx, r.db, r.readRetries, "WebhookRepository.FindByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&webhookEntity, id).Error
+	})
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return &webhookEntity, nil
+}
+
+func (r *WebhookRepositoryImpl) FindActive(ctx context.Context) ([]model.WebhookEntity, error) {
+	var webhookEntities []model.WebhookEntity
+	err := read(ctx, r.db, r.readRetries, "WebhookRepository.FindActive", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("webhook_active = ?", true).Order("webhook_id").Find(&webhookEntities).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return webhookEntities, nil
+}
+
+// Update writes every column so a webhook can be deactivated.
+func (r *WebhookRepositoryImpl) Update(ctx context.Context, webhookEntity *model.WebhookEntity) error {
+	err := r.db.WithContext(ctx).Model(webhookEntity).
+		Select("webhook_url", "webhook_events", "webhook_secret", "webhook_active", "webhook_updated_at").
+		Updates(webhookEntity).Error
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// Delete removes the webhook together with its delivery log.
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, id int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&model.WebhookDeliveryEntity{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.WebhookEntity{}, id).Error
+	})
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// SaveDeliveries skips the deliveries of an event a webhook already has, so an event handed over again
+// is not sent twice.
+func (r *WebhookRepositoryImpl) SaveDeliveries(ctx context.Context, deliveryEntities []model.WebhookDeliveryEntity) error {
+	if len(deliveryEntities) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: 	[]clause.Column{{Name: "webhook_id"}, {Name: "delivery_event_id"}},
+		DoNothing: 	true,
+	}).Create(&deliveryEntities).Error
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// ClaimDueDelivery takes the oldest pending or failed delivery of an active webhook whose next attempt is due
+// and moves its next attempt to until, so no other worker picks it up while it is sent. A worker that dies
+// leaves the delivery to be claimed again once until has passed. It returns nil when nothing is due.
+// On Postgres a delivery another worker is claiming is skipped rather than waited for.
+func (r *WebhookRepositoryImpl) ClaimDueDelivery(ctx context.Context, now time.Time, until time.Time) (*model.WebhookDeliveryEntity, error) {
+	var deliveryEntities []model.WebhookDeliveryEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("delivery_status IN ?", []string{model.WebhookDeliveryPending, model.WebhookDeliveryFailed}).
+			Where("delivery_next_attempt_at <= ?", now).
+			Where("webhook_id IN (?)", tx.Model(&model.WebhookEntity{}).Select("webhook_id").Where("webhook_active = ?", true)).
+			Order("delivery_id").
+			Limit(1)
+		if r.db.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Find(&deliveryEntities).Error; err != nil {
+			return err
+		}
+		if len(deliveryEntities) == 0 {
+			return nil
+		}
+
+		deliveryEntities[0].NextAttemptAt = until
+		return tx.Model(&deliveryEntities[0]).Update("delivery_next_attempt_at", until).Error
+	})
+	if err != nil {
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	if len(deliveryEntities) == 0 {
+		return nil, nil
+	}
+	return &deliveryEntities[0], nil
+}
+
+// FindDeliveries returns one page of the delivery log of a webhook, newest first, together with the total matching the filter.
+func (r *WebhookRepositoryImpl) FindDeliveries(ctx context.Context, webhookID int, filter *model.WebhookDeliveryFilter) ([]model.WebhookDeliveryEntity, int64, error) {
+	var total int64
+	err := read(ctx, r.db, r.readRetries, "WebhookRepository.FindDeliveries", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.WebhookDeliveryEntity{}).Scopes(webhookDeliveryFilterScope(webhookID, filter)).Count(&total).Error
+	})
+	if err != nil {
+		return nil, 0, errs.NewInternalServerError(err.Error())
+	}
+
+	var deliveryEntities []model.WebhookDeliveryEntity
+	err = read(ctx, r.db, r.readRetries, "WebhookRepository.FindDeliveries", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Scopes(webhookDeliveryFilterScope(webhookID, filter)).
+			Order("delivery_id DESC").
+			Limit(filter.Limit).
+			Offset(filter.Offset).
+			Find(&deliveryEntities).Error
+	})
+	if err != nil {
+		return nil, 0, errs.NewInternalServerError(err.Error())
+	}
+	return deliveryEntities, total, nil
+}
+
+func (r *WebhookRepositoryImpl) FindDeliveryByID(ctx context.Context, webhookID int, deliveryID int64) (*model.WebhookDeliveryEntity, error) {
+	var deliveryEntity model.WebhookDeliveryEntity
+	err := read(ctx, r.db, r.readRetries, "WebhookRepository.FindDeliveryByID", func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("webhook_id = ?", webhookID).First(&deliveryEntity, deliveryID).Error
+	})
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewInternalServerError(err.Error())
+	}
+	return &deliveryEntity, nil
+}
+
+// UpdateDelivery writes the status, attempts and next attempt of the delivery, what a redelivery resets.
+// A worker sending the delivery meanwhile loses its claim and does not write its outcome over it.
+func (r *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, deliveryEntity *model.WebhookDeliveryEntity) error {
+	err := r.db.WithContext(ctx).Model(deliveryEntity).
+		Select("delivery_status", "delivery_attempts", "delivery_next_attempt_at").
+		Updates(deliveryEntity).Error
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// FinishDelivery writes the outcome of an attempt on a delivery claimed until claimedUntil. It writes nothing
+// and returns false when the claim was lost, the delivery was redelivered or claimed again after the claim ran out.
+func (r *WebhookRepositoryImpl) FinishDelivery(ctx context.Context, deliveryEntity *model.WebhookDeliveryEntity, claimedUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(deliveryEntity).
+		Where("delivery_next_attempt_at = ?", claimedUntil).
+		Select("delivery_status", "delivery_attempts", "delivery_next_attempt_at", "delivery_last_status_code", "delivery_last_error", "delivery_delivered_at").
+		Updates(deliveryEntity)
+	if result.Error != nil {
+		return false, errs.NewInternalServerError(result.Error.Error())
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func webhookDeliveryFilterScope(webhookID int, filter *model.WebhookDeliveryFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("webhook_id = ?", webhookID)
+		if filter.Status != "" {
+			db = db.Where("delivery_status = ?", filter.Status)
+		}
+		return db
+	}
+}
@@ -0,0 +1,385 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+)
+
+func TestWebhookSave(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	now := time.Now()
+	webhookMock := &model.WebhookEntity{
+		URL: 		"https://example.com/hook",
+		Events: 	"producttype.created,producttype.deleted",
+		Secret: 	"0123456789abcdef",
+		Active: 	true,
+		CreatedAt: 	now,
+		UpdatedAt: 	now,
+	}
+
+	t.Run("test case : save webhook success", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "webhook"`).
+			WithArgs("https://example.com/hook", "producttype.created,producttype.deleted", "0123456789abcdef", true, now, now).
+			WillReturnRows(sqlmock.NewRows([]string{"webhook_id"}).AddRow(3))
+		mock.ExpectCommit()
+
+		err := repo.Save(context.Background(), webhookMock)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, webhookMock.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : save webhook fail", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "webhook"`).
+			WillReturnError(errors.New("Unexpected Error"))
+		mock.ExpectRollback()
+
+		err := repo.Save(context.Background(), &model.WebhookEntity{})
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookFindByID(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find webhook by id pass", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+		rows := sqlmock.NewRows([]string{"webhook_id", "webhook_url", "webhook_events", "webhook_active"}).
+			AddRow(1, "https://example.com/hook", "producttype.created", true)
+
+		mock.ExpectQuery(`SELECT \* FROM "webhook" WHERE "webhook"."webhook_id" = \$1`).
+			WithArgs(1, 1).
+			WillReturnRows(rows)
+
+		result, err := repo.FindByID(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.WebhookEntity{ID: 1, URL: "https://example.com/hook", Events: "producttype.created", Active: true}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : find webhook fail gorm not found", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(`SELECT \* FROM "webhook" WHERE`).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.FindByID(context.Background(), 1)
+
+		assert.Equal(t, errs.NewNotFoundError("record not found"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookFindActive(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find active webhooks pass", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+		rows := sqlmock.NewRows([]string{"webhook_id", "webhook_events", "webhook_active"}).
+			AddRow(1, "producttype.created", true).
+			AddRow(2, "producttype.deleted", true)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook" WHERE webhook_active = $1 ORDER BY webhook_id`)).
+			WithArgs(true).
+			WillReturnRows(rows)
+
+		result, err := repo.FindActive(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookUpdate(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : update webhook writes inactive", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook" SET "webhook_url"=$1,"webhook_events"=$2,"webhook_secret"=$3,"webhook_active"=$4,"webhook_updated_at"=$5 WHERE "webhook_id" = $6`)).
+			WithArgs("https://example.com/hook", "producttype.updated", "0123456789abcdef", false, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Update(context.Background(), &model.WebhookEntity{ID: 1, URL: "https://example.com/hook", Events: "producttype.updated", Secret: "0123456789abcdef", Active: false, UpdatedAt: now})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookDelete(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : delete webhook with deliveries", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_delivery" WHERE webhook_id = $1`)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook" WHERE "webhook"."webhook_id" = $1`)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Delete(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : delete webhook fail rolls back", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM "webhook_delivery"`).
+			WillReturnError(errors.New("Unexpected Error"))
+		mock.ExpectRollback()
+
+		err := repo.Delete(context.Background(), 1)
+
+		assert.Equal(t, errs.NewInternalServerError("Unexpected Error"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookSaveDeliveries(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : save deliveries in one insert", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+		now := time.Now()
+		deliveriesMock := []model.WebhookDeliveryEntity{
+			{WebhookID: 1, EventID: "e1", EventType: "producttype.created", Payload: `{}`, Status: "pending", NextAttemptAt: now, CreatedAt: now},
+			{WebhookID: 2, EventID: "e1", EventType: "producttype.created", Payload: `{}`, Status: "pending", NextAttemptAt: now, CreatedAt: now},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "webhook_delivery" .* ON CONFLICT \("webhook_id","delivery_event_id"\) DO NOTHING`).
+			WillReturnRows(sqlmock.NewRows([]string{"delivery_id"}).AddRow(1).AddRow(2))
+		mock.ExpectCommit()
+
+		err := repo.SaveDeliveries(context.Background(), deliveriesMock)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : save no deliveries does nothing", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		err := repo.SaveDeliveries(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookClaimDueDelivery(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : claim due delivery of active webhook skips locked", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+		now := time.Now()
+		until := now.Add(time.Minute)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_delivery" WHERE delivery_status IN ($1,$2) AND delivery_next_attempt_at <= $3 AND webhook_id IN (SELECT "webhook_id" FROM "webhook" WHERE webhook_active = $4) ORDER BY delivery_id LIMIT $5 FOR UPDATE SKIP LOCKED`)).
+			WithArgs("pending", "failed", now, true, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "webhook_id", "delivery_status"}).AddRow(1, 1, "pending"))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_delivery" SET "delivery_next_attempt_at"=$1 WHERE "delivery_id" = $2`)).
+			WithArgs(until, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		result, err := repo.ClaimDueDelivery(context.Background(), now, until)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.WebhookDeliveryEntity{ID: 1, WebhookID: 1, Status: "pending", NextAttemptAt: until}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : claim nothing due", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT \* FROM "webhook_delivery"`).
+			WillReturnRows(sqlmock.NewRows([]string{"delivery_id"}))
+		mock.ExpectCommit()
+
+		result, err := repo.ClaimDueDelivery(context.Background(), time.Now(), time.Now())
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookFinishDelivery(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	deliveredAt := time.Now()
+	claimedUntil := deliveredAt.Add(time.Minute)
+	statusCode := 200
+
+	t.Run("test case : finish delivery under its claim", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_delivery" SET "delivery_status"=$1,"delivery_attempts"=$2,"delivery_next_attempt_at"=$3,"delivery_last_status_code"=$4,"delivery_last_error"=$5,"delivery_delivered_at"=$6 WHERE delivery_next_attempt_at = $7 AND "delivery_id" = $8`)).
+			WithArgs("succeeded", 1, claimedUntil, statusCode, "", deliveredAt, claimedUntil, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		finished, err := repo.FinishDelivery(context.Background(), &model.WebhookDeliveryEntity{ID: 1, Status: "succeeded", Attempts: 1, NextAttemptAt: claimedUntil, LastStatusCode: &statusCode, DeliveredAt: &deliveredAt}, claimedUntil)
+
+		assert.NoError(t, err)
+		assert.True(t, finished)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test case : finish delivery whose claim was lost", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "webhook_delivery" SET`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		finished, err := repo.FinishDelivery(context.Background(), &model.WebhookDeliveryEntity{ID: 1, Status: "failed", Attempts: 1}, claimedUntil)
+
+		assert.NoError(t, err)
+		assert.False(t, finished)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookUpdateDelivery(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : update delivery writes only what redelivery resets", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_delivery" SET "delivery_status"=$1,"delivery_attempts"=$2,"delivery_next_attempt_at"=$3 WHERE "delivery_id" = $4`)).
+			WithArgs("pending", 0, now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateDelivery(context.Background(), &model.WebhookDeliveryEntity{ID: 1, Status: "pending", NextAttemptAt: now, Payload: "{}"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookFindDeliveries(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find deliveries page by status", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "webhook_delivery" WHERE webhook_id = $1 AND delivery_status = $2`)).
+			WithArgs(1, "dead").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_delivery" WHERE webhook_id = $1 AND delivery_status = $2 ORDER BY delivery_id DESC LIMIT $3 OFFSET $4`)).
+			WithArgs(1, "dead", 2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "webhook_id"}).AddRow(3, 1).AddRow(2, 1))
+
+		result, total, err := repo.FindDeliveries(context.Background(), 1, &model.WebhookDeliveryFilter{Status: "dead", Limit: 2, Offset: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookFindDeliveryByID(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	t.Run("test case : find delivery of another webhook not found", func(t *testing.T) {
+		repo := repository.NewWebhookRepositoryImpl(db, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_delivery" WHERE webhook_id = $1 AND "webhook_delivery"."delivery_id" = $2`)).
+			WithArgs(2, 5, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.FindDeliveryByID(context.Background(), 2, 5)
+
+		assert.Equal(t, errs.NewNotFoundError("record not found"), err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
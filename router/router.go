@@ -1,35 +1,65 @@
 package router
 
 import (
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/config"
 	"github.com/Yoshikrit/fiber-test/middleware"
 	"github.com/Yoshikrit/fiber-test/handler"
+	"github.com/Yoshikrit/fiber-test/health"
+	"github.com/Yoshikrit/fiber-test/helper"
 	"github.com/Yoshikrit/fiber-test/service"
 	"github.com/Yoshikrit/fiber-test/repository"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/goccy/go-json"
 	"gorm.io/gorm"
 
 	"github.com/gofiber/swagger"
 	_ "github.com/Yoshikrit/fiber-test/docs"
 )
 
-func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
-	router.Get("/swagger/*", swagger.HandlerDefault)
-	router.Get("/metrics", middleware.Metrics())
+// NewApp creates the fiber app with the JSON codec and the error handler every response relies on.
+func NewApp() *fiber.App {
+	return fiber.New(fiber.Config{
+		JSONEncoder: 	json.Marshal,
+		JSONDecoder: 	json.Unmarshal,
+		ErrorHandler: 	helper.ErrorHandler,
+	})
+}
+
+func NewRouter(router *fiber.App, db *gorm.DB, store *repository.Store, prodTypeBroker broker.Broker, webhookService service.WebhookService, healthRegistry *health.Registry, configData *config.Config) *fiber.App {
+	//middleware, the global chain has to be in place before the first route
+	pipeline := middleware.NewPipeline(configData)
+	for _, handler := range pipeline.Global {
+		router.Use(handler)
+	}
+
+	//ops
+	router.Get("/swagger/*", pipeline.Ops.With(swagger.HandlerDefault)...)
+	if configData.MetricsToken != "" {
+		router.Get("/metrics", pipeline.Ops.With(middleware.Metrics(configData.MetricsToken))...)
+	}
+
+	//probes
+	healthHandler := handler.NewHealthHandler(healthRegistry, configData.OpsToken)
 
-	//healthcheck
-	healthCheckHandler := handler.NewHealthCheckHandler()
+	router.Get("/livez", pipeline.Ops.With(healthHandler.Livez)...)
+	router.Get("/readyz", pipeline.Ops.With(healthHandler.Readyz)...)
+	router.Get("/startupz", pipeline.Ops.With(healthHandler.Startupz)...)
 
-	router.Get("/healthcheck", healthCheckHandler.HealthCheck)
+	//audit
+	auditRepository := repository.NewAuditRepositoryImpl(db, configData.DBReadRetries)
+	auditService := service.NewAuditServiceImpl(auditRepository)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	//webhooks
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 
 	//auths
-	userRepository := repository.NewUserRepositoryImpl(db)
-	roleRepository := repository.NewRoleRepositoryImpl(db)
-	oauthRepository := repository.NewOauthRepositoryImpl(db)
-	authService := service.NewAuthServiceImpl(userRepository, roleRepository, oauthRepository)
+	authService := service.NewAuthServiceImpl(store.Users, store.Roles, store.Oauths, store.TxManager, auditService)
 	authHandler := handler.NewAuthHandler(authService)
 
-	authRouter := router.Group("/auths")
+	authRouter := router.Group("/auths", pipeline.Public...)
 
 	authRouter.Post("/", authHandler.Register)
 	authRouter.Post("/login", authHandler.Login)
@@ -39,24 +69,67 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 	})
 
 	//create jwt middleware
-	jwtMiddleware := middleware.NewJWTMiddleware(userRepository, oauthRepository, roleRepository)
+	jwtMiddleware := middleware.NewJWTMiddleware(store.Users, store.Oauths, store.Roles, "Manager")
+	jwtAdminMiddleware := middleware.NewJWTMiddleware(store.Users, store.Oauths, store.Roles, "Admin")
 
 	//producttypes
-	prodTypeRepository := repository.NewProductTypeRepositoryImpl(db)
-	prodTypeService := service.NewProductTypeServiceImpl(prodTypeRepository)
+	prodTypeService := service.NewProductTypeServiceImpl(store.ProductTypes, store.TxManager, auditService, prodTypeBroker)
 	prodTypeHandler := handler.NewProductTypeHandler(prodTypeService)
 
-    productTypeRouter := router.Group("/producttypes")
-	productTypeRouter.Use(jwtMiddleware)
+	productTypeRouter := router.Group("/producttypes", pipeline.API.With(jwtMiddleware)...)
 	
 	productTypeRouter.Post("/", prodTypeHandler.Create)
+	productTypeRouter.Post("/bulk", pipeline.LongTimeout, prodTypeHandler.Bulk)
 	productTypeRouter.Get("/", prodTypeHandler.FindAll)
 	productTypeRouter.Get("/count", prodTypeHandler.Count)
+	productTypeRouter.Get("/trash", prodTypeHandler.FindAllTrash)
+	productTypeRouter.Get("/tree", prodTypeHandler.FindTree)
+	productTypeRouter.Get("/changes", prodTypeHandler.FindChanges)
+	productTypeRouter.Get("/stream", prodTypeHandler.Stream)
+	productTypeRouter.Get("/export", pipeline.LongTimeout, prodTypeHandler.Export)
+	productTypeRouter.Post("/import", pipeline.LongTimeout, prodTypeHandler.Import)
 
 	productTypeRouter.Route("/:id", func(router fiber.Router) {
 		router.Get("/", prodTypeHandler.FindByID)
 		router.Put("/", prodTypeHandler.Update)
 		router.Delete("/", prodTypeHandler.Delete)
+		router.Post("/restore", prodTypeHandler.Restore)
+		router.Get("/children", prodTypeHandler.FindChildren)
+		router.Get("/ancestors", prodTypeHandler.FindAncestors)
+		router.Put("/move", prodTypeHandler.Move)
+		router.Get("/revisions", prodTypeHandler.FindRevisions)
+		router.Post("/revisions/:rev/revert", prodTypeHandler.Revert)
+	})
+
+	//admin
+	adminRouter := router.Group("/admin", pipeline.API.With(jwtAdminMiddleware)...)
+
+	adminRouter.Delete("/producttypes/:id", prodTypeHandler.Purge)
+
+	//settings
+	settingsService := service.NewSettingsServiceImpl(auditService)
+	settingsHandler := handler.NewSettingsHandler(settingsService)
+
+	adminRouter.Get("/settings", settingsHandler.FindAll)
+	adminRouter.Patch("/settings", settingsHandler.Update)
+
+	//audit
+	auditRouter := router.Group("/audit", pipeline.API.With(jwtAdminMiddleware)...)
+
+	auditRouter.Get("/", auditHandler.FindAll)
+
+	//webhooks
+	webhookRouter := router.Group("/webhooks", pipeline.API.With(jwtAdminMiddleware)...)
+
+	webhookRouter.Post("/", webhookHandler.Create)
+	webhookRouter.Get("/", webhookHandler.FindAll)
+
+	webhookRouter.Route("/:id", func(router fiber.Router) {
+		router.Get("/", webhookHandler.FindByID)
+		router.Put("/", webhookHandler.Update)
+		router.Delete("/", webhookHandler.Delete)
+		router.Get("/deliveries", webhookHandler.FindDeliveries)
+		router.Post("/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
 	})
 
 	return router
@@ -0,0 +1,205 @@
+package router_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/gofiber/fiber/v2"
+	"github.com/goccy/go-json"
+
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/health"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/router"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupApp(t *testing.T) *fiber.App {
+	app, _ := setupAppWithRegistry(t)
+	return app
+}
+
+func setupAppWithRegistry(t *testing.T) (*fiber.App, *health.Registry) {
+	db, _ := testutils.SetupMockDB(t)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	app := router.NewApp()
+	registry := health.NewRegistry(0)
+	router.NewRouter(app, db, repository.NewGormStore(db, 0),
+		broker.NewBrokerImpl(broker.DefaultHistorySize, broker.DefaultBufferSize),
+		service.NewWebhookServiceImpl(repository.NewWebhookRepositoryImpl(db, 0), 0, 0, 0),
+		registry,
+		&config.Config{
+			CorsAllowOrigins: 	"https://shop.example.com",
+			RateLimitMax: 		2,
+			RateLimitWindow: 	60,
+			RequestTimeout: 	5,
+			MetricsToken: 		"secret",
+			OpsToken: 			"ops-secret",
+		},
+	)
+	// registered after the router, like any route the global chain has to cover
+	app.Get("/panic", func(ctx *fiber.Ctx) error {
+		panic("boom")
+	})
+	return app, registry
+}
+
+func decodeBody(t *testing.T, resp *http.Response) map[string]interface{} {
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	decoded := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(body, &decoded))
+	return decoded
+}
+
+func TestGlobalPipeline(t *testing.T) {
+	app := setupApp(t)
+
+	t.Run("test case : panic in handler returns 500 json", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, map[string]interface{}{"code": float64(500), "message": "Internal Server Error"}, decodeBody(t, resp))
+		assert.NotEmpty(t, resp.Header.Get(fiber.HeaderXRequestID))
+	})
+
+	t.Run("test case : unknown route returns 404 json", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/nothing", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, float64(404), decodeBody(t, resp)["code"])
+	})
+
+	t.Run("test case : api route has security and cors headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/producttypes/", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://shop.example.com")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+		assert.Equal(t, "https://shop.example.com", resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+		assert.NotEmpty(t, resp.Header.Get(fiber.HeaderXRequestID))
+	})
+
+	t.Run("test case : cors refuses unknown origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://evil.example.com")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("test case : liveness probe answers", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("test case : readiness probe fails while draining", func(t *testing.T) {
+		app, registry := setupAppWithRegistry(t)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		registry.Drain()
+
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/livez", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("test case : verbose probe needs the ops token", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/startupz?verbose=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+		req := httptest.NewRequest(http.MethodGet, "/readyz?verbose=true", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer ops-secret")
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		checks := decodeBody(t, resp)["checks"].(map[string]interface{})
+		assert.NotEmpty(t, checks["draining"].(map[string]interface{})["duration"])
+	})
+}
+
+func TestGroupPipelines(t *testing.T) {
+	t.Run("test case : public auth routes are rate limited", func(t *testing.T) {
+		app := setupApp(t)
+
+		statuses := []int{}
+		for i := 0; i < 3; i++ {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/auths/login", nil))
+			assert.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}
+
+		assert.Equal(t, []int{fiber.StatusBadRequest, fiber.StatusBadRequest, fiber.StatusTooManyRequests}, statuses)
+	})
+
+	t.Run("test case : api routes are rate limited before the jwt check", func(t *testing.T) {
+		app := setupApp(t)
+
+		statuses := []int{}
+		for i := 0; i < 3; i++ {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/producttypes/", nil))
+			assert.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}
+
+		assert.Equal(t, []int{fiber.StatusBadRequest, fiber.StatusBadRequest, fiber.StatusTooManyRequests}, statuses)
+	})
+
+	t.Run("test case : api routes reject a request without token", func(t *testing.T) {
+		for _, path := range []string{"/producttypes/count", "/admin/producttypes/1", "/audit/", "/webhooks/"} {
+			app := setupApp(t)
+			method := http.MethodGet
+			if path == "/admin/producttypes/1" {
+				method = http.MethodDelete
+			}
+
+			resp, err := app.Test(httptest.NewRequest(method, path, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, path)
+			assert.Equal(t, "Token format is invalid", decodeBody(t, resp)["message"], path)
+		}
+	})
+
+	t.Run("test case : ops routes are not rate limited", func(t *testing.T) {
+		app := setupApp(t)
+
+		for i := 0; i < 5; i++ {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/livez", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer secret")
+			resp, err = app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		}
+	})
+}
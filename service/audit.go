@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+// AuditRecorder is what the other services write audit entries through.
+type AuditRecorder interface {
+	Record(context.Context, *model.AuditLogEntity)
+}
+
+type AuditService interface {
+	AuditRecorder
+	FindAll(context.Context, *model.AuditLogFilter) (*model.AuditLogPage, error)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/helper"
+
+	"github.com/goccy/go-json"
+)
+
+const auditDefaultLimit = 50
+
+type AuditServiceImpl struct {
+	AuditRepo repository.AuditRepository
+}
+
+func NewAuditServiceImpl(auditRepo repository.AuditRepository) AuditService {
+	return &AuditServiceImpl{
+		AuditRepo: auditRepo,
+	}
+}
+
+// Record fills the actor, system actor, IP and request ID from ctx and stores the entry.
+// A failure is logged and never fails the write being audited.
+func (s *AuditServiceImpl) Record(ctx context.Context, auditEntity *model.AuditLogEntity) {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	actor := helper.ActorFromContext(ctx)
+	if auditEntity.ActorID == nil {
+		auditEntity.ActorID = actor.UserID
+	}
+	auditEntity.ActorSystem = actor.System
+	auditEntity.IP = actor.IP
+	auditEntity.RequestID = actor.RequestID
+	auditEntity.CreatedAt = time.Now()
+
+	if err := s.AuditRepo.Create(ctx, auditEntity); err != nil {
+		logger.Error(ctx, err, "action", auditEntity.Action, "resource_type", auditEntity.ResourceType, "resource_id", auditEntity.ResourceID)
+	}
+}
+
+func (s *AuditServiceImpl) FindAll(ctx context.Context, filter *model.AuditLogFilter) (*model.AuditLogPage, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.FindAll")
+	defer span.End()
+
+	if err := helper.ValidateAuditLogFilter(filter); err != nil {
+		logger.Error(ctx, "Audit filter is not valid")
+		return nil, errs.NewValidateBadRequestError(err)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = auditDefaultLimit
+	}
+
+	auditEntities, total, err := s.AuditRepo.FindAll(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	page := &model.AuditLogPage{
+		Total: 	total,
+		Limit: 	filter.Limit,
+		Offset: filter.Offset,
+		Items: 	[]model.AuditLog{},
+	}
+	for _, auditEntity := range auditEntities {
+		page.Items = append(page.Items, model.AuditLog{
+			ID: 			auditEntity.ID,
+			ActorID: 		auditEntity.ActorID,
+			ActorSystem: 	auditEntity.ActorSystem,
+			Action: 		auditEntity.Action,
+			ResourceType: 	auditEntity.ResourceType,
+			ResourceID: 	auditEntity.ResourceID,
+			Before: 		auditJSON(auditEntity.Before),
+			After: 			auditJSON(auditEntity.After),
+			RequestID: 		auditEntity.RequestID,
+			IP: 			auditEntity.IP,
+			CreatedAt: 		auditEntity.CreatedAt,
+		})
+	}
+
+	logger.Info(ctx, "Service: Find All Audit Logs Successfully")
+	return page, nil
+}
+
+// newAuditEntry builds an entry with before and after marshalled to JSON, a nil state is stored as NULL.
+func newAuditEntry(ctx context.Context, action, resourceType, resourceID string, before, after interface{}) *model.AuditLogEntity {
+	return &model.AuditLogEntity{
+		Action: 		action,
+		ResourceType: 	resourceType,
+		ResourceID: 	resourceID,
+		Before: 		auditState(ctx, before),
+		After: 			auditState(ctx, after),
+	}
+}
+
+func auditState(ctx context.Context, state interface{}) *string {
+	if state == nil {
+		return nil
+	}
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil
+	}
+	stateString := string(stateJSON)
+	return &stateString
+}
+
+func auditJSON(state *string) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	return json.RawMessage(*state)
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditRecord(t *testing.T) {
+	actorID := 7
+	ctx := helper.ContextWithActor(context.Background(), &model.Actor{UserID: &actorID, IP: "127.0.0.1", RequestID: "req-1"})
+
+	t.Run("test case : record fills actor from context", func(t *testing.T) {
+		mockRepository := testutils.NewAuditRepositoryMock()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(auditEntity *model.AuditLogEntity) bool {
+			return *auditEntity.ActorID == 7 &&
+				auditEntity.IP == "127.0.0.1" &&
+				auditEntity.RequestID == "req-1" &&
+				!auditEntity.CreatedAt.IsZero()
+		})).Return(nil)
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		service.Record(ctx, &model.AuditLogEntity{Action: model.AuditActionCreate, ResourceType: model.AuditResourceProductType, ResourceID: "1"})
+
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : record fills system actor from context", func(t *testing.T) {
+		mockRepository := testutils.NewAuditRepositoryMock()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(auditEntity *model.AuditLogEntity) bool {
+			return auditEntity.ActorID == nil && auditEntity.ActorSystem == helper.ActorTrashRetention
+		})).Return(nil)
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		service.Record(helper.SystemContext(context.Background(), helper.ActorTrashRetention), &model.AuditLogEntity{Action: model.AuditActionPurge, ResourceType: model.AuditResourceProductType, ResourceID: "1"})
+
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : record keeps explicit actor", func(t *testing.T) {
+		userID := 9
+		mockRepository := testutils.NewAuditRepositoryMock()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(auditEntity *model.AuditLogEntity) bool {
+			return *auditEntity.ActorID == 9
+		})).Return(nil)
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		service.Record(ctx, &model.AuditLogEntity{ActorID: &userID, Action: model.AuditActionLogin, ResourceType: model.AuditResourceOauth})
+
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : record fail from repository does not panic", func(t *testing.T) {
+		mockRepository := testutils.NewAuditRepositoryMock()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(errs.NewInternalServerError(""))
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		service.Record(context.Background(), &model.AuditLogEntity{Action: model.AuditActionCreate, ResourceType: model.AuditResourceProductType})
+
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuditFindAll(t *testing.T) {
+	t.Run("test case : find all audit logs success with default limit", func(t *testing.T) {
+		after := `{"prodtype_id":1}`
+		mockRepository := testutils.NewAuditRepositoryMock()
+		mockRepository.On("FindAll", mock.Anything, &model.AuditLogFilter{Limit: 50}).Return([]model.AuditLogEntity{
+			{ID: 1, Action: model.AuditActionCreate, ResourceType: model.AuditResourceProductType, ResourceID: "1", After: &after},
+		}, int64(1), nil)
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		auditPage, err := service.FindAll(context.Background(), &model.AuditLogFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), auditPage.Total)
+		assert.Equal(t, 50, auditPage.Limit)
+		assert.JSONEq(t, after, string(auditPage.Items[0].After))
+		assert.Nil(t, auditPage.Items[0].Before)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find all audit logs fail validate limit", func(t *testing.T) {
+		mockRepository := testutils.NewAuditRepositoryMock()
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		auditPage, err := service.FindAll(context.Background(), &model.AuditLogFilter{Limit: 1000})
+
+		expectedBody := errs.ValErrorResponse{
+			Code: 400,
+			Message: []errs.ErrorMessage{
+				{
+					FailedField: "AuditLogFilter.Limit",
+					Tag:        "max",
+					Value:      "500",
+				},
+			},
+		}
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, auditPage)
+		mockRepository.AssertNotCalled(t, "FindAll", mock.Anything, mock.Anything)
+	})
+
+	t.Run("test case : find all audit logs fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewAuditRepositoryMock()
+		mockRepository.On("FindAll", mock.Anything, &model.AuditLogFilter{Limit: 10}).Return([]model.AuditLogEntity(nil), int64(0), errs.NewInternalServerError(""))
+
+		service := service.NewAuditServiceImpl(mockRepository)
+		auditPage, err := service.FindAll(context.Background(), &model.AuditLogFilter{Limit: 10})
+
+		assert.Error(t, err)
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		assert.Nil(t, auditPage)
+		mockRepository.AssertExpectations(t)
+	})
+}
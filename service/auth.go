@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
+
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
 type AuthService interface {
-	Register(*model.UserCreate) error
-	Login(*model.LoginRequest) (*model.UserPassport, error)
-	RefreshPassport(*model.RefreshToken) (*model.UserPassport, error)
-	Delete(int) (error)
+	Register(context.Context, *model.UserCreate) error
+	Login(context.Context, *model.LoginRequest) (*model.UserPassport, error)
+	RefreshPassport(context.Context, *model.RefreshToken) (*model.UserPassport, error)
+	Delete(context.Context, int) (error)
 }
\ No newline at end of file
@@ -1,55 +1,70 @@
 package service
 
 import (
+	"context"
+	"strconv"
+
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/repository"
 	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/replica"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/metrics"
 )
 
 const (
 	UserExist = "User with this ID already exists"
 	RoleExist = "Role with this ID already exists"
+	UserDisabled = "User is disabled"
 )
 
 type AuthServiceImpl struct {
 	UserRepo repository.UserRepository
 	RoleRepo repository.RoleRepository
 	OauthRepo repository.OauthRepository
+	TxManager repository.TxManager
+	Auditor AuditRecorder
 }
 
-func NewAuthServiceImpl(UserRepo repository.UserRepository, RoleRepo repository.RoleRepository, OauthRepo repository.OauthRepository) AuthService {
+func NewAuthServiceImpl(UserRepo repository.UserRepository, RoleRepo repository.RoleRepository, OauthRepo repository.OauthRepository, TxManager repository.TxManager, Auditor AuditRecorder) AuthService {
 	return &AuthServiceImpl{
 		UserRepo: UserRepo,
 		RoleRepo: RoleRepo,
 		OauthRepo: OauthRepo,
+		TxManager: TxManager,
+		Auditor: Auditor,
 	}
 }
 
-func (s *AuthServiceImpl) Register(userCreateReq *model.UserCreate) error {
+func (s *AuthServiceImpl) Register(ctx context.Context, userCreateReq *model.UserCreate) error {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
 	if err := helper.ValidateUserCreate(userCreateReq); err != nil {
-		logger.Error("User data is not valid")
+		logger.Error(ctx, "User data is not valid")
 		return errs.NewValidateBadRequestError(err)
 	}
 
 	//check user id
-	userFromDB, _ := s.UserRepo.FindByID(userCreateReq.ID)
+	userFromDB, _ := s.UserRepo.FindByID(ctx, userCreateReq.ID)
     if userFromDB != nil && userFromDB.ID == userCreateReq.ID {
-        logger.Error(UserExist)
+        logger.Error(ctx, UserExist)
         return errs.NewConflictError(UserExist)
     }
 
 	//check role id
-	_, err := s.RoleRepo.FindByID(userCreateReq.RoleID)
+	_, err := s.RoleRepo.FindByID(ctx, userCreateReq.RoleID)
     if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx, err.Error())
 		return err
 	}
 
 	hashedPassword, err := helper.HashPassword(userCreateReq.Password)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(ctx, err.Error())
 		return err
 	}
 
@@ -61,30 +76,45 @@ func (s *AuthServiceImpl) Register(userCreateReq *model.UserCreate) error {
 		Password: string(hashedPassword),
 	}
 
-	if err := s.UserRepo.Create(userEntity); err != nil {
-		logger.Error(err)
+	if err := s.UserRepo.Create(ctx, userEntity); err != nil {
+		logger.Error(ctx, err)
 		return err
 	}
+	s.audit(ctx, model.AuditActionRegister, model.AuditResourceUser, userEntity.ID, userEntity.ID, nil, toAuditUser(userEntity))
 
-	logger.Info("Service: Register User Successfully")
+	logger.Info(ctx, "Service: Register User Successfully")
 	return nil
 }
 
-func (s *AuthServiceImpl) Login(loginReq *model.LoginRequest) (*model.UserPassport, error) {
-	userEntity, err := s.UserRepo.FindByEmail(loginReq.Email)
+func (s *AuthServiceImpl) Login(ctx context.Context, loginReq *model.LoginRequest) (*model.UserPassport, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	userEntity, err := s.UserRepo.FindByEmail(ctx, loginReq.Email)
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
 		return nil, err
 	}
 
 	if err := helper.CompareHashAndPassword([]byte(userEntity.Password), []byte(loginReq.Password)); err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		s.audit(ctx, model.AuditActionLoginFailed, model.AuditResourceUser, userEntity.ID, userEntity.ID, nil, nil)
 		return nil, err
 	}
 
-	roleEntity, err := s.RoleRepo.FindByID(userEntity.RoleID)
+	if userEntity.DisabledAt != nil {
+		logger.Error(ctx, UserDisabled)
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		s.audit(ctx, model.AuditActionLoginFailed, model.AuditResourceUser, userEntity.ID, userEntity.ID, nil, nil)
+		return nil, errs.NewUnauthorizedError(UserDisabled)
+	}
+
+	roleEntity, err := s.RoleRepo.FindByID(ctx, userEntity.RoleID)
     if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return nil, err
 	}
 
@@ -95,7 +125,7 @@ func (s *AuthServiceImpl) Login(loginReq *model.LoginRequest) (*model.UserPasspo
 
 	pairTokens, err := helper.GeneratePairTokens(userClaims, roleEntity.Title)
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return nil, err
 	}
 
@@ -105,14 +135,20 @@ func (s *AuthServiceImpl) Login(loginReq *model.LoginRequest) (*model.UserPasspo
 		RefreshToken: 	pairTokens.RefreshToken,
 	}
 
-	if err := s.OauthRepo.Create(oauthEntity); err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	oauthFromDB, err := s.OauthRepo.FindByRefleshToken(oauthEntity.RefreshToken)
+	var oauthFromDB *model.OauthEntity
+	err = s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		if err := tx.Oauths().Create(ctx, oauthEntity); err != nil {
+			return err
+		}
+		oauthCreated, err := tx.Oauths().FindByRefleshToken(ctx, oauthEntity.RefreshToken)
+		if err != nil {
+			return err
+		}
+		oauthFromDB = oauthCreated
+		return nil
+	})
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return nil, err
 	}
 
@@ -134,95 +170,130 @@ func (s *AuthServiceImpl) Login(loginReq *model.LoginRequest) (*model.UserPasspo
 		Tokens: tokens,
 	}
 
-	logger.Info("Service: Login User Successfully")
+	s.audit(ctx, model.AuditActionLogin, model.AuditResourceOauth, oauthFromDB.ID, userEntity.ID, nil, nil)
+	metrics.LoginsTotal.WithLabelValues("success").Inc()
+
+	logger.Info(ctx, "Service: Login User Successfully")
 	return userPassport, nil
 }
 
-func (s *AuthServiceImpl) RefreshPassport(refreshToken *model.RefreshToken) (*model.UserPassport, error) {
-	claims, err := helper.ParseToken(refreshToken.RefreshToken)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	oauthEntity, err := s.OauthRepo.FindByRefleshToken(refreshToken.RefreshToken)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	userEntity, err := s.UserRepo.FindByID(oauthEntity.UserID)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	newUserClaims := &model.UserClaims{
-		ID:     userEntity.ID,
-		RoleID: userEntity.RoleID,
-	}
-
-	newUserDTO := &model.UserDTO{
-		ID:     userEntity.ID,
-		RoleID: userEntity.RoleID,
-		Name: 	userEntity.Name,
-		Email: 	userEntity.Email,
-	}
-
-	roleEntity, err := s.RoleRepo.FindByID(userEntity.RoleID)
-    if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
+// RefreshPassport locks the oauth row of the refresh token until the new tokens are stored,
+// a concurrent refresh with the same token waits and then finds it already replaced.
+func (s *AuthServiceImpl) RefreshPassport(ctx context.Context, refreshToken *model.RefreshToken) (*model.UserPassport, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RefreshPassport")
+	defer span.End()
+	ctx = replica.Primary(ctx)
 
-	newAccessToken, err := helper.NewAccessToken(roleEntity.Title, newUserClaims) 
+	claims, err := helper.ParseToken(refreshToken.RefreshToken)
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return nil, err
 	}
 
-	newRefreshToken, err := helper.RepeatToken(roleEntity.Title, newUserClaims, claims.ExpiresAt.Unix()) 
+	var newPassport *model.UserPassport
+	err = s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		oauthEntity, err := tx.Oauths().FindByRefleshTokenForUpdate(ctx, refreshToken.RefreshToken)
+		if err != nil {
+			return err
+		}
+
+		userEntity, err := tx.Users().FindByID(ctx, oauthEntity.UserID)
+		if err != nil {
+			return err
+		}
+
+		newUserClaims := &model.UserClaims{
+			ID:     userEntity.ID,
+			RoleID: userEntity.RoleID,
+		}
+
+		newUserDTO := &model.UserDTO{
+			ID:     userEntity.ID,
+			RoleID: userEntity.RoleID,
+			Name: 	userEntity.Name,
+			Email: 	userEntity.Email,
+		}
+
+		roleEntity, err := tx.Roles().FindByID(ctx, userEntity.RoleID)
+		if err != nil {
+			return err
+		}
+
+		newAccessToken, err := helper.NewAccessToken(roleEntity.Title, newUserClaims) 
+		if err != nil {
+			return err
+		}
+
+		newRefreshToken, err := helper.RepeatToken(roleEntity.Title, newUserClaims, claims.ExpiresAt.Unix()) 
+		if err != nil {
+			return err
+		}
+
+		newOauthEntity := &model.OauthEntity{
+			ID:     		oauthEntity.ID,
+			UserID: 		userEntity.ID,
+			AccessToken: 	newAccessToken,
+			RefreshToken: 	newRefreshToken,
+		}
+
+		if err := tx.Oauths().Update(ctx, newOauthEntity); err != nil {
+			return err
+		}
+
+		newPassport = &model.UserPassport{
+			User: newUserDTO,
+			Tokens: &model.UserToken{
+				ID:           oauthEntity.ID,
+				AccessToken:  newAccessToken,
+				RefreshToken: newRefreshToken,
+			},
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	newPassport := &model.UserPassport{
-		User: newUserDTO,
-		Tokens: &model.UserToken{
-			ID:           oauthEntity.ID,
-			AccessToken:  newAccessToken,
-			RefreshToken: newRefreshToken,
-		},
-	}
-
-	newOauthEntity := &model.OauthEntity{
-		ID:     		oauthEntity.ID,
-		UserID: 		userEntity.ID,
-		AccessToken: 	newAccessToken,
-		RefreshToken: 	newRefreshToken,
-	}
-
-	if err := s.OauthRepo.Update(newOauthEntity); err != nil {
+		logger.Error(ctx, err)
 		return nil, err
 	}
+	s.audit(ctx, model.AuditActionRefresh, model.AuditResourceOauth, newPassport.Tokens.ID, newPassport.User.ID, nil, nil)
 
-	logger.Info("Service: Reflesh Token Successfully")
+	logger.Info(ctx, "Service: Reflesh Token Successfully")
 	return newPassport, nil
 }
 
-func (s *AuthServiceImpl) Delete(id int) error {
-	_, err := s.OauthRepo.FindByID(id)
+func (s *AuthServiceImpl) Delete(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "AuthService.Delete")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	oauthEntity, err := s.OauthRepo.FindByID(ctx, id)
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return err
 	}
 	
-	if err := s.OauthRepo.Delete(id); err != nil {
-		logger.Error(err)
+	if err := s.OauthRepo.Delete(ctx, id); err != nil {
+		logger.Error(ctx, err)
 		return err
 	}
+	s.audit(ctx, model.AuditActionLogout, model.AuditResourceOauth, id, oauthEntity.UserID, nil, nil)
 
-	logger.Info("Service: Logout User Successfully")
+	logger.Info(ctx, "Service: Logout User Successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// audit records an auth event, the acting user is known from the credentials
+// even though these requests are not behind the JWT middleware. Tokens and passwords are never recorded.
+func (s *AuthServiceImpl) audit(ctx context.Context, action, resourceType string, resourceID, actorID int, before, after interface{}) {
+	auditEntity := newAuditEntry(ctx, action, resourceType, strconv.Itoa(resourceID), before, after)
+	auditEntity.ActorID = &actorID
+	s.Auditor.Record(ctx, auditEntity)
+}
+
+func toAuditUser(userEntity *model.UserEntity) *model.UserDTO {
+	return &model.UserDTO{
+		ID: 		userEntity.ID,
+		RoleID: 	userEntity.RoleID,
+		Name: 		userEntity.Name,
+		Email: 		userEntity.Email,
+	}
+}
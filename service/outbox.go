@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+// EventSink is somewhere the outbox relay publishes events to. Publish may see an event
+// more than once and must return an error unless the event has been accepted.
+type EventSink interface {
+	Name() string
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+type OutboxService interface {
+	Relay(ctx context.Context) (int, error)
+}
+
+// OutboxTailService feeds the events of one aggregate type to the broker of this instance.
+type OutboxTailService interface {
+	Tail(ctx context.Context) (int, error)
+}
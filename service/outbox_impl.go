@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	outboxRelayBatchSize 		= 100
+	outboxRetention 			= 7 * 24 * time.Hour
+	outboxLastErrorSize 		= 1024
+	outboxDefaultMaxAttempts 	= 10
+	outboxDefaultBackoff 		= 5 * time.Second
+	outboxMaxBackoff 			= time.Hour
+	outboxTailBatchSize 		= 100
+)
+
+type OutboxServiceImpl struct {
+	OutboxRepo 	repository.OutboxRepository
+	Sinks 		[]EventSink
+	MaxAttempts int
+	Backoff 	time.Duration
+}
+
+// NewOutboxServiceImpl retries a failed event after backoff, doubling it on every attempt up to an hour,
+// and dead-letters it after maxAttempts. Zero values take the defaults.
+func NewOutboxServiceImpl(outboxRepo repository.OutboxRepository, maxAttempts int, backoff time.Duration, sinks ...EventSink) OutboxService {
+	if maxAttempts <= 0 {
+		maxAttempts = outboxDefaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = outboxDefaultBackoff
+	}
+	return &OutboxServiceImpl{
+		OutboxRepo: 	outboxRepo,
+		Sinks: 			sinks,
+		MaxAttempts: 	maxAttempts,
+		Backoff: 		backoff,
+	}
+}
+
+// Relay publishes the unpublished events to every sink in the order they were written and returns
+// how many were published. An event is marked published only after all sinks accepted it, a retry
+// skips the sinks that already did. Once an event of an aggregate fails, the later events of that
+// aggregate wait for its retry to keep them in order, events of other aggregates still go out.
+// An event failing MaxAttempts times is dead-lettered and no longer holds its aggregate back.
+// The run holds the relay lock, while another instance relays it publishes nothing.
+func (s *OutboxServiceImpl) Relay(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "OutboxService.Relay")
+	defer span.End()
+
+	published := 0
+	locked, err := s.OutboxRepo.RelayLocked(ctx, func(outboxRepo repository.OutboxRepository) error {
+		var err error
+		published, err = s.relay(ctx, outboxRepo)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return 0, err
+	}
+	if !locked {
+		logger.Info(ctx, "Service: Relay Outbox Events Skipped, another relay is running")
+		return 0, nil
+	}
+
+	logger.Info(ctx, "Service: Relay Outbox Events Successfully", "published", published)
+	return published, nil
+}
+
+func (s *OutboxServiceImpl) relay(ctx context.Context, outboxRepo repository.OutboxRepository) (int, error) {
+	outboxEntities, err := outboxRepo.FindUnpublished(ctx, outboxRelayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	blocked := map[string]bool{}
+	published := 0
+	for i := range outboxEntities {
+		if ctx.Err() != nil {
+			break
+		}
+
+		outboxEntity := &outboxEntities[i]
+		aggregate := outboxEntity.AggregateType + ":" + outboxEntity.AggregateID
+		if blocked[aggregate] {
+			continue
+		}
+		if outboxEntity.NextAttemptAt != nil && outboxEntity.NextAttemptAt.After(now) {
+			blocked[aggregate] = true
+			continue
+		}
+
+		if err := s.publish(ctx, outboxEntity); err != nil {
+			logger.Error(ctx, err, "outbox_id", outboxEntity.ID, "aggregate", aggregate)
+			s.fail(outboxEntity, err, time.Now())
+			if outboxEntity.DeadAt == nil {
+				blocked[aggregate] = true
+			} else {
+				logger.Info(ctx, "Service: Outbox Event Dead-Lettered", "outbox_id", outboxEntity.ID, "attempts", outboxEntity.Attempts)
+			}
+			if err := outboxRepo.MarkFailed(ctx, outboxEntity); err != nil {
+				logger.Error(ctx, err, "outbox_id", outboxEntity.ID)
+				blocked[aggregate] = true
+			}
+			continue
+		}
+
+		if err := outboxRepo.MarkPublished(ctx, outboxEntity.ID, time.Now()); err != nil {
+			logger.Error(ctx, err, "outbox_id", outboxEntity.ID)
+			blocked[aggregate] = true
+			continue
+		}
+		published++
+	}
+
+	if _, err := outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-outboxRetention)); err != nil {
+		logger.Error(ctx, err)
+	}
+	return published, nil
+}
+
+// publish hands the event to the sinks that have not accepted it yet and adds every sink that accepts
+// it to PublishedSinks.
+func (s *OutboxServiceImpl) publish(ctx context.Context, outboxEntity *model.OutboxEventEntity) error {
+	event := toOutboxEvent(outboxEntity)
+	for _, sink := range s.Sinks {
+		if publishedTo(outboxEntity, sink.Name()) {
+			continue
+		}
+		if err := sink.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+		if outboxEntity.PublishedSinks != "" {
+			outboxEntity.PublishedSinks += ","
+		}
+		outboxEntity.PublishedSinks += sink.Name()
+	}
+	return nil
+}
+
+// fail counts the failed attempt and either schedules the retry or dead-letters the event.
+func (s *OutboxServiceImpl) fail(outboxEntity *model.OutboxEventEntity, err error, now time.Time) {
+	outboxEntity.Attempts++
+	outboxEntity.LastError = truncate(err.Error(), outboxLastErrorSize)
+	if outboxEntity.Attempts >= s.MaxAttempts {
+		outboxEntity.NextAttemptAt = nil
+		outboxEntity.DeadAt = &now
+		return
+	}
+	nextAttemptAt := now.Add(s.backoff(outboxEntity.Attempts))
+	outboxEntity.NextAttemptAt = &nextAttemptAt
+}
+
+// backoff is the wait after the given number of failed attempts.
+func (s *OutboxServiceImpl) backoff(attempts int) time.Duration {
+	wait := s.Backoff
+	for i := 1; i < attempts && wait < outboxMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return wait
+}
+
+func publishedTo(outboxEntity *model.OutboxEventEntity, name string) bool {
+	for _, published := range strings.Split(outboxEntity.PublishedSinks, ",") {
+		if published == name {
+			return true
+		}
+	}
+	return false
+}
+
+type OutboxTailServiceImpl struct {
+	OutboxRepo 		repository.OutboxRepository
+	AggregateType 	string
+	Broker 			broker.Broker
+	mu 				sync.Mutex
+	cursor 			int64
+	started 		bool
+}
+
+func NewOutboxTailServiceImpl(outboxRepo repository.OutboxRepository, aggregateType string, eventBroker broker.Broker) OutboxTailService {
+	return &OutboxTailServiceImpl{
+		OutboxRepo: 	outboxRepo,
+		AggregateType: 	aggregateType,
+		Broker: 		eventBroker,
+	}
+}
+
+// Tail publishes the events of AggregateType written since the last run to the broker under their
+// outbox IDs and returns how many it published. It reads the outbox itself rather than waiting for the
+// relay, so every instance feeds its own broker without the relay lock and a stream client can resume
+// on any of them. The first run only finds where to start, after the latest event.
+// The product type writers hold the change lock while they write their events, so the events of an
+// aggregate type are committed in ID order and none is passed over.
+func (s *OutboxTailServiceImpl) Tail(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "OutboxTailService.Tail")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		lastID, err := s.OutboxRepo.FindLastID(ctx, s.AggregateType)
+		if err != nil {
+			logger.Error(ctx, err)
+			return 0, err
+		}
+		s.cursor, s.started = lastID, true
+		logger.Info(ctx, "Service: Tail Outbox Events Started", "aggregate_type", s.AggregateType, "after", lastID)
+		return 0, nil
+	}
+
+	published := 0
+	for {
+		outboxEntities, err := s.OutboxRepo.FindAfter(ctx, s.AggregateType, s.cursor, outboxTailBatchSize)
+		if err != nil {
+			logger.Error(ctx, err)
+			return published, err
+		}
+		for i := range outboxEntities {
+			event := toOutboxEvent(&outboxEntities[i])
+			s.Broker.Publish(event.ID, event.Type, event.Payload)
+			s.cursor = event.ID
+			published++
+		}
+		if len(outboxEntities) < outboxTailBatchSize {
+			break
+		}
+	}
+
+	logger.Info(ctx, "Service: Tail Outbox Events Successfully", "published", published)
+	return published, nil
+}
+
+func toOutboxEvent(outboxEntity *model.OutboxEventEntity) *model.OutboxEvent {
+	var payload json.RawMessage
+	if outboxEntity.Payload != nil {
+		payload = json.RawMessage(*outboxEntity.Payload)
+	}
+	return &model.OutboxEvent{
+		ID: 			outboxEntity.ID,
+		AggregateType: 	outboxEntity.AggregateType,
+		AggregateID: 	outboxEntity.AggregateID,
+		Type: 			outboxEntity.EventType,
+		Payload: 		payload,
+		CreatedAt: 		outboxEntity.CreatedAt,
+	}
+}
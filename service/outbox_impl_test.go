@@ -0,0 +1,246 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxRelay(t *testing.T) {
+	payloadMock := `{"prodtype_id":1,"prodtype_name":"A"}`
+	// the relay writes its progress into the events, every case gets its own
+	outboxMock := func() []model.OutboxEventEntity {
+		return []model.OutboxEventEntity{
+			{ID: 1, AggregateType: "producttype", AggregateID: "1", EventType: "created", Payload: &payloadMock},
+			{ID: 2, AggregateType: "producttype", AggregateID: "2", EventType: "created"},
+			{ID: 3, AggregateType: "producttype", AggregateID: "1", EventType: "updated"},
+		}
+	}
+
+	t.Run("test case : relay publishes in order", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return(outboxMock(), nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(1), mock.Anything).Return(nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(2), mock.Anything).Return(nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(3), mock.Anything).Return(nil)
+		mockRepository.On("DeletePublishedBefore", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		eventSink := testutils.NewEventSinkMock()
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, eventSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, published)
+		assert.Equal(t, []int64{1, 2, 3}, eventSink.IDs())
+		assert.JSONEq(t, payloadMock, string(eventSink.Events[0].Payload))
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay failure holds back later events of the aggregate", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return(outboxMock(), nil)
+		mockRepository.On("MarkFailed", mock.Anything, mock.MatchedBy(func(outboxEntity *model.OutboxEventEntity) bool {
+			return outboxEntity.ID == 1 && outboxEntity.Attempts == 1 && outboxEntity.LastError == "mock: unavailable" &&
+				outboxEntity.NextAttemptAt != nil && outboxEntity.DeadAt == nil
+		})).Return(nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(2), mock.Anything).Return(nil)
+		mockRepository.On("DeletePublishedBefore", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		eventSink := testutils.NewEventSinkMock()
+		eventSink.FailAggregates["1"] = errors.New("unavailable")
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, eventSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []int64{2}, eventSink.IDs())
+		mockRepository.AssertNotCalled(t, "MarkPublished", mock.Anything, int64(3), mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay publishes to every sink", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return(outboxMock()[:1], nil)
+		mockRepository.On("MarkFailed", mock.Anything, mock.MatchedBy(func(outboxEntity *model.OutboxEventEntity) bool {
+			return outboxEntity.ID == 1 && outboxEntity.PublishedSinks == "first" && outboxEntity.LastError == "second: unavailable"
+		})).Return(nil)
+		mockRepository.On("DeletePublishedBefore", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		firstSink, secondSink := testutils.NewEventSinkMock(), testutils.NewEventSinkMock()
+		firstSink.SinkName, secondSink.SinkName = "first", "second"
+		secondSink.FailAggregates["1"] = errors.New("unavailable")
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, firstSink, secondSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, published)
+		assert.Equal(t, []int64{1}, firstSink.IDs())
+		mockRepository.AssertNotCalled(t, "MarkPublished", mock.Anything, mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay retry skips the sinks that accepted the event", func(t *testing.T) {
+		retryMock := outboxMock()[:1]
+		retryMock[0].Attempts = 1
+		retryMock[0].PublishedSinks = "first"
+
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return(retryMock, nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(1), mock.Anything).Return(nil)
+		mockRepository.On("DeletePublishedBefore", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		firstSink, secondSink := testutils.NewEventSinkMock(), testutils.NewEventSinkMock()
+		firstSink.SinkName, secondSink.SinkName = "first", "second"
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, firstSink, secondSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Empty(t, firstSink.IDs())
+		assert.Equal(t, []int64{1}, secondSink.IDs())
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay holds back the aggregate until the retry is due", func(t *testing.T) {
+		nextAttemptAt := time.Now().Add(time.Minute)
+		retryMock := outboxMock()
+		retryMock[0].Attempts = 1
+		retryMock[0].NextAttemptAt = &nextAttemptAt
+
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return(retryMock, nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(2), mock.Anything).Return(nil)
+		mockRepository.On("DeletePublishedBefore", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		eventSink := testutils.NewEventSinkMock()
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, eventSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []int64{2}, eventSink.IDs())
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay dead-letters an event after max attempts", func(t *testing.T) {
+		retryMock := outboxMock()
+		retryMock[0].Attempts = 2
+
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return(retryMock, nil)
+		mockRepository.On("MarkFailed", mock.Anything, mock.MatchedBy(func(outboxEntity *model.OutboxEventEntity) bool {
+			return outboxEntity.ID == 1 && outboxEntity.Attempts == 3 && outboxEntity.DeadAt != nil && outboxEntity.NextAttemptAt == nil
+		})).Return(nil)
+		mockRepository.On("MarkFailed", mock.Anything, mock.MatchedBy(func(outboxEntity *model.OutboxEventEntity) bool {
+			return outboxEntity.ID == 3 && outboxEntity.Attempts == 1 && outboxEntity.DeadAt == nil
+		})).Return(nil)
+		mockRepository.On("MarkPublished", mock.Anything, int64(2), mock.Anything).Return(nil)
+		mockRepository.On("DeletePublishedBefore", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		eventSink := testutils.NewEventSinkMock()
+		eventSink.FailAggregates["1"] = errors.New("unavailable")
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, eventSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []int64{2}, eventSink.IDs())
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(true, nil)
+		mockRepository.On("FindUnpublished", mock.Anything, 100).Return([]model.OutboxEventEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, testutils.NewEventSinkMock())
+		published, err := service.Relay(context.Background())
+
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		assert.Equal(t, 0, published)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : relay skipped while another relay runs", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("RelayLocked", mock.Anything).Return(false, nil)
+
+		eventSink := testutils.NewEventSinkMock()
+		service := service.NewOutboxServiceImpl(mockRepository, 3, time.Minute, eventSink)
+		published, err := service.Relay(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, published)
+		assert.Empty(t, eventSink.IDs())
+		mockRepository.AssertNotCalled(t, "FindUnpublished", mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestOutboxTail(t *testing.T) {
+	payloadMock := `{"prodtype_id":1,"prodtype_name":"A"}`
+
+	t.Run("test case : tail starts after the latest event", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("FindLastID", mock.Anything, "producttype").Return(int64(7), nil)
+		mockRepository.On("FindAfter", mock.Anything, "producttype", int64(7), 100).Return([]model.OutboxEventEntity{
+			{ID: 9, AggregateType: "producttype", AggregateID: "1", EventType: "updated", Payload: &payloadMock},
+		}, nil).Once()
+		mockRepository.On("FindAfter", mock.Anything, "producttype", int64(9), 100).Return([]model.OutboxEventEntity{}, nil).Once()
+
+		eventBroker := testutils.NewBrokerMock()
+		service := service.NewOutboxTailServiceImpl(mockRepository, "producttype", eventBroker)
+		published, err := service.Tail(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, published)
+
+		published, err = service.Tail(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+
+		published, err = service.Tail(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, published)
+
+		assert.Equal(t, []string{"updated"}, eventBroker.Types())
+		assert.Equal(t, "9", eventBroker.Events[0].ID)
+		assert.JSONEq(t, payloadMock, string(eventBroker.Events[0].Data.(json.RawMessage)))
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : tail fail from repository keeps its place", func(t *testing.T) {
+		mockRepository := testutils.NewOutboxRepositoryMock()
+		mockRepository.On("FindLastID", mock.Anything, "producttype").Return(int64(0), errs.NewInternalServerError("")).Once()
+		mockRepository.On("FindLastID", mock.Anything, "producttype").Return(int64(3), nil).Once()
+		mockRepository.On("FindAfter", mock.Anything, "producttype", int64(3), 100).Return([]model.OutboxEventEntity{}, errs.NewInternalServerError("")).Once()
+		mockRepository.On("FindAfter", mock.Anything, "producttype", int64(3), 100).Return([]model.OutboxEventEntity{}, nil).Once()
+
+		service := service.NewOutboxTailServiceImpl(mockRepository, "producttype", testutils.NewBrokerMock())
+		_, err := service.Tail(context.Background())
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		_, err = service.Tail(context.Background())
+		assert.NoError(t, err)
+		_, err = service.Tail(context.Background())
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		_, err = service.Tail(context.Background())
+		assert.NoError(t, err)
+
+		mockRepository.AssertExpectations(t)
+	})
+}
@@ -1,14 +1,35 @@
 package service
 
 import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
 	"github.com/Yoshikrit/fiber-test/model"
 )
 
 type ProductTypeService interface {
-	Create(*model.ProductTypeCreate) error
-	FindAll() ([]model.ProductType, error)
-	FindByID(int) (*model.ProductType, error)
-	Update(int, *model.ProductTypeUpdate) error
-	Delete(int) (error)
-	Count() (int64, error)
-}
\ No newline at end of file
+	Create(context.Context, *model.ProductTypeCreate) error
+	FindAll(context.Context) ([]model.ProductType, error)
+	FindByID(context.Context, int) (*model.ProductType, error)
+	Update(context.Context, int, *model.ProductTypeUpdate) error
+	Delete(context.Context, int, string) (error)
+	Count(context.Context) (int64, error)
+	FindAllTrash(context.Context) ([]model.ProductTypeTrash, error)
+	Restore(context.Context, int) error
+	Purge(context.Context, int) error
+	PurgeExpired(context.Context, time.Duration) (int64, error)
+	Bulk(context.Context, *model.ProductTypeBulkRequest) (*model.ProductTypeBulkSummary, error)
+	Export(context.Context, string) (func(io.Writer) error, error)
+	Import(context.Context, *model.ProductTypeImportOptions, io.Reader) (*model.ProductTypeImportReport, error)
+	FindTree(context.Context) ([]*model.ProductTypeNode, error)
+	FindChildren(context.Context, int) ([]model.ProductType, error)
+	FindAncestors(context.Context, int) ([]model.ProductType, error)
+	Move(context.Context, int, *model.ProductTypeMove) error
+	FindByIDAsOf(context.Context, int, time.Time) (*model.ProductType, error)
+	FindRevisions(context.Context, int) ([]model.ProductTypeRevision, error)
+	Revert(context.Context, int, int) error
+	FindChanges(context.Context, *model.ProductTypeChangeQuery) (*model.ProductTypeChangeFeed, error)
+	Subscribe(context.Context, string) *broker.Subscription
+}
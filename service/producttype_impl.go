@@ -1,53 +1,110 @@
 package service
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/repository"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/replica"
+	"github.com/Yoshikrit/fiber-test/helper/spreadsheet"
 	"github.com/Yoshikrit/fiber-test/helper"
 )
 
+const (
+	productTypeColumnID 		= "prodtype_id"
+	productTypeColumnName 		= "prodtype_name"
+	productTypeColumnParentCode = "prodtype_parent_code"
+
+	productTypeExportBatchSize = 500
+
+	productTypeChangeDefaultLimit = 100
+)
+
 type ProductTypeServiceImpl struct {
 	ProdTypeRepo 	repository.ProductTypeRepository
+	TxManager 		repository.TxManager
+	Auditor 		AuditRecorder
+	Broker 			broker.Broker
 }
 
-func NewProductTypeServiceImpl(prodTypeRepo repository.ProductTypeRepository) ProductTypeService {
+func NewProductTypeServiceImpl(prodTypeRepo repository.ProductTypeRepository, txManager repository.TxManager, auditor AuditRecorder, prodTypeBroker broker.Broker) ProductTypeService {
 	return &ProductTypeServiceImpl{
 		ProdTypeRepo: 	prodTypeRepo,
+		TxManager: 	txManager,
+		Auditor: 		auditor,
+		Broker: 		prodTypeBroker,
 	}
 }
 
-func (s *ProductTypeServiceImpl) Create(prodTypeCreateReq *model.ProductTypeCreate) error {
+func (s *ProductTypeServiceImpl) Create(ctx context.Context, prodTypeCreateReq *model.ProductTypeCreate) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Create")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
 	if err := helper.ValidateProductTypeCreate(prodTypeCreateReq); err != nil {
-		logger.Error("ProductType data is not valid")
+		logger.Error(ctx, "ProductType data is not valid")
 		return errs.NewValidateBadRequestError(err)
 	}
 
-	prodTypeFromDB, _ := s.ProdTypeRepo.FindByID(prodTypeCreateReq.ID)
-    if prodTypeFromDB != nil && prodTypeFromDB.ID == prodTypeCreateReq.ID {
-        logger.Error("ProductType with this ID already exists")
-        return errs.NewConflictError("ProductType with this ID already exists")
-    }
-
 	prodTypeEntity := &model.ProductTypeEntity{
 		ID:       prodTypeCreateReq.ID,
 		Name:     prodTypeCreateReq.Name,
+		ParentID: prodTypeCreateReq.ParentID,
 	}
 	
-	if err := s.ProdTypeRepo.Save(prodTypeEntity); err != nil {
-		logger.Error(err)
+	// the checks run under the change lock, so the parent can not be deleted or purged before the save
+	err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if err := prodTypeRepo.LockChanges(ctx); err != nil {
+			return err
+		}
+
+		prodTypeFromDB, _ := prodTypeRepo.FindByID(ctx, prodTypeCreateReq.ID)
+		if prodTypeFromDB != nil && prodTypeFromDB.ID == prodTypeCreateReq.ID {
+			return errs.NewConflictError("ProductType with this ID already exists")
+		}
+
+		prodTypeFromTrash, _ := prodTypeRepo.FindDeletedByID(ctx, prodTypeCreateReq.ID)
+		if prodTypeFromTrash != nil && prodTypeFromTrash.ID == prodTypeCreateReq.ID {
+			return errs.NewConflictError("ProductType with this ID is in trash")
+		}
+
+		if prodTypeCreateReq.ParentID != nil {
+			if _, err := prodTypeRepo.FindByID(ctx, *prodTypeCreateReq.ParentID); err != nil {
+				return parentNotFound(err)
+			}
+		}
+
+		if err := prodTypeRepo.Save(ctx, prodTypeEntity); err != nil {
+			return err
+		}
+		written(model.AuditActionCreate, prodTypeEntity.ID, nil, toProductType(prodTypeEntity))
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
 		return err
 	}
 
-	logger.Info("Service: Create ProductType Successfully")
+	logger.Info(ctx, "Service: Create ProductType Successfully")
 	return nil
 }
 
-func (s *ProductTypeServiceImpl) FindAll() ([]model.ProductType, error) {
-	prodTypeEntities, err := s.ProdTypeRepo.FindAll()
+func (s *ProductTypeServiceImpl) FindAll(ctx context.Context) ([]model.ProductType, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindAll")
+	defer span.End()
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindAll(ctx)
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return nil, err
 	}
 
@@ -56,39 +113,48 @@ func (s *ProductTypeServiceImpl) FindAll() ([]model.ProductType, error) {
 		prodTypeRes := &model.ProductType{
 			ID:       prodTypeEntity.ID,
 			Name:     prodTypeEntity.Name,
+			ParentID: prodTypeEntity.ParentID,
 		}
 		prodTypesRes = append(prodTypesRes, *prodTypeRes)
 	}
 
-	logger.Info("Service: Find All ProductTypes Successfully")
+	logger.Info(ctx, "Service: Find All ProductTypes Successfully")
 	return prodTypesRes, nil
 }
 
-func (s *ProductTypeServiceImpl) FindByID(id int) (*model.ProductType, error) {
-	prodTypeEntity, err := s.ProdTypeRepo.FindByID(id);
+func (s *ProductTypeServiceImpl) FindByID(ctx context.Context, id int) (*model.ProductType, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindByID")
+	defer span.End()
+
+	prodTypeEntity, err := s.ProdTypeRepo.FindByID(ctx, id);
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return nil, err
 	}
 
 	prodTypeRes := &model.ProductType{
 		ID:       prodTypeEntity.ID,
 		Name:     prodTypeEntity.Name,
+		ParentID: prodTypeEntity.ParentID,
 	}
 
-	logger.Info("Service: Find ProductType By ID Successfully")
+	logger.Info(ctx, "Service: Find ProductType By ID Successfully")
 	return prodTypeRes, nil
 }
 
-func (s *ProductTypeServiceImpl) Update(id int, prodTypeUpdateReq *model.ProductTypeUpdate) error {
+func (s *ProductTypeServiceImpl) Update(ctx context.Context, id int, prodTypeUpdateReq *model.ProductTypeUpdate) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Update")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
 	if err := helper.ValidateProductTypeUpdate(prodTypeUpdateReq); err != nil {
-		logger.Error("ProductType Update data is not valid")
+		logger.Error(ctx, "ProductType Update data is not valid")
 		return errs.NewValidateBadRequestError(err)
 	}
 
-	_, err := s.ProdTypeRepo.FindByID(id)
+	prodTypeFromDB, err := s.ProdTypeRepo.FindByID(ctx, id)
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return err
 	}
 
@@ -96,38 +162,1261 @@ func (s *ProductTypeServiceImpl) Update(id int, prodTypeUpdateReq *model.Product
 		ID:       id,
 		Name:     prodTypeUpdateReq.Name,
 	}
-	if err := s.ProdTypeRepo.Update(prodTypeEntity); err != nil {
-		logger.Error(err)
+	err = s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if err := prodTypeRepo.Update(ctx, prodTypeEntity); err != nil {
+			return err
+		}
+		updatedEntity := *prodTypeEntity
+		updatedEntity.ParentID = prodTypeFromDB.ParentID
+		written(model.AuditActionUpdate, id, toProductType(prodTypeFromDB), toProductType(&updatedEntity))
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
 		return err
 	}
 
-	logger.Info("Service: Update ProductType Successfully")
+	logger.Info(ctx, "Service: Update ProductType Successfully")
 	return nil
 }
 
-func (s *ProductTypeServiceImpl) Delete(id int) error {
-	_, err := s.ProdTypeRepo.FindByID(id)
+// Delete soft deletes the product type, children decides what happens to its children:
+// reject refuses while it has any, cascade deletes the whole subtree
+// and reparent moves them up to the deleted product type's parent.
+func (s *ProductTypeServiceImpl) Delete(ctx context.Context, id int, children string) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Delete")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if children == "" {
+		children = model.DeleteChildrenReject
+	}
+
+	switch children {
+	case model.DeleteChildrenReject, model.DeleteChildrenCascade, model.DeleteChildrenReparent:
+	default:
+		logger.Error(ctx, "Children mode " + children + " is not supported")
+		return errs.NewBadRequestError("Children mode " + children + " is not supported")
+	}
+
+	// the product type and its children are read under the change lock,
+	// so no child can be added or moved in between
+	err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if err := prodTypeRepo.LockChanges(ctx); err != nil {
+			return err
+		}
+		prodTypeEntity, err := prodTypeRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		switch children {
+		case model.DeleteChildrenReject:
+			if err := checkNoChildren(ctx, prodTypeRepo, id); err != nil {
+				return err
+			}
+			if err := prodTypeRepo.Delete(ctx, id); err != nil {
+				return err
+			}
+			written(model.AuditActionDelete, id, toProductType(prodTypeEntity), nil)
+		case model.DeleteChildrenCascade:
+			deletedEntities, err := prodTypeRepo.DeleteSubtree(ctx, id)
+			if err != nil {
+				return err
+			}
+			for i := range deletedEntities {
+				written(model.AuditActionDelete, deletedEntities[i].ID, toProductType(&deletedEntities[i]), nil)
+			}
+		case model.DeleteChildrenReparent:
+			childEntities, err := prodTypeRepo.DeleteAndReparent(ctx, id, prodTypeEntity.ParentID)
+			if err != nil {
+				return err
+			}
+			written(model.AuditActionDelete, id, toProductType(prodTypeEntity), nil)
+			childrenMoved(written, childEntities, prodTypeEntity.ParentID)
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return err
 	}
-	
-	if err := s.ProdTypeRepo.Delete(id); err != nil {
-		logger.Error(err)
+
+	logger.Info(ctx, "Service: Delete ProductType Successfully")
+	return nil
+}
+
+func (s *ProductTypeServiceImpl) Count(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Count")
+	defer span.End()
+
+	count, err := s.ProdTypeRepo.Count(ctx);
+	if err != nil {
+		logger.Error(ctx, err)
+		return 0, err
+	}
+
+	logger.Info(ctx, "Service: Get ProductType'Count Successfully")
+	return count, nil
+}
+
+func (s *ProductTypeServiceImpl) FindAllTrash(ctx context.Context) ([]model.ProductTypeTrash, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindAllTrash")
+	defer span.End()
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindAllDeleted(ctx)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	var prodTypesRes []model.ProductTypeTrash
+	for _, prodTypeEntity := range prodTypeEntities {
+		prodTypeRes := &model.ProductTypeTrash{
+			ID:       	prodTypeEntity.ID,
+			Name:     	prodTypeEntity.Name,
+			DeletedAt: 	prodTypeEntity.DeletedAt.Time,
+		}
+		prodTypesRes = append(prodTypesRes, *prodTypeRes)
+	}
+
+	logger.Info(ctx, "Service: Find All ProductTypes In Trash Successfully")
+	return prodTypesRes, nil
+}
+
+func (s *ProductTypeServiceImpl) Restore(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Restore")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	// the parent is checked under the change lock, so it can not be deleted before the restore
+	err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if err := prodTypeRepo.LockChanges(ctx); err != nil {
+			return err
+		}
+		prodTypeEntity, err := prodTypeRepo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if prodTypeEntity.ParentID != nil {
+			if _, err := prodTypeRepo.FindByID(ctx, *prodTypeEntity.ParentID); err != nil {
+				return errs.NewConflictError("Parent ProductType is not available")
+			}
+		}
+
+		if err := prodTypeRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+		written(model.AuditActionRestore, id, toProductTypeTrash(prodTypeEntity), toProductType(prodTypeEntity))
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
 		return err
 	}
 
-	logger.Info("Service: Delete ProductType Successfully")
+	logger.Info(ctx, "Service: Restore ProductType Successfully")
 	return nil
 }
 
-func (s *ProductTypeServiceImpl) Count() (int64, error) {
-	count, err := s.ProdTypeRepo.Count();
+func (s *ProductTypeServiceImpl) Purge(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Purge")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if err := prodTypeRepo.LockChanges(ctx); err != nil {
+			return err
+		}
+		prodTypeEntity, err := prodTypeRepo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		childEntities, err := prodTypeRepo.Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+		written(model.AuditActionPurge, id, toProductTypeTrash(prodTypeEntity), nil)
+		childrenMoved(written, childEntities, nil)
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	logger.Info(ctx, "Service: Purge ProductType Successfully")
+	return nil
+}
+
+// PurgeExpired purges the product types deleted longer than retention ago, each one is audited as Purge does.
+func (s *ProductTypeServiceImpl) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.PurgeExpired")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	deletedBefore := time.Now().Add(-retention)
+	var purged int64
+	err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		prodTypesEntity, childEntities, err := prodTypeRepo.PurgeDeletedBefore(ctx, deletedBefore)
+		if err != nil {
+			return err
+		}
+		purged = int64(len(prodTypesEntity))
+		for i := range prodTypesEntity {
+			written(model.AuditActionPurge, prodTypesEntity[i].ID, toProductTypeTrash(&prodTypesEntity[i]), nil)
+		}
+		childrenMoved(written, childEntities, nil)
+		return nil
+	})
 	if err != nil {
-		logger.Error(err)
+		logger.Error(ctx, err)
 		return 0, err
 	}
 
-	logger.Info("Service: Get ProductType'Count Successfully")
-	return count, nil
-}
\ No newline at end of file
+	logger.Info(ctx, "Service: Purge Expired ProductTypes Successfully", "purged", purged)
+	return purged, nil
+}
+
+func (s *ProductTypeServiceImpl) Bulk(ctx context.Context, bulkReq *model.ProductTypeBulkRequest) (*model.ProductTypeBulkSummary, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Bulk")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if err := helper.ValidateProductTypeBulk(bulkReq); err != nil {
+		logger.Error(ctx, "ProductType Bulk data is not valid")
+		return nil, errs.NewValidateBadRequestError(err)
+	}
+
+	ids := make([]int, 0, len(bulkReq.Operations))
+	for _, op := range bulkReq.Operations {
+		ids = append(ids, op.ID)
+	}
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindByIDsUnscoped(ctx, ids)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	prodTypesFromDB := make(map[int]model.ProductTypeEntity, len(prodTypeEntities))
+	for _, prodTypeEntity := range prodTypeEntities {
+		prodTypesFromDB[prodTypeEntity.ID] = prodTypeEntity
+	}
+
+	results := make([]model.ProductTypeBulkResult, len(bulkReq.Operations))
+	seen := make(map[int]bool, len(bulkReq.Operations))
+	failed := false
+	for i := range bulkReq.Operations {
+		op := &bulkReq.Operations[i]
+		results[i] = model.ProductTypeBulkResult{Index: i, Op: op.Op, ID: op.ID}
+
+		if valErrs := validateBulkOperation(op); valErrs != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Errors = valErrs
+			failed = true
+			continue
+		}
+
+		if seen[op.ID] {
+			results[i].Status = http.StatusConflict
+			results[i].Message = "ProductType ID is duplicated in bulk request"
+			failed = true
+			continue
+		}
+		seen[op.ID] = true
+
+		prodTypeFromDB, found := prodTypesFromDB[op.ID]
+		switch {
+		case op.Op == model.BulkOpCreate && found && prodTypeFromDB.DeletedAt.Valid:
+			results[i].Status = http.StatusConflict
+			results[i].Message = "ProductType with this ID is in trash"
+			failed = true
+		case op.Op == model.BulkOpCreate && found:
+			results[i].Status = http.StatusConflict
+			results[i].Message = "ProductType with this ID already exists"
+			failed = true
+		case op.Op != model.BulkOpCreate && (!found || prodTypeFromDB.DeletedAt.Valid):
+			results[i].Status = http.StatusNotFound
+			results[i].Message = "record not found"
+			failed = true
+		}
+	}
+
+	if bulkReq.Mode == model.BulkModeTransactional {
+		s.applyBulkTransactional(ctx, bulkReq.Operations, prodTypesFromDB, results, failed)
+	} else {
+		s.applyBulkBestEffort(ctx, bulkReq.Operations, prodTypesFromDB, results)
+	}
+
+	summary := &model.ProductTypeBulkSummary{
+		Mode: 		bulkReq.Mode,
+		Results: 	results,
+	}
+	for _, result := range results {
+		if result.Status >= http.StatusBadRequest {
+			summary.Failed++
+			continue
+		}
+		summary.Succeeded++
+	}
+
+	logger.Info(ctx, "Service: Bulk ProductTypes Successfully", "succeeded", summary.Succeeded, "failed", summary.Failed)
+	return summary, nil
+}
+
+func (s *ProductTypeServiceImpl) applyBulkTransactional(ctx context.Context, ops []model.ProductTypeBulkOperation, prodTypesFromDB map[int]model.ProductTypeEntity, results []model.ProductTypeBulkResult, failed bool) {
+	if failed {
+		for i := range results {
+			if results[i].Status == 0 {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Message = "Not applied because another operation failed"
+			}
+		}
+		return
+	}
+
+	batch := &model.ProductTypeBatch{}
+	for _, op := range ops {
+		switch op.Op {
+		case model.BulkOpCreate:
+			batch.Creates = append(batch.Creates, model.ProductTypeEntity{ID: op.ID, Name: op.Name})
+		case model.BulkOpUpdate:
+			batch.Updates = append(batch.Updates, model.ProductTypeEntity{ID: op.ID, Name: op.Name})
+		case model.BulkOpDelete:
+			batch.Deletes = append(batch.Deletes, op.ID)
+		}
+	}
+
+	// the children are looked up once the batch is applied, so children deleted by the same request do not count
+	conflicting := -1
+	err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if err := prodTypeRepo.ApplyBatch(ctx, batch); err != nil {
+			return err
+		}
+		for i, op := range ops {
+			if op.Op != model.BulkOpDelete {
+				continue
+			}
+			if err := checkNoChildren(ctx, prodTypeRepo, op.ID); err != nil {
+				conflicting = i
+				return err
+			}
+		}
+		for _, op := range ops {
+			bulkWritten(written, op, prodTypesFromDB[op.ID])
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		for i := range results {
+			if conflicting >= 0 && i != conflicting {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Message = "Not applied because another operation failed"
+				continue
+			}
+			results[i].Status = errorStatus(err)
+			results[i].Message = err.Error()
+		}
+		return
+	}
+
+	for i := range results {
+		results[i].Status = bulkSuccessStatus(results[i].Op)
+	}
+}
+
+// applyBulkBestEffort applies every operation in its own transaction so a failure only fails that operation.
+// A delete is refused while the product type has live children, children deleted by earlier operations
+// of the request are gone by then.
+func (s *ProductTypeServiceImpl) applyBulkBestEffort(ctx context.Context, ops []model.ProductTypeBulkOperation, prodTypesFromDB map[int]model.ProductTypeEntity, results []model.ProductTypeBulkResult) {
+	for i, op := range ops {
+		if results[i].Status != 0 {
+			continue
+		}
+
+		err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+			var err error
+			switch op.Op {
+			case model.BulkOpCreate:
+				err = prodTypeRepo.Save(ctx, &model.ProductTypeEntity{ID: op.ID, Name: op.Name})
+			case model.BulkOpUpdate:
+				err = prodTypeRepo.Update(ctx, &model.ProductTypeEntity{ID: op.ID, Name: op.Name})
+			case model.BulkOpDelete:
+				if err = checkNoChildren(ctx, prodTypeRepo, op.ID); err == nil {
+					err = prodTypeRepo.Delete(ctx, op.ID)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			bulkWritten(written, op, prodTypesFromDB[op.ID])
+			return nil
+		})
+		if err != nil {
+			logger.Error(ctx, err)
+			results[i].Status = errorStatus(err)
+			results[i].Message = err.Error()
+			continue
+		}
+		results[i].Status = bulkSuccessStatus(op.Op)
+	}
+}
+
+func bulkWritten(written writtenFunc, op model.ProductTypeBulkOperation, prodTypeFromDB model.ProductTypeEntity) {
+	switch op.Op {
+	case model.BulkOpCreate:
+		written(model.AuditActionCreate, op.ID, nil, &model.ProductType{ID: op.ID, Name: op.Name})
+	case model.BulkOpUpdate:
+		written(model.AuditActionUpdate, op.ID, toProductType(&prodTypeFromDB), &model.ProductType{ID: op.ID, Name: op.Name, ParentID: prodTypeFromDB.ParentID})
+	case model.BulkOpDelete:
+		written(model.AuditActionDelete, op.ID, toProductType(&prodTypeFromDB), nil)
+	}
+}
+
+// checkNoChildren refuses to delete a product type that still has live children,
+// as Delete does when children is reject.
+func checkNoChildren(ctx context.Context, prodTypeRepo repository.ProductTypeRepository, id int) error {
+	childEntities, err := prodTypeRepo.FindChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(childEntities) > 0 {
+		return errs.NewConflictError("ProductType has children")
+	}
+	return nil
+}
+
+// errorStatus is the HTTP status of a service error, anything unexpected is an internal server error.
+func errorStatus(err error) int {
+	if errRes, ok := err.(errs.ErrorResponse); ok {
+		return errRes.Code
+	}
+	return http.StatusInternalServerError
+}
+
+func validateBulkOperation(op *model.ProductTypeBulkOperation) []errs.ErrorMessage {
+	if valErrs := helper.ValidateProductTypeBulkOperation(op); valErrs != nil {
+		return valErrs
+	}
+
+	switch op.Op {
+	case model.BulkOpCreate:
+		return helper.ValidateProductTypeCreate(&model.ProductTypeCreate{ID: op.ID, Name: op.Name})
+	case model.BulkOpUpdate:
+		return helper.ValidateProductTypeUpdate(&model.ProductTypeUpdate{Name: op.Name})
+	}
+	return nil
+}
+
+func bulkSuccessStatus(op string) int {
+	if op == model.BulkOpCreate {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+// Export reads the first batch before it returns, so a database that can not be read fails the request
+// rather than the file. The returned function writes the file and reads the rest of the batches as it goes,
+// the file is left unfinished when a later batch fails.
+func (s *ProductTypeServiceImpl) Export(ctx context.Context, format string) (func(io.Writer) error, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Export")
+	defer span.End()
+
+	if !spreadsheet.IsSupported(format) {
+		logger.Error(ctx, "Format " + format + " is not supported")
+		return nil, errs.NewBadRequestError("Format " + format + " is not supported")
+	}
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindAllAfter(ctx, 0, productTypeExportBatchSize)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	return func(w io.Writer) error {
+		writer, err := spreadsheet.NewWriter(format, w)
+		if err != nil {
+			logger.Error(ctx, err)
+			return err
+		}
+
+		if err := writer.WriteHeader([]string{productTypeColumnID, productTypeColumnName, productTypeColumnParentCode}); err != nil {
+			logger.Error(ctx, err)
+			return errs.NewInternalServerError(err.Error())
+		}
+
+		for len(prodTypeEntities) > 0 {
+			for _, prodTypeEntity := range prodTypeEntities {
+				if err := writer.WriteRow([]interface{}{prodTypeEntity.ID, prodTypeEntity.Name, exportParentCode(prodTypeEntity.ParentID)}); err != nil {
+					logger.Error(ctx, err)
+					return errs.NewInternalServerError(err.Error())
+				}
+			}
+			if len(prodTypeEntities) < productTypeExportBatchSize {
+				break
+			}
+
+			afterID := prodTypeEntities[len(prodTypeEntities)-1].ID
+			if prodTypeEntities, err = s.ProdTypeRepo.FindAllAfter(ctx, afterID, productTypeExportBatchSize); err != nil {
+				logger.Error(ctx, err)
+				return err
+			}
+		}
+
+		if err := writer.Close(); err != nil {
+			logger.Error(ctx, err)
+			return errs.NewInternalServerError(err.Error())
+		}
+
+		logger.Info(ctx, "Service: Export ProductTypes Successfully")
+		return nil
+	}, nil
+}
+
+// exportParentCode leaves the cell of a root product type empty.
+func exportParentCode(parentID *int) interface{} {
+	if parentID == nil {
+		return ""
+	}
+	return *parentID
+}
+
+func (s *ProductTypeServiceImpl) Import(ctx context.Context, opts *model.ProductTypeImportOptions, r io.Reader) (*model.ProductTypeImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Import")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if err := helper.ValidateProductTypeImportOptions(opts); err != nil {
+		logger.Error(ctx, "ProductType Import options is not valid")
+		return nil, errs.NewValidateBadRequestError(err)
+	}
+
+	header, records, err := spreadsheet.ReadAll(opts.Format, r)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	idColumn, err := findImportColumn(header, opts.Mapping, productTypeColumnID)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+	nameColumn, err := findImportColumn(header, opts.Mapping, productTypeColumnName)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+	// The parent column is optional, without it the parents are left as they are.
+	parentColumn, err := findImportColumn(header, opts.Mapping, productTypeColumnParentCode)
+	if err != nil {
+		if _, mapped := opts.Mapping[productTypeColumnParentCode]; mapped {
+			logger.Error(ctx, err)
+			return nil, err
+		}
+		parentColumn = -1
+	}
+
+	report := &model.ProductTypeImportReport{DryRun: opts.DryRun}
+	seen := make(map[int]bool, len(records))
+	var ids []int
+	for i, record := range records {
+		row := model.ProductTypeImportRow{Row: i + 2}
+		rawID := importCell(record, idColumn)
+		row.Name = importCell(record, nameColumn)
+		rawParentID := importCell(record, parentColumn)
+
+		id, err := strconv.Atoi(rawID)
+		parentID, parentErr := importParentID(rawParentID)
+		switch {
+		case err != nil:
+			row.Errors = []errs.ErrorMessage{{FailedField: "ProductTypeCreate.ID", Tag: "numeric", Value: rawID}}
+		case parentErr != nil:
+			row.ID = id
+			row.Errors = []errs.ErrorMessage{{FailedField: "ProductTypeCreate.ParentID", Tag: "numeric", Value: rawParentID}}
+		default:
+			row.ID = id
+			row.ParentID = parentID
+			row.Errors = helper.ValidateProductTypeCreate(&model.ProductTypeCreate{ID: id, Name: row.Name, ParentID: parentID})
+		}
+
+		if row.Errors == nil && seen[row.ID] {
+			row.Errors = []errs.ErrorMessage{{FailedField: "ProductTypeCreate.ID", Tag: "unique", Value: ""}}
+		}
+		if row.Errors == nil && row.ParentID != nil && *row.ParentID == row.ID {
+			row.Errors = []errs.ErrorMessage{{FailedField: "ProductTypeCreate.ParentID", Tag: "nefield", Value: "ID"}}
+		}
+
+		if row.Errors != nil {
+			report.Invalid++
+		} else {
+			seen[row.ID] = true
+			ids = append(ids, row.ID)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	// Parents that are not in the file are looked up together with the rows.
+	parentsFromDB := make(map[int]bool)
+	var parentIDs []int
+	for _, row := range report.Rows {
+		if row.Errors == nil && row.ParentID != nil && !seen[*row.ParentID] && !parentsFromDB[*row.ParentID] {
+			parentsFromDB[*row.ParentID] = true
+			parentIDs = append(parentIDs, *row.ParentID)
+		}
+	}
+	lookupIDs := append(append([]int(nil), ids...), parentIDs...)
+
+	prodTypesFromDB := make(map[int]model.ProductTypeEntity)
+	if len(lookupIDs) > 0 {
+		prodTypeEntities, err := s.ProdTypeRepo.FindByIDsUnscoped(ctx, lookupIDs)
+		if err != nil {
+			logger.Error(ctx, err)
+			return nil, err
+		}
+		for _, prodTypeEntity := range prodTypeEntities {
+			prodTypesFromDB[prodTypeEntity.ID] = prodTypeEntity
+		}
+	}
+
+	batch := &model.ProductTypeBatch{}
+	var moves []int
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if row.Errors != nil {
+			continue
+		}
+		if row.ParentID != nil && parentsFromDB[*row.ParentID] {
+			if parentFromDB, found := prodTypesFromDB[*row.ParentID]; !found || parentFromDB.DeletedAt.Valid {
+				row.Errors = []errs.ErrorMessage{{FailedField: "ProductTypeCreate.ParentID", Tag: "exists", Value: ""}}
+				report.Invalid++
+				continue
+			}
+		}
+
+		prodTypeFromDB, found := prodTypesFromDB[row.ID]
+		switch {
+		case !found:
+			row.Action = model.ImportActionCreate
+			report.Created++
+			batch.Creates = append(batch.Creates, model.ProductTypeEntity{ID: row.ID, Name: row.Name, ParentID: row.ParentID})
+		case opts.OnConflict == model.ImportOnConflictSkip:
+			row.Action = model.ImportActionSkip
+			report.Skipped++
+		case prodTypeFromDB.DeletedAt.Valid:
+			row.Errors = []errs.ErrorMessage{{FailedField: "ProductTypeCreate.ID", Tag: "trash", Value: ""}}
+			report.Invalid++
+		default:
+			row.Action = model.ImportActionUpdate
+			report.Updated++
+			batch.Updates = append(batch.Updates, model.ProductTypeEntity{ID: row.ID, Name: row.Name})
+			if parentColumn < 0 {
+				row.ParentID = prodTypeFromDB.ParentID
+			} else if !sameParent(row.ParentID, prodTypeFromDB.ParentID) {
+				moves = append(moves, i)
+			}
+		}
+	}
+	batch.Creates = parentsFirst(batch.Creates)
+
+	if opts.DryRun || report.Invalid > 0 {
+		logger.Info(ctx, "Service: Import ProductTypes Not Applied", "dry_run", opts.DryRun, "invalid", report.Invalid)
+		return report, nil
+	}
+
+	if len(batch.Creates) > 0 || len(batch.Updates) > 0 {
+		err := s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+			// The parents are checked again under the change lock, the hierarchy can not change
+			// until the import commits.
+			var placed []model.ProductTypeImportRow
+			for _, row := range report.Rows {
+				if row.Action == model.ImportActionCreate && row.ParentID != nil {
+					placed = append(placed, row)
+				}
+			}
+			for _, i := range moves {
+				if report.Rows[i].ParentID != nil {
+					placed = append(placed, report.Rows[i])
+				}
+			}
+			if len(placed) > 0 {
+				if err := prodTypeRepo.LockChanges(ctx); err != nil {
+					return err
+				}
+				if err := checkImportParents(ctx, prodTypeRepo, parentIDs); err != nil {
+					return err
+				}
+			}
+
+			if err := prodTypeRepo.ApplyBatch(ctx, batch); err != nil {
+				return err
+			}
+			for _, i := range moves {
+				if err := prodTypeRepo.Move(ctx, report.Rows[i].ID, report.Rows[i].ParentID); err != nil {
+					return err
+				}
+			}
+			for _, row := range placed {
+				if err := checkNotDescendant(ctx, prodTypeRepo, row.ID, *row.ParentID); err != nil {
+					return err
+				}
+			}
+
+			for _, row := range report.Rows {
+				prodTypeFromDB := prodTypesFromDB[row.ID]
+				switch row.Action {
+				case model.ImportActionCreate:
+					written(model.AuditActionCreate, row.ID, nil, &model.ProductType{ID: row.ID, Name: row.Name, ParentID: row.ParentID})
+				case model.ImportActionUpdate:
+					written(model.AuditActionUpdate, row.ID, toProductType(&prodTypeFromDB), &model.ProductType{ID: row.ID, Name: row.Name, ParentID: row.ParentID})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Error(ctx, err)
+			return nil, err
+		}
+	}
+	report.Applied = true
+
+	logger.Info(ctx, "Service: Import ProductTypes Successfully")
+	return report, nil
+}
+
+// importParentID parses the parent cell, an empty cell is a root.
+func importParentID(raw string) (*int, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	parentID, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &parentID, nil
+}
+
+func sameParent(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// parentsFirst orders the creates so a parent created by the same import is inserted before its children.
+func parentsFirst(creates []model.ProductTypeEntity) []model.ProductTypeEntity {
+	index := make(map[int]int, len(creates))
+	for i, prodTypeEntity := range creates {
+		index[prodTypeEntity.ID] = i
+	}
+
+	ordered := make([]model.ProductTypeEntity, 0, len(creates))
+	placed := make(map[int]bool, len(creates))
+	var place func(i int)
+	place = func(i int) {
+		if placed[creates[i].ID] {
+			return
+		}
+		placed[creates[i].ID] = true
+		if parentID := creates[i].ParentID; parentID != nil {
+			if parent, ok := index[*parentID]; ok {
+				place(parent)
+			}
+		}
+		ordered = append(ordered, creates[i])
+	}
+	for i := range creates {
+		place(i)
+	}
+	return ordered
+}
+
+// checkImportParents makes sure the parents the import found in the database are still live.
+func checkImportParents(ctx context.Context, prodTypeRepo repository.ProductTypeRepository, parentIDs []int) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	prodTypeEntities, err := prodTypeRepo.FindByIDsUnscoped(ctx, parentIDs)
+	if err != nil {
+		return err
+	}
+	live := 0
+	for _, prodTypeEntity := range prodTypeEntities {
+		if !prodTypeEntity.DeletedAt.Valid {
+			live++
+		}
+	}
+	if live < len(parentIDs) {
+		return errs.NewConflictError("Parent ProductType is not available")
+	}
+	return nil
+}
+
+func findImportColumn(header []string, mapping map[string]string, field string) (int, error) {
+	column := field
+	if mapped, ok := mapping[field]; ok && mapped != "" {
+		column = mapped
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+	return 0, errs.NewBadRequestError("Column " + column + " is not found in file")
+}
+
+func importCell(record []string, column int) string {
+	if column < 0 || column >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[column])
+}
+
+func (s *ProductTypeServiceImpl) FindTree(ctx context.Context) ([]*model.ProductTypeNode, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindTree")
+	defer span.End()
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindAll(ctx)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	nodes := make(map[int]*model.ProductTypeNode, len(prodTypeEntities))
+	for _, prodTypeEntity := range prodTypeEntities {
+		nodes[prodTypeEntity.ID] = &model.ProductTypeNode{
+			ID:       	prodTypeEntity.ID,
+			Name:     	prodTypeEntity.Name,
+			ParentID: 	prodTypeEntity.ParentID,
+			Children: 	[]*model.ProductTypeNode{},
+		}
+	}
+
+	// Nodes whose parent is not live are shown as roots so they never disappear from the tree.
+	roots := []*model.ProductTypeNode{}
+	for _, prodTypeEntity := range prodTypeEntities {
+		node := nodes[prodTypeEntity.ID]
+		if prodTypeEntity.ParentID != nil {
+			if parent, ok := nodes[*prodTypeEntity.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	logger.Info(ctx, "Service: Find ProductType Tree Successfully")
+	return roots, nil
+}
+
+func (s *ProductTypeServiceImpl) FindChildren(ctx context.Context, id int) ([]model.ProductType, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindChildren")
+	defer span.End()
+
+	if _, err := s.ProdTypeRepo.FindByID(ctx, id); err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindChildren(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "Service: Find ProductType Children Successfully")
+	return toProductTypes(prodTypeEntities), nil
+}
+
+func (s *ProductTypeServiceImpl) FindAncestors(ctx context.Context, id int) ([]model.ProductType, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindAncestors")
+	defer span.End()
+
+	if _, err := s.ProdTypeRepo.FindByID(ctx, id); err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	prodTypeEntities, err := s.ProdTypeRepo.FindAncestors(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "Service: Find ProductType Ancestors Successfully")
+	return toProductTypes(prodTypeEntities), nil
+}
+
+// Move reattaches the product type and its subtree under a new parent,
+// a nil parent makes it a root. Moving under itself or one of its descendants is rejected.
+func (s *ProductTypeServiceImpl) Move(ctx context.Context, id int, prodTypeMoveReq *model.ProductTypeMove) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Move")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if err := helper.ValidateProductTypeMove(prodTypeMoveReq); err != nil {
+		logger.Error(ctx, "ProductType Move data is not valid")
+		return errs.NewValidateBadRequestError(err)
+	}
+
+	prodTypeEntity, err := s.ProdTypeRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	if parentID := prodTypeMoveReq.ParentID; parentID != nil && *parentID == id {
+		logger.Error(ctx, "ProductType can not be moved under itself")
+		return errs.NewConflictError("ProductType can not be moved under itself")
+	}
+
+	err = s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if parentID := prodTypeMoveReq.ParentID; parentID != nil {
+			if err := prodTypeRepo.LockChanges(ctx); err != nil {
+				return err
+			}
+			if _, err := prodTypeRepo.FindByID(ctx, *parentID); err != nil {
+				return parentNotFound(err)
+			}
+			if err := checkNotDescendant(ctx, prodTypeRepo, id, *parentID); err != nil {
+				return err
+			}
+		}
+
+		if err := prodTypeRepo.Move(ctx, id, prodTypeMoveReq.ParentID); err != nil {
+			return err
+		}
+		movedEntity := *prodTypeEntity
+		movedEntity.ParentID = prodTypeMoveReq.ParentID
+		written(model.AuditActionMove, id, toProductType(prodTypeEntity), toProductType(&movedEntity))
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	logger.Info(ctx, "Service: Move ProductType Successfully")
+	return nil
+}
+
+// FindByIDAsOf returns the product type as it was at asOf, built from the revision current at that time.
+func (s *ProductTypeServiceImpl) FindByIDAsOf(ctx context.Context, id int, asOf time.Time) (*model.ProductType, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindByIDAsOf")
+	defer span.End()
+
+	revisionEntity, err := s.ProdTypeRepo.FindRevisionAsOf(ctx, id, asOf)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+	if revisionEntity.DeletedAt != nil && !revisionEntity.DeletedAt.After(asOf) {
+		logger.Error(ctx, "ProductType was deleted at " + asOf.Format(time.RFC3339))
+		return nil, errs.NewNotFoundError("record not found")
+	}
+
+	prodTypeRes := &model.ProductType{
+		ID:       revisionEntity.ProductTypeID,
+		Name:     revisionEntity.Name,
+		ParentID: revisionEntity.ParentID,
+	}
+
+	logger.Info(ctx, "Service: Find ProductType By ID As Of Successfully")
+	return prodTypeRes, nil
+}
+
+func (s *ProductTypeServiceImpl) FindRevisions(ctx context.Context, id int) ([]model.ProductTypeRevision, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindRevisions")
+	defer span.End()
+
+	revisionsEntity, err := s.ProdTypeRepo.FindRevisions(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+	if len(revisionsEntity) == 0 {
+		logger.Error(ctx, "ProductType revisions are not found")
+		return nil, errs.NewNotFoundError("record not found")
+	}
+
+	revisionsRes := []model.ProductTypeRevision{}
+	for _, revisionEntity := range revisionsEntity {
+		revisionsRes = append(revisionsRes, model.ProductTypeRevision{
+			ID: 		revisionEntity.ProductTypeID,
+			Revision: 	revisionEntity.Revision,
+			Name: 		revisionEntity.Name,
+			ParentID: 	revisionEntity.ParentID,
+			DeletedAt: 	revisionEntity.DeletedAt,
+			Operation: 	revisionEntity.Operation,
+			CreatedAt: 	revisionEntity.CreatedAt,
+		})
+	}
+
+	logger.Info(ctx, "Service: Find ProductType Revisions Successfully")
+	return revisionsRes, nil
+}
+
+// Revert brings the name and parent of a live product type back to an earlier revision,
+// the parent of that revision must still be available and not have become a descendant since.
+func (s *ProductTypeServiceImpl) Revert(ctx context.Context, id int, revision int) error {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Revert")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	prodTypeEntity, err := s.ProdTypeRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	revisionEntity, err := s.ProdTypeRepo.FindRevision(ctx, id, revision)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	err = s.write(ctx, func(prodTypeRepo repository.ProductTypeRepository, written writtenFunc) error {
+		if parentID := revisionEntity.ParentID; parentID != nil {
+			if err := prodTypeRepo.LockChanges(ctx); err != nil {
+				return err
+			}
+			if _, err := prodTypeRepo.FindByID(ctx, *parentID); err != nil {
+				return errs.NewConflictError("Parent ProductType is not available")
+			}
+			if err := checkNotDescendant(ctx, prodTypeRepo, id, *parentID); err != nil {
+				return err
+			}
+		}
+
+		if err := prodTypeRepo.Revert(ctx, revisionEntity); err != nil {
+			return err
+		}
+		revertedEntity := *prodTypeEntity
+		revertedEntity.Name = revisionEntity.Name
+		revertedEntity.ParentID = revisionEntity.ParentID
+		written(model.AuditActionRevert, id, toProductType(prodTypeEntity), toProductType(&revertedEntity))
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	logger.Info(ctx, "Service: Revert ProductType Successfully")
+	return nil
+}
+
+// FindChanges returns the changes after query.Since in sequence order, a client passes
+// the returned cursor as since on its next call to sync incrementally.
+func (s *ProductTypeServiceImpl) FindChanges(ctx context.Context, query *model.ProductTypeChangeQuery) (*model.ProductTypeChangeFeed, error) {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.FindChanges")
+	defer span.End()
+
+	if err := helper.ValidateProductTypeChangeQuery(query); err != nil {
+		logger.Error(ctx, "ProductType change query is not valid")
+		return nil, errs.NewValidateBadRequestError(err)
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = productTypeChangeDefaultLimit
+	}
+
+	// one extra row tells whether another page is waiting
+	revisionsEntity, err := s.ProdTypeRepo.FindChanges(ctx, query.Since, limit + 1)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	changeFeed := &model.ProductTypeChangeFeed{
+		Changes: 	[]model.ProductTypeChange{},
+		Cursor: 	query.Since,
+		HasMore: 	len(revisionsEntity) > limit,
+	}
+	if changeFeed.HasMore {
+		revisionsEntity = revisionsEntity[:limit]
+	}
+	for _, revisionEntity := range revisionsEntity {
+		changeFeed.Changes = append(changeFeed.Changes, toProductTypeChange(&revisionEntity))
+		changeFeed.Cursor = revisionEntity.ID
+	}
+
+	logger.Info(ctx, "Service: Find ProductType Changes Successfully")
+	return changeFeed, nil
+}
+
+func toProductTypeChange(revisionEntity *model.ProductTypeRevisionEntity) model.ProductTypeChange {
+	change := model.ProductTypeChange{
+		Seq: 		revisionEntity.ID,
+		ID: 		revisionEntity.ProductTypeID,
+		ChangedAt: 	revisionEntity.CreatedAt,
+	}
+	switch {
+	case revisionEntity.DeletedAt != nil:
+		change.Op = model.ChangeOpDelete
+		return change
+	case revisionEntity.Operation == model.RevisionOpCreate || revisionEntity.Operation == model.RevisionOpRestore:
+		change.Op = model.ChangeOpInsert
+	default:
+		change.Op = model.ChangeOpUpdate
+	}
+	change.Name = revisionEntity.Name
+	change.ParentID = revisionEntity.ParentID
+	return change
+}
+
+// checkNotDescendant refuses a parent that sits below the product type, which would turn the tree
+// into a cycle. It runs in the write transaction after LockChanges, so two moves in opposite
+// directions can not both pass it. A parent chain as long as the ancestors query follows is refused
+// too, the product type could be further up it.
+func checkNotDescendant(ctx context.Context, prodTypeRepo repository.ProductTypeRepository, id int, parentID int) error {
+	ancestors, err := prodTypeRepo.FindAncestors(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors) >= repository.ProductTypeMaxDepth {
+		return errs.NewConflictError("ProductType hierarchy is too deep")
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == id {
+			return errs.NewConflictError("ProductType can not be moved under its descendant")
+		}
+	}
+	return nil
+}
+
+func toProductTypes(prodTypeEntities []model.ProductTypeEntity) []model.ProductType {
+	prodTypesRes := []model.ProductType{}
+	for i := range prodTypeEntities {
+		prodTypesRes = append(prodTypesRes, *toProductType(&prodTypeEntities[i]))
+	}
+	return prodTypesRes
+}
+
+func toProductType(prodTypeEntity *model.ProductTypeEntity) *model.ProductType {
+	return &model.ProductType{
+		ID:       prodTypeEntity.ID,
+		Name:     prodTypeEntity.Name,
+		ParentID: prodTypeEntity.ParentID,
+	}
+}
+
+func toProductTypeTrash(prodTypeEntity *model.ProductTypeEntity) *model.ProductTypeTrash {
+	return &model.ProductTypeTrash{
+		ID:       	prodTypeEntity.ID,
+		Name:     	prodTypeEntity.Name,
+		DeletedAt: 	prodTypeEntity.DeletedAt.Time,
+	}
+}
+
+// trashedProductType is the state of a product type in trash, it is audited like a live one
+// but gets no event.
+type trashedProductType struct {
+	*model.ProductType
+}
+
+// childrenMoved reports the children a reparent or a purge moved under parentID, as they were before.
+func childrenMoved(written writtenFunc, childEntities []model.ProductTypeEntity, parentID *int) {
+	for i := range childEntities {
+		movedEntity := childEntities[i]
+		movedEntity.ParentID = parentID
+		var after interface{} = toProductType(&movedEntity)
+		if movedEntity.DeletedAt.Valid {
+			after = trashedProductType{toProductType(&movedEntity)}
+		}
+		written(model.AuditActionMove, movedEntity.ID, toProductType(&childEntities[i]), after)
+	}
+}
+
+// writtenFunc reports one change to a product type made inside write,
+// before and after are nil for creates and deletes respectively.
+type writtenFunc func(action string, id int, before, after interface{})
+
+type productTypeWrite struct {
+	action 	string
+	id 		int
+	before 	interface{}
+	after 	interface{}
+}
+
+// write runs fn in a transaction with the product type repository joined to it. The changes fn reports
+// are stored as outbox events in the same transaction, so an event exists exactly when its change was
+// committed, and they are audited once the transaction commits.
+func (s *ProductTypeServiceImpl) write(ctx context.Context, fn func(repository.ProductTypeRepository, writtenFunc) error) error {
+	var writes []productTypeWrite
+	err := s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		writes = nil
+		err := fn(tx.ProductTypes(), func(action string, id int, before, after interface{}) {
+			writes = append(writes, productTypeWrite{action: action, id: id, before: before, after: after})
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Outbox().Save(ctx, productTypeOutboxEvents(ctx, writes))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, w := range writes {
+		s.Auditor.Record(ctx, newAuditEntry(ctx, w.action, model.AuditResourceProductType, strconv.Itoa(w.id), w.before, w.after))
+	}
+	return nil
+}
+
+// productTypeOutboxEvents maps the changes to events, a purge has no event since
+// subscribers already saw the delete. A product type in trash gets none either,
+// its restore sends the state it comes back with.
+func productTypeOutboxEvents(ctx context.Context, writes []productTypeWrite) []model.OutboxEventEntity {
+	now := time.Now()
+	var outboxEntities []model.OutboxEventEntity
+	for _, w := range writes {
+		var event string
+		var data interface{}
+		if _, trashed := w.after.(trashedProductType); trashed {
+			continue
+		}
+		switch w.action {
+		case model.AuditActionCreate, model.AuditActionRestore:
+			event, data = model.ProductTypeEventCreated, w.after
+		case model.AuditActionUpdate, model.AuditActionMove, model.AuditActionRevert:
+			event, data = model.ProductTypeEventUpdated, w.after
+		case model.AuditActionDelete:
+			event, data = model.ProductTypeEventDeleted, w.before
+		default:
+			continue
+		}
+		outboxEntities = append(outboxEntities, model.OutboxEventEntity{
+			AggregateType: 	model.AuditResourceProductType,
+			AggregateID: 	strconv.Itoa(w.id),
+			EventType: 		event,
+			Payload: 		auditState(ctx, data),
+			CreatedAt: 		now,
+		})
+	}
+	return outboxEntities
+}
+
+// Subscribe streams product type events, lastEventID resumes after an event already seen.
+func (s *ProductTypeServiceImpl) Subscribe(ctx context.Context, lastEventID string) *broker.Subscription {
+	ctx, span := tracing.Start(ctx, "ProductTypeService.Subscribe")
+	defer span.End()
+
+	logger.Info(ctx, "Service: Subscribe ProductType Events Successfully")
+	return s.Broker.Subscribe(lastEventID)
+}
+
+// parentNotFound reports a missing parent as a bad request,
+// the product type being written exists so a 404 would be misleading.
+func parentNotFound(err error) error {
+	if errRes, ok := err.(errs.ErrorResponse); ok && errRes.Code == http.StatusNotFound {
+		return errs.NewBadRequestError("Parent ProductType is not found")
+	}
+	return err
+}
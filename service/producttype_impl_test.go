@@ -1,25 +1,81 @@
 package service_test
 
 import (
+	"context"
 	"github.com/Yoshikrit/fiber-test/model"
 	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper"
 	"github.com/Yoshikrit/fiber-test/helper/errs"
 	"github.com/Yoshikrit/fiber-test/testutils"
 	
+	"bytes"
+	"strings"
 	"testing"
+	"time"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestCreate(t *testing.T) {
 	t.Run("test case : create success", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, nil)
-		mockRepository.On("Save", &model.ProductTypeEntity{ID:1,Name:"A"}).Return(nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"A"}).Return(nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:"A"})
+		actorID := 7
+		auditor, txManager := testutils.NewAuditRecorderMock(), testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, auditor, testutils.NewBrokerMock())
+		err := service.Create(helper.ContextWithActor(context.Background(), &model.Actor{UserID: &actorID}), &model.ProductTypeCreate{ID:1,Name:"A"})
 
 		assert.NoError(t, err)
+		assert.Equal(t, []string{model.ProductTypeEventCreated}, txManager.EventTypes())
+		assert.Equal(t, model.AuditResourceProductType, txManager.Events[0].AggregateType)
+		assert.Equal(t, "1", txManager.Events[0].AggregateID)
+		assert.JSONEq(t, `{"prodtype_id":1,"prodtype_name":"A"}`, *txManager.Events[0].Payload)
+		assert.Equal(t, []string{model.AuditActionCreate}, auditor.Actions())
+		assert.Equal(t, &actorID, auditor.Entries[0].ActorID)
+		assert.Nil(t, auditor.Entries[0].Before)
+		assert.JSONEq(t, `{"prodtype_id":1,"prodtype_name":"A"}`, *auditor.Entries[0].After)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : create with parent success", func(t *testing.T) {
+		parentID := 2
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"B"}, nil)
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"A",ParentID:&parentID}).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A",ParentID:&parentID})
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : create fail parent not found", func(t *testing.T) {
+		parentID := 2
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+
+		txManager := testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A",ParentID:&parentID})
+
+		assert.Empty(t, txManager.Events)
+		expectedBody := errs.NewBadRequestError("Parent ProductType is not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 		mockRepository.AssertExpectations(t)
 	})
 
@@ -36,8 +92,8 @@ func TestCreate(t *testing.T) {
 		}
 		mockRepository := testutils.NewProductTypeRepositoryMock()
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Create(&model.ProductTypeCreate{ID:0,Name:"A"})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:0,Name:"A"})
 
 		expectedBody := valError
 		assert.Error(t, err)
@@ -58,8 +114,8 @@ func TestCreate(t *testing.T) {
 		}
 		mockRepository := testutils.NewProductTypeRepositoryMock()
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:""})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:""})
 
 		expectedBody := valError
 		assert.Error(t, err)
@@ -87,8 +143,8 @@ func TestCreate(t *testing.T) {
 
 		mockRepository := testutils.NewProductTypeRepositoryMock()
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Create(&model.ProductTypeCreate{ID:0,Name:""})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:0,Name:""})
 
 		expectedBody := valError
 		assert.Error(t, err)
@@ -97,10 +153,11 @@ func TestCreate(t *testing.T) {
 
 	t.Run("test case : create fail conflict", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:"A"})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A"})
 
 		expectedBody := errs.NewConflictError("ProductType with this ID already exists")
 		assert.Error(t, err)
@@ -108,17 +165,37 @@ func TestCreate(t *testing.T) {
 		mockRepository.AssertExpectations(t)
 	})
 
+	t.Run("test case : create fail conflict in trash", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A"})
+
+		expectedBody := errs.NewConflictError("ProductType with this ID is in trash")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+
 	t.Run("test case : create fail from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, nil)
-		mockRepository.On("Save", &model.ProductTypeEntity{ID:1,Name:"A"}).Return(errs.NewInternalServerError(""))
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"A"}).Return(errs.NewInternalServerError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Create(&model.ProductTypeCreate{ID:1,Name:"A"})
+		auditor, txManager := testutils.NewAuditRecorderMock(), testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, auditor, testutils.NewBrokerMock())
+		err := service.Create(context.Background(), &model.ProductTypeCreate{ID:1,Name:"A"})
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
 		assert.Equal(t, expectedBody, err)
+		assert.Empty(t, txManager.Events)
+		assert.Empty(t, auditor.Entries)
 		mockRepository.AssertExpectations(t)
 	})
 }
@@ -126,10 +203,10 @@ func TestCreate(t *testing.T) {
 func TestFindAll(t *testing.T) {
 	t.Run("test case : find all success", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindAll").Return([]model.ProductTypeEntity{{ID:1,Name:"A",},{ID:2,Name:"B",}}, nil)
+		mockRepository.On("FindAll", mock.Anything).Return([]model.ProductTypeEntity{{ID:1,Name:"A",},{ID:2,Name:"B",}}, nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		prodTypeRes, err := service.FindAll()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodTypeRes, err := service.FindAll(context.Background())
 
 		expectedBody := []model.ProductType{{ID:1,Name:"A",},{ID:2,Name:"B",}}
 		assert.NoError(t, err)
@@ -140,10 +217,10 @@ func TestFindAll(t *testing.T) {
 	
 	t.Run("test case : find all fail from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindAll").Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+		mockRepository.On("FindAll", mock.Anything).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		prodTypesRes, err := service.FindAll()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodTypesRes, err := service.FindAll(context.Background())
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -156,10 +233,10 @@ func TestFindAll(t *testing.T) {
 func TestFindByID(t *testing.T) {
 	t.Run("test case : find by ID success", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		prodTypeRes, err := service.FindByID(1)
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodTypeRes, err := service.FindByID(context.Background(), 1)
 
 		expectedBody := &model.ProductType{ID:1,Name:"A"}
 		assert.NoError(t, err)
@@ -170,10 +247,10 @@ func TestFindByID(t *testing.T) {
 	
 	t.Run("test case : find by ID fail from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewInternalServerError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		prodTypesRes, err := service.FindByID(1)
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodTypesRes, err := service.FindByID(context.Background(), 1)
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -186,13 +263,17 @@ func TestFindByID(t *testing.T) {
 func TestUpdate(t *testing.T) {
 	t.Run("test case : update success", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Update", &model.ProductTypeEntity{ID:1,Name:"B"}).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Update", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"B"}).Return(nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Update(1, &model.ProductTypeUpdate{Name:"B"})
+		auditor := testutils.NewAuditRecorderMock()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), auditor, testutils.NewBrokerMock())
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:"B"})
 
 		assert.NoError(t, err)
+		assert.Equal(t, []string{model.AuditActionUpdate}, auditor.Actions())
+		assert.JSONEq(t, `{"prodtype_id":1,"prodtype_name":"A"}`, *auditor.Entries[0].Before)
+		assert.JSONEq(t, `{"prodtype_id":1,"prodtype_name":"B"}`, *auditor.Entries[0].After)
 		mockRepository.AssertExpectations(t)
 	})
 
@@ -210,8 +291,8 @@ func TestUpdate(t *testing.T) {
 
 		mockRepository := testutils.NewProductTypeRepositoryMock()
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Update(1, &model.ProductTypeUpdate{Name:""})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:""})
 
 		expectedBody := errs.ValErrorResponse(valError)
 		assert.Error(t, err)
@@ -220,10 +301,10 @@ func TestUpdate(t *testing.T) {
 
 	t.Run("test case : update fail not found from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Update(1, &model.ProductTypeUpdate{Name:"B"})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:"B"})
 
 		expectedBody := errs.NewNotFoundError("")
 		assert.Error(t, err)
@@ -233,11 +314,11 @@ func TestUpdate(t *testing.T) {
 	
 	t.Run("test case : update fail from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Update", &model.ProductTypeEntity{ID:1,Name:"B"}).Return(errs.NewNotFoundError(""))
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Update", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"B"}).Return(errs.NewNotFoundError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Update(1, &model.ProductTypeUpdate{Name:"B"})
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Update(context.Background(), 1, &model.ProductTypeUpdate{Name:"B"})
 
 		expectedBody := errs.NewNotFoundError("")
 		assert.Error(t, err)
@@ -249,22 +330,90 @@ func TestUpdate(t *testing.T) {
 func TestDelete(t *testing.T) {
 	t.Run("test case : delete success", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Delete", 1).Return(nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Delete", mock.Anything, 1).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "")
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : delete cascade success", func(t *testing.T) {
+		parentID := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("DeleteSubtree", mock.Anything, 1).Return([]model.ProductTypeEntity{{ID:1,Name:"A"},{ID:2,Name:"B",ParentID:&parentID}}, nil)
+
+		auditor := testutils.NewAuditRecorderMock()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), auditor, testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "cascade")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.AuditActionDelete, model.AuditActionDelete}, auditor.Actions())
+		assert.Equal(t, "2", auditor.Entries[1].ResourceID)
+		assert.Nil(t, auditor.Entries[1].After)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : delete reparent success", func(t *testing.T) {
+		parentID, childParentID := 2, 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A",ParentID:&parentID}, nil)
+		mockRepository.On("DeleteAndReparent", mock.Anything, 1, &parentID).Return([]model.ProductTypeEntity{{ID:3,Name:"C",ParentID:&childParentID}}, nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Delete(1)
+		auditor, txManager := testutils.NewAuditRecorderMock(), testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, auditor, testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "reparent")
 
 		assert.NoError(t, err)
+		assert.Equal(t, []string{model.ProductTypeEventDeleted, model.ProductTypeEventUpdated}, txManager.EventTypes())
+		assert.Equal(t, []string{"1", "3"}, []string{txManager.Events[0].AggregateID, txManager.Events[1].AggregateID})
+		assert.Equal(t, []string{model.AuditActionDelete, model.AuditActionMove}, auditor.Actions())
+		assert.JSONEq(t, `{"prodtype_id":3,"prodtype_name":"C","prodtype_parent_id":2}`, *auditor.Entries[1].After)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : delete fail has children", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{{ID:2,Name:"B"}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "reject")
+
+		expectedBody := errs.NewConflictError("ProductType has children")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Delete", mock.Anything, 1)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : delete fail unsupported children mode", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "orphan")
+
+		expectedBody := errs.NewBadRequestError("Children mode orphan is not supported")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
 		mockRepository.AssertExpectations(t)
 	})
 
 	t.Run("test case : delete fail not found from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Delete(1)
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "")
 
 		expectedBody := errs.NewNotFoundError("")
 		assert.Error(t, err)
@@ -274,11 +423,13 @@ func TestDelete(t *testing.T) {
 	
 	t.Run("test case : delete fail from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("FindByID", 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
-		mockRepository.On("Delete", 1).Return(errs.NewInternalServerError(""))
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Delete", mock.Anything, 1).Return(errs.NewInternalServerError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		err := service.Delete(1)
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Delete(context.Background(), 1, "")
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -290,10 +441,10 @@ func TestDelete(t *testing.T) {
 func TestCount(t *testing.T) {
 	t.Run("test case : getcount success", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("Count").Return(int64(1), nil)
+		mockRepository.On("Count", mock.Anything).Return(int64(1), nil)
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		count, err := service.Count()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		count, err := service.Count(context.Background())
 
 		expectedBody := int64(1)
 		assert.NoError(t, err)
@@ -304,10 +455,10 @@ func TestCount(t *testing.T) {
 	
 	t.Run("test case : delete fail from repository", func(t *testing.T) {
 		mockRepository := testutils.NewProductTypeRepositoryMock()
-		mockRepository.On("Count").Return(int64(0), errs.NewInternalServerError(""))
+		mockRepository.On("Count", mock.Anything).Return(int64(0), errs.NewInternalServerError(""))
 
-		service := service.NewProductTypeServiceImpl(mockRepository)
-		count, err := service.Count()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		count, err := service.Count(context.Background())
 
 		expectedBody := errs.NewInternalServerError("")
 		assert.Error(t, err)
@@ -317,3 +468,1224 @@ func TestCount(t *testing.T) {
 	})
 }
 
+
+
+func TestFindAllTrash(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : find all trash success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAllDeleted", mock.Anything).Return([]model.ProductTypeEntity{{ID:1,Name:"A",DeletedAt:gorm.DeletedAt{Time:deletedAt,Valid:true}}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodTypesRes, err := service.FindAllTrash(context.Background())
+
+		expectedBody := []model.ProductTypeTrash{{ID:1,Name:"A",DeletedAt:deletedAt}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, prodTypesRes)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find all trash fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAllDeleted", mock.Anything).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodTypesRes, err := service.FindAllTrash(context.Background())
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, prodTypesRes)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("test case : restore success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Restore", mock.Anything, 1).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Restore(context.Background(), 1)
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : restore fail not found in trash", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Restore(context.Background(), 1)
+
+		expectedBody := errs.NewNotFoundError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : restore fail parent not available", func(t *testing.T) {
+		parentID := 2
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A",ParentID:&parentID}, nil)
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Restore(context.Background(), 1)
+
+		expectedBody := errs.NewConflictError("Parent ProductType is not available")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Restore", mock.Anything, 1)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : restore fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Restore", mock.Anything, 1).Return(errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Restore(context.Background(), 1)
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestPurge(t *testing.T) {
+	t.Run("test case : purge success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Purge", mock.Anything, 1).Return([]model.ProductTypeEntity{}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Purge(context.Background(), 1)
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : purge audits the detached children", func(t *testing.T) {
+		parentID := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Purge", mock.Anything, 1).Return([]model.ProductTypeEntity{
+			{ID:2,Name:"B",ParentID:&parentID},
+			{ID:3,Name:"C",ParentID:&parentID,DeletedAt:gorm.DeletedAt{Time:time.Now(),Valid:true}},
+		}, nil)
+
+		auditor, txManager := testutils.NewAuditRecorderMock(), testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, auditor, testutils.NewBrokerMock())
+		err := service.Purge(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.ProductTypeEventUpdated}, txManager.EventTypes())
+		assert.Equal(t, "2", txManager.Events[0].AggregateID)
+		assert.Equal(t, []string{model.AuditActionPurge, model.AuditActionMove, model.AuditActionMove}, auditor.Actions())
+		assert.Equal(t, []string{"1", "2", "3"}, []string{auditor.Entries[0].ResourceID, auditor.Entries[1].ResourceID, auditor.Entries[2].ResourceID})
+		assert.JSONEq(t, `{"prodtype_id":3,"prodtype_name":"C","prodtype_parent_id":1}`, *auditor.Entries[2].Before)
+		assert.JSONEq(t, `{"prodtype_id":3,"prodtype_name":"C"}`, *auditor.Entries[2].After)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : purge fail not found in trash", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Purge(context.Background(), 1)
+
+		expectedBody := errs.NewNotFoundError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : purge fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindDeletedByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"A"}, nil)
+		mockRepository.On("Purge", mock.Anything, 1).Return([]model.ProductTypeEntity(nil), errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Purge(context.Background(), 1)
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestPurgeExpired(t *testing.T) {
+	t.Run("test case : purge expired success", func(t *testing.T) {
+		parentID := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("PurgeDeletedBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= 24 * time.Hour
+		})).Return([]model.ProductTypeEntity{{ID:1,Name:"A"},{ID:2,Name:"B"}}, []model.ProductTypeEntity{{ID:3,Name:"C",ParentID:&parentID}}, nil)
+
+		auditor, txManager := testutils.NewAuditRecorderMock(), testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, auditor, testutils.NewBrokerMock())
+		purged, err := service.PurgeExpired(context.Background(), 24 * time.Hour)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		assert.Equal(t, []string{model.AuditActionPurge, model.AuditActionPurge, model.AuditActionMove}, auditor.Actions())
+		assert.Equal(t, []string{"1", "2", "3"}, []string{auditor.Entries[0].ResourceID, auditor.Entries[1].ResourceID, auditor.Entries[2].ResourceID})
+		assert.Nil(t, auditor.Entries[0].After)
+		assert.Equal(t, []string{model.ProductTypeEventUpdated}, txManager.EventTypes())
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : purge expired fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("PurgeDeletedBefore", mock.Anything, mock.Anything).Return([]model.ProductTypeEntity(nil), []model.ProductTypeEntity(nil), errs.NewInternalServerError(""))
+
+		auditor := testutils.NewAuditRecorderMock()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), auditor, testutils.NewBrokerMock())
+		purged, err := service.PurgeExpired(context.Background(), 24 * time.Hour)
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Equal(t, int64(0), purged)
+		assert.Empty(t, auditor.Entries)
+		mockRepository.AssertExpectations(t)
+	})
+}
+func TestBulk(t *testing.T) {
+	t.Run("test case : bulk transactional success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2, 3}).Return([]model.ProductTypeEntity{{ID:2,Name:"B"},{ID:3,Name:"C"}}, nil)
+		mockRepository.On("ApplyBatch", mock.Anything, &model.ProductTypeBatch{
+			Creates: []model.ProductTypeEntity{{ID:1,Name:"A"}},
+			Updates: []model.ProductTypeEntity{{ID:2,Name:"BB"}},
+			Deletes: []int{3},
+		}).Return(nil)
+		mockRepository.On("FindChildren", mock.Anything, 3).Return([]model.ProductTypeEntity{}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeTransactional,
+			Operations: []model.ProductTypeBulkOperation{
+				{Op:"create",ID:1,Name:"A"},
+				{Op:"update",ID:2,Name:"BB"},
+				{Op:"delete",ID:3},
+			},
+		})
+
+		expectedBody := &model.ProductTypeBulkSummary{
+			Mode: model.BulkModeTransactional,
+			Succeeded: 3,
+			Results: []model.ProductTypeBulkResult{
+				{Index:0,Op:"create",ID:1,Status:201},
+				{Index:1,Op:"update",ID:2,Status:200},
+				{Index:2,Op:"delete",ID:3,Status:200},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, summary)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk transactional fail nothing applied", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2, 3}).Return([]model.ProductTypeEntity{{ID:2,Name:"B"}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeTransactional,
+			Operations: []model.ProductTypeBulkOperation{
+				{Op:"create",ID:1,Name:""},
+				{Op:"create",ID:2,Name:"B"},
+				{Op:"delete",ID:3},
+			},
+		})
+
+		expectedBody := &model.ProductTypeBulkSummary{
+			Mode: model.BulkModeTransactional,
+			Failed: 3,
+			Results: []model.ProductTypeBulkResult{
+				{Index:0,Op:"create",ID:1,Status:400,Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.Name",Tag:"required",Value:""}}},
+				{Index:1,Op:"create",ID:2,Status:409,Message:"ProductType with this ID already exists"},
+				{Index:2,Op:"delete",ID:3,Status:404,Message:"record not found"},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, summary)
+		mockRepository.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk transactional fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1}).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("ApplyBatch", mock.Anything, mock.Anything).Return(errs.NewInternalServerError("db down"))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeTransactional,
+			Operations: []model.ProductTypeBulkOperation{{Op:"create",ID:1,Name:"A"}},
+		})
+
+		expectedBody := &model.ProductTypeBulkSummary{
+			Mode: model.BulkModeTransactional,
+			Failed: 1,
+			Results: []model.ProductTypeBulkResult{{Index:0,Op:"create",ID:1,Status:500,Message:"db down"}},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, summary)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk transactional fail delete with children", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		parentID := 2
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2}).Return([]model.ProductTypeEntity{{ID:1,Name:"A"},{ID:2,Name:"B"}}, nil)
+		mockRepository.On("ApplyBatch", mock.Anything, &model.ProductTypeBatch{
+			Updates: []model.ProductTypeEntity{{ID:1,Name:"AA"}},
+			Deletes: []int{2},
+		}).Return(nil)
+		mockRepository.On("FindChildren", mock.Anything, 2).Return([]model.ProductTypeEntity{{ID:3,Name:"C",ParentID:&parentID}}, nil)
+
+		txManager := testutils.NewTxManagerMock(mockRepository)
+		service := service.NewProductTypeServiceImpl(mockRepository, txManager, testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeTransactional,
+			Operations: []model.ProductTypeBulkOperation{
+				{Op:"update",ID:1,Name:"AA"},
+				{Op:"delete",ID:2},
+			},
+		})
+
+		expectedBody := &model.ProductTypeBulkSummary{
+			Mode: model.BulkModeTransactional,
+			Failed: 2,
+			Results: []model.ProductTypeBulkResult{
+				{Index:0,Op:"update",ID:1,Status:424,Message:"Not applied because another operation failed"},
+				{Index:1,Op:"delete",ID:2,Status:409,Message:"ProductType has children"},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, summary)
+		assert.Empty(t, txManager.EventTypes())
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk best effort fail delete with children", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		parentID := 1
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2}).Return([]model.ProductTypeEntity{{ID:1,Name:"A"},{ID:2,Name:"B"}}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{{ID:3,Name:"C",ParentID:&parentID}}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 2).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Delete", mock.Anything, 2).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeBestEffort,
+			Operations: []model.ProductTypeBulkOperation{
+				{Op:"delete",ID:1},
+				{Op:"delete",ID:2},
+			},
+		})
+
+		expectedBody := &model.ProductTypeBulkSummary{
+			Mode: model.BulkModeBestEffort,
+			Succeeded: 1,
+			Failed: 1,
+			Results: []model.ProductTypeBulkResult{
+				{Index:0,Op:"delete",ID:1,Status:409,Message:"ProductType has children"},
+				{Index:1,Op:"delete",ID:2,Status:200},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, summary)
+		mockRepository.AssertNotCalled(t, "Delete", mock.Anything, 1)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk best effort partial success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 1, 2, 3}).Return([]model.ProductTypeEntity{{ID:2,Name:"B",DeletedAt:gorm.DeletedAt{Valid:true}}}, nil)
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:1,Name:"A"}).Return(nil)
+		mockRepository.On("Save", mock.Anything, &model.ProductTypeEntity{ID:3,Name:"C"}).Return(errs.NewInternalServerError("db down"))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeBestEffort,
+			Operations: []model.ProductTypeBulkOperation{
+				{Op:"create",ID:1,Name:"A"},
+				{Op:"update",ID:1,Name:"AA"},
+				{Op:"create",ID:2,Name:"B"},
+				{Op:"create",ID:3,Name:"C"},
+			},
+		})
+
+		expectedBody := &model.ProductTypeBulkSummary{
+			Mode: model.BulkModeBestEffort,
+			Succeeded: 1,
+			Failed: 3,
+			Results: []model.ProductTypeBulkResult{
+				{Index:0,Op:"create",ID:1,Status:201},
+				{Index:1,Op:"update",ID:1,Status:409,Message:"ProductType ID is duplicated in bulk request"},
+				{Index:2,Op:"create",ID:2,Status:409,Message:"ProductType with this ID is in trash"},
+				{Index:3,Op:"create",ID:3,Status:500,Message:"db down"},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, summary)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : bulk fail validate request", func(t *testing.T) {
+		valError := errs.ValErrorResponse{
+			Code: 400,
+			Message: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeBulkRequest.Mode",
+					Tag:        "oneof",
+					Value:      "transactional best_effort",
+				},
+			},
+		}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: "all",
+			Operations: []model.ProductTypeBulkOperation{{Op:"create",ID:1,Name:"A"}},
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, valError, err)
+		assert.Nil(t, summary)
+	})
+
+	t.Run("test case : bulk fail from repository lookup", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1}).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		summary, err := service.Bulk(context.Background(), &model.ProductTypeBulkRequest{
+			Mode: model.BulkModeBestEffort,
+			Operations: []model.ProductTypeBulkOperation{{Op:"create",ID:1,Name:"A"}},
+		})
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, summary)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestExport(t *testing.T) {
+	parentID := 1
+
+	t.Run("test case : export csv success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAllAfter", mock.Anything, 0, 500).Return([]model.ProductTypeEntity{{ID:1,Name:"A"},{ID:2,Name:"B",ParentID:&parentID}}, nil)
+
+		var buf bytes.Buffer
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		writeExport, err := service.Export(context.Background(), "csv")
+		assert.NoError(t, err)
+		err = writeExport(&buf)
+
+		expectedBody := "prodtype_id,prodtype_name,prodtype_parent_code\n1,A,\n2,B,1\n"
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, buf.String())
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : export reads next batch after full one", func(t *testing.T) {
+		fullBatch := make([]model.ProductTypeEntity, 500)
+		for i := range fullBatch {
+			fullBatch[i] = model.ProductTypeEntity{ID: i + 1, Name: "A"}
+		}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAllAfter", mock.Anything, 0, 500).Return(fullBatch, nil)
+		mockRepository.On("FindAllAfter", mock.Anything, 500, 500).Return([]model.ProductTypeEntity{{ID:501,Name:"B"}}, nil)
+
+		var buf bytes.Buffer
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		writeExport, err := service.Export(context.Background(), "csv")
+		assert.NoError(t, err)
+		err = writeExport(&buf)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(buf.String(), "500,A,\n501,B,\n"))
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : export fail unsupported format", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		writeExport, err := service.Export(context.Background(), "pdf")
+
+		expectedBody := errs.NewBadRequestError("Format pdf is not supported")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, writeExport)
+	})
+
+	t.Run("test case : export fail first batch from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAllAfter", mock.Anything, 0, 500).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		writeExport, err := service.Export(context.Background(), "csv")
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, writeExport)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : export fail next batch from repository", func(t *testing.T) {
+		fullBatch := make([]model.ProductTypeEntity, 500)
+		for i := range fullBatch {
+			fullBatch[i] = model.ProductTypeEntity{ID: i + 1, Name: "A"}
+		}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAllAfter", mock.Anything, 0, 500).Return(fullBatch, nil)
+		mockRepository.On("FindAllAfter", mock.Anything, 500, 500).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+
+		var buf bytes.Buffer
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		writeExport, err := service.Export(context.Background(), "csv")
+		assert.NoError(t, err)
+		err = writeExport(&buf)
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Run("test case : import upsert success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2}).Return([]model.ProductTypeEntity{{ID:2,Name:"B"}}, nil)
+		mockRepository.On("ApplyBatch", mock.Anything, &model.ProductTypeBatch{
+			Creates: []model.ProductTypeEntity{{ID:1,Name:"A"}},
+			Updates: []model.ProductTypeEntity{{ID:2,Name:"BB"}},
+		}).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"upsert",Mapping:map[string]string{"prodtype_id":"Code","prodtype_name":"Name"}},
+			strings.NewReader("Code,Name\n1,A\n2,BB\n"),
+		)
+
+		expectedBody := &model.ProductTypeImportReport{
+			Applied: true,
+			Created: 1,
+			Updated: 1,
+			Rows: []model.ProductTypeImportRow{
+				{Row:2,ID:1,Name:"A",Action:"create"},
+				{Row:3,ID:2,Name:"BB",Action:"update"},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, report)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : import skip dry run", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2}).Return([]model.ProductTypeEntity{{ID:2,Name:"B"}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"skip",DryRun:true},
+			strings.NewReader("prodtype_id,prodtype_name\n1,A\n2,BB\n"),
+		)
+
+		expectedBody := &model.ProductTypeImportReport{
+			DryRun: true,
+			Created: 1,
+			Skipped: 1,
+			Rows: []model.ProductTypeImportRow{
+				{Row:2,ID:1,Name:"A",Action:"create"},
+				{Row:3,ID:2,Name:"BB",Action:"skip"},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, report)
+		mockRepository.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : import invalid rows nothing applied", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1}).Return([]model.ProductTypeEntity{}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"skip"},
+			strings.NewReader("prodtype_id,prodtype_name\n1,A\nx,B\n1,C\n3,\n"),
+		)
+
+		expectedBody := &model.ProductTypeImportReport{
+			Created: 1,
+			Invalid: 3,
+			Rows: []model.ProductTypeImportRow{
+				{Row:2,ID:1,Name:"A",Action:"create"},
+				{Row:3,Name:"B",Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.ID",Tag:"numeric",Value:"x"}}},
+				{Row:4,ID:1,Name:"C",Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.ID",Tag:"unique",Value:""}}},
+				{Row:5,ID:3,Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.Name",Tag:"required",Value:""}}},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, report)
+		mockRepository.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : import with parent success", func(t *testing.T) {
+		food, snack, other := 1, 2, 9
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{2, 1, 3, 9}).Return([]model.ProductTypeEntity{{ID:3,Name:"C"},{ID:9,Name:"I"}}, nil).Once()
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{9}).Return([]model.ProductTypeEntity{{ID:9,Name:"I"}}, nil).Once()
+		mockRepository.On("ApplyBatch", mock.Anything, &model.ProductTypeBatch{
+			Creates: []model.ProductTypeEntity{{ID:1,Name:"A"},{ID:2,Name:"B",ParentID:&food}},
+			Updates: []model.ProductTypeEntity{{ID:3,Name:"C"}},
+		}).Return(nil)
+		mockRepository.On("Move", mock.Anything, 3, &other).Return(nil)
+		mockRepository.On("FindAncestors", mock.Anything, food).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, other).Return([]model.ProductTypeEntity{}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"upsert"},
+			strings.NewReader("prodtype_id,prodtype_name,prodtype_parent_code\n2,B,1\n1,A,\n3,C,9\n"),
+		)
+
+		expectedBody := &model.ProductTypeImportReport{
+			Applied: true,
+			Created: 2,
+			Updated: 1,
+			Rows: []model.ProductTypeImportRow{
+				{Row:2,ID:snack,Name:"B",ParentID:&food,Action:"create"},
+				{Row:3,ID:food,Name:"A",Action:"create"},
+				{Row:4,ID:3,Name:"C",ParentID:&other,Action:"update"},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, report)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : import invalid parents nothing applied", func(t *testing.T) {
+		self, trashed := 2, 8
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{3, 8}).Return([]model.ProductTypeEntity{{ID:8,Name:"H",DeletedAt:gorm.DeletedAt{Time:time.Now(),Valid:true}}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"upsert"},
+			strings.NewReader("prodtype_id,prodtype_name,prodtype_parent_code\n1,A,x\n2,B,2\n3,C,8\n"),
+		)
+
+		expectedBody := &model.ProductTypeImportReport{
+			Invalid: 3,
+			Rows: []model.ProductTypeImportRow{
+				{Row:2,ID:1,Name:"A",Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.ParentID",Tag:"numeric",Value:"x"}}},
+				{Row:3,ID:2,Name:"B",ParentID:&self,Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.ParentID",Tag:"nefield",Value:"ID"}}},
+				{Row:4,ID:3,Name:"C",ParentID:&trashed,Errors:[]errs.ErrorMessage{{FailedField:"ProductTypeCreate.ParentID",Tag:"exists",Value:""}}},
+			},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, report)
+		mockRepository.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : import fail parent under descendant", func(t *testing.T) {
+		food := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1, 2}).Return([]model.ProductTypeEntity{{ID:1,Name:"A"}}, nil)
+		mockRepository.On("ApplyBatch", mock.Anything, &model.ProductTypeBatch{
+			Creates: []model.ProductTypeEntity{{ID:2,Name:"B",ParentID:&food}},
+			Updates: []model.ProductTypeEntity{{ID:1,Name:"A"}},
+		}).Return(nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("Move", mock.Anything, 1, mock.Anything).Return(nil)
+		mockRepository.On("FindAncestors", mock.Anything, 1).Return([]model.ProductTypeEntity{{ID:2,Name:"B"}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"upsert"},
+			strings.NewReader("prodtype_id,prodtype_name,prodtype_parent_code\n1,A,2\n2,B,1\n"),
+		)
+
+		expectedBody := errs.NewConflictError("ProductType can not be moved under its descendant")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, report)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : import fail column not found", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"skip"},
+			strings.NewReader("id,name\n1,A\n"),
+		)
+
+		expectedBody := errs.NewBadRequestError("Column prodtype_id is not found in file")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, report)
+	})
+
+	t.Run("test case : import fail validate options", func(t *testing.T) {
+		valError := errs.ValErrorResponse{
+			Code: 400,
+			Message: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeImportOptions.OnConflict",
+					Tag:        "oneof",
+					Value:      "upsert skip",
+				},
+			},
+		}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"csv",OnConflict:"replace"},
+			strings.NewReader(""),
+		)
+
+		assert.Error(t, err)
+		assert.Equal(t, valError, err)
+		assert.Nil(t, report)
+	})
+
+	t.Run("test case : import fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByIDsUnscoped", mock.Anything, []int{1}).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("ApplyBatch", mock.Anything, mock.Anything).Return(errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		report, err := service.Import(context.Background(), 
+			&model.ProductTypeImportOptions{Format:"jsonl",OnConflict:"skip"},
+			strings.NewReader(`{"prodtype_id":1,"prodtype_name":"A"}` + "\n"),
+		)
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, report)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestFindTree(t *testing.T) {
+	t.Run("test case : find tree success", func(t *testing.T) {
+		food, snack, orphan := 1, 2, 9
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAll", mock.Anything).Return([]model.ProductTypeEntity{
+			{ID:1,Name:"Food"},
+			{ID:2,Name:"Snack",ParentID:&food},
+			{ID:3,Name:"Chips",ParentID:&snack},
+			{ID:4,Name:"Drink"},
+			{ID:5,Name:"Lost",ParentID:&orphan},
+		}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		tree, err := service.FindTree(context.Background())
+
+		expectedBody := []*model.ProductTypeNode{
+			{ID:1,Name:"Food",Children:[]*model.ProductTypeNode{
+				{ID:2,Name:"Snack",ParentID:&food,Children:[]*model.ProductTypeNode{
+					{ID:3,Name:"Chips",ParentID:&snack,Children:[]*model.ProductTypeNode{}},
+				}},
+			}},
+			{ID:4,Name:"Drink",Children:[]*model.ProductTypeNode{}},
+			{ID:5,Name:"Lost",ParentID:&orphan,Children:[]*model.ProductTypeNode{}},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, tree)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find tree fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindAll", mock.Anything).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		tree, err := service.FindTree(context.Background())
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, tree)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestFindChildren(t *testing.T) {
+	t.Run("test case : find children success", func(t *testing.T) {
+		parentID := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"Food"}, nil)
+		mockRepository.On("FindChildren", mock.Anything, 1).Return([]model.ProductTypeEntity{{ID:2,Name:"Snack",ParentID:&parentID}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		children, err := service.FindChildren(context.Background(), 1)
+
+		expectedBody := []model.ProductType{{ID:2,Name:"Snack",ParentID:&parentID}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, children)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find children fail not found", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		children, err := service.FindChildren(context.Background(), 1)
+
+		expectedBody := errs.NewNotFoundError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, children)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestFindAncestors(t *testing.T) {
+	t.Run("test case : find ancestors success", func(t *testing.T) {
+		parentID := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 3).Return(&model.ProductTypeEntity{ID:3,Name:"Chips"}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 3).Return([]model.ProductTypeEntity{{ID:1,Name:"Food"},{ID:2,Name:"Snack",ParentID:&parentID}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		ancestors, err := service.FindAncestors(context.Background(), 3)
+
+		expectedBody := []model.ProductType{{ID:1,Name:"Food"},{ID:2,Name:"Snack",ParentID:&parentID}}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, ancestors)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find ancestors fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 3).Return(&model.ProductTypeEntity{ID:3,Name:"Chips"}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 3).Return([]model.ProductTypeEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		ancestors, err := service.FindAncestors(context.Background(), 3)
+
+		expectedBody := errs.NewInternalServerError("")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, ancestors)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestMove(t *testing.T) {
+	t.Run("test case : move success", func(t *testing.T) {
+		parentID := 4
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snack"}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 4).Return(&model.ProductTypeEntity{ID:4,Name:"Drink"}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 4).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Move", mock.Anything, 2, &parentID).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 2, &model.ProductTypeMove{ParentID:&parentID})
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : move to root success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snack"}, nil)
+		mockRepository.On("Move", mock.Anything, 2, (*int)(nil)).Return(nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 2, &model.ProductTypeMove{})
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : move fail under itself", func(t *testing.T) {
+		parentID := 2
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snack"}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 2, &model.ProductTypeMove{ParentID:&parentID})
+
+		expectedBody := errs.NewConflictError("ProductType can not be moved under itself")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : move fail under descendant", func(t *testing.T) {
+		parentID, snack := 3, 2
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"Food"}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 3).Return(&model.ProductTypeEntity{ID:3,Name:"Chips",ParentID:&snack}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 3).Return([]model.ProductTypeEntity{{ID:1,Name:"Food"},{ID:2,Name:"Snack"}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 1, &model.ProductTypeMove{ParentID:&parentID})
+
+		expectedBody := errs.NewConflictError("ProductType can not be moved under its descendant")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : move fail parent chain too deep", func(t *testing.T) {
+		parentID := 100
+		ancestors := make([]model.ProductTypeEntity, repository.ProductTypeMaxDepth)
+		for i := range ancestors {
+			ancestors[i] = model.ProductTypeEntity{ID: 200 + i, Name: "Level"}
+		}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snack"}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 100).Return(&model.ProductTypeEntity{ID:100,Name:"Deep"}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 100).Return(ancestors, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 2, &model.ProductTypeMove{ParentID:&parentID})
+
+		expectedBody := errs.NewConflictError("ProductType hierarchy is too deep")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : move fail parent not found", func(t *testing.T) {
+		parentID := 9
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snack"}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 9).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 2, &model.ProductTypeMove{ParentID:&parentID})
+
+		expectedBody := errs.NewBadRequestError("Parent ProductType is not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : move fail validate", func(t *testing.T) {
+		parentID := -1
+		valError := errs.ValErrorResponse{
+			Code: 400,
+			Message: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeMove.ParentID",
+					Tag:        "gte",
+					Value:      "0",
+				},
+			},
+		}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Move(context.Background(), 2, &model.ProductTypeMove{ParentID:&parentID})
+
+		assert.Error(t, err)
+		assert.Equal(t, valError, err)
+	})
+}
+
+func TestFindByIDAsOf(t *testing.T) {
+	asOf := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : find by id as of success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindRevisionAsOf", mock.Anything, 1, asOf).Return(&model.ProductTypeRevisionEntity{ProductTypeID:1,Revision:1,Name:"A"}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodType, err := service.FindByIDAsOf(context.Background(), 1, asOf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.ProductType{ID:1,Name:"A"}, prodType)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find by id as of fail deleted at that time", func(t *testing.T) {
+		deletedAt := asOf.Add(-time.Hour)
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindRevisionAsOf", mock.Anything, 1, asOf).Return(&model.ProductTypeRevisionEntity{ProductTypeID:1,Revision:2,Name:"A",DeletedAt:&deletedAt}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodType, err := service.FindByIDAsOf(context.Background(), 1, asOf)
+
+		expectedBody := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, prodType)
+	})
+
+	t.Run("test case : find by id as of fail before first revision", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindRevisionAsOf", mock.Anything, 1, asOf).Return(&model.ProductTypeRevisionEntity{}, errs.NewNotFoundError("record not found"))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		prodType, err := service.FindByIDAsOf(context.Background(), 1, asOf)
+
+		expectedBody := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, prodType)
+	})
+}
+
+func TestFindRevisions(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : find revisions success", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindRevisions", mock.Anything, 1).Return([]model.ProductTypeRevisionEntity{
+			{ID:2,ProductTypeID:1,Revision:2,Name:"B",Operation:"update",CreatedAt:createdAt},
+			{ID:1,ProductTypeID:1,Revision:1,Name:"A",Operation:"create",CreatedAt:createdAt},
+		}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		revisions, err := service.FindRevisions(context.Background(), 1)
+
+		expectedBody := []model.ProductTypeRevision{
+			{ID:1,Revision:2,Name:"B",Operation:"update",CreatedAt:createdAt},
+			{ID:1,Revision:1,Name:"A",Operation:"create",CreatedAt:createdAt},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, revisions)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find revisions fail not found", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindRevisions", mock.Anything, 9).Return([]model.ProductTypeRevisionEntity{}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		revisions, err := service.FindRevisions(context.Background(), 9)
+
+		expectedBody := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, revisions)
+	})
+
+	t.Run("test case : find revisions fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindRevisions", mock.Anything, 1).Return([]model.ProductTypeRevisionEntity(nil), errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		revisions, err := service.FindRevisions(context.Background(), 1)
+
+		assert.Error(t, err)
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		assert.Nil(t, revisions)
+	})
+}
+
+func TestRevert(t *testing.T) {
+	t.Run("test case : revert success", func(t *testing.T) {
+		parentID := 4
+		revisionEntity := &model.ProductTypeRevisionEntity{ProductTypeID:2,Revision:1,Name:"Snack",ParentID:&parentID}
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snacks"}, nil)
+		mockRepository.On("FindRevision", mock.Anything, 2, 1).Return(revisionEntity, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 4).Return(&model.ProductTypeEntity{ID:4,Name:"Food"}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 4).Return([]model.ProductTypeEntity{}, nil)
+		mockRepository.On("Revert", mock.Anything, revisionEntity).Return(nil)
+
+		auditor := testutils.NewAuditRecorderMock()
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), auditor, testutils.NewBrokerMock())
+		err := service.Revert(context.Background(), 2, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.AuditActionRevert}, auditor.Actions())
+		assert.JSONEq(t, `{"prodtype_id":2,"prodtype_name":"Snacks"}`, *auditor.Entries[0].Before)
+		assert.JSONEq(t, `{"prodtype_id":2,"prodtype_name":"Snack","prodtype_parent_id":4}`, *auditor.Entries[0].After)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : revert fail revision not found", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snacks"}, nil)
+		mockRepository.On("FindRevision", mock.Anything, 2, 9).Return(&model.ProductTypeRevisionEntity{}, errs.NewNotFoundError("record not found"))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Revert(context.Background(), 2, 9)
+
+		expectedBody := errs.NewNotFoundError("record not found")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Revert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("test case : revert fail parent not available", func(t *testing.T) {
+		parentID := 4
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 2).Return(&model.ProductTypeEntity{ID:2,Name:"Snacks"}, nil)
+		mockRepository.On("FindRevision", mock.Anything, 2, 1).Return(&model.ProductTypeRevisionEntity{ProductTypeID:2,Revision:1,Name:"Snack",ParentID:&parentID}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 4).Return(&model.ProductTypeEntity{}, errs.NewNotFoundError("record not found"))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Revert(context.Background(), 2, 1)
+
+		expectedBody := errs.NewConflictError("Parent ProductType is not available")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Revert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("test case : revert fail parent is now a descendant", func(t *testing.T) {
+		parentID := 3
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.ProductTypeEntity{ID:1,Name:"Food"}, nil)
+		mockRepository.On("FindRevision", mock.Anything, 1, 1).Return(&model.ProductTypeRevisionEntity{ProductTypeID:1,Revision:1,Name:"Food",ParentID:&parentID}, nil)
+		mockRepository.On("LockChanges", mock.Anything).Return(nil)
+		mockRepository.On("FindByID", mock.Anything, 3).Return(&model.ProductTypeEntity{ID:3,Name:"Chips"}, nil)
+		mockRepository.On("FindAncestors", mock.Anything, 3).Return([]model.ProductTypeEntity{{ID:1,Name:"Food"}}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		err := service.Revert(context.Background(), 1, 1)
+
+		expectedBody := errs.NewConflictError("ProductType can not be moved under its descendant")
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		mockRepository.AssertNotCalled(t, "Revert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFindChanges(t *testing.T) {
+	changedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("test case : find changes success with tombstone", func(t *testing.T) {
+		parentID := 1
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindChanges", mock.Anything, int64(10), 101).Return([]model.ProductTypeRevisionEntity{
+			{ID:11,ProductTypeID:3,Revision:1,Name:"Chips",ParentID:&parentID,Operation:"create",CreatedAt:changedAt},
+			{ID:12,ProductTypeID:3,Revision:2,Name:"Crisps",ParentID:&parentID,Operation:"update",CreatedAt:changedAt},
+			{ID:13,ProductTypeID:3,Revision:3,Name:"Crisps",ParentID:&parentID,DeletedAt:&changedAt,Operation:"delete",CreatedAt:changedAt},
+			{ID:14,ProductTypeID:3,Revision:4,Name:"Crisps",ParentID:&parentID,Operation:"restore",CreatedAt:changedAt},
+		}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Since:10})
+
+		expectedBody := &model.ProductTypeChangeFeed{
+			Changes: []model.ProductTypeChange{
+				{Seq:11,Op:"insert",ID:3,Name:"Chips",ParentID:&parentID,ChangedAt:changedAt},
+				{Seq:12,Op:"update",ID:3,Name:"Crisps",ParentID:&parentID,ChangedAt:changedAt},
+				{Seq:13,Op:"delete",ID:3,ChangedAt:changedAt},
+				{Seq:14,Op:"insert",ID:3,Name:"Crisps",ParentID:&parentID,ChangedAt:changedAt},
+			},
+			Cursor: 	14,
+			HasMore: 	false,
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, changeFeed)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : find changes success has more", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindChanges", mock.Anything, int64(0), 3).Return([]model.ProductTypeRevisionEntity{
+			{ID:1,ProductTypeID:1,Name:"A",Operation:"create"},
+			{ID:2,ProductTypeID:2,Name:"B",Operation:"create"},
+			{ID:3,ProductTypeID:3,Name:"C",Operation:"create"},
+		}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Limit:2})
+
+		assert.NoError(t, err)
+		assert.Len(t, changeFeed.Changes, 2)
+		assert.Equal(t, int64(2), changeFeed.Cursor)
+		assert.True(t, changeFeed.HasMore)
+	})
+
+	t.Run("test case : find changes success keeps cursor when nothing changed", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindChanges", mock.Anything, int64(42), 101).Return([]model.ProductTypeRevisionEntity{}, nil)
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Since:42})
+
+		expectedBody := &model.ProductTypeChangeFeed{Changes: []model.ProductTypeChange{}, Cursor: 42}
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, changeFeed)
+	})
+
+	t.Run("test case : find changes fail validate", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{Since:-1})
+
+		expectedBody := errs.ValErrorResponse{
+			Code: 400,
+			Message: []errs.ErrorMessage{
+				{
+					FailedField: "ProductTypeChangeQuery.Since",
+					Tag:        "gte",
+					Value:      "0",
+				},
+			},
+		}
+		assert.Error(t, err)
+		assert.Equal(t, expectedBody, err)
+		assert.Nil(t, changeFeed)
+		mockRepository.AssertNotCalled(t, "FindChanges", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("test case : find changes fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewProductTypeRepositoryMock()
+		mockRepository.On("FindChanges", mock.Anything, int64(0), 101).Return([]model.ProductTypeRevisionEntity(nil), errs.NewInternalServerError(""))
+
+		service := service.NewProductTypeServiceImpl(mockRepository, testutils.NewTxManagerMock(mockRepository), testutils.NewAuditRecorderMock(), testutils.NewBrokerMock())
+		changeFeed, err := service.FindChanges(context.Background(), &model.ProductTypeChangeQuery{})
+
+		assert.Error(t, err)
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		assert.Nil(t, changeFeed)
+	})
+}
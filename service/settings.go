@@ -0,0 +1,13 @@
+package service
+
+import (
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"context"
+)
+
+// SettingsService reads and changes the settings the server applies without a restart.
+type SettingsService interface {
+	FindAll(ctx context.Context) (model.Settings, error)
+	Update(ctx context.Context, changes model.Settings) (model.Settings, error)
+}
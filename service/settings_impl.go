@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+)
+
+// settingsResourceID is the resource id of the audit entries, there is one set of settings.
+const settingsResourceID = "runtime"
+
+type SettingsServiceImpl struct {
+	Auditor AuditRecorder
+}
+
+func NewSettingsServiceImpl(Auditor AuditRecorder) SettingsService {
+	return &SettingsServiceImpl{
+		Auditor: Auditor,
+	}
+}
+
+func (s *SettingsServiceImpl) FindAll(ctx context.Context) (model.Settings, error) {
+	ctx, span := tracing.Start(ctx, "SettingsService.FindAll")
+	defer span.End()
+
+	configData, err := config.GetConfig()
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "Service: Get Settings Successfully")
+	return configData.Reloadable(), nil
+}
+
+// Update applies changes to the running server, all of them or none. A setting that is only read
+// at startup is refused with the reason.
+func (s *SettingsServiceImpl) Update(ctx context.Context, changes model.Settings) (model.Settings, error) {
+	ctx, span := tracing.Start(ctx, "SettingsService.Update")
+	defer span.End()
+
+	if len(changes) == 0 {
+		logger.Error(ctx, "No setting to change")
+		return nil, errs.NewBadRequestError("No setting to change")
+	}
+
+	previous, err := config.GetConfig()
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	next, err := config.Update(changes)
+	if err != nil {
+		logger.Error(ctx, err)
+		var validationError config.ValidationError
+		if errors.As(err, &validationError) {
+			return nil, errs.NewBadRequestError(err.Error())
+		}
+		return nil, err
+	}
+	s.Auditor.Record(ctx, newAuditEntry(ctx, model.AuditActionUpdate, model.AuditResourceSettings, settingsResourceID, previous.Reloadable(), next.Reloadable()))
+
+	logger.Info(ctx, "Service: Update Settings Successfully", "keys", len(changes))
+	return next.Reloadable(), nil
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"github.com/Yoshikrit/fiber-test/config"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSettingsService(t *testing.T) (service.SettingsService, *testutils.AuditRecorderMock) {
+	require.NoError(t, config.LoadConfig(config.Options{Overrides: map[string]string{
+		"POSTGRES_HOST": 	"db",
+		"POSTGRES_USER": 	"user",
+		"POSTGRES_DB": 		"fiber",
+		"JWT_SECRET_KEY": 	strings.Repeat("k", config.JWTSecretMinLength),
+	}}))
+	auditor := testutils.NewAuditRecorderMock()
+	return service.NewSettingsServiceImpl(auditor), auditor
+}
+
+func TestSettingsUpdate(t *testing.T) {
+	t.Run("test case : update settings success", func(t *testing.T) {
+		settingsService, auditor := setupSettingsService(t)
+
+		settings, err := settingsService.Update(context.Background(), model.Settings{"JWT_ACCESS_EXPIRES": "300"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "300", settings["JWT_ACCESS_EXPIRES"])
+		configData, _ := config.GetConfig()
+		assert.Equal(t, 300, configData.JWTAccessExpires)
+		assert.Equal(t, []string{model.AuditActionUpdate}, auditor.Actions())
+		assert.Contains(t, *auditor.Entries[0].Before, `"JWT_ACCESS_EXPIRES":"900"`)
+		assert.Contains(t, *auditor.Entries[0].After, `"JWT_ACCESS_EXPIRES":"300"`)
+	})
+
+	t.Run("test case : update settings fail not reloadable", func(t *testing.T) {
+		settingsService, auditor := setupSettingsService(t)
+
+		_, err := settingsService.Update(context.Background(), model.Settings{"POSTGRES_HOST": "other"})
+
+		assert.IsType(t, errs.ErrorResponse{}, err)
+		assert.Contains(t, err.Error(), "POSTGRES_HOST is only read at startup")
+		assert.Empty(t, auditor.Actions())
+	})
+
+	t.Run("test case : update settings fail nothing to change", func(t *testing.T) {
+		settingsService, _ := setupSettingsService(t)
+
+		_, err := settingsService.Update(context.Background(), model.Settings{})
+
+		assert.Equal(t, errs.NewBadRequestError("No setting to change"), err)
+	})
+}
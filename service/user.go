@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+)
+
+// UserService administers accounts from the command line, its audit entries have no actor.
+type UserService interface {
+	SetRole(ctx context.Context, id int, roleTitle string) error
+	ResetPassword(ctx context.Context, id int, password string) error
+	Disable(ctx context.Context, id int) error
+	RevokeTokens(ctx context.Context, id int) (int64, error)
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/replica"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper"
+)
+
+const passwordMaxLength = 255
+
+type UserServiceImpl struct {
+	UserRepo repository.UserRepository
+	RoleRepo repository.RoleRepository
+	TxManager repository.TxManager
+	Auditor AuditRecorder
+}
+
+func NewUserServiceImpl(UserRepo repository.UserRepository, RoleRepo repository.RoleRepository, TxManager repository.TxManager, Auditor AuditRecorder) UserService {
+	return &UserServiceImpl{
+		UserRepo: UserRepo,
+		RoleRepo: RoleRepo,
+		TxManager: TxManager,
+		Auditor: Auditor,
+	}
+}
+
+// SetRole moves the user to the role titled roleTitle and revokes their sessions,
+// the tokens carry the role so it only applies from the next login.
+func (s *UserServiceImpl) SetRole(ctx context.Context, id int, roleTitle string) error {
+	ctx, span := tracing.Start(ctx, "UserService.SetRole")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	roleEntity, err := s.RoleRepo.FindByTitle(ctx, roleTitle)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	var before, after *model.UserDTO
+	err = s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		userEntity, err := tx.Users().FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before = toAuditUser(userEntity)
+
+		userEntity.RoleID = roleEntity.ID
+		if err := tx.Users().Update(ctx, userEntity); err != nil {
+			return err
+		}
+		after = toAuditUser(userEntity)
+
+		_, err = tx.Oauths().DeleteByUserID(ctx, id)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+	s.audit(ctx, model.AuditActionSetRole, id, before, after)
+
+	logger.Info(ctx, "Service: Set User Role Successfully")
+	return nil
+}
+
+// ResetPassword replaces the password of the user and revokes their sessions.
+func (s *UserServiceImpl) ResetPassword(ctx context.Context, id int, password string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if password == "" || len(password) > passwordMaxLength {
+		logger.Error(ctx, "Password is not valid")
+		return errs.NewBadRequestError("Password must be 1 to " + strconv.Itoa(passwordMaxLength) + " characters")
+	}
+
+	hashedPassword, err := helper.HashPassword(password)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	err = s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		userEntity, err := tx.Users().FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		userEntity.Password = string(hashedPassword)
+		if err := tx.Users().Update(ctx, userEntity); err != nil {
+			return err
+		}
+
+		_, err = tx.Oauths().DeleteByUserID(ctx, id)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+	s.audit(ctx, model.AuditActionResetPassword, id, nil, nil)
+
+	logger.Info(ctx, "Service: Reset User Password Successfully")
+	return nil
+}
+
+// Disable stops the user from logging in and revokes their sessions, disabling twice keeps the first date.
+func (s *UserServiceImpl) Disable(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "UserService.Disable")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	err := s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		userEntity, err := tx.Users().FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if userEntity.DisabledAt == nil {
+			disabledAt := time.Now()
+			userEntity.DisabledAt = &disabledAt
+			if err := tx.Users().Update(ctx, userEntity); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Oauths().DeleteByUserID(ctx, id)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+	s.audit(ctx, model.AuditActionDisable, id, nil, nil)
+
+	logger.Info(ctx, "Service: Disable User Successfully")
+	return nil
+}
+
+// RevokeTokens logs the user out of every session and returns how many were revoked.
+func (s *UserServiceImpl) RevokeTokens(ctx context.Context, id int) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UserService.RevokeTokens")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	var revoked int64
+	err := s.TxManager.Do(ctx, func(tx repository.Tx) error {
+		if _, err := tx.Users().FindByID(ctx, id); err != nil {
+			return err
+		}
+
+		count, err := tx.Oauths().DeleteByUserID(ctx, id)
+		revoked = count
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return 0, err
+	}
+	s.audit(ctx, model.AuditActionRevoke, id, nil, nil)
+
+	logger.Info(ctx, "Service: Revoke User Tokens Successfully", "revoked", revoked)
+	return revoked, nil
+}
+
+func (s *UserServiceImpl) audit(ctx context.Context, action string, id int, before, after interface{}) {
+	s.Auditor.Record(ctx, newAuditEntry(ctx, action, model.AuditResourceUser, strconv.Itoa(id), before, after))
+}
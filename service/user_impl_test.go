@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupUserService() (service.UserService, *testutils.UserRepositoryMock, *testutils.RoleRepositoryMock, *testutils.OauthRepositoryMock, *testutils.AuditRecorderMock) {
+	userRepo, roleRepo, oauthRepo := testutils.NewUserRepositoryMock(), testutils.NewRoleRepositoryMock(), testutils.NewOauthRepositoryMock()
+	txManager := testutils.NewTxManagerMock(testutils.NewProductTypeRepositoryMock())
+	txManager.UserRepo, txManager.RoleRepo, txManager.OauthRepo = userRepo, roleRepo, oauthRepo
+	auditor := testutils.NewAuditRecorderMock()
+	return service.NewUserServiceImpl(userRepo, roleRepo, txManager, auditor), userRepo, roleRepo, oauthRepo, auditor
+}
+
+func TestSetRole(t *testing.T) {
+	t.Run("test case : set role success", func(t *testing.T) {
+		userService, userRepo, roleRepo, oauthRepo, auditor := setupUserService()
+		roleRepo.On("FindByTitle", mock.Anything, "Admin").Return(&model.RoleEntity{ID:2,Title:"Admin"}, nil)
+		userRepo.On("FindByID", mock.Anything, 1).Return(&model.UserEntity{ID:1,RoleID:1,Name:"A"}, nil)
+		userRepo.On("Update", mock.Anything, &model.UserEntity{ID:1,RoleID:2,Name:"A"}).Return(nil)
+		oauthRepo.On("DeleteByUserID", mock.Anything, 1).Return(int64(2), nil)
+
+		err := userService.SetRole(context.Background(), 1, "Admin")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.AuditActionSetRole}, auditor.Actions())
+		assert.Nil(t, auditor.Entries[0].ActorID)
+		assert.JSONEq(t, `{"ID":1,"RoleID":1,"Name":"A","Email":""}`, *auditor.Entries[0].Before)
+		assert.JSONEq(t, `{"ID":1,"RoleID":2,"Name":"A","Email":""}`, *auditor.Entries[0].After)
+		userRepo.AssertExpectations(t)
+		oauthRepo.AssertExpectations(t)
+	})
+
+	t.Run("test case : set role fail role not found", func(t *testing.T) {
+		userService, userRepo, roleRepo, _, auditor := setupUserService()
+		roleRepo.On("FindByTitle", mock.Anything, "Owner").Return(&model.RoleEntity{}, errs.NewNotFoundError("record not found"))
+
+		err := userService.SetRole(context.Background(), 1, "Owner")
+
+		assert.Equal(t, errs.NewNotFoundError("record not found"), err)
+		assert.Empty(t, auditor.Actions())
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	t.Run("test case : reset password success", func(t *testing.T) {
+		userService, userRepo, _, oauthRepo, auditor := setupUserService()
+		userRepo.On("FindByID", mock.Anything, 1).Return(&model.UserEntity{ID:1,Password:"old"}, nil)
+		userRepo.On("Update", mock.Anything, mock.MatchedBy(func(userEntity *model.UserEntity) bool {
+			return helper.CompareHashAndPassword([]byte(userEntity.Password), []byte("new-password")) == nil
+		})).Return(nil)
+		oauthRepo.On("DeleteByUserID", mock.Anything, 1).Return(int64(1), nil)
+
+		err := userService.ResetPassword(context.Background(), 1, "new-password")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.AuditActionResetPassword}, auditor.Actions())
+		assert.Nil(t, auditor.Entries[0].After)
+		userRepo.AssertExpectations(t)
+		oauthRepo.AssertExpectations(t)
+	})
+
+	t.Run("test case : reset password fail empty", func(t *testing.T) {
+		userService, userRepo, _, _, _ := setupUserService()
+
+		err := userService.ResetPassword(context.Background(), 1, "")
+
+		assert.Equal(t, errs.NewBadRequestError("Password must be 1 to 255 characters"), err)
+		userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDisable(t *testing.T) {
+	t.Run("test case : disable success", func(t *testing.T) {
+		userService, userRepo, _, oauthRepo, auditor := setupUserService()
+		userRepo.On("FindByID", mock.Anything, 1).Return(&model.UserEntity{ID:1}, nil)
+		userRepo.On("Update", mock.Anything, mock.MatchedBy(func(userEntity *model.UserEntity) bool {
+			return userEntity.DisabledAt != nil
+		})).Return(nil)
+		oauthRepo.On("DeleteByUserID", mock.Anything, 1).Return(int64(3), nil)
+
+		err := userService.Disable(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.AuditActionDisable}, auditor.Actions())
+		userRepo.AssertExpectations(t)
+		oauthRepo.AssertExpectations(t)
+	})
+
+	t.Run("test case : disable already disabled keeps the date", func(t *testing.T) {
+		disabledAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		userService, userRepo, _, oauthRepo, _ := setupUserService()
+		userRepo.On("FindByID", mock.Anything, 1).Return(&model.UserEntity{ID:1,DisabledAt:&disabledAt}, nil)
+		oauthRepo.On("DeleteByUserID", mock.Anything, 1).Return(int64(0), nil)
+
+		err := userService.Disable(context.Background(), 1)
+
+		assert.NoError(t, err)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRevokeTokens(t *testing.T) {
+	t.Run("test case : revoke success", func(t *testing.T) {
+		userService, userRepo, _, oauthRepo, auditor := setupUserService()
+		userRepo.On("FindByID", mock.Anything, 1).Return(&model.UserEntity{ID:1}, nil)
+		oauthRepo.On("DeleteByUserID", mock.Anything, 1).Return(int64(2), nil)
+
+		revoked, err := userService.RevokeTokens(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), revoked)
+		assert.Equal(t, []string{model.AuditActionRevoke}, auditor.Actions())
+	})
+
+	t.Run("test case : revoke fail user not found", func(t *testing.T) {
+		userService, userRepo, _, oauthRepo, auditor := setupUserService()
+		userRepo.On("FindByID", mock.Anything, 9).Return(&model.UserEntity{}, errs.NewNotFoundError("record not found"))
+
+		_, err := userService.RevokeTokens(context.Background(), 9)
+
+		assert.Equal(t, errs.NewNotFoundError("record not found"), err)
+		assert.Empty(t, auditor.Actions())
+		oauthRepo.AssertNotCalled(t, "DeleteByUserID", mock.Anything, mock.Anything)
+	})
+}
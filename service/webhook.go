@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+)
+
+// WebhookEnqueuer is what events for webhooks are handed to, eventID is sent as X-Webhook-Delivery
+// so a receiver can drop an event it has already seen.
+type WebhookEnqueuer interface {
+	Enqueue(ctx context.Context, eventID string, eventType string, data interface{}) error
+}
+
+type WebhookService interface {
+	WebhookEnqueuer
+	Create(context.Context, *model.WebhookCreate) (*model.Webhook, error)
+	FindAll(ctx context.Context) ([]model.Webhook, error)
+	FindByID(ctx context.Context, id int) (*model.Webhook, error)
+	Update(ctx context.Context, id int, webhookUpdateReq *model.WebhookUpdate) error
+	Delete(ctx context.Context, id int) error
+	FindDeliveries(ctx context.Context, id int, filter *model.WebhookDeliveryFilter) (*model.WebhookDeliveryPage, error)
+	Redeliver(ctx context.Context, id int, deliveryID int64) error
+	DeliverDue(ctx context.Context) (int, error)
+}
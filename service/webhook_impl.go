@@ -0,0 +1,464 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+	"github.com/Yoshikrit/fiber-test/tracing"
+	"github.com/Yoshikrit/fiber-test/replica"
+	"github.com/Yoshikrit/fiber-test/helper"
+
+	"github.com/goccy/go-json"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	webhookDefaultTimeout 		= 10 * time.Second
+	webhookDefaultMaxAttempts 	= 8
+	webhookDefaultBackoff 		= 10 * time.Second
+	webhookMaxBackoff 			= time.Hour
+	// webhookClaimSlack is added to the request timeout for how long a claimed delivery is kept from other workers.
+	webhookClaimSlack 			= 30 * time.Second
+
+	webhookDeliveryDefaultLimit = 50
+	webhookDeliveryBatchSize 	= 100
+	webhookLastErrorSize 		= 1024
+	webhookResponseReadLimit 	= 64 << 10
+)
+
+type WebhookServiceImpl struct {
+	WebhookRepo repository.WebhookRepository
+	Client 		*http.Client
+	MaxAttempts int
+	Backoff 	time.Duration
+}
+
+// NewWebhookServiceImpl gives every attempt timeout to respond, retries a failed delivery after backoff,
+// doubling it on every attempt up to an hour, and dead-letters it after maxAttempts. Zero values take the defaults.
+func NewWebhookServiceImpl(webhookRepo repository.WebhookRepository, timeout time.Duration, maxAttempts int, backoff time.Duration) WebhookService {
+	if timeout <= 0 {
+		timeout = webhookDefaultTimeout
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = webhookDefaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = webhookDefaultBackoff
+	}
+	return &WebhookServiceImpl{
+		WebhookRepo: 	webhookRepo,
+		Client: 		&http.Client{Timeout: timeout},
+		MaxAttempts: 	maxAttempts,
+		Backoff: 		backoff,
+	}
+}
+
+func (s *WebhookServiceImpl) Create(ctx context.Context, webhookCreateReq *model.WebhookCreate) (*model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Create")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if err := helper.ValidateWebhookCreate(webhookCreateReq); err != nil {
+		logger.Error(ctx, "Webhook data is not valid")
+		return nil, errs.NewValidateBadRequestError(err)
+	}
+
+	now := time.Now()
+	webhookEntity := &model.WebhookEntity{
+		URL: 		webhookCreateReq.URL,
+		Events: 	strings.Join(webhookCreateReq.Events, ","),
+		Secret: 	webhookCreateReq.Secret,
+		Active: 	true,
+		CreatedAt: 	now,
+		UpdatedAt: 	now,
+	}
+	if err := s.WebhookRepo.Save(ctx, webhookEntity); err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "Service: Create Webhook Successfully")
+	return toWebhook(webhookEntity), nil
+}
+
+func (s *WebhookServiceImpl) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FindAll")
+	defer span.End()
+
+	webhookEntities, err := s.WebhookRepo.FindAll(ctx)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	webhooksRes := []model.Webhook{}
+	for i := range webhookEntities {
+		webhooksRes = append(webhooksRes, *toWebhook(&webhookEntities[i]))
+	}
+
+	logger.Info(ctx, "Service: Find All Webhooks Successfully")
+	return webhooksRes, nil
+}
+
+func (s *WebhookServiceImpl) FindByID(ctx context.Context, id int) (*model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FindByID")
+	defer span.End()
+
+	webhookEntity, err := s.WebhookRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "Service: Find Webhook By ID Successfully")
+	return toWebhook(webhookEntity), nil
+}
+
+func (s *WebhookServiceImpl) Update(ctx context.Context, id int, webhookUpdateReq *model.WebhookUpdate) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Update")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if err := helper.ValidateWebhookUpdate(webhookUpdateReq); err != nil {
+		logger.Error(ctx, "Webhook Update data is not valid")
+		return errs.NewValidateBadRequestError(err)
+	}
+
+	webhookEntity, err := s.WebhookRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	webhookEntity.URL = webhookUpdateReq.URL
+	webhookEntity.Events = strings.Join(webhookUpdateReq.Events, ",")
+	if webhookUpdateReq.Secret != "" {
+		webhookEntity.Secret = webhookUpdateReq.Secret
+	}
+	if webhookUpdateReq.Active != nil {
+		webhookEntity.Active = *webhookUpdateReq.Active
+	}
+	webhookEntity.UpdatedAt = time.Now()
+
+	if err := s.WebhookRepo.Update(ctx, webhookEntity); err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	logger.Info(ctx, "Service: Update Webhook Successfully")
+	return nil
+}
+
+func (s *WebhookServiceImpl) Delete(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Delete")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if _, err := s.WebhookRepo.FindByID(ctx, id); err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	if err := s.WebhookRepo.Delete(ctx, id); err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	logger.Info(ctx, "Service: Delete Webhook Successfully")
+	return nil
+}
+
+func (s *WebhookServiceImpl) FindDeliveries(ctx context.Context, id int, filter *model.WebhookDeliveryFilter) (*model.WebhookDeliveryPage, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FindDeliveries")
+	defer span.End()
+
+	if err := helper.ValidateWebhookDeliveryFilter(filter); err != nil {
+		logger.Error(ctx, "Webhook delivery filter is not valid")
+		return nil, errs.NewValidateBadRequestError(err)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = webhookDeliveryDefaultLimit
+	}
+
+	if _, err := s.WebhookRepo.FindByID(ctx, id); err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	deliveryEntities, total, err := s.WebhookRepo.FindDeliveries(ctx, id, filter)
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	page := &model.WebhookDeliveryPage{
+		Total: 	total,
+		Limit: 	filter.Limit,
+		Offset: filter.Offset,
+		Items: 	[]model.WebhookDelivery{},
+	}
+	for _, deliveryEntity := range deliveryEntities {
+		page.Items = append(page.Items, model.WebhookDelivery{
+			ID: 			deliveryEntity.ID,
+			WebhookID: 		deliveryEntity.WebhookID,
+			EventID: 		deliveryEntity.EventID,
+			EventType: 		deliveryEntity.EventType,
+			Payload: 		json.RawMessage(deliveryEntity.Payload),
+			Status: 		deliveryEntity.Status,
+			Attempts: 		deliveryEntity.Attempts,
+			NextAttemptAt: 	deliveryEntity.NextAttemptAt,
+			LastStatusCode: deliveryEntity.LastStatusCode,
+			LastError: 		deliveryEntity.LastError,
+			DeliveredAt: 	deliveryEntity.DeliveredAt,
+			CreatedAt: 		deliveryEntity.CreatedAt,
+		})
+	}
+
+	logger.Info(ctx, "Service: Find Webhook Deliveries Successfully")
+	return page, nil
+}
+
+// Redeliver schedules a delivery to be sent again on the next run with a fresh set of attempts,
+// this is how a dead delivery is brought back once the receiver is fixed.
+func (s *WebhookServiceImpl) Redeliver(ctx context.Context, id int, deliveryID int64) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+	ctx = replica.Primary(ctx)
+
+	if _, err := s.WebhookRepo.FindByID(ctx, id); err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	deliveryEntity, err := s.WebhookRepo.FindDeliveryByID(ctx, id, deliveryID)
+	if err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	deliveryEntity.Status = model.WebhookDeliveryPending
+	deliveryEntity.Attempts = 0
+	deliveryEntity.NextAttemptAt = time.Now()
+	if err := s.WebhookRepo.UpdateDelivery(ctx, deliveryEntity); err != nil {
+		logger.Error(ctx, err)
+		return err
+	}
+
+	logger.Info(ctx, "Service: Redeliver Webhook Delivery Successfully")
+	return nil
+}
+
+// Enqueue records a pending delivery of the event for every active webhook subscribed to eventType.
+func (s *WebhookServiceImpl) Enqueue(ctx context.Context, eventID string, eventType string, data interface{}) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Enqueue")
+	defer span.End()
+
+	webhookEntities, err := s.WebhookRepo.FindActive(ctx)
+	if err != nil {
+		logger.Error(ctx, err, "event_type", eventType)
+		return err
+	}
+
+	now := time.Now()
+	payload := model.WebhookPayload{
+		ID: 		eventID,
+		Type: 		eventType,
+		CreatedAt: 	now,
+		Data: 		data,
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error(ctx, err, "event_type", eventType)
+		return errs.NewInternalServerError(err.Error())
+	}
+
+	var deliveryEntities []model.WebhookDeliveryEntity
+	for _, webhookEntity := range webhookEntities {
+		if !webhookSubscribed(&webhookEntity, eventType) {
+			continue
+		}
+		deliveryEntities = append(deliveryEntities, model.WebhookDeliveryEntity{
+			WebhookID: 		webhookEntity.ID,
+			EventID: 		eventID,
+			EventType: 		eventType,
+			Payload: 		string(payloadJSON),
+			Status: 		model.WebhookDeliveryPending,
+			NextAttemptAt: 	now,
+			CreatedAt: 		now,
+		})
+	}
+
+	if err := s.WebhookRepo.SaveDeliveries(ctx, deliveryEntities); err != nil {
+		logger.Error(ctx, err, "event_type", eventType, "event_id", eventID)
+		return err
+	}
+	return nil
+}
+
+// DeliverDue claims and sends the deliveries that were due when it started, one at a time,
+// and returns how many succeeded. Deliveries claimed by another instance are left to it.
+func (s *WebhookServiceImpl) DeliverDue(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeliverDue")
+	defer span.End()
+
+	now := time.Now()
+	webhookEntities := map[int]*model.WebhookEntity{}
+	delivered := 0
+	for i := 0; i < webhookDeliveryBatchSize && ctx.Err() == nil; i++ {
+		// Postgres keeps microseconds, the claim is matched again when the outcome is written.
+		claimedUntil := time.Now().Add(s.Client.Timeout + webhookClaimSlack).Truncate(time.Microsecond)
+		deliveryEntity, err := s.WebhookRepo.ClaimDueDelivery(ctx, now, claimedUntil)
+		if err != nil {
+			logger.Error(ctx, err)
+			return delivered, err
+		}
+		if deliveryEntity == nil {
+			break
+		}
+
+		webhookEntity, ok := webhookEntities[deliveryEntity.WebhookID]
+		if !ok {
+			webhookEntity, err = s.WebhookRepo.FindByID(ctx, deliveryEntity.WebhookID)
+			if err != nil {
+				logger.Error(ctx, err, "webhook_id", deliveryEntity.WebhookID)
+				continue
+			}
+			webhookEntities[deliveryEntity.WebhookID] = webhookEntity
+		}
+
+		if s.deliver(ctx, webhookEntity, deliveryEntity, claimedUntil) {
+			delivered++
+		}
+	}
+
+	logger.Info(ctx, "Service: Deliver Webhooks Successfully")
+	return delivered, nil
+}
+
+// deliver makes one attempt and records its outcome on the delivery, unless the claim was lost meanwhile.
+func (s *WebhookServiceImpl) deliver(ctx context.Context, webhookEntity *model.WebhookEntity, deliveryEntity *model.WebhookDeliveryEntity, claimedUntil time.Time) bool {
+	now := time.Now()
+	statusCode, err := s.send(ctx, webhookEntity, deliveryEntity, now)
+
+	deliveryEntity.Attempts++
+	deliveryEntity.LastStatusCode = nil
+	if statusCode != 0 {
+		deliveryEntity.LastStatusCode = &statusCode
+	}
+	if err == nil {
+		deliveryEntity.Status = model.WebhookDeliverySucceeded
+		deliveryEntity.LastError = ""
+		deliveryEntity.DeliveredAt = &now
+	} else {
+		logger.Error(ctx, err, "webhook_id", webhookEntity.ID, "delivery_id", deliveryEntity.ID, "attempts", deliveryEntity.Attempts)
+		deliveryEntity.LastError = truncate(err.Error(), webhookLastErrorSize)
+		if deliveryEntity.Attempts >= s.MaxAttempts {
+			deliveryEntity.Status = model.WebhookDeliveryDead
+		} else {
+			deliveryEntity.Status = model.WebhookDeliveryFailed
+			deliveryEntity.NextAttemptAt = now.Add(s.backoff(deliveryEntity.Attempts))
+		}
+	}
+
+	finished, updateErr := s.WebhookRepo.FinishDelivery(ctx, deliveryEntity, claimedUntil)
+	if updateErr != nil {
+		logger.Error(ctx, updateErr, "delivery_id", deliveryEntity.ID)
+	} else if !finished {
+		logger.Info(ctx, "Service: Webhook Delivery Claim Lost", "delivery_id", deliveryEntity.ID)
+	}
+	return err == nil
+}
+
+// send posts the payload signed with the webhook secret, anything but a 2xx is a failure.
+// The request carries the traceparent of its client span so the receiver can continue the trace.
+func (s *WebhookServiceImpl) send(ctx context.Context, webhookEntity *model.WebhookEntity, deliveryEntity *model.WebhookDeliveryEntity, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "POST webhook",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(http.MethodPost), attribute.Int("webhook.id", webhookEntity.ID)),
+	)
+	defer span.End()
+
+	payload := []byte(deliveryEntity.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookEntity.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(helper.HeaderWebhookID, strconv.Itoa(webhookEntity.ID))
+	req.Header.Set(helper.HeaderWebhookEvent, deliveryEntity.EventType)
+	req.Header.Set(helper.HeaderWebhookDelivery, deliveryEntity.EventID)
+	req.Header.Set(helper.HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(helper.HeaderWebhookSignature, helper.SignWebhook(webhookEntity.Secret, timestamp, payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseReadLimit))
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		span.SetStatus(codes.Error, "")
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff is the wait after the given number of failed attempts.
+func (s *WebhookServiceImpl) backoff(attempts int) time.Duration {
+	wait := s.Backoff
+	for i := 1; i < attempts && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return wait
+}
+
+func webhookSubscribed(webhookEntity *model.WebhookEntity, eventType string) bool {
+	for _, event := range strings.Split(webhookEntity.Events, ",") {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func toWebhook(webhookEntity *model.WebhookEntity) *model.Webhook {
+	return &model.Webhook{
+		ID: 		webhookEntity.ID,
+		URL: 		webhookEntity.URL,
+		Events: 	strings.Split(webhookEntity.Events, ","),
+		Active: 	webhookEntity.Active,
+		CreatedAt: 	webhookEntity.CreatedAt,
+		UpdatedAt: 	webhookEntity.UpdatedAt,
+	}
+}
+
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	return value[:size]
+}
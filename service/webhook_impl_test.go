@@ -0,0 +1,366 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const webhookSecretMock = "0123456789abcdef"
+
+// webhookReceiver is a local receiver that answers with status and keeps the requests it got.
+func webhookReceiver(t *testing.T, status int) (*httptest.Server, chan *http.Request, chan []byte) {
+	requests, bodies := make(chan *http.Request, 10), make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests, bodies
+}
+
+func TestWebhookCreate(t *testing.T) {
+	t.Run("test case : create webhook success", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("Save", mock.Anything, mock.MatchedBy(func(webhookEntity *model.WebhookEntity) bool {
+			return webhookEntity.Events == "producttype.created,producttype.deleted" &&
+				webhookEntity.Secret == webhookSecretMock &&
+				webhookEntity.Active
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.WebhookEntity).ID = 3
+		})
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		result, err := service.Create(context.Background(), &model.WebhookCreate{
+			URL: 	"https://example.com/hook",
+			Events: []string{"producttype.created", "producttype.deleted"},
+			Secret: webhookSecretMock,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ID)
+		assert.Equal(t, []string{"producttype.created", "producttype.deleted"}, result.Events)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : create webhook fail validation", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		_, err := service.Create(context.Background(), &model.WebhookCreate{URL: "https://example.com/hook", Events: []string{"producttype.created"}, Secret: "short"})
+
+		assert.Equal(t, errs.NewValidateBadRequestError([]errs.ErrorMessage{{FailedField: "WebhookCreate.Secret", Tag: "min", Value: "16"}}), err)
+		mockRepository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+func TestWebhookUpdate(t *testing.T) {
+	t.Run("test case : update webhook keeps secret and deactivates", func(t *testing.T) {
+		inactive := false
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: "https://example.com/old", Events: "producttype.created", Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("Update", mock.Anything, mock.MatchedBy(func(webhookEntity *model.WebhookEntity) bool {
+			return webhookEntity.URL == "https://example.com/new" &&
+				webhookEntity.Events == "producttype.updated" &&
+				webhookEntity.Secret == webhookSecretMock &&
+				!webhookEntity.Active
+		})).Return(nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Update(context.Background(), 1, &model.WebhookUpdate{URL: "https://example.com/new", Events: []string{"producttype.updated"}, Active: &inactive})
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : update webhook fail not found", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return((*model.WebhookEntity)(nil), errs.NewNotFoundError("record not found"))
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Update(context.Background(), 1, &model.WebhookUpdate{URL: "https://example.com/new", Events: []string{"producttype.updated"}})
+
+		assert.Equal(t, errs.NewNotFoundError("record not found"), err)
+		mockRepository.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestWebhookDelete(t *testing.T) {
+	t.Run("test case : delete webhook success", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1}, nil)
+		mockRepository.On("Delete", mock.Anything, 1).Return(nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Delete(context.Background(), 1)
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestWebhookEnqueue(t *testing.T) {
+	t.Run("test case : enqueue for subscribed webhooks only", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindActive", mock.Anything).Return([]model.WebhookEntity{
+			{ID: 1, Events: "producttype.created,producttype.deleted"},
+			{ID: 2, Events: "producttype.updated"},
+			{ID: 3, Events: "producttype.deleted"},
+		}, nil)
+		mockRepository.On("SaveDeliveries", mock.Anything, mock.MatchedBy(func(deliveryEntities []model.WebhookDeliveryEntity) bool {
+			if len(deliveryEntities) != 2 || deliveryEntities[0].WebhookID != 1 || deliveryEntities[1].WebhookID != 3 {
+				return false
+			}
+			var payload map[string]interface{}
+			json.Unmarshal([]byte(deliveryEntities[0].Payload), &payload)
+			return deliveryEntities[0].EventID == "42" &&
+				deliveryEntities[1].EventID == "42" &&
+				deliveryEntities[0].Status == model.WebhookDeliveryPending &&
+				payload["type"] == "producttype.deleted" &&
+				payload["id"] == "42"
+		})).Return(nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Enqueue(context.Background(), "42", model.WebhookEventProductTypeDeleted, &model.ProductType{ID: 1, Name: "A"})
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : enqueue fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindActive", mock.Anything).Return([]model.WebhookEntity{}, errs.NewInternalServerError(""))
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Enqueue(context.Background(), "42", model.WebhookEventProductTypeCreated, &model.ProductType{ID: 1, Name: "A"})
+
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+		mockRepository.AssertNotCalled(t, "SaveDeliveries", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookDeliverDue(t *testing.T) {
+	payloadMock := `{"id":"e1","type":"producttype.created","created_at":"2024-01-01T00:00:00Z","data":{"prodtype_id":1,"prodtype_name":"A"}}`
+
+	t.Run("test case : deliver signed payload success", func(t *testing.T) {
+		server, requests, bodies := webhookReceiver(t, http.StatusNoContent)
+
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, EventID: "e1", EventType: "producttype.created", Payload: payloadMock, Status: model.WebhookDeliveryPending}, nil).Once()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), nil).Once()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: server.URL, Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("FinishDelivery", mock.Anything, mock.MatchedBy(func(deliveryEntity *model.WebhookDeliveryEntity) bool {
+			return deliveryEntity.Status == model.WebhookDeliverySucceeded &&
+				deliveryEntity.Attempts == 1 &&
+				*deliveryEntity.LastStatusCode == http.StatusNoContent &&
+				deliveryEntity.DeliveredAt != nil
+		}), mock.Anything).Return(true, nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, time.Second, 3, time.Minute)
+		delivered, err := service.DeliverDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		req, body := <-requests, <-bodies
+		timestamp, _ := strconv.ParseInt(req.Header.Get(helper.HeaderWebhookTimestamp), 10, 64)
+		assert.Equal(t, payloadMock, string(body))
+		assert.Equal(t, "1", req.Header.Get(helper.HeaderWebhookID))
+		assert.Equal(t, "producttype.created", req.Header.Get(helper.HeaderWebhookEvent))
+		assert.Equal(t, "e1", req.Header.Get(helper.HeaderWebhookDelivery))
+		assert.Equal(t, helper.SignWebhook(webhookSecretMock, timestamp, body), req.Header.Get(helper.HeaderWebhookSignature))
+		assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), 5 * time.Second)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : deliver propagates trace context", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+		server, requests, _ := webhookReceiver(t, http.StatusNoContent)
+
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, EventID: "e1", EventType: "producttype.created", Payload: payloadMock, Status: model.WebhookDeliveryPending}, nil).Once()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), nil).Once()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: server.URL, Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("FinishDelivery", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, time.Second, 3, time.Minute)
+		_, err := service.DeliverDue(context.Background())
+		req := <-requests
+
+		assert.NoError(t, err)
+		var sendSpan sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == "POST webhook" {
+				sendSpan = span
+			}
+		}
+		assert.NotNil(t, sendSpan)
+		assert.Equal(t, trace.SpanKindClient, sendSpan.SpanKind())
+		assert.Equal(t, "WebhookService.DeliverDue", recorder.Ended()[len(recorder.Ended()) - 1].Name())
+		assert.Equal(t, "00-" + sendSpan.SpanContext().TraceID().String() + "-" + sendSpan.SpanContext().SpanID().String() + "-01", req.Header.Get("traceparent"))
+	})
+
+	t.Run("test case : deliver fail schedules retry with backoff", func(t *testing.T) {
+		server, _, _ := webhookReceiver(t, http.StatusInternalServerError)
+
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, EventID: "e1", Payload: payloadMock, Status: model.WebhookDeliveryFailed, Attempts: 1}, nil).Once()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), nil).Once()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: server.URL, Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("FinishDelivery", mock.Anything, mock.MatchedBy(func(deliveryEntity *model.WebhookDeliveryEntity) bool {
+			wait := time.Until(deliveryEntity.NextAttemptAt)
+			return deliveryEntity.Status == model.WebhookDeliveryFailed &&
+				deliveryEntity.Attempts == 2 &&
+				*deliveryEntity.LastStatusCode == http.StatusInternalServerError &&
+				deliveryEntity.LastError == "webhook responded with status 500" &&
+				wait > time.Minute && wait <= 2 * time.Minute
+		}), mock.Anything).Return(true, nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, time.Second, 3, time.Minute)
+		delivered, err := service.DeliverDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : deliver fail on last attempt is dead", func(t *testing.T) {
+		server, _, _ := webhookReceiver(t, http.StatusBadGateway)
+
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, EventID: "e1", Payload: payloadMock, Status: model.WebhookDeliveryFailed, Attempts: 2}, nil).Once()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), nil).Once()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: server.URL, Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("FinishDelivery", mock.Anything, mock.MatchedBy(func(deliveryEntity *model.WebhookDeliveryEntity) bool {
+			return deliveryEntity.Status == model.WebhookDeliveryDead && deliveryEntity.Attempts == 3
+		}), mock.Anything).Return(true, nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, time.Second, 3, time.Minute)
+		_, err := service.DeliverDue(context.Background())
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : deliver unreachable receiver records error", func(t *testing.T) {
+		server, _, _ := webhookReceiver(t, http.StatusOK)
+		server.Close()
+
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, EventID: "e1", Payload: payloadMock, Status: model.WebhookDeliveryPending}, nil).Once()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), nil).Once()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: server.URL, Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("FinishDelivery", mock.Anything, mock.MatchedBy(func(deliveryEntity *model.WebhookDeliveryEntity) bool {
+			return deliveryEntity.Status == model.WebhookDeliveryFailed &&
+				deliveryEntity.LastStatusCode == nil &&
+				deliveryEntity.LastError != ""
+		}), mock.Anything).Return(true, nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, time.Second, 3, time.Minute)
+		_, err := service.DeliverDue(context.Background())
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : deliver writes outcome under its claim", func(t *testing.T) {
+		server, _, _ := webhookReceiver(t, http.StatusOK)
+
+		var claimedUntil time.Time
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.MatchedBy(func(until time.Time) bool {
+			claimedUntil = until
+			lease := time.Until(until)
+			return lease > 30 * time.Second && lease <= 31 * time.Second
+		})).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, EventID: "e1", Payload: payloadMock, Status: model.WebhookDeliveryPending}, nil).Once()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), nil).Once()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1, URL: server.URL, Secret: webhookSecretMock, Active: true}, nil)
+		mockRepository.On("FinishDelivery", mock.Anything, mock.Anything, mock.MatchedBy(func(until time.Time) bool {
+			return until.Equal(claimedUntil)
+		})).Return(false, nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, time.Second, 3, time.Minute)
+		delivered, err := service.DeliverDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : deliver due fail from repository", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("ClaimDueDelivery", mock.Anything, mock.Anything, mock.Anything).Return((*model.WebhookDeliveryEntity)(nil), errs.NewInternalServerError(""))
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		_, err := service.DeliverDue(context.Background())
+
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+	})
+}
+
+func TestWebhookFindDeliveries(t *testing.T) {
+	t.Run("test case : find deliveries with default limit", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1}, nil)
+		mockRepository.On("FindDeliveries", mock.Anything, 1, &model.WebhookDeliveryFilter{Status: "dead", Limit: 50}).Return([]model.WebhookDeliveryEntity{
+			{ID: 10, WebhookID: 1, EventID: "e1", Payload: `{"id":"e1"}`, Status: model.WebhookDeliveryDead, Attempts: 8},
+		}, int64(1), nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		result, err := service.FindDeliveries(context.Background(), 1, &model.WebhookDeliveryFilter{Status: "dead"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Total)
+		assert.Equal(t, json.RawMessage(`{"id":"e1"}`), result.Items[0].Payload)
+		mockRepository.AssertExpectations(t)
+	})
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	t.Run("test case : redeliver dead delivery resets attempts", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1}, nil)
+		mockRepository.On("FindDeliveryByID", mock.Anything, 1, int64(10)).Return(&model.WebhookDeliveryEntity{ID: 10, WebhookID: 1, Status: model.WebhookDeliveryDead, Attempts: 8}, nil)
+		mockRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(deliveryEntity *model.WebhookDeliveryEntity) bool {
+			return deliveryEntity.Status == model.WebhookDeliveryPending &&
+				deliveryEntity.Attempts == 0 &&
+				!deliveryEntity.NextAttemptAt.After(time.Now())
+		})).Return(nil)
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Redeliver(context.Background(), 1, 10)
+
+		assert.NoError(t, err)
+		mockRepository.AssertExpectations(t)
+	})
+
+	t.Run("test case : redeliver fail delivery not found", func(t *testing.T) {
+		mockRepository := testutils.NewWebhookRepositoryMock()
+		mockRepository.On("FindByID", mock.Anything, 1).Return(&model.WebhookEntity{ID: 1}, nil)
+		mockRepository.On("FindDeliveryByID", mock.Anything, 1, int64(10)).Return((*model.WebhookDeliveryEntity)(nil), errs.NewNotFoundError("record not found"))
+
+		service := service.NewWebhookServiceImpl(mockRepository, 0, 0, 0)
+		err := service.Redeliver(context.Background(), 1, 10)
+
+		assert.Equal(t, errs.NewNotFoundError("record not found"), err)
+		mockRepository.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,25 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+)
+
+// LogSink writes every event to the log, it never fails.
+type LogSink struct{}
+
+func NewLogSink() service.EventSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	logger.Info(ctx, "Sink: Event Published", "id", event.ID, "aggregate_type", event.AggregateType, "aggregate_id", event.AggregateID, "type", event.Type, "payload", string(event.Payload))
+	return nil
+}
@@ -0,0 +1,53 @@
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+
+	"github.com/goccy/go-json"
+)
+
+const natsFlushTimeout = 5 * time.Second
+
+// NATSPublisher is the part of *nats.Conn the sink uses.
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+	FlushTimeout(timeout time.Duration) error
+}
+
+// NATSSink publishes the event as JSON on "<prefix>.<aggregate type>.<type>". It waits for the server
+// to have read the message before reporting success, a stream capturing the subjects makes it durable.
+type NATSSink struct {
+	conn 			NATSPublisher
+	subjectPrefix 	string
+}
+
+func NewNATSSink(conn NATSPublisher, subjectPrefix string) service.EventSink {
+	return &NATSSink{
+		conn: 			conn,
+		subjectPrefix: 	subjectPrefix,
+	}
+}
+
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+func (s *NATSSink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	subject := event.AggregateType + "." + event.Type
+	if s.subjectPrefix != "" {
+		subject = s.subjectPrefix + "." + subject
+	}
+	if err := s.conn.Publish(subject, data); err != nil {
+		return err
+	}
+	return s.conn.FlushTimeout(natsFlushTimeout)
+}
@@ -0,0 +1,67 @@
+package sink_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/sink"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+)
+
+type natsPublisherMock struct {
+	subjects 	[]string
+	messages 	[][]byte
+	flushErr 	error
+}
+
+func (p *natsPublisherMock) Publish(subject string, data []byte) error {
+	p.subjects = append(p.subjects, subject)
+	p.messages = append(p.messages, data)
+	return nil
+}
+
+func (p *natsPublisherMock) FlushTimeout(timeout time.Duration) error {
+	return p.flushErr
+}
+
+func TestNATSSink(t *testing.T) {
+	event := &model.OutboxEvent{ID: 7, AggregateType: "producttype", AggregateID: "1", Type: "updated", Payload: json.RawMessage(`{"prodtype_id":1}`)}
+
+	t.Run("test case : publish on prefixed subject", func(t *testing.T) {
+		publisher := &natsPublisherMock{}
+		natsSink := sink.NewNATSSink(publisher, "fiber")
+
+		err := natsSink.Publish(context.Background(), event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fiber.producttype.updated"}, publisher.subjects)
+		var published model.OutboxEvent
+		assert.NoError(t, json.Unmarshal(publisher.messages[0], &published))
+		assert.Equal(t, int64(7), published.ID)
+		assert.JSONEq(t, `{"prodtype_id":1}`, string(published.Payload))
+	})
+
+	t.Run("test case : publish without prefix", func(t *testing.T) {
+		publisher := &natsPublisherMock{}
+		natsSink := sink.NewNATSSink(publisher, "")
+
+		err := natsSink.Publish(context.Background(), event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"producttype.updated"}, publisher.subjects)
+	})
+
+	t.Run("test case : publish fail to flush", func(t *testing.T) {
+		publisher := &natsPublisherMock{flushErr: errors.New("nats: timeout")}
+		natsSink := sink.NewNATSSink(publisher, "fiber")
+
+		err := natsSink.Publish(context.Background(), event)
+
+		assert.EqualError(t, err, "nats: timeout")
+	})
+}
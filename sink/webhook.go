@@ -0,0 +1,27 @@
+package sink
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/service"
+)
+
+// WebhookSink queues the event for the subscribed webhooks as "<aggregate type>.<type>",
+// the outbox ID is the event ID the receivers see.
+type WebhookSink struct {
+	webhooks service.WebhookEnqueuer
+}
+
+func NewWebhookSink(webhooks service.WebhookEnqueuer) service.EventSink {
+	return &WebhookSink{webhooks: webhooks}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	return s.webhooks.Enqueue(ctx, strconv.FormatInt(event.ID, 10), event.AggregateType + "." + event.Type, event.Payload)
+}
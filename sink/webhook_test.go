@@ -0,0 +1,36 @@
+package sink_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/sink"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("test case : publish enqueues with outbox id", func(t *testing.T) {
+		webhooks := testutils.NewWebhookEnqueuerMock()
+		webhookSink := sink.NewWebhookSink(webhooks)
+
+		err := webhookSink.Publish(context.Background(), &model.OutboxEvent{ID: 42, AggregateType: "producttype", Type: "deleted"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"42"}, webhooks.EventIDs)
+		assert.Equal(t, []string{model.WebhookEventProductTypeDeleted}, webhooks.Types())
+	})
+
+	t.Run("test case : publish fail from enqueuer", func(t *testing.T) {
+		webhooks := testutils.NewWebhookEnqueuerMock()
+		webhooks.Err = errs.NewInternalServerError("")
+		webhookSink := sink.NewWebhookSink(webhooks)
+
+		err := webhookSink.Publish(context.Background(), &model.OutboxEvent{ID: 42, AggregateType: "producttype", Type: "deleted"})
+
+		assert.Equal(t, errs.NewInternalServerError(""), err)
+	})
+}
@@ -0,0 +1,78 @@
+package testutils
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/helper"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// AuditRecorderMock keeps every recorded entry in memory so tests do not need
+// an expectation for each audit write.
+type AuditRecorderMock struct {
+	mu 		sync.Mutex
+	Entries []model.AuditLogEntity
+}
+
+func NewAuditRecorderMock() *AuditRecorderMock {
+	return &AuditRecorderMock{}
+}
+
+func (m *AuditRecorderMock) Record(ctx context.Context, auditEntity *model.AuditLogEntity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := *auditEntity
+	if entry.ActorID == nil {
+		entry.ActorID = helper.ActorFromContext(ctx).UserID
+	}
+	entry.ActorSystem = helper.ActorFromContext(ctx).System
+	m.Entries = append(m.Entries, entry)
+}
+
+// Actions returns the recorded actions in order.
+func (m *AuditRecorderMock) Actions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	actions := []string{}
+	for _, entry := range m.Entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+type AuditRepositoryMock struct {
+	mock.Mock
+}
+
+func NewAuditRepositoryMock() *AuditRepositoryMock {
+	return &AuditRepositoryMock{}
+}
+
+func (m *AuditRepositoryMock) Create(ctx context.Context, auditEntity *model.AuditLogEntity) error {
+	args := m.Called(ctx, auditEntity)
+	return args.Error(0)
+}
+
+func (m *AuditRepositoryMock) FindAll(ctx context.Context, filter *model.AuditLogFilter) ([]model.AuditLogEntity, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.AuditLogEntity), args.Get(1).(int64), args.Error(2)
+}
+
+type AuditServiceMock struct {
+	AuditRecorderMock
+	mock.Mock
+}
+
+func NewAuditServiceMock() *AuditServiceMock {
+	return &AuditServiceMock{}
+}
+
+func (m *AuditServiceMock) FindAll(ctx context.Context, filter *model.AuditLogFilter) (*model.AuditLogPage, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*model.AuditLogPage), args.Error(1)
+}
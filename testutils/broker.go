@@ -0,0 +1,44 @@
+package testutils
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/Yoshikrit/fiber-test/broker"
+)
+
+// BrokerMock keeps every published event in memory so tests do not need
+// an expectation for each publish.
+type BrokerMock struct {
+	mu 		sync.Mutex
+	Events 	[]broker.Event
+}
+
+func NewBrokerMock() *BrokerMock {
+	return &BrokerMock{}
+}
+
+func (m *BrokerMock) Publish(id int64, eventType string, data interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Events = append(m.Events, broker.Event{ID: strconv.FormatInt(id, 10), Type: eventType, Data: data})
+}
+
+func (m *BrokerMock) Subscribe(lastEventID string) *broker.Subscription {
+	return &broker.Subscription{Events: make(chan broker.Event)}
+}
+
+func (m *BrokerMock) Close() {}
+
+// Types returns the published event types in order.
+func (m *BrokerMock) Types() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	types := []string{}
+	for _, event := range m.Events {
+		types = append(types, event.Type)
+	}
+	return types
+}
@@ -21,3 +21,14 @@ func SetupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 
 	return db, mock
 }
+
+// SQLStateError stands in for the driver error, which reports its code through SQLState.
+type SQLStateError string
+
+func (e SQLStateError) Error() string {
+	return "SQLSTATE " + string(e)
+}
+
+func (e SQLStateError) SQLState() string {
+	return string(e)
+}
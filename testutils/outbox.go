@@ -0,0 +1,136 @@
+package testutils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+func NewOutboxRepositoryMock() *OutboxRepositoryMock {
+	return &OutboxRepositoryMock{}
+}
+
+func (m *OutboxRepositoryMock) Save(ctx context.Context, outboxEntities []model.OutboxEventEntity) error {
+	args := m.Called(ctx, outboxEntities)
+	return args.Error(0)
+}
+
+// RelayLocked runs fn on the mock itself when it is set up to return true.
+func (m *OutboxRepositoryMock) RelayLocked(ctx context.Context, fn func(outboxRepo repository.OutboxRepository) error) (bool, error) {
+	args := m.Called(ctx)
+	if !args.Bool(0) {
+		return false, args.Error(1)
+	}
+	if err := fn(m); err != nil {
+		return true, err
+	}
+	return true, args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) FindUnpublished(ctx context.Context, limit int) ([]model.OutboxEventEntity, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]model.OutboxEventEntity), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) FindAfter(ctx context.Context, aggregateType string, afterID int64, limit int) ([]model.OutboxEventEntity, error) {
+	args := m.Called(ctx, aggregateType, afterID, limit)
+	return args.Get(0).([]model.OutboxEventEntity), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) FindLastID(ctx context.Context, aggregateType string) (int64, error) {
+	args := m.Called(ctx, aggregateType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	args := m.Called(ctx, id, publishedAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkFailed(ctx context.Context, outboxEntity *model.OutboxEventEntity) error {
+	args := m.Called(ctx, outboxEntity)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// EventSinkMock keeps every published event in memory and fails the events of
+// the aggregates in FailAggregates. Its name is SinkName, "mock" when empty.
+type EventSinkMock struct {
+	mu 				sync.Mutex
+	SinkName 		string
+	FailAggregates 	map[string]error
+	Events 			[]model.OutboxEvent
+}
+
+func NewEventSinkMock() *EventSinkMock {
+	return &EventSinkMock{FailAggregates: map[string]error{}}
+}
+
+func (m *EventSinkMock) Name() string {
+	if m.SinkName == "" {
+		return "mock"
+	}
+	return m.SinkName
+}
+
+func (m *EventSinkMock) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.FailAggregates[event.AggregateID]; err != nil {
+		return err
+	}
+	m.Events = append(m.Events, *event)
+	return nil
+}
+
+// IDs returns the published event ids in order.
+func (m *EventSinkMock) IDs() []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := []int64{}
+	for _, event := range m.Events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+type OutboxServiceMock struct {
+	mock.Mock
+}
+
+func NewOutboxServiceMock() *OutboxServiceMock {
+	return &OutboxServiceMock{}
+}
+
+func (m *OutboxServiceMock) Relay(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type OutboxTailServiceMock struct {
+	mock.Mock
+}
+
+func NewOutboxTailServiceMock() *OutboxTailServiceMock {
+	return &OutboxTailServiceMock{}
+}
+
+func (m *OutboxTailServiceMock) Tail(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
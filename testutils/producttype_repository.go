@@ -1,6 +1,9 @@
 package testutils
 
 import (
+	"context"
+	"time"
+
 	"github.com/Yoshikrit/fiber-test/model"
 
 	"github.com/stretchr/testify/mock"
@@ -14,32 +17,126 @@ func NewProductTypeRepositoryMock() *ProdTypeRepositoryMock {
 	return &ProdTypeRepositoryMock{}
 }
 
-func (m *ProdTypeRepositoryMock) Save(prodTypeCreateReq *model.ProductTypeEntity) error {
-	args := m.Called(prodTypeCreateReq)
+func (m *ProdTypeRepositoryMock) Save(ctx context.Context, prodTypeCreateReq *model.ProductTypeEntity) error {
+	args := m.Called(ctx, prodTypeCreateReq)
 	return args.Error(0)
 }
 
-func (m *ProdTypeRepositoryMock) FindAll() ([]model.ProductTypeEntity, error) {
-	args := m.Called()
+func (m *ProdTypeRepositoryMock) FindAll(ctx context.Context) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
 }
 
-func (m *ProdTypeRepositoryMock) FindByID(id int) (*model.ProductTypeEntity, error) {
-	args := m.Called(id)
+func (m *ProdTypeRepositoryMock) FindByID(ctx context.Context, id int) (*model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*model.ProductTypeEntity), args.Error(1)
 }
 
-func (m *ProdTypeRepositoryMock) Update(prodTypeUpdateReq *model.ProductTypeEntity) error {
-	args := m.Called(prodTypeUpdateReq)
+func (m *ProdTypeRepositoryMock) Update(ctx context.Context, prodTypeUpdateReq *model.ProductTypeEntity) error {
+	args := m.Called(ctx, prodTypeUpdateReq)
 	return args.Error(0)
 }
 
-func (m *ProdTypeRepositoryMock) Delete(id int) error {
-	args := m.Called(id)
+func (m *ProdTypeRepositoryMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *ProdTypeRepositoryMock) Count() (int64, error) {
-	args := m.Called()
+func (m *ProdTypeRepositoryMock) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *ProdTypeRepositoryMock) FindAllDeleted(ctx context.Context) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) FindDeletedByID(ctx context.Context, id int) (*model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) Restore(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ProdTypeRepositoryMock) Purge(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]model.ProductTypeEntity, []model.ProductTypeEntity, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).([]model.ProductTypeEntity), args.Get(1).([]model.ProductTypeEntity), args.Error(2)
+}
+
+func (m *ProdTypeRepositoryMock) FindByIDsUnscoped(ctx context.Context, ids []int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) ApplyBatch(ctx context.Context, batch *model.ProductTypeBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *ProdTypeRepositoryMock) FindAllAfter(ctx context.Context, afterID int, limit int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+func (m *ProdTypeRepositoryMock) FindChildren(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) FindAncestors(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) LockChanges(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *ProdTypeRepositoryMock) Move(ctx context.Context, id int, parentID *int) error {
+	args := m.Called(ctx, id, parentID)
+	return args.Error(0)
+}
+
+func (m *ProdTypeRepositoryMock) DeleteSubtree(ctx context.Context, id int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) DeleteAndReparent(ctx context.Context, id int, parentID *int) ([]model.ProductTypeEntity, error) {
+	args := m.Called(ctx, id, parentID)
+	return args.Get(0).([]model.ProductTypeEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) FindRevisions(ctx context.Context, id int) ([]model.ProductTypeRevisionEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductTypeRevisionEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) FindRevision(ctx context.Context, id int, revision int) (*model.ProductTypeRevisionEntity, error) {
+	args := m.Called(ctx, id, revision)
+	return args.Get(0).(*model.ProductTypeRevisionEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) FindRevisionAsOf(ctx context.Context, id int, asOf time.Time) (*model.ProductTypeRevisionEntity, error) {
+	args := m.Called(ctx, id, asOf)
+	return args.Get(0).(*model.ProductTypeRevisionEntity), args.Error(1)
+}
+
+func (m *ProdTypeRepositoryMock) Revert(ctx context.Context, revisionEntity *model.ProductTypeRevisionEntity) error {
+	args := m.Called(ctx, revisionEntity)
+	return args.Error(0)
+}
+
+func (m *ProdTypeRepositoryMock) FindChanges(ctx context.Context, since int64, limit int) ([]model.ProductTypeRevisionEntity, error) {
+	args := m.Called(ctx, since, limit)
+	return args.Get(0).([]model.ProductTypeRevisionEntity), args.Error(1)
+}
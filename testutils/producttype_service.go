@@ -1,6 +1,11 @@
 package testutils
 
 import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
 	"github.com/Yoshikrit/fiber-test/model"
 
 	"github.com/stretchr/testify/mock"
@@ -14,32 +19,120 @@ func NewProductTypeServiceMock() *ProdTypeServiceMock {
 	return &ProdTypeServiceMock{}
 }
 
-func (m *ProdTypeServiceMock) Create(prodTypeCreateReq *model.ProductTypeCreate) error {
-	args := m.Called(prodTypeCreateReq)
+func (m *ProdTypeServiceMock) Create(ctx context.Context, prodTypeCreateReq *model.ProductTypeCreate) error {
+	args := m.Called(ctx, prodTypeCreateReq)
 	return args.Error(0)
 }
 
-func (m *ProdTypeServiceMock) FindAll() ([]model.ProductType, error) {
-	args := m.Called()
+func (m *ProdTypeServiceMock) FindAll(ctx context.Context) ([]model.ProductType, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]model.ProductType), args.Error(1)
 }
 
-func (m *ProdTypeServiceMock) FindByID(id int) (*model.ProductType, error) {
-	args := m.Called(id)
+func (m *ProdTypeServiceMock) FindByID(ctx context.Context, id int) (*model.ProductType, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*model.ProductType), args.Error(1)
 }
 
-func (m *ProdTypeServiceMock) Update(id int, prodTypeUpdateReq *model.ProductTypeUpdate) error {
-	args := m.Called(id, prodTypeUpdateReq)
+func (m *ProdTypeServiceMock) Update(ctx context.Context, id int, prodTypeUpdateReq *model.ProductTypeUpdate) error {
+	args := m.Called(ctx, id, prodTypeUpdateReq)
 	return args.Error(0)
 }
 
-func (m *ProdTypeServiceMock) Delete(id int) error {
-	args := m.Called(id)
+func (m *ProdTypeServiceMock) Delete(ctx context.Context, id int, children string) error {
+	args := m.Called(ctx, id, children)
 	return args.Error(0)
 }
 
-func (m *ProdTypeServiceMock) Count() (int64, error) {
-	args := m.Called()
+func (m *ProdTypeServiceMock) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *ProdTypeServiceMock) FindAllTrash(ctx context.Context) ([]model.ProductTypeTrash, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.ProductTypeTrash), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) Restore(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ProdTypeServiceMock) Purge(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ProdTypeServiceMock) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	args := m.Called(ctx, retention)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) Bulk(ctx context.Context, bulkReq *model.ProductTypeBulkRequest) (*model.ProductTypeBulkSummary, error) {
+	args := m.Called(ctx, bulkReq)
+	return args.Get(0).(*model.ProductTypeBulkSummary), args.Error(1)
+}
+
+// Export returns a function writing the string set as its first return value,
+// or the error set as its second one once it wrote the string.
+func (m *ProdTypeServiceMock) Export(ctx context.Context, format string) (func(io.Writer) error, error) {
+	args := m.Called(ctx, format)
+	content, ok := args.Get(0).(string)
+	if !ok {
+		return nil, args.Error(2)
+	}
+	return func(w io.Writer) error {
+		io.WriteString(w, content)
+		return args.Error(1)
+	}, args.Error(2)
+}
+
+func (m *ProdTypeServiceMock) Import(ctx context.Context, opts *model.ProductTypeImportOptions, r io.Reader) (*model.ProductTypeImportReport, error) {
+	args := m.Called(ctx, opts, r)
+	return args.Get(0).(*model.ProductTypeImportReport), args.Error(1)
+}
+func (m *ProdTypeServiceMock) FindTree(ctx context.Context) ([]*model.ProductTypeNode, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.ProductTypeNode), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) FindChildren(ctx context.Context, id int) ([]model.ProductType, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductType), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) FindAncestors(ctx context.Context, id int) ([]model.ProductType, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductType), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) Move(ctx context.Context, id int, prodTypeMoveReq *model.ProductTypeMove) error {
+	args := m.Called(ctx, id, prodTypeMoveReq)
+	return args.Error(0)
+}
+
+func (m *ProdTypeServiceMock) FindByIDAsOf(ctx context.Context, id int, asOf time.Time) (*model.ProductType, error) {
+	args := m.Called(ctx, id, asOf)
+	return args.Get(0).(*model.ProductType), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) FindRevisions(ctx context.Context, id int) ([]model.ProductTypeRevision, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.ProductTypeRevision), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) Revert(ctx context.Context, id int, revision int) error {
+	args := m.Called(ctx, id, revision)
+	return args.Error(0)
+}
+
+func (m *ProdTypeServiceMock) FindChanges(ctx context.Context, query *model.ProductTypeChangeQuery) (*model.ProductTypeChangeFeed, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(*model.ProductTypeChangeFeed), args.Error(1)
+}
+
+func (m *ProdTypeServiceMock) Subscribe(ctx context.Context, lastEventID string) *broker.Subscription {
+	args := m.Called(ctx, lastEventID)
+	return args.Get(0).(*broker.Subscription)
+}
@@ -0,0 +1,27 @@
+package testutils
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SettingsServiceMock struct {
+	mock.Mock
+}
+
+func NewSettingsServiceMock() *SettingsServiceMock {
+	return &SettingsServiceMock{}
+}
+
+func (m *SettingsServiceMock) FindAll(ctx context.Context) (model.Settings, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(model.Settings), args.Error(1)
+}
+
+func (m *SettingsServiceMock) Update(ctx context.Context, changes model.Settings) (model.Settings, error) {
+	args := m.Called(ctx, changes)
+	return args.Get(0).(model.Settings), args.Error(1)
+}
@@ -0,0 +1,96 @@
+package testutils
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/repository"
+)
+
+// TxManagerMock runs fn straight on the given repositories and keeps the outbox events
+// of every fn that returned nil in memory, as a commit would. Set UserRepo, OauthRepo
+// and RoleRepo for services that use them.
+type TxManagerMock struct {
+	mu 				sync.Mutex
+	prodTypeRepo 	repository.ProductTypeRepository
+	UserRepo 		repository.UserRepository
+	OauthRepo 		repository.OauthRepository
+	RoleRepo 		repository.RoleRepository
+	Events 			[]model.OutboxEventEntity
+}
+
+func NewTxManagerMock(prodTypeRepo repository.ProductTypeRepository) *TxManagerMock {
+	return &TxManagerMock{prodTypeRepo: prodTypeRepo}
+}
+
+func (m *TxManagerMock) Do(ctx context.Context, fn func(repository.Tx) error) error {
+	tx := &txMock{manager: m}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Events = append(m.Events, tx.events...)
+	return nil
+}
+
+// EventTypes returns the committed event types in order.
+func (m *TxManagerMock) EventTypes() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	types := []string{}
+	for _, event := range m.Events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+type txMock struct {
+	manager 	*TxManagerMock
+	events 		[]model.OutboxEventEntity
+}
+
+func (t *txMock) Users() repository.UserRepository {
+	return t.manager.UserRepo
+}
+
+func (t *txMock) Oauths() repository.OauthRepository {
+	return t.manager.OauthRepo
+}
+
+func (t *txMock) Roles() repository.RoleRepository {
+	return t.manager.RoleRepo
+}
+
+func (t *txMock) ProductTypes() repository.ProductTypeRepository {
+	return t.manager.prodTypeRepo
+}
+
+func (t *txMock) Outbox() repository.OutboxRepository {
+	return &outboxStagingMock{tx: t}
+}
+
+// Do drops the events fn staged when it fails, as rolling back to the savepoint would.
+func (t *txMock) Do(fn func(repository.Tx) error) error {
+	staged := len(t.events)
+	if err := fn(t); err != nil {
+		t.events = t.events[:staged]
+		return err
+	}
+	return nil
+}
+
+// outboxStagingMock only supports Save, which is all a write does inside a transaction.
+type outboxStagingMock struct {
+	repository.OutboxRepository
+	tx *txMock
+}
+
+func (o *outboxStagingMock) Save(ctx context.Context, outboxEntities []model.OutboxEventEntity) error {
+	o.tx.events = append(o.tx.events, outboxEntities...)
+	return nil
+}
@@ -0,0 +1,113 @@
+package testutils
+
+import (
+	"context"
+
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRepositoryMock struct {
+	mock.Mock
+}
+
+func NewUserRepositoryMock() *UserRepositoryMock {
+	return &UserRepositoryMock{}
+}
+
+func (m *UserRepositoryMock) Create(ctx context.Context, userCreateReq *model.UserEntity) error {
+	args := m.Called(ctx, userCreateReq)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) FindByID(ctx context.Context, id int) (*model.UserEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.UserEntity), args.Error(1)
+}
+
+func (m *UserRepositoryMock) FindByEmail(ctx context.Context, email string) (*model.UserEntity, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(*model.UserEntity), args.Error(1)
+}
+
+func (m *UserRepositoryMock) Update(ctx context.Context, userUpdateReq *model.UserEntity) error {
+	args := m.Called(ctx, userUpdateReq)
+	return args.Error(0)
+}
+
+type RoleRepositoryMock struct {
+	mock.Mock
+}
+
+func NewRoleRepositoryMock() *RoleRepositoryMock {
+	return &RoleRepositoryMock{}
+}
+
+func (m *RoleRepositoryMock) FindByID(ctx context.Context, id int) (*model.RoleEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.RoleEntity), args.Error(1)
+}
+
+func (m *RoleRepositoryMock) FindByTitle(ctx context.Context, title string) (*model.RoleEntity, error) {
+	args := m.Called(ctx, title)
+	return args.Get(0).(*model.RoleEntity), args.Error(1)
+}
+
+type OauthRepositoryMock struct {
+	mock.Mock
+}
+
+func NewOauthRepositoryMock() *OauthRepositoryMock {
+	return &OauthRepositoryMock{}
+}
+
+func (m *OauthRepositoryMock) Create(ctx context.Context, oauthEntity *model.OauthEntity) error {
+	args := m.Called(ctx, oauthEntity)
+	return args.Error(0)
+}
+
+func (m *OauthRepositoryMock) FindByID(ctx context.Context, id int) (*model.OauthEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.OauthEntity), args.Error(1)
+}
+
+func (m *OauthRepositoryMock) FindByUserID(ctx context.Context, id int) (*model.OauthEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.OauthEntity), args.Error(1)
+}
+
+func (m *OauthRepositoryMock) FindByAccessToken(ctx context.Context, id int, accessToken string) (*model.OauthEntity, error) {
+	args := m.Called(ctx, id, accessToken)
+	return args.Get(0).(*model.OauthEntity), args.Error(1)
+}
+
+func (m *OauthRepositoryMock) FindByRefleshToken(ctx context.Context, refleshToken string) (*model.OauthEntity, error) {
+	args := m.Called(ctx, refleshToken)
+	return args.Get(0).(*model.OauthEntity), args.Error(1)
+}
+
+func (m *OauthRepositoryMock) FindByRefleshTokenForUpdate(ctx context.Context, refleshToken string) (*model.OauthEntity, error) {
+	args := m.Called(ctx, refleshToken)
+	return args.Get(0).(*model.OauthEntity), args.Error(1)
+}
+
+func (m *OauthRepositoryMock) Update(ctx context.Context, oauthEntity *model.OauthEntity) error {
+	args := m.Called(ctx, oauthEntity)
+	return args.Error(0)
+}
+
+func (m *OauthRepositoryMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *OauthRepositoryMock) DeleteByUserID(ctx context.Context, userID int) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *OauthRepositoryMock) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
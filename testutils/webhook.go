@@ -0,0 +1,170 @@
+package testutils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Yoshikrit/fiber-test/broker"
+	"github.com/Yoshikrit/fiber-test/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// WebhookEnqueuerMock keeps every enqueued event in memory so tests do not need
+// an expectation for each event.
+type WebhookEnqueuerMock struct {
+	mu 			sync.Mutex
+	EventIDs 	[]string
+	Events 		[]broker.Event
+	Err 		error
+}
+
+func NewWebhookEnqueuerMock() *WebhookEnqueuerMock {
+	return &WebhookEnqueuerMock{}
+}
+
+func (m *WebhookEnqueuerMock) Enqueue(ctx context.Context, eventID string, eventType string, data interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	m.EventIDs = append(m.EventIDs, eventID)
+	m.Events = append(m.Events, broker.Event{Type: eventType, Data: data})
+	return nil
+}
+
+// Types returns the enqueued event types in order.
+func (m *WebhookEnqueuerMock) Types() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	types := []string{}
+	for _, event := range m.Events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+func NewWebhookRepositoryMock() *WebhookRepositoryMock {
+	return &WebhookRepositoryMock{}
+}
+
+func (m *WebhookRepositoryMock) Save(ctx context.Context, webhookEntity *model.WebhookEntity) error {
+	args := m.Called(ctx, webhookEntity)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) FindAll(ctx context.Context) ([]model.WebhookEntity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.WebhookEntity), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) FindByID(ctx context.Context, id int) (*model.WebhookEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.WebhookEntity), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) FindActive(ctx context.Context) ([]model.WebhookEntity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.WebhookEntity), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Update(ctx context.Context, webhookEntity *model.WebhookEntity) error {
+	args := m.Called(ctx, webhookEntity)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) SaveDeliveries(ctx context.Context, deliveryEntities []model.WebhookDeliveryEntity) error {
+	args := m.Called(ctx, deliveryEntities)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) ClaimDueDelivery(ctx context.Context, now time.Time, until time.Time) (*model.WebhookDeliveryEntity, error) {
+	args := m.Called(ctx, now, until)
+	return args.Get(0).(*model.WebhookDeliveryEntity), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) FindDeliveries(ctx context.Context, webhookID int, filter *model.WebhookDeliveryFilter) ([]model.WebhookDeliveryEntity, int64, error) {
+	args := m.Called(ctx, webhookID, filter)
+	return args.Get(0).([]model.WebhookDeliveryEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *WebhookRepositoryMock) FindDeliveryByID(ctx context.Context, webhookID int, deliveryID int64) (*model.WebhookDeliveryEntity, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	return args.Get(0).(*model.WebhookDeliveryEntity), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) UpdateDelivery(ctx context.Context, deliveryEntity *model.WebhookDeliveryEntity) error {
+	args := m.Called(ctx, deliveryEntity)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) FinishDelivery(ctx context.Context, deliveryEntity *model.WebhookDeliveryEntity, claimedUntil time.Time) (bool, error) {
+	args := m.Called(ctx, deliveryEntity, claimedUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+func NewWebhookServiceMock() *WebhookServiceMock {
+	return &WebhookServiceMock{}
+}
+
+func (m *WebhookServiceMock) Enqueue(ctx context.Context, eventID string, eventType string, data interface{}) error {
+	args := m.Called(ctx, eventID, eventType, data)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) Create(ctx context.Context, webhookCreateReq *model.WebhookCreate) (*model.Webhook, error) {
+	args := m.Called(ctx, webhookCreateReq)
+	return args.Get(0).(*model.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) FindByID(ctx context.Context, id int) (*model.Webhook, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Update(ctx context.Context, id int, webhookUpdateReq *model.WebhookUpdate) error {
+	args := m.Called(ctx, id, webhookUpdateReq)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) FindDeliveries(ctx context.Context, id int, filter *model.WebhookDeliveryFilter) (*model.WebhookDeliveryPage, error) {
+	args := m.Called(ctx, id, filter)
+	return args.Get(0).(*model.WebhookDeliveryPage), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Redeliver(ctx context.Context, id int, deliveryID int64) error {
+	args := m.Called(ctx, id, deliveryID)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) DeliverDue(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"context"
+	"errors"
+)
+
+const gormSpanKey = "tracing:span"
+
+type GormPlugin struct{}
+
+// gormSpan keeps the context the query was given, a statement reused for another query must not
+// hang that one below this span.
+type gormSpan struct {
+	span 	trace.Span
+	parent 	context.Context
+}
+
+// NewGormPlugin opens a span with the SQL statement for every query run through the db it is
+// registered on with db.Use, as a child of the span in the context the query was given.
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	registrations := []func() error{
+		func() error { return db.Callback().Create().Before("*").Register("tracing:before_create", before("create")) },
+		func() error { return db.Callback().Create().After("*").Register("tracing:after_create", after) },
+		func() error { return db.Callback().Query().Before("*").Register("tracing:before_query", before("query")) },
+		func() error { return db.Callback().Query().After("*").Register("tracing:after_query", after) },
+		func() error { return db.Callback().Update().Before("*").Register("tracing:before_update", before("update")) },
+		func() error { return db.Callback().Update().After("*").Register("tracing:after_update", after) },
+		func() error { return db.Callback().Delete().Before("*").Register("tracing:before_delete", before("delete")) },
+		func() error { return db.Callback().Delete().After("*").Register("tracing:after_delete", after) },
+		func() error { return db.Callback().Row().Before("*").Register("tracing:before_row", before("row")) },
+		func() error { return db.Callback().Row().After("*").Register("tracing:after_row", after) },
+		func() error { return db.Callback().Raw().Before("*").Register("tracing:before_raw", before("raw")) },
+		func() error { return db.Callback().Raw().After("*").Register("tracing:after_raw", after) },
+	}
+	for _, register := range registrations {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		ctx, span := Start(parent, "gorm." + operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperation(operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, &gormSpan{span: span, parent: parent})
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	started, ok := value.(*gormSpan)
+	if !ok {
+		return
+	}
+	span := started.span
+	db.Statement.Context = started.parent
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBStatement(db.Statement.SQL.String()),
+		semconv.DBSQLTable(db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"context"
+	"fmt"
+)
+
+const instrumentationName = "github.com/Yoshikrit/fiber-test"
+
+const (
+	ExporterNone 	= "none"
+	ExporterStdout 	= "stdout"
+	ExporterOTLP 	= "otlp"
+)
+
+// Setup installs the global tracer provider and the W3C trace context propagator. exporter is otlp,
+// sending to endpoint over HTTP (the OTEL_EXPORTER_OTLP_* variables when empty), stdout or none.
+// With none spans are still created so trace IDs reach the logs, they are just not exported.
+// Call shutdown before exiting to flush the spans still buffered.
+func Setup(ctx context.Context, exporter, serviceName, endpoint string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	}
+	switch exporter {
+	case ExporterNone, "":
+	case ExporterStdout:
+		spanExporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(spanExporter))
+	case ExporterOTLP:
+		var clientOptions []otlptracehttp.Option
+		if endpoint != "" {
+			clientOptions = append(clientOptions, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		spanExporter, err := otlptracehttp.New(ctx, clientOptions...)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(spanExporter))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", exporter)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(tracerProvider)
+	return tracerProvider.Shutdown, nil
+}
+
+// Start opens a span named name as a child of the one in ctx.
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, options...)
+}
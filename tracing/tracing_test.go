@@ -0,0 +1,101 @@
+package tracing_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Yoshikrit/fiber-test/model"
+	"github.com/Yoshikrit/fiber-test/testutils"
+	"github.com/Yoshikrit/fiber-test/tracing"
+
+	"context"
+	"errors"
+	"testing"
+)
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("test case : setup without exporter", func(t *testing.T) {
+		shutdown, err := tracing.Setup(context.Background(), tracing.ExporterNone, "fiber-test", "")
+
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("test case : setup fail unknown exporter", func(t *testing.T) {
+		shutdown, err := tracing.Setup(context.Background(), "jaeger", "fiber-test", "")
+
+		assert.Nil(t, shutdown)
+		assert.EqualError(t, err, "unknown tracing exporter: jaeger")
+	})
+}
+
+func TestGormPlugin(t *testing.T) {
+	db, mock := testutils.SetupMockDB(t)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+	assert.NoError(t, db.Use(tracing.NewGormPlugin()))
+
+	t.Run("test case : query span is child with statement", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+		mock.ExpectQuery(`SELECT \* FROM "oauth"`).
+			WillReturnRows(sqlmock.NewRows([]string{"oauth_id"}).AddRow(1))
+
+		ctx, parent := tracing.Start(context.Background(), "parent")
+		var oauthEntities []model.OauthEntity
+		err := db.WithContext(ctx).Find(&oauthEntities).Error
+		parent.End()
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "gorm.query", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Equal(t, `SELECT * FROM "oauth"`, attributeValue(spans[0], "db.statement"))
+		assert.Equal(t, "oauth", attributeValue(spans[0], "db.sql.table"))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("test case : failed query span has error status", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+		mock.ExpectQuery(`SELECT \* FROM "oauth"`).
+			WillReturnError(errors.New("connection refused"))
+
+		var oauthEntities []model.OauthEntity
+		err := db.WithContext(context.Background()).Find(&oauthEntities).Error
+
+		assert.Error(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "connection refused", spans[0].Status().Description)
+	})
+
+	t.Run("test case : not found is not an error", func(t *testing.T) {
+		recorder := testutils.SetupSpanRecorder(t)
+		mock.ExpectQuery(`SELECT \* FROM "oauth"`).
+			WillReturnRows(sqlmock.NewRows([]string{"oauth_id"}))
+
+		var oauthEntity model.OauthEntity
+		err := db.WithContext(context.Background()).First(&oauthEntity).Error
+
+		assert.Error(t, err)
+		assert.Equal(t, codes.Unset, recorder.Ended()[0].Status().Code)
+	})
+}
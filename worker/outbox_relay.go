@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"context"
+	"time"
+)
+
+const outboxRelayDefaultInterval = time.Second
+
+type OutboxRelayWorker struct {
+	outboxSrv 	service.OutboxService
+	interval 	time.Duration
+	ctx 		context.Context
+	cancel 		context.CancelFunc
+	done 		chan struct{}
+}
+
+// NewOutboxRelayWorker relays on every interval, zero takes one second.
+func NewOutboxRelayWorker(outboxSrv service.OutboxService, interval time.Duration) *OutboxRelayWorker {
+	if interval == 0 {
+		interval = outboxRelayDefaultInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxRelayWorker{
+		outboxSrv: 	outboxSrv,
+		interval: 	interval,
+		ctx: 		ctx,
+		cancel: 	cancel,
+		done: 		make(chan struct{}),
+	}
+}
+
+// Start relays the outbox on every interval until Stop is called. A negative interval
+// disables the worker, for when another instance runs the relay.
+func (w *OutboxRelayWorker) Start() {
+	if w.interval < 0 {
+		logger.Info(w.ctx, "Worker: Outbox Relay is disabled")
+		close(w.done)
+		return
+	}
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.RunOnce()
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (w *OutboxRelayWorker) RunOnce() {
+	if _, err := w.outboxSrv.Relay(w.ctx); err != nil {
+		logger.Error(w.ctx, err)
+	}
+}
+
+// Stop cancels an in flight relay and waits for the worker to exit,
+// events not yet marked published are relayed again on the next start.
+func (w *OutboxRelayWorker) Stop() {
+	w.cancel()
+	<-w.done
+}
@@ -0,0 +1,62 @@
+package worker_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/worker"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"testing"
+	"time"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRelayWorker(t *testing.T) {
+	t.Run("test case : run once relay", func(t *testing.T) {
+		mockService := testutils.NewOutboxServiceMock()
+		mockService.On("Relay", mock.Anything).Return(2, nil)
+
+		relayWorker := worker.NewOutboxRelayWorker(mockService, time.Minute)
+		relayWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : run once fail from service", func(t *testing.T) {
+		mockService := testutils.NewOutboxServiceMock()
+		mockService.On("Relay", mock.Anything).Return(0, errs.NewInternalServerError(""))
+
+		relayWorker := worker.NewOutboxRelayWorker(mockService, time.Minute)
+		relayWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : start relays on interval", func(t *testing.T) {
+		relayed := make(chan struct{}, 10)
+		mockService := testutils.NewOutboxServiceMock()
+		mockService.On("Relay", mock.Anything).Return(0, nil).Run(func(args mock.Arguments) {
+			relayed <- struct{}{}
+		})
+
+		relayWorker := worker.NewOutboxRelayWorker(mockService, 10 * time.Millisecond)
+		relayWorker.Start()
+		defer relayWorker.Stop()
+
+		select {
+		case <-relayed:
+		case <-time.After(time.Second):
+			t.Fatal("Relay was not called")
+		}
+	})
+
+	t.Run("test case : start disabled", func(t *testing.T) {
+		mockService := testutils.NewOutboxServiceMock()
+
+		relayWorker := worker.NewOutboxRelayWorker(mockService, -1)
+		relayWorker.Start()
+		relayWorker.Stop()
+
+		assert.Empty(t, mockService.Calls)
+	})
+}
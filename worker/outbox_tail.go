@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"context"
+	"time"
+)
+
+const outboxTailDefaultInterval = time.Second
+
+type OutboxTailWorker struct {
+	tailSrv 	service.OutboxTailService
+	interval 	time.Duration
+	ctx 		context.Context
+	cancel 		context.CancelFunc
+	done 		chan struct{}
+}
+
+// NewOutboxTailWorker tails on every interval, zero takes one second.
+func NewOutboxTailWorker(tailSrv service.OutboxTailService, interval time.Duration) *OutboxTailWorker {
+	if interval == 0 {
+		interval = outboxTailDefaultInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxTailWorker{
+		tailSrv: 	tailSrv,
+		interval: 	interval,
+		ctx: 		ctx,
+		cancel: 	cancel,
+		done: 		make(chan struct{}),
+	}
+}
+
+// Start finds where to tail from at once and then tails the outbox on every interval until Stop
+// is called. Unlike the relay it runs on every instance, each one feeds its own event stream.
+// A negative interval disables the worker.
+func (w *OutboxTailWorker) Start() {
+	if w.interval < 0 {
+		logger.Info(w.ctx, "Worker: Outbox Tail is disabled")
+		close(w.done)
+		return
+	}
+
+	go func() {
+		defer close(w.done)
+
+		w.RunOnce()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.RunOnce()
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (w *OutboxTailWorker) RunOnce() {
+	if _, err := w.tailSrv.Tail(w.ctx); err != nil {
+		logger.Error(w.ctx, err)
+	}
+}
+
+// Stop cancels an in flight tail and waits for the worker to exit.
+func (w *OutboxTailWorker) Stop() {
+	w.cancel()
+	<-w.done
+}
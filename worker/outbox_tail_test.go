@@ -0,0 +1,62 @@
+package worker_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/worker"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"testing"
+	"time"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxTailWorker(t *testing.T) {
+	t.Run("test case : run once tail", func(t *testing.T) {
+		mockService := testutils.NewOutboxTailServiceMock()
+		mockService.On("Tail", mock.Anything).Return(2, nil)
+
+		tailWorker := worker.NewOutboxTailWorker(mockService, time.Minute)
+		tailWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : run once fail from service", func(t *testing.T) {
+		mockService := testutils.NewOutboxTailServiceMock()
+		mockService.On("Tail", mock.Anything).Return(0, errs.NewInternalServerError(""))
+
+		tailWorker := worker.NewOutboxTailWorker(mockService, time.Minute)
+		tailWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : start tails at once", func(t *testing.T) {
+		tailed := make(chan struct{}, 10)
+		mockService := testutils.NewOutboxTailServiceMock()
+		mockService.On("Tail", mock.Anything).Return(0, nil).Run(func(args mock.Arguments) {
+			tailed <- struct{}{}
+		})
+
+		tailWorker := worker.NewOutboxTailWorker(mockService, time.Minute)
+		tailWorker.Start()
+		defer tailWorker.Stop()
+
+		select {
+		case <-tailed:
+		case <-time.After(time.Second):
+			t.Fatal("Tail was not called")
+		}
+	})
+
+	t.Run("test case : start disabled", func(t *testing.T) {
+		mockService := testutils.NewOutboxTailServiceMock()
+
+		tailWorker := worker.NewOutboxTailWorker(mockService, -1)
+		tailWorker.Start()
+		tailWorker.Stop()
+
+		assert.Empty(t, mockService.Calls)
+	})
+}
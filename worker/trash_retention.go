@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"context"
+	"time"
+)
+
+type TrashRetentionWorker struct {
+	prodTypeSrv service.ProductTypeService
+	retention 	time.Duration
+	interval 	time.Duration
+	stop 		chan struct{}
+	done 		chan struct{}
+}
+
+func NewTrashRetentionWorker(prodTypeSrv service.ProductTypeService, retention, interval time.Duration) *TrashRetentionWorker {
+	return &TrashRetentionWorker{
+		prodTypeSrv: 	prodTypeSrv,
+		retention: 		retention,
+		interval: 		interval,
+		stop: 			make(chan struct{}),
+		done: 			make(chan struct{}),
+	}
+}
+
+// Start purges trash older than the retention on every interval until Stop is called.
+// A zero retention or interval disables the worker.
+func (w *TrashRetentionWorker) Start() {
+	if w.retention <= 0 || w.interval <= 0 {
+		logger.Info(context.Background(), "Worker: Trash Retention is disabled")
+		close(w.done)
+		return
+	}
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.RunOnce()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce purges as the trash retention system actor, so the purges are not audited as an anonymous user's.
+func (w *TrashRetentionWorker) RunOnce() {
+	ctx := helper.SystemContext(context.Background(), helper.ActorTrashRetention)
+	if _, err := w.prodTypeSrv.PurgeExpired(ctx, w.retention); err != nil {
+		logger.Error(ctx, err)
+	}
+}
+
+func (w *TrashRetentionWorker) Stop() {
+	close(w.stop)
+	<-w.done
+}
@@ -0,0 +1,69 @@
+package worker_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/worker"
+	"github.com/Yoshikrit/fiber-test/helper"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"context"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTrashRetentionWorker(t *testing.T) {
+	t.Run("test case : run once purge expired", func(t *testing.T) {
+		mockService := testutils.NewProductTypeServiceMock()
+		mockService.On("PurgeExpired", mock.MatchedBy(func(ctx context.Context) bool {
+			actor := helper.ActorFromContext(ctx)
+			return actor.UserID == nil && actor.System == helper.ActorTrashRetention
+		}), 24 * time.Hour).Return(int64(1), nil)
+
+		trashWorker := worker.NewTrashRetentionWorker(mockService, 24 * time.Hour, time.Minute)
+		trashWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : run once fail from service", func(t *testing.T) {
+		mockService := testutils.NewProductTypeServiceMock()
+		mockService.On("PurgeExpired", mock.Anything, 24 * time.Hour).Return(int64(0), errs.NewInternalServerError(""))
+
+		trashWorker := worker.NewTrashRetentionWorker(mockService, 24 * time.Hour, time.Minute)
+		trashWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : start purge on interval", func(t *testing.T) {
+		called := make(chan struct{}, 1)
+		mockService := testutils.NewProductTypeServiceMock()
+		mockService.On("PurgeExpired", mock.Anything, 24 * time.Hour).Return(int64(0), nil).Run(func(args mock.Arguments) {
+			select {
+			case called <- struct{}{}:
+			default:
+			}
+		})
+
+		trashWorker := worker.NewTrashRetentionWorker(mockService, 24 * time.Hour, 10 * time.Millisecond)
+		trashWorker.Start()
+
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("PurgeExpired was not called")
+		}
+		trashWorker.Stop()
+	})
+
+	t.Run("test case : start disabled", func(t *testing.T) {
+		mockService := testutils.NewProductTypeServiceMock()
+
+		trashWorker := worker.NewTrashRetentionWorker(mockService, 0, time.Minute)
+		trashWorker.Start()
+		trashWorker.Stop()
+
+		mockService.AssertNotCalled(t, "PurgeExpired", mock.Anything, 24 * time.Hour)
+	})
+}
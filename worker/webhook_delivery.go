@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"github.com/Yoshikrit/fiber-test/service"
+	"github.com/Yoshikrit/fiber-test/helper/logger"
+
+	"context"
+	"time"
+)
+
+type WebhookDeliveryWorker struct {
+	webhookSrv 	service.WebhookService
+	interval 	time.Duration
+	ctx 		context.Context
+	cancel 		context.CancelFunc
+	done 		chan struct{}
+}
+
+func NewWebhookDeliveryWorker(webhookSrv service.WebhookService, interval time.Duration) *WebhookDeliveryWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookDeliveryWorker{
+		webhookSrv: webhookSrv,
+		interval: 	interval,
+		ctx: 		ctx,
+		cancel: 	cancel,
+		done: 		make(chan struct{}),
+	}
+}
+
+// Start sends the due webhook deliveries on every interval until Stop is called.
+// A zero interval disables the worker, deliveries are then only queued.
+func (w *WebhookDeliveryWorker) Start() {
+	if w.interval <= 0 {
+		logger.Info(w.ctx, "Worker: Webhook Delivery is disabled")
+		close(w.done)
+		return
+	}
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.RunOnce()
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (w *WebhookDeliveryWorker) RunOnce() {
+	if _, err := w.webhookSrv.DeliverDue(w.ctx); err != nil {
+		logger.Error(w.ctx, err)
+	}
+}
+
+// Stop cancels an in flight delivery run and waits for the worker to exit.
+func (w *WebhookDeliveryWorker) Stop() {
+	w.cancel()
+	<-w.done
+}
@@ -0,0 +1,69 @@
+package worker_test
+
+import (
+	"github.com/Yoshikrit/fiber-test/worker"
+	"github.com/Yoshikrit/fiber-test/helper/errs"
+	"github.com/Yoshikrit/fiber-test/testutils"
+
+	"context"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDeliveryWorker(t *testing.T) {
+	t.Run("test case : run once deliver due", func(t *testing.T) {
+		mockService := testutils.NewWebhookServiceMock()
+		mockService.On("DeliverDue", mock.Anything).Return(1, nil)
+
+		deliveryWorker := worker.NewWebhookDeliveryWorker(mockService, time.Minute)
+		deliveryWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : run once fail from service", func(t *testing.T) {
+		mockService := testutils.NewWebhookServiceMock()
+		mockService.On("DeliverDue", mock.Anything).Return(0, errs.NewInternalServerError(""))
+
+		deliveryWorker := worker.NewWebhookDeliveryWorker(mockService, time.Minute)
+		deliveryWorker.RunOnce()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("test case : stop cancels running delivery", func(t *testing.T) {
+		cancelled := make(chan struct{})
+		mockService := testutils.NewWebhookServiceMock()
+		mockService.On("DeliverDue", mock.Anything).Return(0, nil).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+			select {
+			case <-cancelled:
+			default:
+				close(cancelled)
+			}
+		})
+
+		deliveryWorker := worker.NewWebhookDeliveryWorker(mockService, 10 * time.Millisecond)
+		deliveryWorker.Start()
+		time.Sleep(30 * time.Millisecond)
+		deliveryWorker.Stop()
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("DeliverDue was not cancelled")
+		}
+	})
+
+	t.Run("test case : start disabled", func(t *testing.T) {
+		mockService := testutils.NewWebhookServiceMock()
+
+		deliveryWorker := worker.NewWebhookDeliveryWorker(mockService, 0)
+		deliveryWorker.Start()
+		deliveryWorker.Stop()
+
+		assert.Empty(t, mockService.Calls)
+	})
+}